@@ -15,142 +15,273 @@
 package main
 
 import (
-	"cmp"
 	"context"
+	"errors"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"slices"
-	"strconv"
 	"time"
 
 	"github.com/GoogleChrome/webstatus.dev/backend/pkg/httpserver"
+	"github.com/GoogleChrome/webstatus.dev/lib/config"
 	"github.com/GoogleChrome/webstatus.dev/lib/gcpspanner"
 	"github.com/GoogleChrome/webstatus.dev/lib/gcpspanner/spanneradapters"
 	"github.com/GoogleChrome/webstatus.dev/lib/gds"
 	"github.com/GoogleChrome/webstatus.dev/lib/gds/datastoreadapters"
+	"github.com/GoogleChrome/webstatus.dev/lib/healthcheck"
 	"github.com/GoogleChrome/webstatus.dev/lib/httpmiddlewares"
+	"github.com/GoogleChrome/webstatus.dev/lib/lifecycle"
 	"github.com/GoogleChrome/webstatus.dev/lib/opentelemetry"
 	"github.com/GoogleChrome/webstatus.dev/lib/rediscache"
+	"github.com/GoogleChrome/webstatus.dev/lib/webhooks"
 	"github.com/go-chi/cors"
 )
 
+// jwksFetchTimeout limits how long a request waits for the identity provider's keys.
+const jwksFetchTimeout = 10 * time.Second
+
+// serverConfig contains the settings of the backend server.
+type serverConfig struct {
+	config.GCP
+	Spanner       config.Spanner
+	Datastore     config.Datastore
+	Redis         config.Redis
+	OpenTelemetry config.OpenTelemetry
+	Webhooks      config.Webhooks
+	// Allowed Origin. Can remove after UbP.
+	AllowedOrigin string `env:"CORS_ALLOWED_ORIGIN" usage:"Origin allowed to make cross origin requests"`
+	// Set by Cloud Run. Cached entries are scoped to the revision.
+	Revision          string `env:"K_REVISION" usage:"Revision used as the prefix of cache keys"`
+	RequestsPerMinute int    `env:"RATE_LIMIT_REQUESTS_PER_MINUTE" usage:"Default requests per minute per client"`
+	// Set to "memory" to keep rate limit buckets in process instead of redis.
+	RateLimitStore string `env:"RATE_LIMIT_STORE" usage:"Rate limit store (redis or memory)"`
+	// The backend runs behind the global external load balancer which appends its own address to X-Forwarded-For.
+	TrustedProxyHops int `env:"RATE_LIMIT_TRUSTED_PROXY_HOPS" usage:"Proxies that append to X-Forwarded-For"`
+	// User tokens are verified with the keys from either the URL or the file.
+	// Without either, operations that require a user are rejected.
+	AuthJWKSURL  string `env:"AUTH_JWKS_URL" usage:"URL of the JSON Web Key Set that signs user tokens"`
+	AuthJWKSFile string `env:"AUTH_JWKS_FILE" usage:"JSON Web Key Set file that signs user tokens (development)"`
+	AuthIssuer   string `env:"AUTH_ISSUER" usage:"Expected issuer of user tokens"`
+	AuthAudience string `env:"AUTH_AUDIENCE" usage:"Expected audience of user tokens"`
+}
+
+func (c *serverConfig) authEnabled() bool {
+	return c.AuthJWKSURL != "" || c.AuthJWKSFile != ""
+}
+
+func (c *serverConfig) Validate() []error {
+	var errs []error
+	if c.RequestsPerMinute <= 0 {
+		errs = append(errs, errors.New("RATE_LIMIT_REQUESTS_PER_MINUTE must be greater than zero"))
+	}
+	if c.RateLimitStore != "redis" && c.RateLimitStore != "memory" {
+		errs = append(errs, errors.New("RATE_LIMIT_STORE must be redis or memory"))
+	}
+	if c.TrustedProxyHops < 0 {
+		errs = append(errs, errors.New("RATE_LIMIT_TRUSTED_PROXY_HOPS must not be negative"))
+	}
+	if c.AuthJWKSURL != "" && c.AuthJWKSFile != "" {
+		errs = append(errs, errors.New("only one of AUTH_JWKS_URL or AUTH_JWKS_FILE can be set"))
+	}
+	if c.authEnabled() && (c.AuthIssuer == "" || c.AuthAudience == "") {
+		errs = append(errs, errors.New("AUTH_ISSUER and AUTH_AUDIENCE are required to verify user tokens"))
+	}
+
+	return errs
+}
+
 func main() {
-	var datastoreDB *string
-	if value, found := os.LookupEnv("DATASTORE_DATABASE"); found {
-		datastoreDB = &value
+	cfg := serverConfig{
+		GCP:               config.GCP{ProjectID: ""},
+		Spanner:           config.Spanner{Instance: "", Database: "", EmulatorHost: ""},
+		Datastore:         config.Datastore{Database: nil},
+		Redis:             config.NewRedis(),
+		OpenTelemetry:     config.OpenTelemetry{ServiceName: "", ProjectID: ""},
+		Webhooks:          config.Webhooks{AllowLoopback: false},
+		AllowedOrigin:     "",
+		Revision:          "test-revision",
+		RequestsPerMinute: 600,
+		RateLimitStore:    "redis",
+		TrustedProxyHops:  1,
+		AuthJWKSURL:       "",
+		AuthJWKSFile:      "",
+		AuthIssuer:        "",
+		AuthAudience:      "",
 	}
-	projectID := os.Getenv("PROJECT_ID")
-	fs, err := gds.NewDatastoreClient(projectID, datastoreDB)
+	config.MustLoad(&cfg)
+
+	fs, err := gds.NewDatastoreClient(cfg.ProjectID, cfg.Datastore.Database)
 	if err != nil {
 		slog.Error("failed to create datastore client", "error", err.Error())
 		os.Exit(1)
 	}
 
-	ctx := context.Background()
+	ctx, stop := lifecycle.SignalContext(context.Background())
+	defer stop()
 
-	spannerDB := os.Getenv("SPANNER_DATABASE")
-	spannerInstance := os.Getenv("SPANNER_INSTANCE")
-	spannerClient, err := gcpspanner.NewSpannerClient(projectID, spannerInstance, spannerDB)
+	spannerClient, err := gcpspanner.NewSpannerClient(cfg.ProjectID, cfg.Spanner.Instance, cfg.Spanner.Database)
 	if err != nil {
 		slog.Error("failed to create spanner client", "error", err.Error())
 		os.Exit(1)
 	}
 
-	if _, found := os.LookupEnv("SPANNER_EMULATOR_HOST"); found {
+	if cfg.Spanner.EmulatorHost != "" {
 		slog.Info("setting spanner to local mode")
 		spannerClient.SetFeatureSearchBaseQuery(gcpspanner.LocalFeatureBaseQuery{})
 	}
 
-	// Allowed Origin. Can remove after UbP.
-	allowedOrigin := os.Getenv("CORS_ALLOWED_ORIGIN")
-
-	redisHost := os.Getenv("REDISHOST")
-	redisPort := os.Getenv("REDISPORT")
+	slog.Info("cache settings",
+		"duration", cfg.Redis.CacheTTL, "prefix", cfg.Revision, "connections", cfg.Redis.Connections)
 
-	cacheDuration := os.Getenv("CACHE_TTL")
-	duration, err := time.ParseDuration(cacheDuration)
+	cache, err := rediscache.NewRedisDataCache[string, []byte](
+		cfg.Revision,
+		cfg.Redis.Host,
+		cfg.Redis.Port,
+		cfg.Redis.CacheTTL,
+		cfg.Redis.Connections,
+	)
 	if err != nil {
-		slog.Error("unable to parse CACHE_TTL duration", "input value", cacheDuration)
+		slog.Error("unable to create redis cache instance", "error", err)
 		os.Exit(1)
 	}
+	var rateLimiter httpmiddlewares.RateLimiter = rediscache.NewRedisRateLimiter(cache)
+	if cfg.RateLimitStore == "memory" {
+		slog.Info("using in memory rate limiter")
+		rateLimiter = httpmiddlewares.NewInMemoryRateLimiter()
+	}
 
-	connectionsStr := os.Getenv("CACHE_CONNECTIONS")
-	connections := 10
-	if connectionsStr != "" {
-		var parseErr error
-		connections, parseErr = strconv.Atoi(connectionsStr)
-		if parseErr != nil {
-			slog.Error("unable to parse cache connections", "input", connectionsStr)
+	// Without a key set, bearer tokens are ignored and requests stay anonymous.
+	authMiddleware := func(next http.Handler) http.Handler { return next }
+	var keySet httpmiddlewares.KeySet
+	switch {
+	case cfg.AuthJWKSURL != "":
+		keySet = httpmiddlewares.NewRemoteKeySet(&http.Client{Timeout: jwksFetchTimeout}, cfg.AuthJWKSURL)
+	case cfg.AuthJWKSFile != "":
+		fileKeySet, err := httpmiddlewares.LoadJWKSFile(cfg.AuthJWKSFile)
+		if err != nil {
+			slog.Error("unable to load user token keys", "error", err)
 			os.Exit(1)
 		}
+		keySet = fileKeySet
+	default:
+		slog.Warn("user authentication is disabled. operations that require a user will be rejected")
 	}
-
-	cacheKeyPrefix := cmp.Or[string](os.Getenv("K_REVISION"), "test-revision")
-	slog.Info("cache settings", "duration", duration, "prefix", cacheKeyPrefix, "connections", connections)
-
-	cache, err := rediscache.NewRedisDataCache[string, []byte](
-		cacheKeyPrefix,
-		redisHost,
-		redisPort,
-		duration,
-		connections,
-	)
-	if err != nil {
-		slog.Error("unable to create redis cache instance", "error", err)
-		os.Exit(1)
+	if keySet != nil {
+		authMiddleware = httpmiddlewares.NewAuthMiddleware(
+			httpmiddlewares.NewJWTVerifier(keySet, cfg.AuthIssuer, cfg.AuthAudience))
 	}
+
+	spannerBackend := spanneradapters.NewBackend(spannerClient)
+	datastoreBackend := datastoreadapters.NewBackend(fs)
 	middlewares := []func(http.Handler) http.Handler{
 		opentelemetry.NewOpenTelemetryChiMiddleware(),
 		cors.Handler(
 			//nolint: exhaustruct // No need to use every option of 3rd party struct.
 			cors.Options{
-				AllowedOrigins: []string{allowedOrigin},
+				AllowedOrigins: []string{cfg.AllowedOrigin},
 				// AllowOriginFunc:  func(r *http.Request, origin string) bool { return true },
-				AllowedMethods: []string{"GET", "OPTIONS"},
-				// AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
+				AllowedMethods: []string{"GET", "POST", "DELETE", "OPTIONS"},
+				AllowedHeaders: []string{
+					"Accept", "Authorization", "Content-Type", "X-Requested-With", httpmiddlewares.APIKeyHeader,
+				},
 				// ExposedHeaders:   []string{"Link"},
 				AllowCredentials: true, // Remove after UbP
 				MaxAge:           300,  // Maximum value not ignored by any of major browsers
 			}),
-		httpmiddlewares.NewCacheMiddleware(cache),
+		httpmiddlewares.NewAPIKeyMiddleware(datastoreBackend),
+		authMiddleware,
+		httpmiddlewares.NewRateLimitMiddleware(rateLimiter, httpmiddlewares.RateLimitMiddlewareConfig{
+			Policies: []httpmiddlewares.RateLimitPolicy{
+				// Exports and comparisons read many pages of data per request.
+				{
+					PathPrefix: "/v1/features:export",
+					Limit:      httpmiddlewares.RateLimit{Requests: 10, Period: time.Minute},
+				},
+				{
+					PathPrefix: "/v1/features:compare",
+					Limit:      httpmiddlewares.RateLimit{Requests: 60, Period: time.Minute},
+				},
+			},
+			DefaultLimit: httpmiddlewares.RateLimit{Requests: cfg.RequestsPerMinute, Period: time.Minute},
+			QuotaTierMultipliers: map[string]int{
+				"standard": 2,
+				"high":     10,
+			},
+			TrustedProxyHops: cfg.TrustedProxyHops,
+		}),
+		httpmiddlewares.NewCacheMiddleware(cache, httpmiddlewares.CacheMiddlewareConfig{
+			LastModifiedGetter: spannerBackend,
+			CacheControlPolicies: []httpmiddlewares.CacheControlPolicy{
+				// Badges are embedded in third party pages. Serve stale badges while revalidating
+				// so that those pages never wait on the API.
+				{
+					PathPrefix: "/v1/features/",
+					PathSuffix: "/badge.svg",
+					Value:      "public, max-age=86400, stale-while-revalidate=604800",
+				},
+				// Stats are only recomputed when new data is ingested.
+				{PathPrefix: "/v1/stats/", PathSuffix: "", Value: "public, max-age=3600"},
+				{PathPrefix: "/v1/features", PathSuffix: "", Value: "public, max-age=300"},
+			},
+			// Clients must revalidate with the ETag or Last-Modified.
+			DefaultCacheControl: "no-cache",
+			// Subscriptions are only visible to the API key that created them.
+			BypassPathPrefixes: []string{"/v1/subscriptions"},
+		}),
 	}
 
-	if os.Getenv("OTEL_SERVICE_NAME") != "" {
+	// Closed in order during shutdown, after the in-flight requests finish.
+	var closers []lifecycle.Closer
+	if cfg.OpenTelemetry.Enabled() {
 		slog.Info("opentelemetry settings detected.")
-		otelProjectID := os.Getenv("OTEL_GCP_PROJECT_ID")
-		if otelProjectID == "" {
-			slog.Error("missing project id for opentelemetry")
-			os.Exit(1)
-		}
-		shutdown, err := opentelemetry.SetupOpenTelemetry(ctx, otelProjectID)
+		shutdown, err := opentelemetry.SetupOpenTelemetry(ctx, cfg.OpenTelemetry.ProjectID)
 		if err != nil {
 			slog.Error("failed to setup opentelemetry", "error", err.Error())
 			os.Exit(1)
 		}
-		defer func() {
-			err := shutdown(ctx)
-			if err != nil {
-				slog.Error("unable to shutdown opentelemetry")
-			}
-		}()
+		// Flush the telemetry of the last requests before the clients are closed.
+		closers = append(closers, lifecycle.Closer{Name: "opentelemetry", Close: shutdown})
+		// Include the owner of the API key in the logs of requests that have one.
+		// This is only done here because wrapping the built-in default handler deadlocks.
+		slog.SetDefault(slog.New(httpmiddlewares.NewClientIdentityLogHandler(slog.Default().Handler())))
 		// Prepend the opentelemtry middleware
 		middlewares = slices.Insert(middlewares, 0, opentelemetry.NewOpenTelemetryChiMiddleware())
 	}
 
+	closers = append(closers,
+		lifecycle.NewCloser("spanner", func() error {
+			spannerClient.Close()
+
+			return nil
+		}),
+		lifecycle.NewCloser("datastore", fs.Close),
+		lifecycle.NewCloser("redis", cache.Close),
+	)
+
 	srv, err := httpserver.NewHTTPServer(
 		"8080",
-		datastoreadapters.NewBackend(fs),
-		spanneradapters.NewBackend(spannerClient),
+		datastoreBackend,
+		spannerBackend,
+		datastoreBackend,
+		webhooks.NewCallbackPolicy(net.DefaultResolver, cfg.Webhooks.AllowLoopback),
+		spannerBackend,
+		cache,
 		middlewares,
+		[]healthcheck.Dependency{
+			{Name: "spanner", Check: spannerClient.Ping},
+			{Name: "datastore", Check: fs.Ping},
+			{Name: "redis", Check: cache.Ping},
+		},
 	)
 	if err != nil {
 		slog.Error("unable to create server", "error", err.Error())
 		os.Exit(1)
 	}
-	err = srv.ListenAndServe()
+	err = lifecycle.ListenAndServe(ctx, srv, lifecycle.DefaultShutdownTimeout, closers...)
 	if err != nil {
-		slog.Error("unable to start server", "error", err.Error())
+		slog.Error("server stopped with error", "error", err.Error())
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,232 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/GoogleChrome/webstatus.dev/lib/gcpspanner"
+	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/backend"
+	"golang.org/x/sync/errgroup"
+)
+
+// maxComparedFeatures comes from the <repo_root>/openapi/backend/openapi.yaml.
+const maxComparedFeatures = 10
+
+// compareFeaturesMaxConcurrentQueries limits the number of WPT metric queries that a comparison runs at the same time.
+const compareFeaturesMaxConcurrentQueries = 8
+
+// compareFeaturesChannels are the WPT channels returned for each browser.
+func compareFeaturesChannels() []backend.ChannelPathParam {
+	return []backend.ChannelPathParam{
+		backend.Stable,
+		backend.Experimental,
+	}
+}
+
+// CompareFeatures implements backend.StrictServerInterface.
+// nolint:ireturn // Expected ireturn for openapi generation.
+func (s *Server) CompareFeatures(
+	ctx context.Context,
+	req backend.CompareFeaturesRequestObject,
+) (backend.CompareFeaturesResponseObject, error) {
+	var featureIDs []string
+	for _, featureID := range req.Params.Ids {
+		if !slices.Contains(featureIDs, featureID) {
+			featureIDs = append(featureIDs, featureID)
+		}
+	}
+	if len(featureIDs) == 0 || len(featureIDs) > maxComparedFeatures {
+		return backend.CompareFeatures400JSONResponse{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("between 1 and %d feature ids must be provided", maxComparedFeatures),
+		}, nil
+	}
+	metricView := getWPTMetricViewOrDefault(req.Params.WptMetricView)
+
+	items := make([]backend.FeatureComparisonItem, 0, len(featureIDs))
+	for _, featureID := range featureIDs {
+		feature, err := s.wptMetricsStorer.GetFeature(ctx, featureID, metricView, defaultBrowsers(), nil)
+		if err != nil {
+			if errors.Is(err, gcpspanner.ErrQueryReturnedNoResults) {
+				return backend.CompareFeatures404JSONResponse{
+					Code:    http.StatusNotFound,
+					Message: fmt.Sprintf("feature id %s is not found", featureID),
+				}, nil
+			}
+			slog.ErrorContext(ctx, "unable to get feature for comparison", "feature", featureID, "error", err)
+
+			return backend.CompareFeatures500JSONResponse{
+				Code:    http.StatusInternalServerError,
+				Message: "unable to compare features",
+			}, nil
+		}
+
+		item := backend.FeatureComparisonItem{
+			FeatureId:              feature.FeatureId,
+			Name:                   feature.Name,
+			BrowserImplementations: feature.BrowserImplementations,
+			Wpt:                    nil,
+		}
+		for _, browser := range defaultBrowsers() {
+			for _, channel := range compareFeaturesChannels() {
+				item.Wpt = append(item.Wpt, backend.FeatureComparisonWPTSeries{
+					Browser: string(browser),
+					Channel: string(channel),
+					Points:  nil,
+				})
+			}
+		}
+		items = append(items, item)
+	}
+
+	// series holds the runs of every item's WPT series, in the same order as the items' series.
+	series, err := s.listComparedFeaturesWPTMetrics(ctx, featureIDs, items, metricView,
+		req.Params.StartAt.Time, req.Params.EndAt.Time)
+	if err != nil {
+		slog.ErrorContext(ctx, "unable to get feature metrics for comparison", "error", err)
+
+		return backend.CompareFeatures500JSONResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "unable to compare features",
+		}, nil
+	}
+
+	timestamps := make([]time.Time, 0)
+	for _, itemSeries := range series {
+		for _, runs := range itemSeries {
+			for _, run := range runs {
+				timestamps = append(timestamps, run.RunTimestamp)
+			}
+		}
+	}
+	slices.SortFunc(timestamps, func(a, b time.Time) int { return a.Compare(b) })
+	timestamps = slices.CompactFunc(timestamps, func(a, b time.Time) bool { return a.Equal(b) })
+	for itemIdx := range items {
+		for seriesIdx := range items[itemIdx].Wpt {
+			items[itemIdx].Wpt[seriesIdx].Points = alignWPTRunMetrics(series[itemIdx][seriesIdx], timestamps)
+		}
+	}
+
+	return backend.CompareFeatures200JSONResponse{
+		Timestamps: timestamps,
+		Features:   items,
+	}, nil
+}
+
+// listComparedFeaturesWPTMetrics returns the runs of every item's WPT series.
+// The series are fetched concurrently, with at most compareFeaturesMaxConcurrentQueries queries at a time.
+func (s *Server) listComparedFeaturesWPTMetrics(
+	ctx context.Context,
+	featureIDs []string,
+	items []backend.FeatureComparisonItem,
+	metricView backend.WPTMetricView,
+	startAt, endAt time.Time,
+) ([][][]backend.WPTRunMetric, error) {
+	series := make([][][]backend.WPTRunMetric, len(items))
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(compareFeaturesMaxConcurrentQueries)
+	for itemIdx, item := range items {
+		series[itemIdx] = make([][]backend.WPTRunMetric, len(item.Wpt))
+		for seriesIdx, wptSeries := range item.Wpt {
+			group.Go(func() error {
+				runs, err := s.listAllFeatureWPTMetrics(groupCtx, featureIDs[itemIdx], wptSeries.Browser,
+					wptSeries.Channel, metricView, startAt, endAt)
+				if err != nil {
+					return fmt.Errorf("feature %s browser %s channel %s: %w",
+						featureIDs[itemIdx], wptSeries.Browser, wptSeries.Channel, err)
+				}
+				series[itemIdx][seriesIdx] = runs
+
+				return nil
+			})
+		}
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	return series, nil
+}
+
+// listAllFeatureWPTMetrics returns every page of WPT metrics for the feature, browser and channel.
+func (s *Server) listAllFeatureWPTMetrics(
+	ctx context.Context,
+	featureID string,
+	browser string,
+	channel string,
+	metricView backend.WPTMetricView,
+	startAt, endAt time.Time,
+) ([]backend.WPTRunMetric, error) {
+	var ret []backend.WPTRunMetric
+	var pageToken *string
+	for {
+		metrics, nextPageToken, err := s.wptMetricsStorer.ListMetricsForFeatureIDBrowserAndChannel(
+			ctx,
+			featureID,
+			browser,
+			channel,
+			metricView,
+			startAt,
+			endAt,
+			getPageSizeOrDefault(nil),
+			pageToken,
+		)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, metrics...)
+		if nextPageToken == nil {
+			return ret, nil
+		}
+		pageToken = nextPageToken
+	}
+}
+
+// alignWPTRunMetrics returns one point per timestamp.
+// Each point uses the score of the latest run at or before its timestamp.
+func alignWPTRunMetrics(runs []backend.WPTRunMetric, timestamps []time.Time) []backend.FeatureComparisonWPTPoint {
+	runs = slices.Clone(runs)
+	slices.SortFunc(runs, func(a, b backend.WPTRunMetric) int { return a.RunTimestamp.Compare(b.RunTimestamp) })
+
+	points := make([]backend.FeatureComparisonWPTPoint, 0, len(timestamps))
+	runIdx := 0
+	var score *float64
+	for _, timestamp := range timestamps {
+		for runIdx < len(runs) && !runs[runIdx].RunTimestamp.After(timestamp) {
+			score = wptRunMetricScore(runs[runIdx])
+			runIdx++
+		}
+		points = append(points, backend.FeatureComparisonWPTPoint{Score: score})
+	}
+
+	return points
+}
+
+// wptRunMetricScore returns the pass rate of the run. It returns nil if the run has no tests.
+func wptRunMetricScore(run backend.WPTRunMetric) *float64 {
+	if run.TestPassCount == nil || run.TotalTestsCount == nil || *run.TotalTestsCount == 0 {
+		return nil
+	}
+	score := float64(*run.TestPassCount) / float64(*run.TotalTestsCount)
+
+	return &score
+}
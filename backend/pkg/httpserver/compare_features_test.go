@@ -0,0 +1,401 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/GoogleChrome/webstatus.dev/lib/gcpspanner"
+	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/backend"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// compareFeaturesMetricsConfigs returns the configs for every browser and channel of a feature.
+// Browser and channel pairs without an entry in data have no runs.
+func compareFeaturesMetricsConfigs(
+	featureID string,
+	data map[string][]MockListMetricsForFeatureIDBrowserAndChannelConfig,
+) []MockListMetricsForFeatureIDBrowserAndChannelConfig {
+	var ret []MockListMetricsForFeatureIDBrowserAndChannelConfig
+	for _, browser := range []string{"chrome", "edge", "firefox", "safari"} {
+		for _, channel := range []string{"stable", "experimental"} {
+			cfgs, found := data[browser+"/"+channel]
+			if !found {
+				cfgs = []MockListMetricsForFeatureIDBrowserAndChannelConfig{{
+					expectedFeatureID: featureID,
+					expectedBrowser:   browser,
+					expectedChannel:   channel,
+					expectedMetric:    backend.SubtestCounts,
+					expectedStartAt:   time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+					expectedEndAt:     time.Date(2000, time.February, 1, 0, 0, 0, 0, time.UTC),
+					expectedPageSize:  100,
+					expectedPageToken: nil,
+					data:              nil,
+					pageToken:         nil,
+					err:               nil,
+				}}
+			}
+			ret = append(ret, cfgs...)
+		}
+	}
+
+	return ret
+}
+
+// compareFeaturesSeries returns the series for every browser and channel of a feature.
+// Browser and channel pairs without an entry in points have no scores.
+func compareFeaturesSeries(
+	points map[string][]backend.FeatureComparisonWPTPoint,
+	numTimestamps int,
+) []backend.FeatureComparisonWPTSeries {
+	var ret []backend.FeatureComparisonWPTSeries
+	for _, browser := range []string{"chrome", "edge", "firefox", "safari"} {
+		for _, channel := range []string{"stable", "experimental"} {
+			seriesPoints, found := points[browser+"/"+channel]
+			if !found {
+				seriesPoints = make([]backend.FeatureComparisonWPTPoint, numTimestamps)
+			}
+			ret = append(ret, backend.FeatureComparisonWPTSeries{
+				Browser: browser,
+				Channel: channel,
+				Points:  seriesPoints,
+			})
+		}
+	}
+
+	return ret
+}
+
+func TestCompareFeatures(t *testing.T) {
+	startAt := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	endAt := time.Date(2000, time.February, 1, 0, 0, 0, 0, time.UTC)
+	run1 := time.Date(2000, time.January, 2, 0, 0, 0, 0, time.UTC)
+	run2 := time.Date(2000, time.January, 3, 0, 0, 0, 0, time.UTC)
+	run3 := time.Date(2000, time.January, 4, 0, 0, 0, 0, time.UTC)
+	run4 := time.Date(2000, time.January, 5, 0, 0, 0, 0, time.UTC)
+	nextPageToken := "page2"
+	browsers := []backend.BrowserPathParam{
+		backend.Chrome,
+		backend.Edge,
+		backend.Firefox,
+		backend.Safari,
+	}
+	feature1 := &backend.Feature{
+		FeatureId: "feature1",
+		Name:      "Feature 1",
+		BrowserImplementations: &map[string]backend.BrowserImplementation{
+			"chrome": {
+				Status: valuePtr(backend.Available),
+				Date: valuePtr(
+					openapi_types.Date{Time: time.Date(1999, time.December, 1, 0, 0, 0, 0, time.UTC)},
+				),
+			},
+		},
+		Baseline:       nil,
+		Spec:           nil,
+		Usage:          nil,
+		Wpt:            nil,
+		RedirectedFrom: nil,
+	}
+	feature2 := &backend.Feature{
+		FeatureId:              "feature2",
+		Name:                   "Feature 2",
+		BrowserImplementations: nil,
+		Baseline:               nil,
+		Spec:                   nil,
+		Usage:                  nil,
+		Wpt:                    nil,
+		RedirectedFrom:         nil,
+	}
+
+	var metricsCfgs []MockListMetricsForFeatureIDBrowserAndChannelConfig
+	metricsCfgs = append(metricsCfgs, compareFeaturesMetricsConfigs("feature1",
+		map[string][]MockListMetricsForFeatureIDBrowserAndChannelConfig{
+			"chrome/stable": {
+				{
+					expectedFeatureID: "feature1",
+					expectedBrowser:   "chrome",
+					expectedChannel:   "stable",
+					expectedMetric:    backend.SubtestCounts,
+					expectedStartAt:   startAt,
+					expectedEndAt:     endAt,
+					expectedPageSize:  100,
+					expectedPageToken: nil,
+					data: []backend.WPTRunMetric{
+						{
+							RunTimestamp:    run3,
+							TestPassCount:   valuePtr[int64](2),
+							TotalTestsCount: valuePtr[int64](2),
+						},
+						{
+							RunTimestamp:    run1,
+							TestPassCount:   valuePtr[int64](1),
+							TotalTestsCount: valuePtr[int64](2),
+						},
+					},
+					pageToken: nil,
+					err:       nil,
+				},
+			},
+		})...)
+	metricsCfgs = append(metricsCfgs, compareFeaturesMetricsConfigs("feature2",
+		map[string][]MockListMetricsForFeatureIDBrowserAndChannelConfig{
+			"chrome/stable": {
+				{
+					expectedFeatureID: "feature2",
+					expectedBrowser:   "chrome",
+					expectedChannel:   "stable",
+					expectedMetric:    backend.SubtestCounts,
+					expectedStartAt:   startAt,
+					expectedEndAt:     endAt,
+					expectedPageSize:  100,
+					expectedPageToken: nil,
+					data: []backend.WPTRunMetric{
+						{
+							RunTimestamp:    run2,
+							TestPassCount:   valuePtr[int64](0),
+							TotalTestsCount: valuePtr[int64](4),
+						},
+					},
+					pageToken: &nextPageToken,
+					err:       nil,
+				},
+				{
+					expectedFeatureID: "feature2",
+					expectedBrowser:   "chrome",
+					expectedChannel:   "stable",
+					expectedMetric:    backend.SubtestCounts,
+					expectedStartAt:   startAt,
+					expectedEndAt:     endAt,
+					expectedPageSize:  100,
+					expectedPageToken: &nextPageToken,
+					data: []backend.WPTRunMetric{
+						{
+							RunTimestamp:    run4,
+							TestPassCount:   valuePtr[int64](3),
+							TotalTestsCount: valuePtr[int64](4),
+						},
+					},
+					pageToken: nil,
+					err:       nil,
+				},
+			},
+		})...)
+
+	testCases := []struct {
+		name                    string
+		getFeatureCfgs          []MockGetFeatureByIDConfig
+		metricsCfgs             []MockListMetricsForFeatureIDBrowserAndChannelConfig
+		expectedGetFeatureCalls int
+		expectedMetricsCalls    int
+		request                 backend.CompareFeaturesRequestObject
+		expectedResponse        backend.CompareFeaturesResponseObject
+	}{
+		{
+			name: "Success Case - duplicate ids and multiple pages",
+			getFeatureCfgs: []MockGetFeatureByIDConfig{
+				{
+					expectedFeatureID:     "feature1",
+					expectedWPTMetricView: backend.SubtestCounts,
+					expectedBrowsers:      browsers,
+					expectedAsOf:          nil,
+					data:                  feature1,
+					err:                   nil,
+				},
+				{
+					expectedFeatureID:     "feature2",
+					expectedWPTMetricView: backend.SubtestCounts,
+					expectedBrowsers:      browsers,
+					expectedAsOf:          nil,
+					data:                  feature2,
+					err:                   nil,
+				},
+			},
+			metricsCfgs:             metricsCfgs,
+			expectedGetFeatureCalls: 2,
+			expectedMetricsCalls:    17,
+			request: backend.CompareFeaturesRequestObject{
+				Params: backend.CompareFeaturesParams{
+					Ids:           []string{"feature1", "feature2", "feature1"},
+					StartAt:       openapi_types.Date{Time: startAt},
+					EndAt:         openapi_types.Date{Time: endAt},
+					WptMetricView: nil,
+				},
+			},
+			expectedResponse: backend.CompareFeatures200JSONResponse{
+				Timestamps: []time.Time{run1, run2, run3, run4},
+				Features: []backend.FeatureComparisonItem{
+					{
+						FeatureId:              "feature1",
+						Name:                   "Feature 1",
+						BrowserImplementations: feature1.BrowserImplementations,
+						Wpt: compareFeaturesSeries(map[string][]backend.FeatureComparisonWPTPoint{
+							"chrome/stable": {
+								{Score: valuePtr[float64](0.5)},
+								{Score: valuePtr[float64](0.5)},
+								{Score: valuePtr[float64](1)},
+								{Score: valuePtr[float64](1)},
+							},
+						}, 4),
+					},
+					{
+						FeatureId:              "feature2",
+						Name:                   "Feature 2",
+						BrowserImplementations: nil,
+						Wpt: compareFeaturesSeries(map[string][]backend.FeatureComparisonWPTPoint{
+							"chrome/stable": {
+								{Score: nil},
+								{Score: valuePtr[float64](0)},
+								{Score: valuePtr[float64](0)},
+								{Score: valuePtr[float64](0.75)},
+							},
+						}, 4),
+					},
+				},
+			},
+		},
+		{
+			name: "404 case",
+			getFeatureCfgs: []MockGetFeatureByIDConfig{
+				{
+					expectedFeatureID:     "feature1",
+					expectedWPTMetricView: backend.SubtestCounts,
+					expectedBrowsers:      browsers,
+					expectedAsOf:          nil,
+					data:                  nil,
+					err:                   gcpspanner.ErrQueryReturnedNoResults,
+				},
+			},
+			metricsCfgs:             nil,
+			expectedGetFeatureCalls: 1,
+			expectedMetricsCalls:    0,
+			request: backend.CompareFeaturesRequestObject{
+				Params: backend.CompareFeaturesParams{
+					Ids:           []string{"feature1"},
+					StartAt:       openapi_types.Date{Time: startAt},
+					EndAt:         openapi_types.Date{Time: endAt},
+					WptMetricView: nil,
+				},
+			},
+			expectedResponse: backend.CompareFeatures404JSONResponse{
+				Code:    404,
+				Message: "feature id feature1 is not found",
+			},
+		},
+		{
+			name: "500 case",
+			getFeatureCfgs: []MockGetFeatureByIDConfig{
+				{
+					expectedFeatureID:     "feature1",
+					expectedWPTMetricView: backend.SubtestCounts,
+					expectedBrowsers:      browsers,
+					expectedAsOf:          nil,
+					data:                  feature1,
+					err:                   nil,
+				},
+			},
+			metricsCfgs: compareFeaturesMetricsConfigs("feature1",
+				map[string][]MockListMetricsForFeatureIDBrowserAndChannelConfig{
+					"chrome/stable": {
+						{
+							expectedFeatureID: "feature1",
+							expectedBrowser:   "chrome",
+							expectedChannel:   "stable",
+							expectedMetric:    backend.SubtestCounts,
+							expectedStartAt:   startAt,
+							expectedEndAt:     endAt,
+							expectedPageSize:  100,
+							expectedPageToken: nil,
+							data:              nil,
+							pageToken:         nil,
+							err:               errTest,
+						},
+					},
+				}),
+			expectedGetFeatureCalls: 1,
+			// Every series of the feature is requested at the same time.
+			expectedMetricsCalls: 8,
+			request: backend.CompareFeaturesRequestObject{
+				Params: backend.CompareFeaturesParams{
+					Ids:           []string{"feature1"},
+					StartAt:       openapi_types.Date{Time: startAt},
+					EndAt:         openapi_types.Date{Time: endAt},
+					WptMetricView: nil,
+				},
+			},
+			expectedResponse: backend.CompareFeatures500JSONResponse{
+				Code:    500,
+				Message: "unable to compare features",
+			},
+		},
+		{
+			name:                    "400 case - too many ids",
+			getFeatureCfgs:          nil,
+			metricsCfgs:             nil,
+			expectedGetFeatureCalls: 0,
+			expectedMetricsCalls:    0,
+			request: backend.CompareFeaturesRequestObject{
+				Params: backend.CompareFeaturesParams{
+					Ids:           []string{"f1", "f2", "f3", "f4", "f5", "f6", "f7", "f8", "f9", "f10", "f11"},
+					StartAt:       openapi_types.Date{Time: startAt},
+					EndAt:         openapi_types.Date{Time: endAt},
+					WptMetricView: nil,
+				},
+			},
+			expectedResponse: backend.CompareFeatures400JSONResponse{
+				Code:    400,
+				Message: "between 1 and 10 feature ids must be provided",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// nolint: exhaustruct
+			mockStorer := &MockWPTMetricsStorer{
+				getFeatureByIDConfigs: tc.getFeatureCfgs,
+				featureCfgs:           tc.metricsCfgs,
+				t:                     t,
+			}
+			myServer := Server{
+				wptMetricsStorer:         mockStorer,
+				metadataStorer:           nil,
+				subscriptionStorer:       nil,
+				callbackPolicy:           nil,
+				excludedFeatureKeyStorer: nil,
+				cacheClearer:             nil,
+			}
+
+			resp, err := myServer.CompareFeatures(context.Background(), tc.request)
+			if !errors.Is(err, nil) {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if mockStorer.callCountGetFeature != tc.expectedGetFeatureCalls {
+				t.Errorf("Incorrect GetFeature call count: expected %d, got %d",
+					tc.expectedGetFeatureCalls, mockStorer.callCountGetFeature)
+			}
+			if mockStorer.callCountListMetricsForFeatureIDBrowserAndChannel != tc.expectedMetricsCalls {
+				t.Errorf("Incorrect ListMetricsForFeatureIDBrowserAndChannel call count: expected %d, got %d",
+					tc.expectedMetricsCalls, mockStorer.callCountListMetricsForFeatureIDBrowserAndChannel)
+			}
+			if !reflect.DeepEqual(tc.expectedResponse, resp) {
+				t.Errorf("unexpected response.\nexpected: %+v\nreceived: %+v", tc.expectedResponse, resp)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/GoogleChrome/webstatus.dev/lib/gcpspanner"
+	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/backend"
+	"github.com/GoogleChrome/webstatus.dev/lib/httpmiddlewares"
+)
+
+// maxExcludedFeatureKeyLength matches the maxLength of feature_key in ExcludedFeatureKeyRequest.
+const maxExcludedFeatureKeyLength = 64
+
+func authenticationRequiredError() backend.BasicErrorModel {
+	return backend.BasicErrorModel{
+		Code:    http.StatusUnauthorized,
+		Message: "authentication required",
+	}
+}
+
+// auditAuthor returns who is recorded in the audit log for a change made by the user.
+func auditAuthor(user httpmiddlewares.User) string {
+	if user.Email != "" {
+		return user.Email
+	}
+
+	return user.ID
+}
+
+func validateExcludedFeatureKeyRequest(req backend.ExcludedFeatureKeyRequest) error {
+	if strings.TrimSpace(req.FeatureKey) == "" || len(req.FeatureKey) > maxExcludedFeatureKeyLength {
+		return fmt.Errorf("feature_key must be between 1 and %d characters", maxExcludedFeatureKeyLength)
+	}
+	if strings.TrimSpace(req.Reason) == "" {
+		return errors.New("reason is required")
+	}
+
+	return nil
+}
+
+// clearCache drops the cached responses after the excluded features change.
+// The cache is per region, so other regions serve stale responses until their entries expire.
+func (s *Server) clearCache(ctx context.Context) {
+	if s.cacheClearer == nil {
+		return
+	}
+	if err := s.cacheClearer.Clear(ctx); err != nil {
+		slog.ErrorContext(ctx, "unable to clear cache after excluded feature keys change", "error", err)
+	}
+}
+
+// ListExcludedFeatureKeys implements backend.StrictServerInterface.
+// nolint:ireturn // Expected ireturn for openapi generation.
+func (s *Server) ListExcludedFeatureKeys(
+	ctx context.Context,
+	_ backend.ListExcludedFeatureKeysRequestObject,
+) (backend.ListExcludedFeatureKeysResponseObject, error) {
+	keys, err := s.excludedFeatureKeyStorer.ListExcludedFeatureKeys(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "unable to list excluded feature keys", "error", err)
+
+		return backend.ListExcludedFeatureKeys500JSONResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "unable to list excluded feature keys",
+		}, nil
+	}
+	if keys == nil {
+		keys = []backend.ExcludedFeatureKey{}
+	}
+
+	return backend.ListExcludedFeatureKeys200JSONResponse{
+		Data: keys,
+	}, nil
+}
+
+// AddExcludedFeatureKey implements backend.StrictServerInterface.
+// nolint:ireturn // Expected ireturn for openapi generation.
+func (s *Server) AddExcludedFeatureKey(
+	ctx context.Context,
+	request backend.AddExcludedFeatureKeyRequestObject,
+) (backend.AddExcludedFeatureKeyResponseObject, error) {
+	// The strict auth middleware rejects anonymous requests before they get here.
+	user, ok := httpmiddlewares.UserFromContext(ctx)
+	if !ok {
+		return backend.AddExcludedFeatureKey401JSONResponse(authenticationRequiredError()), nil
+	}
+	if request.Body == nil {
+		return backend.AddExcludedFeatureKey400JSONResponse{
+			Code:    http.StatusBadRequest,
+			Message: "missing request body",
+		}, nil
+	}
+	if err := validateExcludedFeatureKeyRequest(*request.Body); err != nil {
+		return backend.AddExcludedFeatureKey400JSONResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		}, nil
+	}
+
+	key, err := s.excludedFeatureKeyStorer.AddExcludedFeatureKey(
+		ctx, request.Body.FeatureKey, request.Body.Reason, auditAuthor(user))
+	if errors.Is(err, gcpspanner.ErrFeatureKeyAlreadyExcluded) {
+		return backend.AddExcludedFeatureKey409JSONResponse{
+			Code:    http.StatusConflict,
+			Message: fmt.Sprintf("feature key %s is already excluded", request.Body.FeatureKey),
+		}, nil
+	} else if err != nil {
+		slog.ErrorContext(ctx, "unable to add excluded feature key", "error", err)
+
+		return backend.AddExcludedFeatureKey500JSONResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "unable to add excluded feature key",
+		}, nil
+	}
+	s.clearCache(ctx)
+
+	return backend.AddExcludedFeatureKey201JSONResponse(*key), nil
+}
+
+// RemoveExcludedFeatureKey implements backend.StrictServerInterface.
+// nolint:ireturn // Expected ireturn for openapi generation.
+func (s *Server) RemoveExcludedFeatureKey(
+	ctx context.Context,
+	request backend.RemoveExcludedFeatureKeyRequestObject,
+) (backend.RemoveExcludedFeatureKeyResponseObject, error) {
+	// The strict auth middleware rejects anonymous requests before they get here.
+	user, ok := httpmiddlewares.UserFromContext(ctx)
+	if !ok {
+		return backend.RemoveExcludedFeatureKey401JSONResponse(authenticationRequiredError()), nil
+	}
+	err := s.excludedFeatureKeyStorer.RemoveExcludedFeatureKey(ctx, request.FeatureKey, auditAuthor(user))
+	if errors.Is(err, gcpspanner.ErrQueryReturnedNoResults) {
+		return backend.RemoveExcludedFeatureKey404JSONResponse{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("feature key %s is not excluded", request.FeatureKey),
+		}, nil
+	} else if err != nil {
+		slog.ErrorContext(ctx, "unable to remove excluded feature key", "error", err)
+
+		return backend.RemoveExcludedFeatureKey500JSONResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "unable to remove excluded feature key",
+		}, nil
+	}
+	s.clearCache(ctx)
+
+	return backend.RemoveExcludedFeatureKey204Response{}, nil
+}
@@ -0,0 +1,398 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleChrome/webstatus.dev/lib/gcpspanner"
+	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/backend"
+	"github.com/GoogleChrome/webstatus.dev/lib/httpmiddlewares"
+)
+
+type MockExcludedFeatureKeyStorer struct {
+	t                  *testing.T
+	expectedFeatureKey string
+	expectedReason     string
+	expectedAuthor     string
+	keys               []backend.ExcludedFeatureKey
+	err                error
+	callCount          int
+}
+
+func (s *MockExcludedFeatureKeyStorer) ListExcludedFeatureKeys(
+	_ context.Context) ([]backend.ExcludedFeatureKey, error) {
+	s.callCount++
+
+	return s.keys, s.err
+}
+
+func (s *MockExcludedFeatureKeyStorer) AddExcludedFeatureKey(
+	_ context.Context, featureKey, reason, author string) (*backend.ExcludedFeatureKey, error) {
+	s.callCount++
+	if featureKey != s.expectedFeatureKey || reason != s.expectedReason || author != s.expectedAuthor {
+		s.t.Errorf("unexpected input %s %s %s", featureKey, reason, author)
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &s.keys[0], nil
+}
+
+func (s *MockExcludedFeatureKeyStorer) RemoveExcludedFeatureKey(_ context.Context, featureKey, author string) error {
+	s.callCount++
+	if featureKey != s.expectedFeatureKey || author != s.expectedAuthor {
+		s.t.Errorf("unexpected input %s %s", featureKey, author)
+	}
+
+	return s.err
+}
+
+type MockCacheClearer struct {
+	err       error
+	callCount int
+}
+
+func (c *MockCacheClearer) Clear(_ context.Context) error {
+	c.callCount++
+
+	return c.err
+}
+
+func contextWithTestAdmin() context.Context {
+	return httpmiddlewares.ContextWithUser(context.Background(), httpmiddlewares.User{
+		ID:    "uid",
+		Email: "admin@example.com",
+		Roles: []string{"admin"},
+	})
+}
+
+func TestListExcludedFeatureKeys(t *testing.T) {
+	createdAt := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name             string
+		keys             []backend.ExcludedFeatureKey
+		err              error
+		expectedResponse backend.ListExcludedFeatureKeysResponseObject
+	}{
+		{
+			name: "success",
+			keys: []backend.ExcludedFeatureKey{
+				{
+					FeatureKey: "bogus",
+					Reason:     valuePtr("not a real feature"),
+					Author:     valuePtr("admin@example.com"),
+					CreatedAt:  &createdAt,
+				},
+			},
+			err: nil,
+			expectedResponse: backend.ListExcludedFeatureKeys200JSONResponse{
+				Data: []backend.ExcludedFeatureKey{
+					{
+						FeatureKey: "bogus",
+						Reason:     valuePtr("not a real feature"),
+						Author:     valuePtr("admin@example.com"),
+						CreatedAt:  &createdAt,
+					},
+				},
+			},
+		},
+		{
+			name: "empty",
+			keys: nil,
+			err:  nil,
+			expectedResponse: backend.ListExcludedFeatureKeys200JSONResponse{
+				Data: []backend.ExcludedFeatureKey{},
+			},
+		},
+		{
+			name: "500",
+			keys: nil,
+			err:  errTest,
+			expectedResponse: backend.ListExcludedFeatureKeys500JSONResponse{
+				Code:    500,
+				Message: "unable to list excluded feature keys",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// nolint: exhaustruct // Only listing is exercised.
+			mockStorer := &MockExcludedFeatureKeyStorer{t: t, keys: tc.keys, err: tc.err}
+			myServer := Server{
+				wptMetricsStorer:         nil,
+				metadataStorer:           nil,
+				subscriptionStorer:       nil,
+				callbackPolicy:           nil,
+				excludedFeatureKeyStorer: mockStorer,
+				cacheClearer:             nil,
+			}
+			resp, err := myServer.ListExcludedFeatureKeys(
+				contextWithTestAdmin(), backend.ListExcludedFeatureKeysRequestObject{})
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(tc.expectedResponse, resp) {
+				t.Errorf("unexpected response: %v", resp)
+			}
+		})
+	}
+}
+
+func TestAddExcludedFeatureKey(t *testing.T) {
+	createdAt := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	key := backend.ExcludedFeatureKey{
+		FeatureKey: "bogus",
+		Reason:     valuePtr("not a real feature"),
+		Author:     valuePtr("admin@example.com"),
+		CreatedAt:  &createdAt,
+	}
+	testCases := []struct {
+		name               string
+		ctx                context.Context
+		body               *backend.ExcludedFeatureKeyRequest
+		err                error
+		clearErr           error
+		expectedResponse   backend.AddExcludedFeatureKeyResponseObject
+		expectedCallCount  int
+		expectedClearCount int
+	}{
+		{
+			name:               "success",
+			ctx:                contextWithTestAdmin(),
+			body:               &backend.ExcludedFeatureKeyRequest{FeatureKey: "bogus", Reason: "not a real feature"},
+			err:                nil,
+			clearErr:           nil,
+			expectedResponse:   backend.AddExcludedFeatureKey201JSONResponse(key),
+			expectedCallCount:  1,
+			expectedClearCount: 1,
+		},
+		{
+			name:               "clearing the cache fails",
+			ctx:                contextWithTestAdmin(),
+			body:               &backend.ExcludedFeatureKeyRequest{FeatureKey: "bogus", Reason: "not a real feature"},
+			err:                nil,
+			clearErr:           errTest,
+			expectedResponse:   backend.AddExcludedFeatureKey201JSONResponse(key),
+			expectedCallCount:  1,
+			expectedClearCount: 1,
+		},
+		{
+			name:     "anonymous",
+			ctx:      context.Background(),
+			body:     &backend.ExcludedFeatureKeyRequest{FeatureKey: "bogus", Reason: "not a real feature"},
+			err:      nil,
+			clearErr: nil,
+			expectedResponse: backend.AddExcludedFeatureKey401JSONResponse{
+				Code:    401,
+				Message: "authentication required",
+			},
+			expectedCallCount:  0,
+			expectedClearCount: 0,
+		},
+		{
+			name:     "missing body",
+			ctx:      contextWithTestAdmin(),
+			body:     nil,
+			err:      nil,
+			clearErr: nil,
+			expectedResponse: backend.AddExcludedFeatureKey400JSONResponse{
+				Code:    400,
+				Message: "missing request body",
+			},
+			expectedCallCount:  0,
+			expectedClearCount: 0,
+		},
+		{
+			name:     "feature key too long",
+			ctx:      contextWithTestAdmin(),
+			body:     &backend.ExcludedFeatureKeyRequest{FeatureKey: strings.Repeat("a", 65), Reason: "reason"},
+			err:      nil,
+			clearErr: nil,
+			expectedResponse: backend.AddExcludedFeatureKey400JSONResponse{
+				Code:    400,
+				Message: "feature_key must be between 1 and 64 characters",
+			},
+			expectedCallCount:  0,
+			expectedClearCount: 0,
+		},
+		{
+			name:     "missing reason",
+			ctx:      contextWithTestAdmin(),
+			body:     &backend.ExcludedFeatureKeyRequest{FeatureKey: "bogus", Reason: " "},
+			err:      nil,
+			clearErr: nil,
+			expectedResponse: backend.AddExcludedFeatureKey400JSONResponse{
+				Code:    400,
+				Message: "reason is required",
+			},
+			expectedCallCount:  0,
+			expectedClearCount: 0,
+		},
+		{
+			name:     "already excluded",
+			ctx:      contextWithTestAdmin(),
+			body:     &backend.ExcludedFeatureKeyRequest{FeatureKey: "bogus", Reason: "not a real feature"},
+			err:      gcpspanner.ErrFeatureKeyAlreadyExcluded,
+			clearErr: nil,
+			expectedResponse: backend.AddExcludedFeatureKey409JSONResponse{
+				Code:    409,
+				Message: "feature key bogus is already excluded",
+			},
+			expectedCallCount:  1,
+			expectedClearCount: 0,
+		},
+		{
+			name:     "500",
+			ctx:      contextWithTestAdmin(),
+			body:     &backend.ExcludedFeatureKeyRequest{FeatureKey: "bogus", Reason: "not a real feature"},
+			err:      errTest,
+			clearErr: nil,
+			expectedResponse: backend.AddExcludedFeatureKey500JSONResponse{
+				Code:    500,
+				Message: "unable to add excluded feature key",
+			},
+			expectedCallCount:  1,
+			expectedClearCount: 0,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockStorer := &MockExcludedFeatureKeyStorer{
+				t:                  t,
+				expectedFeatureKey: "bogus",
+				expectedReason:     "not a real feature",
+				expectedAuthor:     "admin@example.com",
+				keys:               []backend.ExcludedFeatureKey{key},
+				err:                tc.err,
+				callCount:          0,
+			}
+			mockClearer := &MockCacheClearer{err: tc.clearErr, callCount: 0}
+			myServer := Server{
+				wptMetricsStorer:         nil,
+				metadataStorer:           nil,
+				subscriptionStorer:       nil,
+				callbackPolicy:           nil,
+				excludedFeatureKeyStorer: mockStorer,
+				cacheClearer:             mockClearer,
+			}
+			resp, err := myServer.AddExcludedFeatureKey(tc.ctx, backend.AddExcludedFeatureKeyRequestObject{Body: tc.body})
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(tc.expectedResponse, resp) {
+				t.Errorf("unexpected response: %v", resp)
+			}
+			if mockStorer.callCount != tc.expectedCallCount {
+				t.Errorf("expected %d storer calls, received %d", tc.expectedCallCount, mockStorer.callCount)
+			}
+			if mockClearer.callCount != tc.expectedClearCount {
+				t.Errorf("expected %d cache clears, received %d", tc.expectedClearCount, mockClearer.callCount)
+			}
+		})
+	}
+}
+
+func TestRemoveExcludedFeatureKey(t *testing.T) {
+	testCases := []struct {
+		name               string
+		ctx                context.Context
+		err                error
+		expectedResponse   backend.RemoveExcludedFeatureKeyResponseObject
+		expectedCallCount  int
+		expectedClearCount int
+	}{
+		{
+			name:               "success",
+			ctx:                contextWithTestAdmin(),
+			err:                nil,
+			expectedResponse:   backend.RemoveExcludedFeatureKey204Response{},
+			expectedCallCount:  1,
+			expectedClearCount: 1,
+		},
+		{
+			name: "anonymous",
+			ctx:  context.Background(),
+			err:  nil,
+			expectedResponse: backend.RemoveExcludedFeatureKey401JSONResponse{
+				Code:    401,
+				Message: "authentication required",
+			},
+			expectedCallCount:  0,
+			expectedClearCount: 0,
+		},
+		{
+			name: "not excluded",
+			ctx:  contextWithTestAdmin(),
+			err:  gcpspanner.ErrQueryReturnedNoResults,
+			expectedResponse: backend.RemoveExcludedFeatureKey404JSONResponse{
+				Code:    404,
+				Message: "feature key bogus is not excluded",
+			},
+			expectedCallCount:  1,
+			expectedClearCount: 0,
+		},
+		{
+			name: "500",
+			ctx:  contextWithTestAdmin(),
+			err:  errTest,
+			expectedResponse: backend.RemoveExcludedFeatureKey500JSONResponse{
+				Code:    500,
+				Message: "unable to remove excluded feature key",
+			},
+			expectedCallCount:  1,
+			expectedClearCount: 0,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// nolint: exhaustruct // Nothing is added.
+			mockStorer := &MockExcludedFeatureKeyStorer{
+				t:                  t,
+				expectedFeatureKey: "bogus",
+				expectedAuthor:     "admin@example.com",
+				err:                tc.err,
+			}
+			mockClearer := &MockCacheClearer{err: nil, callCount: 0}
+			myServer := Server{
+				wptMetricsStorer:         nil,
+				metadataStorer:           nil,
+				subscriptionStorer:       nil,
+				callbackPolicy:           nil,
+				excludedFeatureKeyStorer: mockStorer,
+				cacheClearer:             mockClearer,
+			}
+			resp, err := myServer.RemoveExcludedFeatureKey(
+				tc.ctx, backend.RemoveExcludedFeatureKeyRequestObject{FeatureKey: "bogus"})
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(tc.expectedResponse, resp) {
+				t.Errorf("unexpected response: %v", resp)
+			}
+			if mockStorer.callCount != tc.expectedCallCount {
+				t.Errorf("expected %d storer calls, received %d", tc.expectedCallCount, mockStorer.callCount)
+			}
+			if mockClearer.callCount != tc.expectedClearCount {
+				t.Errorf("expected %d cache clears, received %d", tc.expectedClearCount, mockClearer.callCount)
+			}
+		})
+	}
+}
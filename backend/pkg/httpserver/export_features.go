@@ -0,0 +1,262 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/GoogleChrome/webstatus.dev/lib/gcpspanner/searchtypes"
+	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/backend"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// ExportFeatures implements backend.StrictServerInterface.
+// The first page is fetched before responding so that storage failures still result in an error status code.
+// The remaining pages are fetched while the response is being written so that only one page is held in memory.
+// nolint:ireturn // Expected ireturn for openapi generation.
+func (s *Server) ExportFeatures(
+	ctx context.Context,
+	req backend.ExportFeaturesRequestObject,
+) (backend.ExportFeaturesResponseObject, error) {
+	node, parseErr := parseSearchQuery(ctx, req.Params.Q)
+	if parseErr != nil {
+		return backend.ExportFeatures400JSONResponse(*parseErr), nil
+	}
+
+	firstPage, err := s.searchFeaturesForExport(ctx, nil, node)
+	if err != nil {
+		slog.ErrorContext(ctx, "unable to get features for export", "error", err)
+
+		return backend.ExportFeatures500JSONResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "unable to export features",
+		}, nil
+	}
+
+	format := backend.ExportFormatCSV
+	if req.Params.Format != nil {
+		format = *req.Params.Format
+	}
+
+	reader, writer := io.Pipe()
+	exportWriter := newFeatureExportWriter(format, writer)
+	go func() {
+		streamErr := s.streamFeaturesExport(ctx, exportWriter, node, firstPage)
+		if streamErr != nil {
+			slog.ErrorContext(ctx, "unable to stream feature export", "error", streamErr)
+		}
+		// If the client went away, the reader is already closed and this is a no-op.
+		writer.CloseWithError(streamErr)
+	}()
+
+	if format == backend.ExportFormatNDJSON {
+		return backend.ExportFeatures200ApplicationxNdjsonResponse{
+			Body:          reader,
+			ContentLength: 0,
+		}, nil
+	}
+
+	return backend.ExportFeatures200TextcsvResponse{
+		Body:          reader,
+		ContentLength: 0,
+	}, nil
+}
+
+// searchFeaturesForExport returns a page of features for the export.
+// Features are sorted by name so that the export has a stable order.
+func (s *Server) searchFeaturesForExport(
+	ctx context.Context,
+	pageToken *string,
+	node *searchtypes.SearchNode,
+) (*backend.FeaturePage, error) {
+	sortOrder := backend.NameAsc
+
+	return s.wptMetricsStorer.FeaturesSearch(
+		ctx,
+		pageToken,
+		getPageSizeOrDefault(nil),
+		node,
+		&sortOrder,
+		getWPTMetricViewOrDefault(nil),
+		defaultBrowsers(),
+		nil,
+		nil,
+	)
+}
+
+// streamFeaturesExport writes the given page and every page after it.
+func (s *Server) streamFeaturesExport(
+	ctx context.Context,
+	exportWriter featureExportWriter,
+	node *searchtypes.SearchNode,
+	page *backend.FeaturePage,
+) error {
+	err := exportWriter.WriteHeader()
+	if err != nil {
+		return err
+	}
+	for {
+		for _, feature := range page.Data {
+			err = exportWriter.WriteFeature(feature)
+			if err != nil {
+				return err
+			}
+		}
+		// Flush after every page so that the client receives the features as they are fetched.
+		err = exportWriter.Flush()
+		if err != nil {
+			return err
+		}
+
+		if page.Metadata.NextPageToken == nil {
+			return nil
+		}
+		page, err = s.searchFeaturesForExport(ctx, page.Metadata.NextPageToken, node)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// featureExportWriter writes features in a given export format.
+type featureExportWriter interface {
+	WriteHeader() error
+	WriteFeature(feature backend.Feature) error
+	Flush() error
+}
+
+// nolint:ireturn // Each format has its own writer.
+func newFeatureExportWriter(format backend.ExportFeaturesParamsFormat, w io.Writer) featureExportWriter {
+	if format == backend.ExportFormatNDJSON {
+		return ndjsonFeatureExportWriter{encoder: json.NewEncoder(w)}
+	}
+
+	return csvFeatureExportWriter{
+		writer:   csv.NewWriter(w),
+		browsers: defaultBrowsers(),
+	}
+}
+
+// csvFeatureExportWriter writes one row per feature.
+// Each browser has a column for its implementation date and its latest stable and experimental WPT scores.
+type csvFeatureExportWriter struct {
+	writer   *csv.Writer
+	browsers []backend.BrowserPathParam
+}
+
+func (c csvFeatureExportWriter) WriteHeader() error {
+	header := []string{
+		"feature_id",
+		"name",
+		"baseline_status",
+		"baseline_low_date",
+		"baseline_high_date",
+	}
+	for _, browser := range c.browsers {
+		header = append(header,
+			string(browser)+"_implementation_date",
+			string(browser)+"_stable_wpt_score",
+			string(browser)+"_experimental_wpt_score",
+		)
+	}
+
+	return c.writer.Write(header)
+}
+
+func (c csvFeatureExportWriter) WriteFeature(feature backend.Feature) error {
+	row := []string{feature.FeatureId, feature.Name}
+	var baselineStatus string
+	var lowDate, highDate *openapi_types.Date
+	if feature.Baseline != nil {
+		if feature.Baseline.Status != nil {
+			baselineStatus = string(*feature.Baseline.Status)
+		}
+		lowDate = feature.Baseline.LowDate
+		highDate = feature.Baseline.HighDate
+	}
+	row = append(row, baselineStatus, formatExportDate(lowDate), formatExportDate(highDate))
+
+	for _, browser := range c.browsers {
+		var implementationDate *openapi_types.Date
+		if feature.BrowserImplementations != nil {
+			if implementation, found := (*feature.BrowserImplementations)[string(browser)]; found {
+				implementationDate = implementation.Date
+			}
+		}
+		var stableScores, experimentalScores *map[string]backend.WPTFeatureData
+		if feature.Wpt != nil {
+			stableScores = feature.Wpt.Stable
+			experimentalScores = feature.Wpt.Experimental
+		}
+		row = append(row,
+			formatExportDate(implementationDate),
+			formatExportScore(stableScores, browser),
+			formatExportScore(experimentalScores, browser),
+		)
+	}
+
+	return c.writer.Write(row)
+}
+
+func (c csvFeatureExportWriter) Flush() error {
+	c.writer.Flush()
+
+	return c.writer.Error()
+}
+
+// ndjsonFeatureExportWriter writes one Feature JSON object per line.
+type ndjsonFeatureExportWriter struct {
+	encoder *json.Encoder
+}
+
+func (n ndjsonFeatureExportWriter) WriteHeader() error {
+	return nil
+}
+
+func (n ndjsonFeatureExportWriter) WriteFeature(feature backend.Feature) error {
+	return n.encoder.Encode(feature)
+}
+
+func (n ndjsonFeatureExportWriter) Flush() error {
+	// The encoder writes directly to the underlying writer.
+	return nil
+}
+
+func formatExportDate(date *openapi_types.Date) string {
+	if date == nil {
+		return ""
+	}
+
+	return date.Format(time.DateOnly)
+}
+
+func formatExportScore(scores *map[string]backend.WPTFeatureData, browser backend.BrowserPathParam) string {
+	if scores == nil {
+		return ""
+	}
+	data, found := (*scores)[string(browser)]
+	if !found || data.Score == nil {
+		return ""
+	}
+
+	return strconv.FormatFloat(*data.Score, 'f', -1, 64)
+}
@@ -0,0 +1,274 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/GoogleChrome/webstatus.dev/lib/gcpspanner/searchtypes"
+	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/backend"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+func exportTestFeature1() backend.Feature {
+	return backend.Feature{
+		FeatureId: "feature1",
+		Name:      "feature 1",
+		Baseline: &backend.BaselineInfo{
+			Status: valuePtr(backend.Widely),
+			LowDate: valuePtr(
+				openapi_types.Date{Time: time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)},
+			),
+			HighDate: valuePtr(
+				openapi_types.Date{Time: time.Date(2002, time.July, 1, 0, 0, 0, 0, time.UTC)},
+			),
+		},
+		BrowserImplementations: &map[string]backend.BrowserImplementation{
+			"chrome": {
+				Status: valuePtr(backend.Available),
+				Date: valuePtr(
+					openapi_types.Date{Time: time.Date(1999, time.December, 1, 0, 0, 0, 0, time.UTC)},
+				),
+			},
+		},
+		Wpt: &backend.FeatureWPTSnapshots{
+			Stable: &map[string]backend.WPTFeatureData{
+				"chrome": {
+					Score:    valuePtr[float64](0.5),
+					Metadata: nil,
+				},
+			},
+			Experimental: &map[string]backend.WPTFeatureData{
+				"firefox": {
+					Score:    valuePtr[float64](1),
+					Metadata: nil,
+				},
+			},
+		},
+		RedirectedFrom: nil,
+		Spec:           nil,
+		Usage:          nil,
+	}
+}
+
+func exportTestFeature2() backend.Feature {
+	return backend.Feature{
+		FeatureId:              "feature2",
+		Name:                   "feature, 2",
+		Baseline:               nil,
+		BrowserImplementations: nil,
+		Wpt:                    nil,
+		RedirectedFrom:         nil,
+		Spec:                   nil,
+		Usage:                  nil,
+	}
+}
+
+func exportFeaturesSearchConfig(
+	pageToken *string,
+	node *searchtypes.SearchNode,
+	page *backend.FeaturePage,
+	err error) MockFeaturesSearchConfig {
+	return MockFeaturesSearchConfig{
+		expectedPageToken:     pageToken,
+		expectedPageSize:      100,
+		expectedSearchNode:    node,
+		expectedSortBy:        valuePtr[backend.GetV1FeaturesParamsSort](backend.NameAsc),
+		expectedWPTMetricView: backend.SubtestCounts,
+		expectedBrowsers: []backend.BrowserPathParam{
+			backend.Chrome,
+			backend.Edge,
+			backend.Firefox,
+			backend.Safari,
+		},
+		expectedAsOf:   nil,
+		expectedFacets: nil,
+		page:           page,
+		err:            err,
+	}
+}
+
+func TestExportFeatures(t *testing.T) {
+	nextPageToken := "page2"
+	testCases := []struct {
+		name              string
+		mockConfigs       []MockFeaturesSearchConfig
+		expectedCallCount int
+		request           backend.ExportFeaturesRequestObject
+		expectedResponse  backend.ExportFeaturesResponseObject
+		expectedBody      string
+	}{
+		{
+			name: "csv across multiple pages",
+			mockConfigs: []MockFeaturesSearchConfig{
+				exportFeaturesSearchConfig(nil, nil, &backend.FeaturePage{
+					Facets: nil,
+					Metadata: backend.PageMetadataWithTotal{
+						NextPageToken: &nextPageToken,
+						Total:         2,
+					},
+					Data: []backend.Feature{exportTestFeature1()},
+				}, nil),
+				exportFeaturesSearchConfig(&nextPageToken, nil, &backend.FeaturePage{
+					Facets: nil,
+					Metadata: backend.PageMetadataWithTotal{
+						NextPageToken: nil,
+						Total:         2,
+					},
+					Data: []backend.Feature{exportTestFeature2()},
+				}, nil),
+			},
+			expectedCallCount: 2,
+			request: backend.ExportFeaturesRequestObject{
+				Params: backend.ExportFeaturesParams{
+					Q:      nil,
+					Format: nil,
+				},
+			},
+			expectedResponse: nil,
+			expectedBody: "feature_id,name,baseline_status,baseline_low_date,baseline_high_date," +
+				"chrome_implementation_date,chrome_stable_wpt_score,chrome_experimental_wpt_score," +
+				"edge_implementation_date,edge_stable_wpt_score,edge_experimental_wpt_score," +
+				"firefox_implementation_date,firefox_stable_wpt_score,firefox_experimental_wpt_score," +
+				"safari_implementation_date,safari_stable_wpt_score,safari_experimental_wpt_score\n" +
+				"feature1,feature 1,widely,2000-01-01,2002-07-01,1999-12-01,0.5,,,,,,,1,,,\n" +
+				"feature2,\"feature, 2\",,,,,,,,,,,,,,,\n",
+		},
+		{
+			name: "ndjson with query",
+			mockConfigs: []MockFeaturesSearchConfig{
+				exportFeaturesSearchConfig(nil, &searchtypes.SearchNode{
+					Keyword: searchtypes.KeywordRoot,
+					Term:    nil,
+					Children: []*searchtypes.SearchNode{
+						{
+							Keyword:  searchtypes.KeywordNone,
+							Children: nil,
+							Term: &searchtypes.SearchTerm{
+								Identifier: searchtypes.IdentifierAvailableOn,
+								Value:      "chrome",
+								Operator:   searchtypes.OperatorEq,
+							},
+						},
+					},
+				}, &backend.FeaturePage{
+					Facets: nil,
+					Metadata: backend.PageMetadataWithTotal{
+						NextPageToken: nil,
+						Total:         1,
+					},
+					Data: []backend.Feature{exportTestFeature2()},
+				}, nil),
+			},
+			expectedCallCount: 1,
+			request: backend.ExportFeaturesRequestObject{
+				Params: backend.ExportFeaturesParams{
+					Q:      valuePtr(url.QueryEscape("available_on:chrome")),
+					Format: valuePtr(backend.ExportFormatNDJSON),
+				},
+			},
+			expectedResponse: nil,
+			expectedBody:     `{"feature_id":"feature2","name":"feature, 2"}` + "\n",
+		},
+		{
+			name:              "400 case - invalid query",
+			mockConfigs:       nil,
+			expectedCallCount: 0,
+			request: backend.ExportFeaturesRequestObject{
+				Params: backend.ExportFeaturesParams{
+					Q:      valuePtr(url.QueryEscape("badterm:foo")),
+					Format: nil,
+				},
+			},
+			expectedResponse: backend.ExportFeatures400JSONResponse{
+				Code:    400,
+				Message: "query string does not match expected grammar",
+			},
+			expectedBody: "",
+		},
+		{
+			name: "500 case",
+			mockConfigs: []MockFeaturesSearchConfig{
+				exportFeaturesSearchConfig(nil, nil, nil, errTest),
+			},
+			expectedCallCount: 1,
+			request: backend.ExportFeaturesRequestObject{
+				Params: backend.ExportFeaturesParams{
+					Q:      nil,
+					Format: nil,
+				},
+			},
+			expectedResponse: backend.ExportFeatures500JSONResponse{
+				Code:    500,
+				Message: "unable to export features",
+			},
+			expectedBody: "",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// nolint: exhaustruct
+			mockStorer := &MockWPTMetricsStorer{
+				featuresSearchPageCfgs: tc.mockConfigs,
+				t:                      t,
+			}
+			myServer := Server{
+				wptMetricsStorer:         mockStorer,
+				metadataStorer:           nil,
+				subscriptionStorer:       nil,
+				callbackPolicy:           nil,
+				excludedFeatureKeyStorer: nil,
+				cacheClearer:             nil,
+			}
+
+			resp, err := myServer.ExportFeatures(context.Background(), tc.request)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			var body io.Reader
+			switch r := resp.(type) {
+			case backend.ExportFeatures200TextcsvResponse:
+				body = r.Body
+			case backend.ExportFeatures200ApplicationxNdjsonResponse:
+				body = r.Body
+			default:
+				if !reflect.DeepEqual(tc.expectedResponse, resp) {
+					t.Errorf("unexpected response: %v", resp)
+				}
+			}
+			if body != nil {
+				data, err := io.ReadAll(body)
+				if err != nil {
+					t.Fatalf("unable to read body: %v", err)
+				}
+				if string(data) != tc.expectedBody {
+					t.Errorf("unexpected body.\nexpected: %s\nreceived: %s", tc.expectedBody, string(data))
+				}
+			}
+
+			if mockStorer.callCountFeaturesSearch != tc.expectedCallCount {
+				t.Errorf("Incorrect call count: expected %d, got %d",
+					tc.expectedCallCount,
+					mockStorer.callCountFeaturesSearch)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/backend"
+)
+
+// GetBrowserCompatMatrix implements backend.StrictServerInterface.
+// nolint: ireturn // Expected ireturn for openapi generation.
+func (s *Server) GetBrowserCompatMatrix(
+	ctx context.Context,
+	request backend.GetBrowserCompatMatrixRequestObject,
+) (backend.GetBrowserCompatMatrixResponseObject, error) {
+	browsers, err := getBrowsersOrDefault(request.Params.Browsers)
+	if err != nil {
+		return backend.GetBrowserCompatMatrix400JSONResponse{
+			Code:    400,
+			Message: err.Error(),
+		}, nil
+	}
+	at := time.Now().UTC()
+	if request.Params.At != nil {
+		at = request.Params.At.Time
+	}
+
+	matrix, err := s.wptMetricsStorer.GetBrowserCompatMatrix(ctx, browsers, at)
+	if err != nil {
+		slog.ErrorContext(ctx, "unable to get browser compat matrix", "error", err)
+
+		return backend.GetBrowserCompatMatrix500JSONResponse{
+			Code:    500,
+			Message: "unable to get browser compat matrix",
+		}, nil
+	}
+
+	return backend.GetBrowserCompatMatrix200JSONResponse(*matrix), nil
+}
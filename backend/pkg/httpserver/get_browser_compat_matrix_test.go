@@ -0,0 +1,178 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/backend"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+func TestGetBrowserCompatMatrix(t *testing.T) {
+	matrix := &backend.BrowserCompatMatrix{
+		Data: []backend.BrowserPairFeatureGap{
+			{BrowserA: "chrome", BrowserB: "firefox", Count: 10},
+			{BrowserA: "firefox", BrowserB: "chrome", Count: 5},
+		},
+	}
+	testCases := []struct {
+		name              string
+		mockConfig        MockGetBrowserCompatMatrixConfig
+		expectedCallCount int // For the mock method
+		request           backend.GetBrowserCompatMatrixRequestObject
+		expectedResponse  backend.GetBrowserCompatMatrixResponseObject
+		expectedError     error
+	}{
+		{
+			name: "Success Case - no optional params - use defaults",
+			mockConfig: MockGetBrowserCompatMatrixConfig{
+				expectedBrowsers: defaultBrowsers(),
+				expectedAt:       time.Time{},
+				data:             matrix,
+				err:              nil,
+			},
+			expectedCallCount: 1,
+			expectedResponse:  backend.GetBrowserCompatMatrix200JSONResponse(*matrix),
+			request: backend.GetBrowserCompatMatrixRequestObject{
+				Params: backend.GetBrowserCompatMatrixParams{
+					Browsers: nil,
+					At:       nil,
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name: "Success Case - include optional params",
+			mockConfig: MockGetBrowserCompatMatrixConfig{
+				expectedBrowsers: []backend.BrowserPathParam{backend.Chrome, backend.Firefox},
+				expectedAt:       time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+				data:             matrix,
+				err:              nil,
+			},
+			expectedCallCount: 1,
+			expectedResponse:  backend.GetBrowserCompatMatrix200JSONResponse(*matrix),
+			request: backend.GetBrowserCompatMatrixRequestObject{
+				Params: backend.GetBrowserCompatMatrixParams{
+					Browsers: valuePtr[[]string]([]string{"firefox", "chrome"}),
+					At: &openapi_types.Date{
+						Time: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)},
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name: "400 case - unknown browser",
+			// nolint: exhaustruct
+			mockConfig:        MockGetBrowserCompatMatrixConfig{},
+			expectedCallCount: 0,
+			expectedResponse: backend.GetBrowserCompatMatrix400JSONResponse{
+				Code:    400,
+				Message: "unknown browser: netscape",
+			},
+			request: backend.GetBrowserCompatMatrixRequestObject{
+				Params: backend.GetBrowserCompatMatrixParams{
+					Browsers: valuePtr[[]string]([]string{"netscape"}),
+					At:       nil,
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name: "500 case",
+			mockConfig: MockGetBrowserCompatMatrixConfig{
+				expectedBrowsers: defaultBrowsers(),
+				expectedAt:       time.Time{},
+				data:             nil,
+				err:              errTest,
+			},
+			expectedCallCount: 1,
+			expectedResponse: backend.GetBrowserCompatMatrix500JSONResponse{
+				Code:    500,
+				Message: "unable to get browser compat matrix",
+			},
+			request: backend.GetBrowserCompatMatrixRequestObject{
+				Params: backend.GetBrowserCompatMatrixParams{
+					Browsers: nil,
+					At:       nil,
+				},
+			},
+			expectedError: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// nolint: exhaustruct
+			mockStorer := &MockWPTMetricsStorer{
+				getBrowserCompatMatrixConfig: tc.mockConfig,
+				t:                            t,
+			}
+			myServer := Server{
+				wptMetricsStorer:         mockStorer,
+				metadataStorer:           nil,
+				subscriptionStorer:       nil,
+				callbackPolicy:           nil,
+				excludedFeatureKeyStorer: nil,
+				cacheClearer:             nil,
+			}
+
+			// Call the function under test
+			resp, err := myServer.GetBrowserCompatMatrix(context.Background(), tc.request)
+
+			// Assertions
+			if mockStorer.callCountGetBrowserCompatMatrix != tc.expectedCallCount {
+				t.Errorf("Incorrect call count: expected %d, got %d",
+					tc.expectedCallCount,
+					mockStorer.callCountGetBrowserCompatMatrix)
+			}
+
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("Unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(tc.expectedResponse, resp) {
+				t.Errorf("Unexpected response: %v", resp)
+			}
+		})
+	}
+}
+
+func TestGetBrowserCompatMatrixCommaSeparatedBrowsers(t *testing.T) {
+	// nolint: exhaustruct
+	mockStorer := &MockWPTMetricsStorer{
+		getBrowserCompatMatrixConfig: MockGetBrowserCompatMatrixConfig{
+			expectedBrowsers: []backend.BrowserPathParam{"chrome", "edge", "firefox", "safari"},
+			expectedAt:       time.Time{},
+			data:             &backend.BrowserCompatMatrix{Data: []backend.BrowserPairFeatureGap{}},
+			err:              nil,
+		},
+		t: t,
+	}
+	req := httptest.NewRequest(http.MethodGet, "/v1/stats/compat-matrix?browsers=chrome,firefox,safari,edge", nil)
+	rr := serveTestRequest(t, mockStorer, req)
+	if rr.Code != http.StatusOK {
+		t.Errorf("unexpected status code %d. body %s", rr.Code, rr.Body.String())
+	}
+	if mockStorer.callCountGetBrowserCompatMatrix != 1 {
+		t.Errorf("Incorrect call count: expected 1, got %d", mockStorer.callCountGetBrowserCompatMatrix)
+	}
+}
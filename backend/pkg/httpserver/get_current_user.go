@@ -0,0 +1,53 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/backend"
+	"github.com/GoogleChrome/webstatus.dev/lib/httpmiddlewares"
+)
+
+// GetCurrentUser implements backend.StrictServerInterface.
+// nolint:ireturn // Expected ireturn for openapi generation.
+func (s *Server) GetCurrentUser(
+	ctx context.Context,
+	_ backend.GetCurrentUserRequestObject,
+) (backend.GetCurrentUserResponseObject, error) {
+	// The strict auth middleware rejects anonymous requests before they get here.
+	user, ok := httpmiddlewares.UserFromContext(ctx)
+	if !ok {
+		return backend.GetCurrentUser401JSONResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "authentication required",
+		}, nil
+	}
+	var email *string
+	if user.Email != "" {
+		email = &user.Email
+	}
+	roles := user.Roles
+	if roles == nil {
+		roles = []string{}
+	}
+
+	return backend.GetCurrentUser200JSONResponse{
+		Id:    user.ID,
+		Email: email,
+		Roles: roles,
+	}, nil
+}
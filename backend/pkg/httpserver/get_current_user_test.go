@@ -0,0 +1,86 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/backend"
+	"github.com/GoogleChrome/webstatus.dev/lib/httpmiddlewares"
+)
+
+func TestGetCurrentUser(t *testing.T) {
+	testCases := []struct {
+		name             string
+		ctx              context.Context
+		expectedResponse backend.GetCurrentUserResponseObject
+	}{
+		{
+			name: "user with roles",
+			ctx: httpmiddlewares.ContextWithUser(context.Background(), httpmiddlewares.User{
+				ID:    "uid",
+				Email: "user@example.com",
+				Roles: []string{"admin"},
+			}),
+			expectedResponse: backend.GetCurrentUser200JSONResponse{
+				Id:    "uid",
+				Email: valuePtr("user@example.com"),
+				Roles: []string{"admin"},
+			},
+		},
+		{
+			name: "user without email or roles",
+			ctx: httpmiddlewares.ContextWithUser(context.Background(), httpmiddlewares.User{
+				ID:    "uid",
+				Email: "",
+				Roles: nil,
+			}),
+			expectedResponse: backend.GetCurrentUser200JSONResponse{
+				Id:    "uid",
+				Email: nil,
+				Roles: []string{},
+			},
+		},
+		{
+			name: "anonymous",
+			ctx:  context.Background(),
+			expectedResponse: backend.GetCurrentUser401JSONResponse{
+				Code:    401,
+				Message: "authentication required",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			myServer := Server{
+				wptMetricsStorer:         nil,
+				metadataStorer:           nil,
+				subscriptionStorer:       nil,
+				callbackPolicy:           nil,
+				excludedFeatureKeyStorer: nil,
+				cacheClearer:             nil,
+			}
+			resp, err := myServer.GetCurrentUser(tc.ctx, backend.GetCurrentUserRequestObject{})
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(tc.expectedResponse, resp) {
+				t.Errorf("unexpected response: %v", resp)
+			}
+		})
+	}
+}
@@ -0,0 +1,287 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+
+	"github.com/GoogleChrome/webstatus.dev/lib/gcpspanner"
+	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/backend"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// GetFeatureBadge implements backend.StrictServerInterface.
+// nolint:ireturn // Expected ireturn for openapi generation.
+func (s *Server) GetFeatureBadge(
+	ctx context.Context,
+	request backend.GetFeatureBadgeRequestObject,
+) (backend.GetFeatureBadgeResponseObject, error) {
+	style := backend.BadgeStyleFlat
+	if request.Params.Style != nil {
+		style = *request.Params.Style
+	}
+	theme := backend.BadgeThemeLight
+	if request.Params.Theme != nil {
+		theme = *request.Params.Theme
+	}
+	colors, found := badgeThemes[theme]
+	if !found {
+		return backend.GetFeatureBadge400JSONResponse{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("unknown theme %s", theme),
+		}, nil
+	}
+	if style != backend.BadgeStyleFlat && style != backend.BadgeStyleFlatSquare {
+		return backend.GetFeatureBadge400JSONResponse{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("unknown style %s", style),
+		}, nil
+	}
+
+	feature, err := s.wptMetricsStorer.GetFeature(ctx, request.FeatureId,
+		getWPTMetricViewOrDefault(nil),
+		defaultBrowsers(),
+		nil,
+	)
+	notFound := errors.Is(err, gcpspanner.ErrQueryReturnedNoResults)
+	if err != nil && !notFound {
+		slog.ErrorContext(ctx, "unable to get feature badge", "error", err)
+
+		return backend.GetFeatureBadge500JSONResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "unable to get feature badge",
+		}, nil
+	}
+
+	// Unknown features still get a badge so that pages embedding a wrong feature ID render an image.
+	b := badge{label: "Baseline", message: "feature not found", status: ""}
+	if !notFound {
+		b = newFeatureBadge(feature)
+	}
+	body, err := renderBadge(b, style, colors)
+	if err != nil {
+		slog.ErrorContext(ctx, "unable to render feature badge", "error", err)
+
+		return backend.GetFeatureBadge500JSONResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "unable to get feature badge",
+		}, nil
+	}
+
+	if notFound {
+		return backend.GetFeatureBadge404ImagesvgXmlResponse{
+			Body:          bytes.NewReader(body),
+			ContentLength: int64(len(body)),
+		}, nil
+	}
+
+	return backend.GetFeatureBadge200ImagesvgXmlResponse{
+		Body:          bytes.NewReader(body),
+		ContentLength: int64(len(body)),
+	}, nil
+}
+
+// badge is the text of a badge. status selects the color of the message.
+type badge struct {
+	label   string
+	message string
+	status  string
+}
+
+// badgeBrowserNames are the display names of defaultBrowsers.
+// nolint: gochecknoglobals // WONTFIX. Read only map.
+var badgeBrowserNames = map[backend.BrowserPathParam]string{
+	backend.Chrome:  "Chrome",
+	backend.Edge:    "Edge",
+	backend.Firefox: "Firefox",
+	backend.Safari:  "Safari",
+}
+
+func formatBadgeSince(message string, date *openapi_types.Date) string {
+	if date == nil {
+		return message
+	}
+
+	return message + " since " + date.Format("2006-01")
+}
+
+// newFeatureBadge describes the baseline status of the feature.
+// Features with limited availability list the browsers that are missing the feature instead.
+func newFeatureBadge(feature *backend.Feature) badge {
+	if feature.Baseline == nil || feature.Baseline.Status == nil {
+		return badge{label: "Baseline", message: "unknown", status: ""}
+	}
+	status := *feature.Baseline.Status
+	switch status {
+	case backend.Widely:
+		return badge{
+			label:   "Baseline",
+			message: formatBadgeSince("widely available", feature.Baseline.HighDate),
+			status:  string(status),
+		}
+	case backend.Newly:
+		return badge{
+			label:   "Baseline",
+			message: formatBadgeSince("newly available", feature.Baseline.LowDate),
+			status:  string(status),
+		}
+	case backend.Limited:
+		var missing []string
+		for _, browser := range defaultBrowsers() {
+			var impl *backend.BrowserImplementation
+			if feature.BrowserImplementations != nil {
+				if value, found := (*feature.BrowserImplementations)[string(browser)]; found {
+					impl = &value
+				}
+			}
+			if impl == nil || impl.Status == nil || *impl.Status != backend.Available {
+				missing = append(missing, badgeBrowserNames[browser])
+			}
+		}
+		message := "limited availability"
+		if len(missing) > 0 {
+			message = "missing in " + strings.Join(missing, ", ")
+		}
+
+		return badge{label: "Limited", message: message, status: string(status)}
+	}
+
+	return badge{label: "Baseline", message: "unknown", status: ""}
+}
+
+// badgeColors are the colors of a badge theme. The message colors are keyed by baseline status.
+type badgeColors struct {
+	label       string
+	labelText   string
+	messageText string
+	messages    map[string]string
+	unknown     string
+}
+
+// badgeThemes are readable on light and dark pages respectively.
+// nolint: gochecknoglobals // WONTFIX. Read only map.
+var badgeThemes = map[backend.GetFeatureBadgeParamsTheme]badgeColors{
+	backend.BadgeThemeLight: {
+		label:       "#555555",
+		labelText:   "#ffffff",
+		messageText: "#ffffff",
+		messages: map[string]string{
+			string(backend.Widely):  "#1e8e3e",
+			string(backend.Newly):   "#1a73e8",
+			string(backend.Limited): "#d56e0c",
+		},
+		unknown: "#80868b",
+	},
+	backend.BadgeThemeDark: {
+		label:       "#e8eaed",
+		labelText:   "#202124",
+		messageText: "#202124",
+		messages: map[string]string{
+			string(backend.Widely):  "#81c995",
+			string(backend.Newly):   "#8ab4f8",
+			string(backend.Limited): "#fcad70",
+		},
+		unknown: "#bdc1c6",
+	},
+}
+
+// Approximate width of a character of 11px Verdana, the font used by shields.io style badges.
+const (
+	badgeCharWidth   = 6.5
+	badgeTextPadding = 10
+)
+
+func badgeTextWidth(text string) int {
+	return int(math.Ceil(float64(utf8.RuneCountInString(text))*badgeCharWidth)) + badgeTextPadding
+}
+
+// nolint: gochecknoglobals // WONTFIX. Parsed once.
+var badgeTemplate = template.Must(template.New("badge").Parse(
+	`<svg xmlns="http://www.w3.org/2000/svg" width="{{.Width}}" height="20" role="img" ` +
+		`aria-label="{{html .Label}}: {{html .Message}}">` +
+		`<title>{{html .Label}}: {{html .Message}}</title>` +
+		`{{if .Gradient}}<linearGradient id="s" x2="0" y2="100%">` +
+		`<stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/>` +
+		`</linearGradient>{{end}}` +
+		`<clipPath id="r"><rect width="{{.Width}}" height="20" rx="{{.Radius}}" fill="#fff"/></clipPath>` +
+		`<g clip-path="url(#r)">` +
+		`<rect width="{{.LabelWidth}}" height="20" fill="{{.LabelColor}}"/>` +
+		`<rect x="{{.LabelWidth}}" width="{{.MessageWidth}}" height="20" fill="{{.MessageColor}}"/>` +
+		`{{if .Gradient}}<rect width="{{.Width}}" height="20" fill="url(#s)"/>{{end}}` +
+		`</g>` +
+		`<g text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">` +
+		`<text x="{{.LabelX}}" y="14" fill="{{.LabelTextColor}}">{{html .Label}}</text>` +
+		`<text x="{{.MessageX}}" y="14" fill="{{.MessageTextColor}}">{{html .Message}}</text>` +
+		`</g></svg>`,
+))
+
+// renderBadge renders a shields.io style badge.
+func renderBadge(b badge, style backend.GetFeatureBadgeParamsStyle, colors badgeColors) ([]byte, error) {
+	labelWidth := badgeTextWidth(b.label)
+	messageWidth := badgeTextWidth(b.message)
+	messageColor, found := colors.messages[b.status]
+	if !found {
+		messageColor = colors.unknown
+	}
+	radius := 3
+	if style == backend.BadgeStyleFlatSquare {
+		radius = 0
+	}
+
+	var out bytes.Buffer
+	err := badgeTemplate.Execute(&out, struct {
+		Label            string
+		Message          string
+		Width            int
+		LabelWidth       int
+		MessageWidth     int
+		LabelX           float64
+		MessageX         float64
+		Radius           int
+		Gradient         bool
+		LabelColor       string
+		LabelTextColor   string
+		MessageColor     string
+		MessageTextColor string
+	}{
+		Label:            b.label,
+		Message:          b.message,
+		Width:            labelWidth + messageWidth,
+		LabelWidth:       labelWidth,
+		MessageWidth:     messageWidth,
+		LabelX:           float64(labelWidth) / 2,
+		MessageX:         float64(labelWidth) + float64(messageWidth)/2,
+		Radius:           radius,
+		Gradient:         style == backend.BadgeStyleFlat,
+		LabelColor:       colors.label,
+		LabelTextColor:   colors.labelText,
+		MessageColor:     messageColor,
+		MessageTextColor: colors.messageText,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
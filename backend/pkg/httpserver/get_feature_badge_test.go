@@ -0,0 +1,289 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleChrome/webstatus.dev/lib/gcpspanner"
+	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/backend"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+func testBadgeFeature(
+	status *backend.BaselineInfoStatus,
+	implementations map[string]backend.BrowserImplementation,
+) *backend.Feature {
+	return &backend.Feature{
+		Baseline: &backend.BaselineInfo{
+			Status: status,
+			LowDate: valuePtr(
+				openapi_types.Date{Time: time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)},
+			),
+			HighDate: valuePtr(
+				openapi_types.Date{Time: time.Date(2002, time.July, 1, 0, 0, 0, 0, time.UTC)},
+			),
+		},
+		BrowserImplementations: &implementations,
+		FeatureId:              "feature1",
+		Name:                   "feature 1",
+		Spec:                   nil,
+		Usage:                  nil,
+		Wpt:                    nil,
+		RedirectedFrom:         nil,
+	}
+}
+
+func TestGetFeatureBadge(t *testing.T) {
+	available := backend.BrowserImplementation{Status: valuePtr(backend.Available), Date: nil}
+	testCases := []struct {
+		name              string
+		mockConfig        MockGetFeatureByIDConfig
+		expectedCallCount int
+		request           backend.GetFeatureBadgeRequestObject
+		// For badges, the response is checked by the substrings of the body.
+		expectedResponse  backend.GetFeatureBadgeResponseObject
+		expectedBodyParts []string
+	}{
+		{
+			name: "widely available with defaults",
+			mockConfig: MockGetFeatureByIDConfig{
+				expectedFeatureID:     "feature1",
+				expectedWPTMetricView: backend.SubtestCounts,
+				expectedBrowsers:      defaultBrowsers(),
+				expectedAsOf:          nil,
+				data:                  testBadgeFeature(valuePtr(backend.Widely), nil),
+				err:                   nil,
+			},
+			expectedCallCount: 1,
+			request: backend.GetFeatureBadgeRequestObject{
+				FeatureId: "feature1",
+				Params:    backend.GetFeatureBadgeParams{Style: nil, Theme: nil},
+			},
+			expectedResponse: backend.GetFeatureBadge200ImagesvgXmlResponse{Body: nil, ContentLength: 0},
+			expectedBodyParts: []string{
+				`aria-label="Baseline: widely available since 2002-07"`,
+				`fill="#555555"`,
+				`fill="#1e8e3e"`,
+				`rx="3"`,
+				`fill="url(#s)"`,
+			},
+		},
+		{
+			name: "newly available with flat-square style and dark theme",
+			mockConfig: MockGetFeatureByIDConfig{
+				expectedFeatureID:     "feature1",
+				expectedWPTMetricView: backend.SubtestCounts,
+				expectedBrowsers:      defaultBrowsers(),
+				expectedAsOf:          nil,
+				data:                  testBadgeFeature(valuePtr(backend.Newly), nil),
+				err:                   nil,
+			},
+			expectedCallCount: 1,
+			request: backend.GetFeatureBadgeRequestObject{
+				FeatureId: "feature1",
+				Params: backend.GetFeatureBadgeParams{
+					Style: valuePtr(backend.BadgeStyleFlatSquare),
+					Theme: valuePtr(backend.BadgeThemeDark),
+				},
+			},
+			expectedResponse: backend.GetFeatureBadge200ImagesvgXmlResponse{Body: nil, ContentLength: 0},
+			expectedBodyParts: []string{
+				`aria-label="Baseline: newly available since 2000-01"`,
+				`fill="#e8eaed"`,
+				`fill="#8ab4f8"`,
+				`rx="0"`,
+			},
+		},
+		{
+			name: "limited lists the missing browsers",
+			mockConfig: MockGetFeatureByIDConfig{
+				expectedFeatureID:     "feature1",
+				expectedWPTMetricView: backend.SubtestCounts,
+				expectedBrowsers:      defaultBrowsers(),
+				expectedAsOf:          nil,
+				data: testBadgeFeature(valuePtr(backend.Limited), map[string]backend.BrowserImplementation{
+					"chrome":  available,
+					"edge":    available,
+					"firefox": available,
+					"safari":  {Status: valuePtr(backend.Unavailable), Date: nil},
+				}),
+				err: nil,
+			},
+			expectedCallCount: 1,
+			request: backend.GetFeatureBadgeRequestObject{
+				FeatureId: "feature1",
+				Params:    backend.GetFeatureBadgeParams{Style: nil, Theme: nil},
+			},
+			expectedResponse: backend.GetFeatureBadge200ImagesvgXmlResponse{Body: nil, ContentLength: 0},
+			expectedBodyParts: []string{
+				`aria-label="Limited: missing in Safari"`,
+				`fill="#d56e0c"`,
+			},
+		},
+		{
+			name: "no baseline status",
+			mockConfig: MockGetFeatureByIDConfig{
+				expectedFeatureID:     "feature1",
+				expectedWPTMetricView: backend.SubtestCounts,
+				expectedBrowsers:      defaultBrowsers(),
+				expectedAsOf:          nil,
+				data:                  testBadgeFeature(nil, nil),
+				err:                   nil,
+			},
+			expectedCallCount: 1,
+			request: backend.GetFeatureBadgeRequestObject{
+				FeatureId: "feature1",
+				Params:    backend.GetFeatureBadgeParams{Style: nil, Theme: nil},
+			},
+			expectedResponse: backend.GetFeatureBadge200ImagesvgXmlResponse{Body: nil, ContentLength: 0},
+			expectedBodyParts: []string{
+				`aria-label="Baseline: unknown"`,
+				`fill="#80868b"`,
+			},
+		},
+		{
+			name: "404 still renders a badge",
+			mockConfig: MockGetFeatureByIDConfig{
+				expectedFeatureID:     "feature1",
+				expectedWPTMetricView: backend.SubtestCounts,
+				expectedBrowsers:      defaultBrowsers(),
+				expectedAsOf:          nil,
+				data:                  nil,
+				err:                   gcpspanner.ErrQueryReturnedNoResults,
+			},
+			expectedCallCount: 1,
+			request: backend.GetFeatureBadgeRequestObject{
+				FeatureId: "feature1",
+				Params:    backend.GetFeatureBadgeParams{Style: nil, Theme: nil},
+			},
+			expectedResponse:  backend.GetFeatureBadge404ImagesvgXmlResponse{Body: nil, ContentLength: 0},
+			expectedBodyParts: []string{`aria-label="Baseline: feature not found"`},
+		},
+		{
+			name: "500",
+			mockConfig: MockGetFeatureByIDConfig{
+				expectedFeatureID:     "feature1",
+				expectedWPTMetricView: backend.SubtestCounts,
+				expectedBrowsers:      defaultBrowsers(),
+				expectedAsOf:          nil,
+				data:                  nil,
+				err:                   errTest,
+			},
+			expectedCallCount: 1,
+			request: backend.GetFeatureBadgeRequestObject{
+				FeatureId: "feature1",
+				Params:    backend.GetFeatureBadgeParams{Style: nil, Theme: nil},
+			},
+			expectedResponse: backend.GetFeatureBadge500JSONResponse{
+				Code:    500,
+				Message: "unable to get feature badge",
+			},
+			expectedBodyParts: nil,
+		},
+		{
+			name: "400 unknown style",
+			// nolint: exhaustruct // The storer is not called.
+			mockConfig:        MockGetFeatureByIDConfig{},
+			expectedCallCount: 0,
+			request: backend.GetFeatureBadgeRequestObject{
+				FeatureId: "feature1",
+				Params: backend.GetFeatureBadgeParams{
+					Style: valuePtr(backend.GetFeatureBadgeParamsStyle("plastic")),
+					Theme: nil,
+				},
+			},
+			expectedResponse: backend.GetFeatureBadge400JSONResponse{
+				Code:    400,
+				Message: "unknown style plastic",
+			},
+			expectedBodyParts: nil,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// nolint: exhaustruct
+			mockStorer := &MockWPTMetricsStorer{
+				getFeatureByIDConfig: tc.mockConfig,
+				t:                    t,
+			}
+			myServer := Server{
+				wptMetricsStorer:         mockStorer,
+				metadataStorer:           nil,
+				subscriptionStorer:       nil,
+				callbackPolicy:           nil,
+				excludedFeatureKeyStorer: nil,
+				cacheClearer:             nil,
+			}
+
+			resp, err := myServer.GetFeatureBadge(context.Background(), tc.request)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if mockStorer.callCountGetFeature != tc.expectedCallCount {
+				t.Errorf("Incorrect call count: expected %d, got %d",
+					tc.expectedCallCount,
+					mockStorer.callCountGetFeature)
+			}
+
+			var body io.Reader
+			var contentLength int64
+			switch r := resp.(type) {
+			case backend.GetFeatureBadge200ImagesvgXmlResponse:
+				body, contentLength = r.Body, r.ContentLength
+				r.Body, r.ContentLength = nil, 0
+				resp = r
+			case backend.GetFeatureBadge404ImagesvgXmlResponse:
+				body, contentLength = r.Body, r.ContentLength
+				r.Body, r.ContentLength = nil, 0
+				resp = r
+			}
+			if !reflect.DeepEqual(tc.expectedResponse, resp) {
+				t.Errorf("Unexpected response: %v", resp)
+			}
+			if body == nil {
+				return
+			}
+			data, err := io.ReadAll(body)
+			if err != nil {
+				t.Fatalf("unable to read body %v", err)
+			}
+			if int64(len(data)) != contentLength {
+				t.Errorf("expected content length %d, got %d", len(data), contentLength)
+			}
+			for _, part := range tc.expectedBodyParts {
+				if !strings.Contains(string(data), part) {
+					t.Errorf("expected body to contain %s\nbody: %s", part, string(data))
+				}
+			}
+		})
+	}
+}
+
+func TestRenderBadgeEscapesText(t *testing.T) {
+	body, err := renderBadge(badge{label: "A&B", message: `<script>"x"</script>`, status: ""},
+		backend.BadgeStyleFlat, badgeThemes[backend.BadgeThemeLight])
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if strings.Contains(string(body), "<script>") || !strings.Contains(string(body), "A&amp;B") {
+		t.Errorf("expected text to be escaped. body: %s", string(body))
+	}
+}
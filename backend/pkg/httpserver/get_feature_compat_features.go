@@ -0,0 +1,52 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/GoogleChrome/webstatus.dev/lib/gcpspanner"
+	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/backend"
+)
+
+// GetFeatureCompatFeatures implements backend.StrictServerInterface.
+// nolint: ireturn // Signature generated from openapi
+func (s *Server) GetFeatureCompatFeatures(
+	ctx context.Context,
+	request backend.GetFeatureCompatFeaturesRequestObject,
+) (backend.GetFeatureCompatFeaturesResponseObject, error) {
+	compatFeatures, err := s.wptMetricsStorer.GetFeatureCompatFeatures(ctx, request.FeatureId)
+	if err != nil {
+		if errors.Is(err, gcpspanner.ErrQueryReturnedNoResults) {
+			return backend.GetFeatureCompatFeatures404JSONResponse{
+				Code:    http.StatusNotFound,
+				Message: fmt.Sprintf("feature id %s is not found", request.FeatureId),
+			}, nil
+		}
+		// Catch all for all other errors.
+		slog.ErrorContext(ctx, "unable to get feature compat features", "error", err)
+
+		return backend.GetFeatureCompatFeatures500JSONResponse{
+			Code:    500,
+			Message: "unable to get feature compat features",
+		}, nil
+	}
+
+	return backend.GetFeatureCompatFeatures200JSONResponse(*compatFeatures), nil
+}
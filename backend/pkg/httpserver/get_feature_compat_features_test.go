@@ -0,0 +1,139 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/GoogleChrome/webstatus.dev/lib/gcpspanner"
+	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/backend"
+)
+
+func TestGetFeatureCompatFeatures(t *testing.T) {
+	testCases := []struct {
+		name              string
+		mockConfig        MockGetFeatureCompatFeaturesConfig
+		expectedCallCount int // For the mock method
+		request           backend.GetFeatureCompatFeaturesRequestObject
+		expectedResponse  backend.GetFeatureCompatFeaturesResponseObject
+		expectedError     error
+	}{
+		{
+			name: "Success Case",
+			mockConfig: MockGetFeatureCompatFeaturesConfig{
+				expectedFeatureID: "feature1",
+				data: &backend.FeatureCompatFeatures{
+					FeatureId: "feature1",
+					CompatFeatures: []backend.FeatureCompatFeature{
+						{
+							CompatFeature: "api.Feature1",
+							BrowserSupport: []backend.CompatFeatureBrowserSupport{
+								{Browser: "chrome", VersionAdded: valuePtr("100")},
+								{Browser: "safari", VersionAdded: nil},
+							},
+						},
+					},
+				},
+				err: nil,
+			},
+			expectedCallCount: 1,
+			expectedResponse: backend.GetFeatureCompatFeatures200JSONResponse{
+				FeatureId: "feature1",
+				CompatFeatures: []backend.FeatureCompatFeature{
+					{
+						CompatFeature: "api.Feature1",
+						BrowserSupport: []backend.CompatFeatureBrowserSupport{
+							{Browser: "chrome", VersionAdded: valuePtr("100")},
+							{Browser: "safari", VersionAdded: nil},
+						},
+					},
+				},
+			},
+			request: backend.GetFeatureCompatFeaturesRequestObject{
+				FeatureId: "feature1",
+			},
+			expectedError: nil,
+		},
+		{
+			name: "404",
+			mockConfig: MockGetFeatureCompatFeaturesConfig{
+				expectedFeatureID: "feature1",
+				data:              nil,
+				err:               gcpspanner.ErrQueryReturnedNoResults,
+			},
+			expectedCallCount: 1,
+			expectedResponse: backend.GetFeatureCompatFeatures404JSONResponse{
+				Code:    404,
+				Message: "feature id feature1 is not found",
+			},
+			request: backend.GetFeatureCompatFeaturesRequestObject{
+				FeatureId: "feature1",
+			},
+			expectedError: nil,
+		},
+		{
+			name: "500",
+			mockConfig: MockGetFeatureCompatFeaturesConfig{
+				expectedFeatureID: "feature1",
+				data:              nil,
+				err:               errTest,
+			},
+			expectedCallCount: 1,
+			expectedResponse: backend.GetFeatureCompatFeatures500JSONResponse{
+				Code:    500,
+				Message: "unable to get feature compat features",
+			},
+			request: backend.GetFeatureCompatFeaturesRequestObject{
+				FeatureId: "feature1",
+			},
+			expectedError: nil,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// nolint: exhaustruct
+			mockStorer := &MockWPTMetricsStorer{
+				getFeatureCompatFeaturesConfig: tc.mockConfig,
+				t:                              t,
+			}
+			myServer := Server{
+				wptMetricsStorer:         mockStorer,
+				metadataStorer:           nil,
+				subscriptionStorer:       nil,
+				excludedFeatureKeyStorer: nil,
+				cacheClearer:             nil,
+			}
+
+			resp, err := myServer.GetFeatureCompatFeatures(context.Background(), tc.request)
+
+			if mockStorer.callCountGetFeatureCompatFeatures != tc.expectedCallCount {
+				t.Errorf("Incorrect call count: expected %d, got %d",
+					tc.expectedCallCount,
+					mockStorer.callCountGetFeatureCompatFeatures)
+			}
+
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("Unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(tc.expectedResponse, resp) {
+				t.Errorf("Unexpected response: %v", resp)
+			}
+		})
+	}
+}
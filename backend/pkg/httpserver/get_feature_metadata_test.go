@@ -83,7 +83,14 @@ func TestGetFeatureMetadata(t *testing.T) {
 				mockGetFeatureMetadataCfg: tc.mockGetMetadataConfig,
 				t:                         t,
 			}
-			myServer := Server{wptMetricsStorer: mockStorer, metadataStorer: mockMetadataStorer}
+			myServer := Server{
+				wptMetricsStorer:         mockStorer,
+				metadataStorer:           mockMetadataStorer,
+				subscriptionStorer:       nil,
+				callbackPolicy:           nil,
+				excludedFeatureKeyStorer: nil,
+				cacheClearer:             nil,
+			}
 
 			// Call the function under test
 			resp, err := myServer.GetFeatureMetadata(context.Background(), tc.request)
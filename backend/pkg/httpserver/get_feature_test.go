@@ -47,6 +47,7 @@ func TestGetV1FeaturesFeatureId(t *testing.T) {
 					backend.Firefox,
 					backend.Safari,
 				},
+				expectedAsOf: nil,
 				data: &backend.Feature{
 					Baseline: &backend.BaselineInfo{
 						Status: valuePtr(backend.Widely),
@@ -63,11 +64,12 @@ func TestGetV1FeaturesFeatureId(t *testing.T) {
 							Date:   &openapi_types.Date{Time: time.Date(1999, time.January, 1, 0, 0, 0, 0, time.UTC)},
 						},
 					},
-					FeatureId: "feature1",
-					Name:      "feature 1",
-					Spec:      nil,
-					Usage:     nil,
-					Wpt:       nil,
+					FeatureId:      "feature1",
+					Name:           "feature 1",
+					Spec:           nil,
+					Usage:          nil,
+					Wpt:            nil,
+					RedirectedFrom: nil,
 				},
 				err: nil,
 			},
@@ -98,6 +100,7 @@ func TestGetV1FeaturesFeatureId(t *testing.T) {
 				FeatureId: "feature1",
 				Params: backend.GetV1FeaturesFeatureIdParams{
 					WptMetricView: nil,
+					At:            nil,
 				},
 			},
 			expectedError: nil,
@@ -114,6 +117,7 @@ func TestGetV1FeaturesFeatureId(t *testing.T) {
 					backend.Firefox,
 					backend.Safari,
 				},
+				expectedAsOf: valuePtr(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)),
 				data: &backend.Feature{
 					Baseline: &backend.BaselineInfo{
 						Status: valuePtr(backend.Widely),
@@ -130,11 +134,12 @@ func TestGetV1FeaturesFeatureId(t *testing.T) {
 							Date:   &openapi_types.Date{Time: time.Date(1999, time.January, 1, 0, 0, 0, 0, time.UTC)},
 						},
 					},
-					FeatureId: "feature1",
-					Name:      "feature 1",
-					Spec:      nil,
-					Usage:     nil,
-					Wpt:       nil,
+					FeatureId:      "feature1",
+					Name:           "feature 1",
+					Spec:           nil,
+					Usage:          nil,
+					Wpt:            nil,
+					RedirectedFrom: nil,
 				},
 				err: nil,
 			},
@@ -165,6 +170,7 @@ func TestGetV1FeaturesFeatureId(t *testing.T) {
 				FeatureId: "feature1",
 				Params: backend.GetV1FeaturesFeatureIdParams{
 					WptMetricView: valuePtr(backend.TestCounts),
+					At:            &openapi_types.Date{Time: time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)},
 				},
 			},
 			expectedError: nil,
@@ -180,8 +186,9 @@ func TestGetV1FeaturesFeatureId(t *testing.T) {
 					backend.Firefox,
 					backend.Safari,
 				},
-				data: nil,
-				err:  gcpspanner.ErrQueryReturnedNoResults,
+				expectedAsOf: nil,
+				data:         nil,
+				err:          gcpspanner.ErrQueryReturnedNoResults,
 			},
 			expectedCallCount: 1,
 			expectedResponse: backend.GetV1FeaturesFeatureId404JSONResponse{
@@ -192,6 +199,7 @@ func TestGetV1FeaturesFeatureId(t *testing.T) {
 				FeatureId: "feature1",
 				Params: backend.GetV1FeaturesFeatureIdParams{
 					WptMetricView: nil,
+					At:            nil,
 				},
 			},
 			expectedError: nil,
@@ -207,8 +215,9 @@ func TestGetV1FeaturesFeatureId(t *testing.T) {
 					backend.Firefox,
 					backend.Safari,
 				},
-				data: nil,
-				err:  errTest,
+				expectedAsOf: nil,
+				data:         nil,
+				err:          errTest,
 			},
 			expectedCallCount: 1,
 			expectedResponse: backend.GetV1FeaturesFeatureId500JSONResponse{
@@ -219,6 +228,7 @@ func TestGetV1FeaturesFeatureId(t *testing.T) {
 				FeatureId: "feature1",
 				Params: backend.GetV1FeaturesFeatureIdParams{
 					WptMetricView: nil,
+					At:            nil,
 				},
 			},
 			expectedError: nil,
@@ -231,7 +241,14 @@ func TestGetV1FeaturesFeatureId(t *testing.T) {
 				getFeatureByIDConfig: tc.mockConfig,
 				t:                    t,
 			}
-			myServer := Server{wptMetricsStorer: mockStorer, metadataStorer: nil}
+			myServer := Server{
+				wptMetricsStorer:         mockStorer,
+				metadataStorer:           nil,
+				subscriptionStorer:       nil,
+				callbackPolicy:           nil,
+				excludedFeatureKeyStorer: nil,
+				cacheClearer:             nil,
+			}
 
 			// Call the function under test
 			resp, err := myServer.GetV1FeaturesFeatureId(context.Background(), tc.request)
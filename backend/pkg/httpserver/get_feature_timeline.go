@@ -0,0 +1,54 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/GoogleChrome/webstatus.dev/lib/gcpspanner"
+	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/backend"
+)
+
+// GetFeatureTimeline implements backend.StrictServerInterface.
+// nolint: ireturn // Signature generated from openapi
+func (s *Server) GetFeatureTimeline(
+	ctx context.Context,
+	request backend.GetFeatureTimelineRequestObject,
+) (backend.GetFeatureTimelineResponseObject, error) {
+	timeline, err := s.wptMetricsStorer.GetFeatureTimeline(ctx, request.FeatureId,
+		getWPTMetricViewOrDefault(request.Params.WptMetricView),
+	)
+	if err != nil {
+		if errors.Is(err, gcpspanner.ErrQueryReturnedNoResults) {
+			return backend.GetFeatureTimeline404JSONResponse{
+				Code:    http.StatusNotFound,
+				Message: fmt.Sprintf("feature id %s is not found", request.FeatureId),
+			}, nil
+		}
+		// Catch all for all other errors.
+		slog.ErrorContext(ctx, "unable to get feature timeline", "error", err)
+
+		return backend.GetFeatureTimeline500JSONResponse{
+			Code:    500,
+			Message: "unable to get feature timeline",
+		}, nil
+	}
+
+	return backend.GetFeatureTimeline200JSONResponse(*timeline), nil
+}
@@ -0,0 +1,195 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/GoogleChrome/webstatus.dev/lib/gcpspanner"
+	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/backend"
+)
+
+func TestGetFeatureTimeline(t *testing.T) {
+	testCases := []struct {
+		name              string
+		mockConfig        MockGetFeatureTimelineConfig
+		expectedCallCount int // For the mock method
+		request           backend.GetFeatureTimelineRequestObject
+		expectedResponse  backend.GetFeatureTimelineResponseObject
+		expectedError     error
+	}{
+		{
+			name: "Success Case - no optional params - use defaults",
+			mockConfig: MockGetFeatureTimelineConfig{
+				expectedFeatureID:     "feature1",
+				expectedWPTMetricView: backend.SubtestCounts,
+				data: &backend.FeatureTimeline{
+					FeatureId: "feature1",
+					Events: []backend.FeatureTimelineEvent{
+						{
+							Timestamp:         time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+							Type:              backend.BrowserAvailable,
+							Browser:           valuePtr("chrome"),
+							BrowserVersion:    valuePtr("100"),
+							Channel:           nil,
+							PassRateThreshold: nil,
+						},
+						{
+							Timestamp:         time.Date(2000, time.February, 1, 0, 0, 0, 0, time.UTC),
+							Type:              backend.BaselineLow,
+							Browser:           nil,
+							BrowserVersion:    nil,
+							Channel:           nil,
+							PassRateThreshold: nil,
+						},
+					},
+				},
+				err: nil,
+			},
+			expectedCallCount: 1,
+			expectedResponse: backend.GetFeatureTimeline200JSONResponse{
+				FeatureId: "feature1",
+				Events: []backend.FeatureTimelineEvent{
+					{
+						Timestamp:         time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+						Type:              backend.BrowserAvailable,
+						Browser:           valuePtr("chrome"),
+						BrowserVersion:    valuePtr("100"),
+						Channel:           nil,
+						PassRateThreshold: nil,
+					},
+					{
+						Timestamp:         time.Date(2000, time.February, 1, 0, 0, 0, 0, time.UTC),
+						Type:              backend.BaselineLow,
+						Browser:           nil,
+						BrowserVersion:    nil,
+						Channel:           nil,
+						PassRateThreshold: nil,
+					},
+				},
+			},
+			request: backend.GetFeatureTimelineRequestObject{
+				FeatureId: "feature1",
+				Params: backend.GetFeatureTimelineParams{
+					WptMetricView: nil,
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name: "Success Case - with optional params",
+			mockConfig: MockGetFeatureTimelineConfig{
+				expectedFeatureID:     "feature1",
+				expectedWPTMetricView: backend.TestCounts,
+				data: &backend.FeatureTimeline{
+					FeatureId: "feature1",
+					Events:    []backend.FeatureTimelineEvent{},
+				},
+				err: nil,
+			},
+			expectedCallCount: 1,
+			expectedResponse: backend.GetFeatureTimeline200JSONResponse{
+				FeatureId: "feature1",
+				Events:    []backend.FeatureTimelineEvent{},
+			},
+			request: backend.GetFeatureTimelineRequestObject{
+				FeatureId: "feature1",
+				Params: backend.GetFeatureTimelineParams{
+					WptMetricView: valuePtr(backend.TestCounts),
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name: "404",
+			mockConfig: MockGetFeatureTimelineConfig{
+				expectedFeatureID:     "feature1",
+				expectedWPTMetricView: backend.SubtestCounts,
+				data:                  nil,
+				err:                   gcpspanner.ErrQueryReturnedNoResults,
+			},
+			expectedCallCount: 1,
+			expectedResponse: backend.GetFeatureTimeline404JSONResponse{
+				Code:    404,
+				Message: "feature id feature1 is not found",
+			},
+			request: backend.GetFeatureTimelineRequestObject{
+				FeatureId: "feature1",
+				Params: backend.GetFeatureTimelineParams{
+					WptMetricView: nil,
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name: "500",
+			mockConfig: MockGetFeatureTimelineConfig{
+				expectedFeatureID:     "feature1",
+				expectedWPTMetricView: backend.SubtestCounts,
+				data:                  nil,
+				err:                   errTest,
+			},
+			expectedCallCount: 1,
+			expectedResponse: backend.GetFeatureTimeline500JSONResponse{
+				Code:    500,
+				Message: "unable to get feature timeline",
+			},
+			request: backend.GetFeatureTimelineRequestObject{
+				FeatureId: "feature1",
+				Params: backend.GetFeatureTimelineParams{
+					WptMetricView: nil,
+				},
+			},
+			expectedError: nil,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// nolint: exhaustruct
+			mockStorer := &MockWPTMetricsStorer{
+				getFeatureTimelineConfig: tc.mockConfig,
+				t:                        t,
+			}
+			myServer := Server{
+				wptMetricsStorer:         mockStorer,
+				metadataStorer:           nil,
+				subscriptionStorer:       nil,
+				callbackPolicy:           nil,
+				excludedFeatureKeyStorer: nil,
+				cacheClearer:             nil,
+			}
+
+			resp, err := myServer.GetFeatureTimeline(context.Background(), tc.request)
+
+			if mockStorer.callCountGetFeatureTimeline != tc.expectedCallCount {
+				t.Errorf("Incorrect call count: expected %d, got %d",
+					tc.expectedCallCount,
+					mockStorer.callCountGetFeatureTimeline)
+			}
+
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("Unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(tc.expectedResponse, resp) {
+				t.Errorf("Unexpected response: %v", resp)
+			}
+		})
+	}
+}
@@ -16,43 +16,37 @@ package httpserver
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"net/url"
+	"slices"
+	"time"
 
 	"github.com/GoogleChrome/webstatus.dev/lib/gcpspanner/searchtypes"
 	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/backend"
+	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
+var errUnknownFacet = errors.New("unknown facet")
+
 // GetV1Features implements backend.StrictServerInterface.
 // nolint:ireturn // Expected ireturn for openapi generation.
 func (s *Server) GetV1Features(
 	ctx context.Context,
 	req backend.GetV1FeaturesRequestObject,
 ) (backend.GetV1FeaturesResponseObject, error) {
-	var node *searchtypes.SearchNode
-	if req.Params.Q != nil {
-		// Try to decode the url.
-		decodedStr, err := url.QueryUnescape(*req.Params.Q)
-		if err != nil {
-			slog.WarnContext(ctx, "unable to decode string", "input string", *req.Params.Q, "error", err)
-
-			return backend.GetV1Features400JSONResponse{
-				Code:    http.StatusBadRequest,
-				Message: "query string cannot be decoded",
-			}, nil
-		}
-
-		parser := searchtypes.FeaturesSearchQueryParser{}
-		node, err = parser.Parse(decodedStr)
-		if err != nil {
-			slog.WarnContext(ctx, "unable to parse query string", "query", decodedStr, "error", err)
-
-			return backend.GetV1Features400JSONResponse{
-				Code:    http.StatusBadRequest,
-				Message: "query string does not match expected grammar",
-			}, nil
-		}
+	node, parseErr := parseSearchQuery(ctx, req.Params.Q)
+	if parseErr != nil {
+		return backend.GetV1Features400JSONResponse(*parseErr), nil
+	}
+	facets, err := getFeatureSearchFacets(req.Params.Facets)
+	if err != nil {
+		return backend.GetV1Features400JSONResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		}, nil
 	}
 	featurePage, err := s.wptMetricsStorer.FeaturesSearch(
 		ctx,
@@ -62,6 +56,8 @@ func (s *Server) GetV1Features(
 		req.Params.Sort,
 		getWPTMetricViewOrDefault(req.Params.WptMetricView),
 		defaultBrowsers(),
+		getAsOfTime(req.Params.At),
+		facets,
 	)
 
 	if err != nil {
@@ -77,9 +73,60 @@ func (s *Server) GetV1Features(
 	return backend.GetV1Features200JSONResponse{
 		Metadata: featurePage.Metadata,
 		Data:     featurePage.Data,
+		Facets:   featurePage.Facets,
 	}, nil
 }
 
+// parseSearchQuery decodes and parses the optional q parameter.
+// If the query is invalid, an error model describing the problem is returned.
+func parseSearchQuery(ctx context.Context, q *string) (*searchtypes.SearchNode, *backend.BasicErrorModel) {
+	if q == nil {
+		return nil, nil
+	}
+	// Try to decode the url.
+	decodedStr, err := url.QueryUnescape(*q)
+	if err != nil {
+		slog.WarnContext(ctx, "unable to decode string", "input string", *q, "error", err)
+
+		return nil, &backend.BasicErrorModel{
+			Code:    http.StatusBadRequest,
+			Message: "query string cannot be decoded",
+		}
+	}
+
+	parser := searchtypes.FeaturesSearchQueryParser{}
+	node, err := parser.Parse(decodedStr)
+	if err != nil {
+		slog.WarnContext(ctx, "unable to parse query string", "query", decodedStr, "error", err)
+
+		return nil, &backend.BasicErrorModel{
+			Code:    http.StatusBadRequest,
+			Message: "query string does not match expected grammar",
+		}
+	}
+
+	return node, nil
+}
+
+// getFeatureSearchFacets validates the requested facets and removes duplicates.
+// If no facets are requested, nil is returned.
+func getFeatureSearchFacets(facets *[]string) ([]string, error) {
+	if facets == nil || len(*facets) == 0 {
+		return nil, nil
+	}
+	supported := []string{"available_on", "baseline_status"}
+	ret := make([]string, 0, len(*facets))
+	for _, facet := range *facets {
+		if !slices.Contains(supported, facet) {
+			return nil, fmt.Errorf("%w: %s", errUnknownFacet, facet)
+		}
+		ret = append(ret, facet)
+	}
+	slices.Sort(ret)
+
+	return slices.Compact(ret), nil
+}
+
 func getWPTMetricViewOrDefault(in *backend.WPTMetricView) backend.WPTMetricView {
 	if in != nil {
 		switch *in {
@@ -91,3 +138,14 @@ func getWPTMetricViewOrDefault(in *backend.WPTMetricView) backend.WPTMetricView
 	// Default to subtest count if not specified or invalid metric view.
 	return backend.SubtestCounts
 }
+
+// getAsOfTime converts the optional at parameter to the point in time used by the storage layer.
+// A nil result means the current data should be used.
+func getAsOfTime(in *openapi_types.Date) *time.Time {
+	if in == nil {
+		return nil
+	}
+	asOf := in.Time
+
+	return &asOf
+}
@@ -17,6 +17,8 @@ package httpserver
 import (
 	"context"
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"reflect"
 	"testing"
@@ -50,7 +52,10 @@ func TestGetV1Features(t *testing.T) {
 					backend.Firefox,
 					backend.Safari,
 				},
+				expectedAsOf:   nil,
+				expectedFacets: nil,
 				page: &backend.FeaturePage{
+					Facets: nil,
 					Metadata: backend.PageMetadataWithTotal{
 						NextPageToken: nil,
 						Total:         100,
@@ -66,11 +71,12 @@ func TestGetV1Features(t *testing.T) {
 									openapi_types.Date{Time: time.Date(2001, time.January, 1, 0, 0, 0, 0, time.UTC)},
 								),
 							},
-							FeatureId: "feature1",
-							Name:      "feature 1",
-							Spec:      nil,
-							Usage:     nil,
-							Wpt:       nil,
+							FeatureId:      "feature1",
+							Name:           "feature 1",
+							Spec:           nil,
+							Usage:          nil,
+							Wpt:            nil,
+							RedirectedFrom: nil,
 							BrowserImplementations: &map[string]backend.BrowserImplementation{
 								"browser1": {
 									Status: valuePtr(backend.Available),
@@ -84,6 +90,7 @@ func TestGetV1Features(t *testing.T) {
 			},
 			expectedCallCount: 1,
 			expectedResponse: backend.GetV1Features200JSONResponse{
+				Facets: nil,
 				Data: []backend.Feature{
 					{
 						Baseline: &backend.BaselineInfo{
@@ -95,11 +102,12 @@ func TestGetV1Features(t *testing.T) {
 								openapi_types.Date{Time: time.Date(2001, time.January, 1, 0, 0, 0, 0, time.UTC)},
 							),
 						},
-						FeatureId: "feature1",
-						Name:      "feature 1",
-						Spec:      nil,
-						Usage:     nil,
-						Wpt:       nil,
+						FeatureId:      "feature1",
+						Name:           "feature 1",
+						Spec:           nil,
+						Usage:          nil,
+						Wpt:            nil,
+						RedirectedFrom: nil,
 						BrowserImplementations: &map[string]backend.BrowserImplementation{
 							"browser1": {
 								Status: valuePtr(backend.Available),
@@ -120,6 +128,8 @@ func TestGetV1Features(t *testing.T) {
 					Q:             nil,
 					Sort:          nil,
 					WptMetricView: nil,
+					At:            nil,
+					Facets:        nil,
 				},
 			},
 			expectedError: nil,
@@ -136,6 +146,8 @@ func TestGetV1Features(t *testing.T) {
 					backend.Firefox,
 					backend.Safari,
 				},
+				expectedAsOf:   valuePtr(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)),
+				expectedFacets: []string{"available_on", "baseline_status"},
 				expectedSearchNode: &searchtypes.SearchNode{
 					Keyword: searchtypes.KeywordRoot,
 					Term:    nil,
@@ -168,6 +180,14 @@ func TestGetV1Features(t *testing.T) {
 				},
 				expectedSortBy: valuePtr[backend.GetV1FeaturesParamsSort](backend.NameDesc),
 				page: &backend.FeaturePage{
+					Facets: &backend.FeatureSearchFacets{
+						BaselineStatus: &[]backend.FacetValueCount{
+							{Value: "newly", Count: 1},
+						},
+						AvailableOn: &[]backend.FacetValueCount{
+							{Value: "chrome", Count: 1},
+						},
+					},
 					Metadata: backend.PageMetadataWithTotal{
 						NextPageToken: nextPageToken,
 						Total:         100,
@@ -183,11 +203,12 @@ func TestGetV1Features(t *testing.T) {
 									openapi_types.Date{Time: time.Date(2001, time.January, 1, 0, 0, 0, 0, time.UTC)},
 								),
 							},
-							FeatureId: "feature1",
-							Name:      "feature 1",
-							Spec:      nil,
-							Usage:     nil,
-							Wpt:       nil,
+							FeatureId:      "feature1",
+							Name:           "feature 1",
+							Spec:           nil,
+							Usage:          nil,
+							Wpt:            nil,
+							RedirectedFrom: nil,
 							BrowserImplementations: &map[string]backend.BrowserImplementation{
 								"chrome": {
 									Status: valuePtr(backend.Available),
@@ -202,6 +223,14 @@ func TestGetV1Features(t *testing.T) {
 			},
 			expectedCallCount: 1,
 			expectedResponse: backend.GetV1Features200JSONResponse{
+				Facets: &backend.FeatureSearchFacets{
+					BaselineStatus: &[]backend.FacetValueCount{
+						{Value: "newly", Count: 1},
+					},
+					AvailableOn: &[]backend.FacetValueCount{
+						{Value: "chrome", Count: 1},
+					},
+				},
 				Data: []backend.Feature{
 					{
 						Baseline: &backend.BaselineInfo{
@@ -213,11 +242,12 @@ func TestGetV1Features(t *testing.T) {
 								openapi_types.Date{Time: time.Date(2001, time.January, 1, 0, 0, 0, 0, time.UTC)},
 							),
 						},
-						FeatureId: "feature1",
-						Name:      "feature 1",
-						Spec:      nil,
-						Usage:     nil,
-						Wpt:       nil,
+						FeatureId:      "feature1",
+						Name:           "feature 1",
+						Spec:           nil,
+						Usage:          nil,
+						Wpt:            nil,
+						RedirectedFrom: nil,
 						BrowserImplementations: &map[string]backend.BrowserImplementation{
 							"chrome": {
 								Status: valuePtr(backend.Available),
@@ -239,6 +269,8 @@ func TestGetV1Features(t *testing.T) {
 					Q:             valuePtr(url.QueryEscape("available_on:chrome AND name:grid")),
 					Sort:          valuePtr[backend.GetV1FeaturesParamsSort](backend.NameDesc),
 					WptMetricView: valuePtr(backend.TestCounts),
+					At:            &openapi_types.Date{Time: time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)},
+					Facets:        &[]string{"baseline_status", "available_on", "baseline_status"},
 				},
 			},
 			expectedError: nil,
@@ -256,6 +288,8 @@ func TestGetV1Features(t *testing.T) {
 					backend.Firefox,
 					backend.Safari,
 				},
+				expectedAsOf:          nil,
+				expectedFacets:        nil,
 				expectedWPTMetricView: backend.SubtestCounts,
 				page:                  nil,
 				err:                   errTest,
@@ -272,6 +306,8 @@ func TestGetV1Features(t *testing.T) {
 					Q:             nil,
 					Sort:          nil,
 					WptMetricView: nil,
+					At:            nil,
+					Facets:        nil,
 				},
 			},
 			expectedError: nil,
@@ -285,6 +321,8 @@ func TestGetV1Features(t *testing.T) {
 				expectedSortBy:        nil,
 				expectedWPTMetricView: backend.SubtestCounts,
 				expectedBrowsers:      nil,
+				expectedAsOf:          nil,
+				expectedFacets:        nil,
 				page:                  nil,
 				err:                   errTest,
 			},
@@ -300,6 +338,40 @@ func TestGetV1Features(t *testing.T) {
 					Sort:          nil,
 					Q:             valuePtr[string]("badterm:foo"),
 					WptMetricView: nil,
+					At:            nil,
+					Facets:        nil,
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name: "400 case - unknown facet",
+			mockConfig: MockFeaturesSearchConfig{
+				expectedPageToken:     nil,
+				expectedPageSize:      100,
+				expectedSearchNode:    nil,
+				expectedSortBy:        nil,
+				expectedWPTMetricView: backend.SubtestCounts,
+				expectedBrowsers:      nil,
+				expectedAsOf:          nil,
+				expectedFacets:        nil,
+				page:                  nil,
+				err:                   errTest,
+			},
+			expectedCallCount: 0,
+			expectedResponse: backend.GetV1Features400JSONResponse{
+				Code:    400,
+				Message: "unknown facet: spec",
+			},
+			request: backend.GetV1FeaturesRequestObject{
+				Params: backend.GetV1FeaturesParams{
+					PageToken:     nil,
+					PageSize:      nil,
+					Q:             nil,
+					Sort:          nil,
+					WptMetricView: nil,
+					At:            nil,
+					Facets:        &[]string{"baseline_status", "spec"},
 				},
 			},
 			expectedError: nil,
@@ -313,6 +385,8 @@ func TestGetV1Features(t *testing.T) {
 				expectedSortBy:        nil,
 				expectedWPTMetricView: backend.SubtestCounts,
 				expectedBrowsers:      nil,
+				expectedAsOf:          nil,
+				expectedFacets:        nil,
 				page:                  nil,
 				err:                   errTest,
 			},
@@ -328,6 +402,8 @@ func TestGetV1Features(t *testing.T) {
 					Q:             valuePtr[string]("%"),
 					Sort:          nil,
 					WptMetricView: nil,
+					At:            nil,
+					Facets:        nil,
 				},
 			},
 			expectedError: nil,
@@ -340,7 +416,14 @@ func TestGetV1Features(t *testing.T) {
 				featuresSearchCfg: tc.mockConfig,
 				t:                 t,
 			}
-			myServer := Server{wptMetricsStorer: mockStorer, metadataStorer: nil}
+			myServer := Server{
+				wptMetricsStorer:         mockStorer,
+				metadataStorer:           nil,
+				subscriptionStorer:       nil,
+				callbackPolicy:           nil,
+				excludedFeatureKeyStorer: nil,
+				cacheClearer:             nil,
+			}
 
 			// Call the function under test
 			resp, err := myServer.GetV1Features(context.Background(), tc.request)
@@ -362,3 +445,34 @@ func TestGetV1Features(t *testing.T) {
 		})
 	}
 }
+
+func TestGetV1FeaturesCommaSeparatedFacets(t *testing.T) {
+	// nolint: exhaustruct
+	mockStorer := &MockWPTMetricsStorer{
+		featuresSearchCfg: MockFeaturesSearchConfig{
+			expectedPageToken:     nil,
+			expectedPageSize:      100,
+			expectedSearchNode:    nil,
+			expectedSortBy:        nil,
+			expectedWPTMetricView: backend.SubtestCounts,
+			expectedBrowsers:      defaultBrowsers(),
+			expectedAsOf:          nil,
+			expectedFacets:        []string{"available_on", "baseline_status"},
+			page: &backend.FeaturePage{
+				Metadata: backend.PageMetadataWithTotal{NextPageToken: nil, Total: 0},
+				Data:     []backend.Feature{},
+				Facets:   nil,
+			},
+			err: nil,
+		},
+		t: t,
+	}
+	req := httptest.NewRequest(http.MethodGet, "/v1/features?facets=baseline_status,available_on", nil)
+	rr := serveTestRequest(t, mockStorer, req)
+	if rr.Code != http.StatusOK {
+		t.Errorf("unexpected status code %d. body %s", rr.Code, rr.Body.String())
+	}
+	if mockStorer.callCountFeaturesSearch != 1 {
+		t.Errorf("Incorrect call count: expected 1, got %d", mockStorer.callCountFeaturesSearch)
+	}
+}
@@ -0,0 +1,370 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/backend"
+	"github.com/graph-gophers/dataloader/v7"
+	"github.com/graph-gophers/graphql-go"
+	graphqlerrors "github.com/graph-gophers/graphql-go/errors"
+)
+
+//go:embed graphql_schema.graphql
+var graphQLSchema string
+
+const (
+	// graphQLMaxDepth limits how deeply a query can nest fields.
+	graphQLMaxDepth = 10
+	// graphQLMaxParallelism is the number of fields resolved at the same time.
+	// It allows the fields of every feature in a full page to wait on the same batch.
+	graphQLMaxParallelism = 100
+	// graphQLMaxBodyBytes limits the size of a POST request.
+	graphQLMaxBodyBytes = 1 << 20
+	// graphQLMaxConcurrentLoads limits the storer calls made at the same time for one batch.
+	graphQLMaxConcurrentLoads = 10
+)
+
+var errGraphQLMissingQuery = errors.New("query is required")
+
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// parseGraphQLRequest reads the request as described by https://graphql.org/learn/serving-over-http/.
+// GET requests can be cached by the cache middleware. POST requests are never cached.
+func parseGraphQLRequest(r *http.Request) (*graphQLRequest, error) {
+	var req graphQLRequest
+	if r.Method == http.MethodGet {
+		params := r.URL.Query()
+		req.Query = params.Get("query")
+		req.OperationName = params.Get("operationName")
+		if variables := params.Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				return nil, errors.New("variables must be a JSON object")
+			}
+		}
+	} else {
+		decoder := json.NewDecoder(io.LimitReader(r.Body, graphQLMaxBodyBytes))
+		if err := decoder.Decode(&req); err != nil {
+			return nil, errors.New("request body must be a JSON object")
+		}
+	}
+	if req.Query == "" {
+		return nil, errGraphQLMissingQuery
+	}
+
+	return &req, nil
+}
+
+// graphQLHandler serves the GraphQL API. See graphql_schema.graphql.
+type graphQLHandler struct {
+	schema *graphql.Schema
+	server *Server
+}
+
+func newGraphQLHandler(s *Server) (*graphQLHandler, error) {
+	schema, err := graphql.ParseSchema(graphQLSchema, &graphQLResolver{s: s},
+		graphql.UseStringDescriptions(),
+		graphql.UseFieldResolvers(),
+		graphql.MaxDepth(graphQLMaxDepth),
+		graphql.MaxParallelism(graphQLMaxParallelism),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &graphQLHandler{schema: schema, server: s}, nil
+}
+
+func writeGraphQLResponse(w http.ResponseWriter, statusCode int, resp *graphql.Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		slog.Error("unable to write graphql response", "error", err)
+	}
+}
+
+func (h *graphQLHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req, err := parseGraphQLRequest(r)
+	if err != nil {
+		// nolint: exhaustruct // No data or extensions for invalid requests.
+		writeGraphQLResponse(w, http.StatusBadRequest, &graphql.Response{
+			Errors: []*graphqlerrors.QueryError{graphqlerrors.Errorf("%s", err.Error())},
+		})
+
+		return
+	}
+
+	loaders := newGraphQLLoaders(h.server)
+	ctx := context.WithValue(r.Context(), graphQLLoadersKey{}, loaders)
+	resp := h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+
+	// Responses with errors are not cached because only 200 responses are cached.
+	// Internal errors use a 500 so that the clients know the query can be retried.
+	statusCode := http.StatusOK
+	if loaders.internalFailure.Load() {
+		statusCode = http.StatusInternalServerError
+	} else if len(resp.Errors) > 0 {
+		statusCode = http.StatusBadRequest
+	}
+	writeGraphQLResponse(w, statusCode, resp)
+}
+
+// graphQLLoadersKey is the context key of the loaders of a GraphQL request.
+type graphQLLoadersKey struct{}
+
+// graphQLFeatureKey identifies a feature loaded with a metric view.
+type graphQLFeatureKey struct {
+	featureID  string
+	metricView backend.WPTMetricView
+}
+
+// graphQLWPTRunsKey identifies a page of WPT runs of a feature.
+type graphQLWPTRunsKey struct {
+	featureID  string
+	browser    string
+	channel    string
+	metricView backend.WPTMetricView
+	// The times are stored as unix nanoseconds so that equal times are equal keys.
+	startAt   int64
+	endAt     int64
+	pageSize  int
+	pageToken string
+}
+
+type graphQLWPTRunsPage struct {
+	runs          []backend.WPTRunMetric
+	nextPageToken *string
+}
+
+// graphQLLoaders batch and deduplicate the storer calls of one GraphQL request.
+// They are created for each request so that results are never shared between requests.
+type graphQLLoaders struct {
+	features *dataloader.Loader[graphQLFeatureKey, *backend.Feature]
+	metadata *dataloader.Loader[string, *backend.FeatureMetadata]
+	wptRuns  *dataloader.Loader[graphQLWPTRunsKey, *graphQLWPTRunsPage]
+	// internalFailure is set when a storer call fails.
+	internalFailure atomic.Bool
+}
+
+func newGraphQLLoaders(s *Server) *graphQLLoaders {
+	loaders := new(graphQLLoaders)
+	loaders.features = dataloader.NewBatchedLoader(func(
+		ctx context.Context, keys []graphQLFeatureKey) []*dataloader.Result[*backend.Feature] {
+		return loadFeatures(ctx, s, keys)
+	})
+	loaders.metadata = dataloader.NewBatchedLoader(func(
+		ctx context.Context, featureKeys []string) []*dataloader.Result[*backend.FeatureMetadata] {
+		return loadFeatureMetadata(ctx, s, featureKeys)
+	})
+	loaders.wptRuns = dataloader.NewBatchedLoader(func(
+		ctx context.Context, keys []graphQLWPTRunsKey) []*dataloader.Result[*graphQLWPTRunsPage] {
+		return loadWPTRuns(ctx, s, keys)
+	})
+
+	return loaders
+}
+
+// loadFeatures looks up the features of each metric view in one query.
+// Features that do not exist resolve to nil.
+func loadFeatures(
+	ctx context.Context, s *Server, keys []graphQLFeatureKey) []*dataloader.Result[*backend.Feature] {
+	metricViewOf := func(key graphQLFeatureKey) backend.WPTMetricView { return key.metricView }
+
+	return loadGrouped(ctx, keys, metricViewOf, func(
+		ctx context.Context,
+		metricView backend.WPTMetricView,
+		keys []graphQLFeatureKey,
+	) (map[graphQLFeatureKey]*backend.Feature, error) {
+		featureIDs := make([]string, 0, len(keys))
+		for _, key := range keys {
+			featureIDs = append(featureIDs, key.featureID)
+		}
+		features, err := s.wptMetricsStorer.GetFeatures(ctx, featureIDs, metricView, defaultBrowsers())
+		if err != nil {
+			return nil, err
+		}
+		loaded := make(map[graphQLFeatureKey]*backend.Feature, len(keys))
+		for _, key := range keys {
+			loaded[key] = features[key.featureID]
+		}
+
+		return loaded, nil
+	})
+}
+
+// loadWPTRuns looks up the first pages of the features that share the other arguments in one query.
+// Later pages continue from a cursor of a single feature, so they are looked up one at a time.
+func loadWPTRuns(
+	ctx context.Context, s *Server, keys []graphQLWPTRunsKey) []*dataloader.Result[*graphQLWPTRunsPage] {
+	groupOf := func(key graphQLWPTRunsKey) graphQLWPTRunsKey {
+		if key.pageToken == "" {
+			key.featureID = ""
+		}
+
+		return key
+	}
+
+	return loadGrouped(ctx, keys, groupOf, func(
+		ctx context.Context,
+		group graphQLWPTRunsKey,
+		keys []graphQLWPTRunsKey,
+	) (map[graphQLWPTRunsKey]*graphQLWPTRunsPage, error) {
+		if group.pageToken != "" {
+			runs, nextPageToken, err := s.wptMetricsStorer.ListMetricsForFeatureIDBrowserAndChannel(
+				ctx, group.featureID, group.browser, group.channel, group.metricView,
+				unixNanoToTime(group.startAt), unixNanoToTime(group.endAt), group.pageSize, &group.pageToken)
+			if err != nil {
+				return nil, err
+			}
+
+			return map[graphQLWPTRunsKey]*graphQLWPTRunsPage{
+				group: {runs: runs, nextPageToken: nextPageToken},
+			}, nil
+		}
+
+		featureIDs := make([]string, 0, len(keys))
+		for _, key := range keys {
+			featureIDs = append(featureIDs, key.featureID)
+		}
+		pages, err := s.wptMetricsStorer.ListFirstMetricsPageForFeatureIDsBrowserAndChannel(
+			ctx, featureIDs, group.browser, group.channel, group.metricView,
+			unixNanoToTime(group.startAt), unixNanoToTime(group.endAt), group.pageSize)
+		if err != nil {
+			return nil, err
+		}
+		loaded := make(map[graphQLWPTRunsKey]*graphQLWPTRunsPage, len(keys))
+		for _, key := range keys {
+			// Features that do not exist have no runs.
+			loaded[key] = &graphQLWPTRunsPage{runs: nil, nextPageToken: nil}
+			if page, found := pages[key.featureID]; found {
+				loaded[key].runs = page.Data
+				if page.Metadata != nil {
+					loaded[key].nextPageToken = page.Metadata.NextPageToken
+				}
+			}
+		}
+
+		return loaded, nil
+	})
+}
+
+// loadFeatureMetadata looks up the IDs of every feature key in one query, and then
+// the metadata of each ID. Feature keys that do not exist resolve to nil.
+func loadFeatureMetadata(
+	ctx context.Context, s *Server, featureKeys []string) []*dataloader.Result[*backend.FeatureMetadata] {
+	ids, err := s.wptMetricsStorer.GetIDsFromFeatureKeys(ctx, featureKeys)
+	if err != nil {
+		results := make([]*dataloader.Result[*backend.FeatureMetadata], len(featureKeys))
+		for idx := range results {
+			results[idx] = &dataloader.Result[*backend.FeatureMetadata]{Data: nil, Error: err}
+		}
+
+		return results
+	}
+
+	return loadEach(ctx, featureKeys, func(ctx context.Context, featureKey string) (*backend.FeatureMetadata, error) {
+		id, found := ids[featureKey]
+		if !found {
+			// nolint: nilnil // Metadata of a missing feature resolves to null.
+			return nil, nil
+		}
+
+		return s.metadataStorer.GetFeatureMetadata(ctx, id)
+	})
+}
+
+// loadGrouped loads the keys of a batch with one storer call per group of keys.
+// The groups are loaded concurrently. Keys that are missing from the loaded map resolve to the zero value.
+func loadGrouped[K comparable, G comparable, V any](
+	ctx context.Context,
+	keys []K,
+	groupOf func(K) G,
+	load func(context.Context, G, []K) (map[K]V, error),
+) []*dataloader.Result[V] {
+	var groups []G
+	groupKeys := make(map[G][]K)
+	for _, key := range keys {
+		group := groupOf(key)
+		if _, found := groupKeys[group]; !found {
+			groups = append(groups, group)
+		}
+		groupKeys[group] = append(groupKeys[group], key)
+	}
+
+	groupResults := loadEach(ctx, groups, func(ctx context.Context, group G) (map[K]V, error) {
+		return load(ctx, group, groupKeys[group])
+	})
+	loaded := make(map[G]*dataloader.Result[map[K]V], len(groups))
+	for idx, group := range groups {
+		loaded[group] = groupResults[idx]
+	}
+
+	results := make([]*dataloader.Result[V], len(keys))
+	for idx, key := range keys {
+		groupResult := loaded[groupOf(key)]
+		results[idx] = &dataloader.Result[V]{Data: groupResult.Data[key], Error: groupResult.Error}
+	}
+
+	return results
+}
+
+// loadEach loads the keys of a batch concurrently, for storers that look up one key at a time.
+// The loaders already deduplicate the keys, so each key is only loaded once per request.
+func loadEach[K comparable, V any](
+	ctx context.Context, keys []K, load func(context.Context, K) (V, error)) []*dataloader.Result[V] {
+	results := make([]*dataloader.Result[V], len(keys))
+	semaphore := make(chan struct{}, graphQLMaxConcurrentLoads)
+	var wg sync.WaitGroup
+	for idx, key := range keys {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			data, err := load(ctx, key)
+			results[idx] = &dataloader.Result[V]{Data: data, Error: err}
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+// graphQLLoadersFromContext returns the loaders of the request.
+func graphQLLoadersFromContext(ctx context.Context) *graphQLLoaders {
+	// The handler always adds the loaders before executing a query.
+	return ctx.Value(graphQLLoadersKey{}).(*graphQLLoaders)
+}
+
+// graphQLInternalError logs a storer error and returns an error that is safe to show to clients.
+func graphQLInternalError(ctx context.Context, message string, err error) error {
+	slog.ErrorContext(ctx, message, "error", err)
+	graphQLLoadersFromContext(ctx).internalFailure.Store(true)
+
+	return errors.New(message)
+}
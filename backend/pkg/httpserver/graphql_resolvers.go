@@ -0,0 +1,509 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/GoogleChrome/webstatus.dev/lib/gcpspanner/searchtypes"
+	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/backend"
+	"github.com/graph-gophers/graphql-go"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+var errGraphQLInvalidQuery = errors.New("query string does not match expected grammar")
+
+// The enum values of the schema are the upper case values of the REST API.
+func graphQLEnumToREST(value string) string {
+	return strings.ToLower(value)
+}
+
+func restToGraphQLEnum(value string) string {
+	return strings.ToUpper(value)
+}
+
+// graphQLInt converts a count to the 32 bit Int of GraphQL.
+func graphQLInt(value int64) int32 {
+	return int32(min(max(value, math.MinInt32), math.MaxInt32))
+}
+
+func graphQLIntPtr(value *int64) *int32 {
+	if value == nil {
+		return nil
+	}
+	ret := graphQLInt(*value)
+
+	return &ret
+}
+
+func graphQLDate(date *openapi_types.Date) *string {
+	if date == nil {
+		return nil
+	}
+	ret := date.Format(time.DateOnly)
+
+	return &ret
+}
+
+func graphQLPageSize(first int32) int {
+	pageSize := int(first)
+
+	return getPageSizeOrDefault(&pageSize)
+}
+
+func unixNanoToTime(nanos int64) time.Time {
+	return time.Unix(0, nanos).UTC()
+}
+
+func parseGraphQLSearchQuery(query *string) (*searchtypes.SearchNode, error) {
+	if query == nil {
+		// nolint: nilnil // No query matches every feature.
+		return nil, nil
+	}
+	parser := searchtypes.FeaturesSearchQueryParser{}
+	node, err := parser.Parse(*query)
+	if err != nil {
+		return nil, errGraphQLInvalidQuery
+	}
+
+	return node, nil
+}
+
+// graphQLResolver resolves the Query type.
+type graphQLResolver struct {
+	s *Server
+}
+
+type graphQLFeatureArgs struct {
+	ID            graphql.ID
+	WptMetricView string
+}
+
+func (r *graphQLResolver) Feature(ctx context.Context, args graphQLFeatureArgs) (*graphQLFeature, error) {
+	return loadGraphQLFeature(ctx, string(args.ID), backend.WPTMetricView(graphQLEnumToREST(args.WptMetricView)))
+}
+
+type graphQLFeaturesArgs struct {
+	Query         *string
+	Sort          *string
+	First         int32
+	After         *string
+	WptMetricView string
+}
+
+func (r *graphQLResolver) Features(ctx context.Context, args graphQLFeaturesArgs) (*graphQLFeatureConnection, error) {
+	node, err := parseGraphQLSearchQuery(args.Query)
+	if err != nil {
+		return nil, err
+	}
+	page, err := r.s.wptMetricsStorer.FeaturesSearch(
+		ctx,
+		args.After,
+		graphQLPageSize(args.First),
+		node,
+		(*backend.GetV1FeaturesParamsSort)(args.Sort),
+		backend.WPTMetricView(graphQLEnumToREST(args.WptMetricView)),
+		defaultBrowsers(),
+		nil,
+		nil,
+	)
+	if err != nil {
+		return nil, graphQLInternalError(ctx, "unable to get list of features", err)
+	}
+	nodes := make([]*graphQLFeature, 0, len(page.Data))
+	for _, feature := range page.Data {
+		nodes = append(nodes, newGraphQLFeature(feature))
+	}
+
+	return &graphQLFeatureConnection{
+		Nodes:         nodes,
+		Total:         graphQLInt(page.Metadata.Total),
+		NextPageToken: page.Metadata.NextPageToken,
+	}, nil
+}
+
+type graphQLBrowserReleasesArgs struct {
+	Browser string
+	From    graphql.Time
+	To      graphql.Time
+	First   int32
+	After   *string
+}
+
+func (r *graphQLResolver) BrowserReleases(
+	ctx context.Context, args graphQLBrowserReleasesArgs) (*graphQLBrowserReleaseConnection, error) {
+	page, err := r.s.wptMetricsStorer.ListBrowserFeatureCountMetric(
+		ctx,
+		graphQLEnumToREST(args.Browser),
+		args.From.Time,
+		args.To.Time,
+		graphQLPageSize(args.First),
+		args.After,
+	)
+	if err != nil {
+		return nil, graphQLInternalError(ctx, "unable to get feature support metrics", err)
+	}
+	nodes := make([]*graphQLBrowserRelease, 0, len(page.Data))
+	for _, metric := range page.Data {
+		nodes = append(nodes, &graphQLBrowserRelease{
+			Timestamp:    graphql.Time{Time: metric.Timestamp},
+			FeatureCount: graphQLIntPtr(metric.Count),
+		})
+	}
+	var nextPageToken *string
+	if page.Metadata != nil {
+		nextPageToken = page.Metadata.NextPageToken
+	}
+
+	return &graphQLBrowserReleaseConnection{Nodes: nodes, NextPageToken: nextPageToken}, nil
+}
+
+func (r *graphQLResolver) Stats() *graphQLStats {
+	return &graphQLStats{s: r.s}
+}
+
+func loadGraphQLFeature(
+	ctx context.Context, featureID string, metricView backend.WPTMetricView) (*graphQLFeature, error) {
+	feature, err := graphQLLoadersFromContext(ctx).features.Load(ctx, graphQLFeatureKey{
+		featureID:  featureID,
+		metricView: metricView,
+	})()
+	if err != nil {
+		return nil, graphQLInternalError(ctx, "unable to get feature", err)
+	}
+	if feature == nil {
+		// nolint: nilnil // A missing feature resolves to null.
+		return nil, nil
+	}
+
+	return newGraphQLFeature(*feature), nil
+}
+
+type graphQLFeature struct {
+	ID                     graphql.ID
+	Name                   string
+	RedirectedFrom         *string
+	Baseline               *graphQLBaseline
+	BrowserImplementations []*graphQLBrowserImplementation
+	SpecLinks              []string
+	Usage                  *float64
+	wpt                    *backend.FeatureWPTSnapshots
+}
+
+type graphQLBaseline struct {
+	Status   *string
+	LowDate  *string
+	HighDate *string
+}
+
+type graphQLBrowserImplementation struct {
+	Browser string
+	Status  *string
+	Date    *string
+}
+
+func newGraphQLFeature(feature backend.Feature) *graphQLFeature {
+	ret := &graphQLFeature{
+		ID:                     graphql.ID(feature.FeatureId),
+		Name:                   feature.Name,
+		RedirectedFrom:         feature.RedirectedFrom,
+		Baseline:               nil,
+		BrowserImplementations: []*graphQLBrowserImplementation{},
+		SpecLinks:              []string{},
+		Usage:                  nil,
+		wpt:                    feature.Wpt,
+	}
+	if feature.Baseline != nil {
+		ret.Baseline = &graphQLBaseline{
+			Status:   nil,
+			LowDate:  graphQLDate(feature.Baseline.LowDate),
+			HighDate: graphQLDate(feature.Baseline.HighDate),
+		}
+		if feature.Baseline.Status != nil {
+			status := restToGraphQLEnum(string(*feature.Baseline.Status))
+			ret.Baseline.Status = &status
+		}
+	}
+	if feature.BrowserImplementations != nil {
+		for browser, impl := range *feature.BrowserImplementations {
+			var status *string
+			if impl.Status != nil {
+				implStatus := restToGraphQLEnum(string(*impl.Status))
+				status = &implStatus
+			}
+			ret.BrowserImplementations = append(ret.BrowserImplementations, &graphQLBrowserImplementation{
+				Browser: browser,
+				Status:  status,
+				Date:    graphQLDate(impl.Date),
+			})
+		}
+		// Map iteration order is random.
+		slices.SortFunc(ret.BrowserImplementations, func(a, b *graphQLBrowserImplementation) int {
+			return cmp.Compare(a.Browser, b.Browser)
+		})
+	}
+	if feature.Spec != nil && feature.Spec.Links != nil {
+		for _, link := range *feature.Spec.Links {
+			if link.Link != nil {
+				ret.SpecLinks = append(ret.SpecLinks, *link.Link)
+			}
+		}
+	}
+	if feature.Usage != nil {
+		usage := float64(*feature.Usage)
+		ret.Usage = &usage
+	}
+
+	return ret
+}
+
+type graphQLWPTScore struct {
+	Browser string
+	Score   *float64
+}
+
+func (f *graphQLFeature) Wpt(args struct{ Channel string }) []*graphQLWPTScore {
+	var snapshot *map[string]backend.WPTFeatureData
+	if f.wpt != nil {
+		switch backend.ChannelPathParam(graphQLEnumToREST(args.Channel)) {
+		case backend.Stable:
+			snapshot = f.wpt.Stable
+		case backend.Experimental:
+			snapshot = f.wpt.Experimental
+		}
+	}
+	scores := []*graphQLWPTScore{}
+	if snapshot == nil {
+		return scores
+	}
+	for browser, data := range *snapshot {
+		scores = append(scores, &graphQLWPTScore{Browser: browser, Score: data.Score})
+	}
+	// Map iteration order is random.
+	slices.SortFunc(scores, func(a, b *graphQLWPTScore) int {
+		return cmp.Compare(a.Browser, b.Browser)
+	})
+
+	return scores
+}
+
+type graphQLFeatureMetadata struct {
+	Description *string
+	CanIUseIDs  []string
+}
+
+func (f *graphQLFeature) Metadata(ctx context.Context) (*graphQLFeatureMetadata, error) {
+	metadata, err := graphQLLoadersFromContext(ctx).metadata.Load(ctx, string(f.ID))()
+	if err != nil {
+		return nil, graphQLInternalError(ctx, "unable to get feature metadata", err)
+	}
+	if metadata == nil {
+		// nolint: nilnil // The feature was removed after it was loaded.
+		return nil, nil
+	}
+	ret := &graphQLFeatureMetadata{
+		Description: metadata.Description,
+		CanIUseIDs:  []string{},
+	}
+	if metadata.CanIUse != nil && metadata.CanIUse.Items != nil {
+		for _, item := range *metadata.CanIUse.Items {
+			if item.Id != nil {
+				ret.CanIUseIDs = append(ret.CanIUseIDs, *item.Id)
+			}
+		}
+	}
+
+	return ret, nil
+}
+
+type graphQLWPTRunsArgs struct {
+	Browser    string
+	Channel    string
+	MetricView string
+	From       graphql.Time
+	To         graphql.Time
+	First      int32
+	After      *string
+}
+
+func (f *graphQLFeature) WptRuns(ctx context.Context, args graphQLWPTRunsArgs) (*graphQLWPTRunConnection, error) {
+	var pageToken string
+	if args.After != nil {
+		pageToken = *args.After
+	}
+	page, err := graphQLLoadersFromContext(ctx).wptRuns.Load(ctx, graphQLWPTRunsKey{
+		featureID:  string(f.ID),
+		browser:    graphQLEnumToREST(args.Browser),
+		channel:    graphQLEnumToREST(args.Channel),
+		metricView: backend.WPTMetricView(graphQLEnumToREST(args.MetricView)),
+		startAt:    args.From.UnixNano(),
+		endAt:      args.To.UnixNano(),
+		pageSize:   graphQLPageSize(args.First),
+		pageToken:  pageToken,
+	})()
+	if err != nil {
+		return nil, graphQLInternalError(ctx, "unable to get feature metrics", err)
+	}
+
+	return newGraphQLWPTRunConnection(page.runs, page.nextPageToken), nil
+}
+
+type graphQLWPTRun struct {
+	Timestamp       graphql.Time
+	TotalTestsCount *int32
+	TestPassCount   *int32
+}
+
+type graphQLWPTRunConnection struct {
+	Nodes         []*graphQLWPTRun
+	NextPageToken *string
+}
+
+func newGraphQLWPTRunConnection(runs []backend.WPTRunMetric, nextPageToken *string) *graphQLWPTRunConnection {
+	nodes := make([]*graphQLWPTRun, 0, len(runs))
+	for _, run := range runs {
+		nodes = append(nodes, &graphQLWPTRun{
+			Timestamp:       graphql.Time{Time: run.RunTimestamp},
+			TotalTestsCount: graphQLIntPtr(run.TotalTestsCount),
+			TestPassCount:   graphQLIntPtr(run.TestPassCount),
+		})
+	}
+
+	return &graphQLWPTRunConnection{Nodes: nodes, NextPageToken: nextPageToken}
+}
+
+type graphQLFeatureConnection struct {
+	Nodes         []*graphQLFeature
+	Total         int32
+	NextPageToken *string
+}
+
+type graphQLBrowserRelease struct {
+	Timestamp    graphql.Time
+	FeatureCount *int32
+}
+
+type graphQLBrowserReleaseConnection struct {
+	Nodes         []*graphQLBrowserRelease
+	NextPageToken *string
+}
+
+// graphQLStats resolves the Stats type.
+type graphQLStats struct {
+	s *Server
+}
+
+type graphQLAggregatedWPTRunsArgs struct {
+	graphQLWPTRunsArgs
+	FeatureIds *[]graphql.ID
+}
+
+func (st *graphQLStats) WptRuns(
+	ctx context.Context, args graphQLAggregatedWPTRunsArgs) (*graphQLWPTRunConnection, error) {
+	var featureIDs []string
+	if args.FeatureIds != nil {
+		for _, id := range *args.FeatureIds {
+			featureIDs = append(featureIDs, string(id))
+		}
+	}
+	runs, nextPageToken, err := st.s.wptMetricsStorer.ListMetricsOverTimeWithAggregatedTotals(
+		ctx,
+		featureIDs,
+		graphQLEnumToREST(args.Browser),
+		graphQLEnumToREST(args.Channel),
+		backend.WPTMetricView(graphQLEnumToREST(args.MetricView)),
+		args.From.Time,
+		args.To.Time,
+		graphQLPageSize(args.First),
+		args.After,
+	)
+	if err != nil {
+		return nil, graphQLInternalError(ctx, "unable to get wpt metrics", err)
+	}
+
+	return newGraphQLWPTRunConnection(runs, nextPageToken), nil
+}
+
+type graphQLTimeToBaselinePercentile struct {
+	Percentile int32
+	Days       int32
+}
+
+type graphQLTimeToBaselineStats struct {
+	Features                         []*graphQLFeatureTimeToBaseline
+	DaysToBaselineLowPercentiles     []*graphQLTimeToBaselinePercentile
+	DaysBaselineLowToHighPercentiles []*graphQLTimeToBaselinePercentile
+}
+
+func newGraphQLTimeToBaselinePercentiles(
+	percentiles []backend.TimeToBaselinePercentile) []*graphQLTimeToBaselinePercentile {
+	ret := make([]*graphQLTimeToBaselinePercentile, 0, len(percentiles))
+	for _, percentile := range percentiles {
+		ret = append(ret, &graphQLTimeToBaselinePercentile{
+			Percentile: graphQLInt(int64(percentile.Percentile)),
+			Days:       graphQLInt(percentile.Days),
+		})
+	}
+
+	return ret
+}
+
+func (st *graphQLStats) TimeToBaseline(
+	ctx context.Context, args struct{ Query *string }) (*graphQLTimeToBaselineStats, error) {
+	node, err := parseGraphQLSearchQuery(args.Query)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := st.s.wptMetricsStorer.ListFeatureTimeToBaseline(ctx, node)
+	if err != nil {
+		return nil, graphQLInternalError(ctx, "unable to get time to baseline stats", err)
+	}
+	features := make([]*graphQLFeatureTimeToBaseline, 0, len(stats.Data))
+	for _, feature := range stats.Data {
+		features = append(features, &graphQLFeatureTimeToBaseline{
+			featureID:             feature.FeatureId,
+			FirstAvailableDate:    graphQLDate(feature.FirstAvailableDate),
+			BaselineLowDate:       graphQLDate(feature.BaselineLowDate),
+			BaselineHighDate:      graphQLDate(feature.BaselineHighDate),
+			DaysToBaselineLow:     graphQLIntPtr(feature.DaysToBaselineLow),
+			DaysBaselineLowToHigh: graphQLIntPtr(feature.DaysBaselineLowToHigh),
+		})
+	}
+
+	return &graphQLTimeToBaselineStats{
+		Features:                         features,
+		DaysToBaselineLowPercentiles:     newGraphQLTimeToBaselinePercentiles(stats.DaysToBaselineLowPercentiles),
+		DaysBaselineLowToHighPercentiles: newGraphQLTimeToBaselinePercentiles(stats.DaysBaselineLowToHighPercentiles),
+	}, nil
+}
+
+type graphQLFeatureTimeToBaseline struct {
+	featureID             string
+	FirstAvailableDate    *string
+	BaselineLowDate       *string
+	BaselineHighDate      *string
+	DaysToBaselineLow     *int32
+	DaysBaselineLowToHigh *int32
+}
+
+func (f *graphQLFeatureTimeToBaseline) Feature(ctx context.Context) (*graphQLFeature, error) {
+	return loadGraphQLFeature(ctx, f.featureID, getWPTMetricViewOrDefault(nil))
+}
@@ -0,0 +1,544 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GoogleChrome/webstatus.dev/lib/gcpspanner/searchtypes"
+	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/backend"
+)
+
+// fakeGraphQLStorer is safe for concurrent use because the GraphQL resolvers load fields in parallel.
+// Methods that the tests do not use panic through the nil embedded interface.
+type fakeGraphQLStorer struct {
+	WPTMetricsStorer
+	mu       sync.Mutex
+	features map[string]backend.Feature
+	// ids maps feature keys to feature IDs.
+	ids      map[string]string
+	metadata map[string]backend.FeatureMetadata
+	// runs maps feature keys to their WPT runs.
+	runs map[string][]backend.WPTRunMetric
+	err  error
+
+	getFeaturesCalls           [][]string
+	getIDsFromFeatureKeysCalls [][]string
+	getFeatureMetadataCalls    []string
+	listFirstMetricsPageCalls  [][]string
+	listMetricsCalls           []string
+}
+
+func (f *fakeGraphQLStorer) FeaturesSearch(
+	_ context.Context,
+	_ *string,
+	_ int,
+	_ *searchtypes.SearchNode,
+	_ *backend.GetV1FeaturesParamsSort,
+	_ backend.WPTMetricView,
+	_ []backend.BrowserPathParam,
+	_ *time.Time,
+	_ []string,
+) (*backend.FeaturePage, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	keys := make([]string, 0, len(f.features))
+	for key := range f.features {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	page := &backend.FeaturePage{
+		Metadata: backend.PageMetadataWithTotal{
+			NextPageToken: nil,
+			Total:         int64(len(keys)),
+		},
+		Data:   []backend.Feature{},
+		Facets: nil,
+	}
+	for _, key := range keys {
+		page.Data = append(page.Data, f.features[key])
+	}
+
+	return page, nil
+}
+
+func (f *fakeGraphQLStorer) GetFeatures(
+	_ context.Context,
+	featureIDs []string,
+	_ backend.WPTMetricView,
+	_ []backend.BrowserPathParam,
+) (map[string]*backend.Feature, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.getFeaturesCalls = append(f.getFeaturesCalls, slices.Clone(featureIDs))
+	if f.err != nil {
+		return nil, f.err
+	}
+	features := make(map[string]*backend.Feature)
+	for _, featureID := range featureIDs {
+		if feature, found := f.features[featureID]; found {
+			features[featureID] = &feature
+		}
+	}
+
+	return features, nil
+}
+
+func (f *fakeGraphQLStorer) ListFirstMetricsPageForFeatureIDsBrowserAndChannel(
+	_ context.Context,
+	featureIDs []string,
+	_ string,
+	_ string,
+	_ backend.WPTMetricView,
+	_ time.Time,
+	_ time.Time,
+	pageSize int,
+) (map[string]*backend.WPTRunMetricsPage, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.listFirstMetricsPageCalls = append(f.listFirstMetricsPageCalls, slices.Clone(featureIDs))
+	if f.err != nil {
+		return nil, f.err
+	}
+	pages := make(map[string]*backend.WPTRunMetricsPage)
+	for _, featureID := range featureIDs {
+		runs, found := f.runs[featureID]
+		if !found {
+			continue
+		}
+		var nextPageToken *string
+		if len(runs) > pageSize {
+			runs = runs[:pageSize]
+			nextPageToken = valuePtr(featureID + "-token")
+		}
+		pages[featureID] = &backend.WPTRunMetricsPage{
+			Data:     runs,
+			Metadata: &backend.PageMetadata{NextPageToken: nextPageToken},
+		}
+	}
+
+	return pages, nil
+}
+
+func (f *fakeGraphQLStorer) ListMetricsForFeatureIDBrowserAndChannel(
+	_ context.Context,
+	featureID string,
+	_ string,
+	_ string,
+	_ backend.WPTMetricView,
+	_ time.Time,
+	_ time.Time,
+	pageSize int,
+	_ *string,
+) ([]backend.WPTRunMetric, *string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.listMetricsCalls = append(f.listMetricsCalls, featureID)
+	if f.err != nil {
+		return nil, nil, f.err
+	}
+	runs := f.runs[featureID]
+	if len(runs) > pageSize {
+		runs = runs[pageSize:]
+	}
+
+	return runs, nil, nil
+}
+
+func (f *fakeGraphQLStorer) GetIDsFromFeatureKeys(
+	_ context.Context,
+	featureKeys []string,
+) (map[string]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.getIDsFromFeatureKeysCalls = append(f.getIDsFromFeatureKeysCalls, slices.Clone(featureKeys))
+	if f.err != nil {
+		return nil, f.err
+	}
+	ids := make(map[string]string)
+	for _, key := range featureKeys {
+		if id, found := f.ids[key]; found {
+			ids[key] = id
+		}
+	}
+
+	return ids, nil
+}
+
+func (f *fakeGraphQLStorer) GetFeatureMetadata(
+	_ context.Context,
+	featureID string,
+) (*backend.FeatureMetadata, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.getFeatureMetadataCalls = append(f.getFeatureMetadataCalls, featureID)
+	metadata := f.metadata[featureID]
+
+	return &metadata, nil
+}
+
+func newFakeGraphQLStorer() *fakeGraphQLStorer {
+	// nolint: exhaustruct // Call records start empty.
+	return &fakeGraphQLStorer{
+		features: map[string]backend.Feature{
+			"feature1": {
+				FeatureId: "feature1",
+				Name:      "Feature 1",
+				Baseline: &backend.BaselineInfo{
+					Status:   valuePtr(backend.Widely),
+					LowDate:  nil,
+					HighDate: nil,
+				},
+				BrowserImplementations: nil,
+				Spec:                   nil,
+				Usage:                  valuePtr[float32](0.5),
+				Wpt:                    nil,
+				RedirectedFrom:         nil,
+			},
+			"feature2": {
+				FeatureId:              "feature2",
+				Name:                   "Feature 2",
+				Baseline:               nil,
+				BrowserImplementations: nil,
+				Spec:                   nil,
+				Usage:                  nil,
+				Wpt:                    nil,
+				RedirectedFrom:         nil,
+			},
+		},
+		ids: map[string]string{
+			"feature1": "id1",
+			"feature2": "id2",
+		},
+		metadata: map[string]backend.FeatureMetadata{
+			"id1": {Description: valuePtr("desc1"), CanIUse: nil},
+			"id2": {
+				Description: nil,
+				CanIUse: &backend.CanIUseInfo{
+					Items: &[]backend.CanIUseItem{{Id: valuePtr("caniuse2")}},
+				},
+			},
+		},
+		runs: map[string][]backend.WPTRunMetric{
+			"feature1": {
+				{
+					RunTimestamp:    time.Date(2000, time.January, 2, 0, 0, 0, 0, time.UTC),
+					TestPassCount:   valuePtr[int64](2),
+					TotalTestsCount: valuePtr[int64](4),
+				},
+				{
+					RunTimestamp:    time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+					TestPassCount:   valuePtr[int64](1),
+					TotalTestsCount: valuePtr[int64](4),
+				},
+			},
+			"feature2": {
+				{
+					RunTimestamp:    time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+					TestPassCount:   nil,
+					TotalTestsCount: nil,
+				},
+			},
+		},
+		err: nil,
+	}
+}
+
+func executeGraphQLTestQuery(t *testing.T, storer *fakeGraphQLStorer, query string) (int, map[string]any) {
+	// nolint: exhaustruct // Only the storers used by the GraphQL API are needed.
+	handler, err := newGraphQLHandler(&Server{
+		wptMetricsStorer: storer,
+		metadataStorer:   storer,
+	})
+	if err != nil {
+		t.Fatalf("unable to create graphql handler: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/v1/graphql?query="+url.QueryEscape(query), nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	var body map[string]any
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("unable to decode response %s: %v", rr.Body.String(), err)
+	}
+
+	return rr.Code, body
+}
+
+func decodeJSONForTest(t *testing.T, data string) map[string]any {
+	var ret map[string]any
+	if err := json.Unmarshal([]byte(data), &ret); err != nil {
+		t.Fatalf("unable to decode expected data: %v", err)
+	}
+
+	return ret
+}
+
+func TestGraphQLFeaturesWithMetadata(t *testing.T) {
+	storer := newFakeGraphQLStorer()
+	code, body := executeGraphQLTestQuery(t, storer, `{
+		features {
+			total
+			nodes {
+				id
+				name
+				usage
+				baseline { status }
+				metadata { description canIUseIds }
+			}
+		}
+	}`)
+	if code != http.StatusOK {
+		t.Fatalf("unexpected status code %d. body %v", code, body)
+	}
+	expected := decodeJSONForTest(t, `{
+		"features": {
+			"total": 2,
+			"nodes": [
+				{
+					"id": "feature1",
+					"name": "Feature 1",
+					"usage": 0.5,
+					"baseline": {"status": "WIDELY"},
+					"metadata": {"description": "desc1", "canIUseIds": []}
+				},
+				{
+					"id": "feature2",
+					"name": "Feature 2",
+					"usage": null,
+					"baseline": null,
+					"metadata": {"description": null, "canIUseIds": ["caniuse2"]}
+				}
+			]
+		}
+	}`)
+	if !reflect.DeepEqual(body["data"], expected) {
+		t.Errorf("unexpected data\nexpected %v\nreceived %v", expected, body["data"])
+	}
+
+	// The IDs of every feature in the page are looked up together.
+	if len(storer.getIDsFromFeatureKeysCalls) != 1 {
+		t.Fatalf("expected 1 GetIDsFromFeatureKeys call, received %v", storer.getIDsFromFeatureKeysCalls)
+	}
+	keys := storer.getIDsFromFeatureKeysCalls[0]
+	slices.Sort(keys)
+	if !slices.Equal(keys, []string{"feature1", "feature2"}) {
+		t.Errorf("unexpected feature keys %v", keys)
+	}
+	metadataCalls := storer.getFeatureMetadataCalls
+	slices.Sort(metadataCalls)
+	if !slices.Equal(metadataCalls, []string{"id1", "id2"}) {
+		t.Errorf("unexpected metadata calls %v", metadataCalls)
+	}
+}
+
+func TestGraphQLFeature(t *testing.T) {
+	testCases := []struct {
+		name                     string
+		query                    string
+		storerErr                error
+		expectedCode             int
+		expectedData             string
+		expectedGetFeaturesCalls [][]string
+	}{
+		{
+			name:                     "same feature is loaded once",
+			query:                    `{ a: feature(id: "feature1") { id } b: feature(id: "feature1") { name } }`,
+			storerErr:                nil,
+			expectedCode:             http.StatusOK,
+			expectedData:             `{"a": {"id": "feature1"}, "b": {"name": "Feature 1"}}`,
+			expectedGetFeaturesCalls: [][]string{{"feature1"}},
+		},
+		{
+			name:                     "features are loaded together",
+			query:                    `{ a: feature(id: "feature1") { id } b: feature(id: "feature2") { id } }`,
+			storerErr:                nil,
+			expectedCode:             http.StatusOK,
+			expectedData:             `{"a": {"id": "feature1"}, "b": {"id": "feature2"}}`,
+			expectedGetFeaturesCalls: [][]string{{"feature1", "feature2"}},
+		},
+		{
+			name:                     "missing feature",
+			query:                    `{ feature(id: "nope") { id } }`,
+			storerErr:                nil,
+			expectedCode:             http.StatusOK,
+			expectedData:             `{"feature": null}`,
+			expectedGetFeaturesCalls: [][]string{{"nope"}},
+		},
+		{
+			name:                     "storer error",
+			query:                    `{ feature(id: "feature1") { id } }`,
+			storerErr:                errors.New("test error"),
+			expectedCode:             http.StatusInternalServerError,
+			expectedData:             `{"feature": null}`,
+			expectedGetFeaturesCalls: [][]string{{"feature1"}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			storer := newFakeGraphQLStorer()
+			storer.err = tc.storerErr
+			code, body := executeGraphQLTestQuery(t, storer, tc.query)
+			if code != tc.expectedCode {
+				t.Errorf("unexpected status code %d. body %v", code, body)
+			}
+			expected := decodeJSONForTest(t, tc.expectedData)
+			if !reflect.DeepEqual(body["data"], expected) {
+				t.Errorf("unexpected data\nexpected %v\nreceived %v", expected, body["data"])
+			}
+			for _, keys := range storer.getFeaturesCalls {
+				slices.Sort(keys)
+			}
+			if !reflect.DeepEqual(storer.getFeaturesCalls, tc.expectedGetFeaturesCalls) {
+				t.Errorf("unexpected GetFeatures calls %v", storer.getFeaturesCalls)
+			}
+		})
+	}
+}
+
+func TestGraphQLWPTRuns(t *testing.T) {
+	storer := newFakeGraphQLStorer()
+	code, body := executeGraphQLTestQuery(t, storer, `{
+		a: feature(id: "feature1") {
+			wptRuns(browser: CHROME, from: "2000-01-01T00:00:00Z", to: "2000-02-01T00:00:00Z", first: 1) {
+				nodes { timestamp testPassCount }
+				nextPageToken
+			}
+		}
+		b: feature(id: "feature2") {
+			wptRuns(browser: CHROME, from: "2000-01-01T00:00:00Z", to: "2000-02-01T00:00:00Z", first: 1) {
+				nodes { timestamp testPassCount }
+				nextPageToken
+			}
+		}
+		c: feature(id: "feature1") {
+			wptRuns(browser: CHROME, from: "2000-01-01T00:00:00Z", to: "2000-02-01T00:00:00Z", first: 1,
+				after: "feature1-token") {
+				nodes { timestamp testPassCount }
+				nextPageToken
+			}
+		}
+	}`)
+	if code != http.StatusOK {
+		t.Fatalf("unexpected status code %d. body %v", code, body)
+	}
+	expected := decodeJSONForTest(t, `{
+		"a": {
+			"wptRuns": {
+				"nodes": [{"timestamp": "2000-01-02T00:00:00Z", "testPassCount": 2}],
+				"nextPageToken": "feature1-token"
+			}
+		},
+		"b": {
+			"wptRuns": {
+				"nodes": [{"timestamp": "2000-01-01T00:00:00Z", "testPassCount": null}],
+				"nextPageToken": null
+			}
+		},
+		"c": {
+			"wptRuns": {
+				"nodes": [{"timestamp": "2000-01-01T00:00:00Z", "testPassCount": 1}],
+				"nextPageToken": null
+			}
+		}
+	}`)
+	if !reflect.DeepEqual(body["data"], expected) {
+		t.Errorf("unexpected data\nexpected %v\nreceived %v", expected, body["data"])
+	}
+
+	// The first pages are looked up together. Later pages are looked up one at a time.
+	if len(storer.listFirstMetricsPageCalls) != 1 {
+		t.Fatalf("expected 1 ListFirstMetricsPageForFeatureIDsBrowserAndChannel call, received %v",
+			storer.listFirstMetricsPageCalls)
+	}
+	keys := storer.listFirstMetricsPageCalls[0]
+	slices.Sort(keys)
+	if !slices.Equal(keys, []string{"feature1", "feature2"}) {
+		t.Errorf("unexpected feature keys %v", keys)
+	}
+	if !slices.Equal(storer.listMetricsCalls, []string{"feature1"}) {
+		t.Errorf("unexpected ListMetricsForFeatureIDBrowserAndChannel calls %v", storer.listMetricsCalls)
+	}
+}
+
+func TestGraphQLBadRequests(t *testing.T) {
+	testCases := []struct {
+		name  string
+		query string
+	}{
+		{
+			name:  "missing query",
+			query: "",
+		},
+		{
+			name:  "invalid graphql",
+			query: "{ feature(",
+		},
+		{
+			name:  "unknown field",
+			query: `{ feature(id: "feature1") { nope } }`,
+		},
+		{
+			name:  "invalid search query",
+			query: `{ features(query: "badterm:foo") { total } }`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			code, body := executeGraphQLTestQuery(t, newFakeGraphQLStorer(), tc.query)
+			if code != http.StatusBadRequest {
+				t.Errorf("unexpected status code %d. body %v", code, body)
+			}
+			errs, ok := body["errors"].([]any)
+			if !ok || len(errs) == 0 {
+				t.Errorf("expected errors in body %v", body)
+			}
+		})
+	}
+}
+
+func TestGraphQLPost(t *testing.T) {
+	// nolint: exhaustruct // Only the storers used by the GraphQL API are needed.
+	handler, err := newGraphQLHandler(&Server{
+		wptMetricsStorer: newFakeGraphQLStorer(),
+		metadataStorer:   newFakeGraphQLStorer(),
+	})
+	if err != nil {
+		t.Fatalf("unable to create graphql handler: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/v1/graphql", strings.NewReader(
+		`{"query": "query F($id: ID!) { feature(id: $id) { name } }", "variables": {"id": "feature2"}}`))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("unexpected status code %d. body %s", rr.Code, rr.Body.String())
+	}
+	expected := `{"data":{"feature":{"name":"Feature 2"}}}`
+	if strings.TrimSpace(rr.Body.String()) != expected {
+		t.Errorf("unexpected body %s", rr.Body.String())
+	}
+}
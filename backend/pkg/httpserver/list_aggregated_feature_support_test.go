@@ -177,7 +177,14 @@ func TestListAggregatedFeatureSupport(t *testing.T) {
 				listBrowserFeatureCountMetricCfg: tc.mockConfig,
 				t:                                t,
 			}
-			myServer := Server{wptMetricsStorer: mockStorer, metadataStorer: nil}
+			myServer := Server{
+				wptMetricsStorer:         mockStorer,
+				metadataStorer:           nil,
+				subscriptionStorer:       nil,
+				callbackPolicy:           nil,
+				excludedFeatureKeyStorer: nil,
+				cacheClearer:             nil,
+			}
 
 			// Call the function under test
 			resp, err := myServer.ListAggregatedFeatureSupport(context.Background(), tc.request)
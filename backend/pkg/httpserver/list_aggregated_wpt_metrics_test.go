@@ -173,7 +173,14 @@ func TestListAggregatedWPTMetrics(t *testing.T) {
 				aggregateCfg: tc.mockConfig,
 				t:            t,
 			}
-			myServer := Server{wptMetricsStorer: mockStorer, metadataStorer: nil}
+			myServer := Server{
+				wptMetricsStorer:         mockStorer,
+				metadataStorer:           nil,
+				subscriptionStorer:       nil,
+				callbackPolicy:           nil,
+				excludedFeatureKeyStorer: nil,
+				cacheClearer:             nil,
+			}
 
 			// Call the function under test
 			resp, err := myServer.ListAggregatedWPTMetrics(context.Background(), tc.request)
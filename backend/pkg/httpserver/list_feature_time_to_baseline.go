@@ -0,0 +1,46 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/backend"
+)
+
+// ListFeatureTimeToBaseline implements backend.StrictServerInterface.
+// nolint: ireturn // Expected ireturn for openapi generation.
+func (s *Server) ListFeatureTimeToBaseline(
+	ctx context.Context,
+	request backend.ListFeatureTimeToBaselineRequestObject,
+) (backend.ListFeatureTimeToBaselineResponseObject, error) {
+	node, parseErr := parseSearchQuery(ctx, request.Params.Q)
+	if parseErr != nil {
+		return backend.ListFeatureTimeToBaseline400JSONResponse(*parseErr), nil
+	}
+
+	stats, err := s.wptMetricsStorer.ListFeatureTimeToBaseline(ctx, node)
+	if err != nil {
+		slog.ErrorContext(ctx, "unable to get time to baseline", "error", err)
+
+		return backend.ListFeatureTimeToBaseline500JSONResponse{
+			Code:    500,
+			Message: "unable to get time to baseline",
+		}, nil
+	}
+
+	return backend.ListFeatureTimeToBaseline200JSONResponse(*stats), nil
+}
@@ -0,0 +1,192 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/GoogleChrome/webstatus.dev/lib/gcpspanner/searchtypes"
+	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/backend"
+)
+
+func TestListFeatureTimeToBaseline(t *testing.T) {
+	stats := &backend.TimeToBaselineStats{
+		Data: []backend.FeatureTimeToBaseline{
+			{
+				FeatureId:             "feature1",
+				Name:                  "Feature 1",
+				FirstAvailableDate:    nil,
+				BaselineLowDate:       nil,
+				BaselineHighDate:      nil,
+				DaysToBaselineLow:     valuePtr[int64](10),
+				DaysBaselineLowToHigh: valuePtr[int64](900),
+			},
+		},
+		DaysToBaselineLowPercentiles: []backend.TimeToBaselinePercentile{
+			{Percentile: 50, Days: 10},
+		},
+		DaysBaselineLowToHighPercentiles: []backend.TimeToBaselinePercentile{
+			{Percentile: 50, Days: 900},
+		},
+	}
+	testCases := []struct {
+		name              string
+		mockConfig        MockListFeatureTimeToBaselineConfig
+		expectedCallCount int // For the mock method
+		request           backend.ListFeatureTimeToBaselineRequestObject
+		expectedResponse  backend.ListFeatureTimeToBaselineResponseObject
+		expectedError     error
+	}{
+		{
+			name: "Success Case - no optional params - use defaults",
+			mockConfig: MockListFeatureTimeToBaselineConfig{
+				expectedSearchNode: nil,
+				data:               stats,
+				err:                nil,
+			},
+			expectedCallCount: 1,
+			expectedResponse:  backend.ListFeatureTimeToBaseline200JSONResponse(*stats),
+			request: backend.ListFeatureTimeToBaselineRequestObject{
+				Params: backend.ListFeatureTimeToBaselineParams{
+					Q: nil,
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name: "Success Case - include optional params",
+			mockConfig: MockListFeatureTimeToBaselineConfig{
+				expectedSearchNode: &searchtypes.SearchNode{
+					Keyword: searchtypes.KeywordRoot,
+					Term:    nil,
+					Children: []*searchtypes.SearchNode{
+						{
+							Keyword: searchtypes.KeywordAND,
+							Term:    nil,
+							Children: []*searchtypes.SearchNode{
+								{
+									Children: nil,
+									Term: &searchtypes.SearchTerm{
+										Identifier: searchtypes.IdentifierAvailableOn,
+										Value:      "chrome",
+										Operator:   searchtypes.OperatorEq,
+									},
+									Keyword: searchtypes.KeywordNone,
+								},
+								{
+									Children: nil,
+									Term: &searchtypes.SearchTerm{
+										Identifier: searchtypes.IdentifierName,
+										Value:      "grid",
+										Operator:   searchtypes.OperatorEq,
+									},
+									Keyword: searchtypes.KeywordNone,
+								},
+							},
+						},
+					},
+				},
+				data: stats,
+				err:  nil,
+			},
+			expectedCallCount: 1,
+			expectedResponse:  backend.ListFeatureTimeToBaseline200JSONResponse(*stats),
+			request: backend.ListFeatureTimeToBaselineRequestObject{
+				Params: backend.ListFeatureTimeToBaselineParams{
+					Q: valuePtr(url.QueryEscape("available_on:chrome AND name:grid")),
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name: "500 case",
+			mockConfig: MockListFeatureTimeToBaselineConfig{
+				expectedSearchNode: nil,
+				data:               nil,
+				err:                errTest,
+			},
+			expectedCallCount: 1,
+			expectedResponse: backend.ListFeatureTimeToBaseline500JSONResponse{
+				Code:    500,
+				Message: "unable to get time to baseline",
+			},
+			request: backend.ListFeatureTimeToBaselineRequestObject{
+				Params: backend.ListFeatureTimeToBaselineParams{
+					Q: nil,
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name: "400 case - query string does not match grammar",
+			mockConfig: MockListFeatureTimeToBaselineConfig{
+				expectedSearchNode: nil,
+				data:               nil,
+				err:                nil,
+			},
+			expectedCallCount: 0,
+			expectedResponse: backend.ListFeatureTimeToBaseline400JSONResponse{
+				Code:    400,
+				Message: "query string does not match expected grammar",
+			},
+			request: backend.ListFeatureTimeToBaselineRequestObject{
+				Params: backend.ListFeatureTimeToBaselineParams{
+					Q: valuePtr[string]("badterm:foo"),
+				},
+			},
+			expectedError: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// nolint: exhaustruct
+			mockStorer := &MockWPTMetricsStorer{
+				listFeatureTimeToBaselineConfig: tc.mockConfig,
+				t:                               t,
+			}
+			myServer := Server{
+				wptMetricsStorer:         mockStorer,
+				metadataStorer:           nil,
+				subscriptionStorer:       nil,
+				callbackPolicy:           nil,
+				excludedFeatureKeyStorer: nil,
+				cacheClearer:             nil,
+			}
+
+			// Call the function under test
+			resp, err := myServer.ListFeatureTimeToBaseline(context.Background(), tc.request)
+
+			// Assertions
+			if mockStorer.callCountListFeatureTimeToBaseline != tc.expectedCallCount {
+				t.Errorf("Incorrect call count: expected %d, got %d",
+					tc.expectedCallCount,
+					mockStorer.callCountListFeatureTimeToBaseline)
+			}
+
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("Unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(tc.expectedResponse, resp) {
+				t.Errorf("Unexpected response: %v", resp)
+			}
+		})
+	}
+}
@@ -175,7 +175,14 @@ func TestListFeatureWPTMetrics(t *testing.T) {
 				featureCfg: tc.mockConfig,
 				t:          t,
 			}
-			myServer := Server{wptMetricsStorer: mockStorer, metadataStorer: nil}
+			myServer := Server{
+				wptMetricsStorer:         mockStorer,
+				metadataStorer:           nil,
+				subscriptionStorer:       nil,
+				callbackPolicy:           nil,
+				excludedFeatureKeyStorer: nil,
+				cacheClearer:             nil,
+			}
 
 			// Call the function under test
 			resp, err := myServer.ListFeatureWPTMetrics(context.Background(), tc.request)
@@ -0,0 +1,83 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/backend"
+)
+
+var errUnknownBrowser = errors.New("unknown browser")
+
+// ListInteropWPTMetrics implements backend.StrictServerInterface.
+// nolint: revive, ireturn // Name generated from openapi
+func (s *Server) ListInteropWPTMetrics(
+	ctx context.Context,
+	request backend.ListInteropWPTMetricsRequestObject,
+) (backend.ListInteropWPTMetricsResponseObject, error) {
+	browsers, err := getBrowsersOrDefault(request.Params.Browsers)
+	if err != nil {
+		return backend.ListInteropWPTMetrics400JSONResponse{
+			Code:    400,
+			Message: err.Error(),
+		}, nil
+	}
+	page, err := s.wptMetricsStorer.ListWPTInteropMetrics(
+		ctx,
+		getFeatureIDsOrDefault(request.Params.FeatureIds),
+		browsers,
+		string(request.Channel),
+		request.MetricView,
+		request.Params.StartAt.Time,
+		request.Params.EndAt.Time,
+		getPageSizeOrDefault(request.Params.PageSize),
+		request.Params.PageToken,
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, "unable to get interop metrics", "error", err)
+
+		return backend.ListInteropWPTMetrics500JSONResponse{
+			Code:    500,
+			Message: "unable to get interop metrics",
+		}, nil
+	}
+
+	return backend.ListInteropWPTMetrics200JSONResponse(*page), nil
+}
+
+// getBrowsersOrDefault validates the requested browsers and removes duplicates.
+// If no browsers are requested, all of the supported browsers are returned.
+func getBrowsersOrDefault(browsers *[]string) ([]backend.BrowserPathParam, error) {
+	if browsers == nil || len(*browsers) == 0 {
+		return defaultBrowsers(), nil
+	}
+	supported := defaultBrowsers()
+	ret := make([]backend.BrowserPathParam, 0, len(*browsers))
+	for _, browser := range *browsers {
+		b := backend.BrowserPathParam(browser)
+		if !slices.Contains(supported, b) {
+			return nil, fmt.Errorf("%w: %s", errUnknownBrowser, browser)
+		}
+		ret = append(ret, b)
+	}
+	slices.Sort(ret)
+
+	return slices.Compact(ret), nil
+}
@@ -0,0 +1,294 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/backend"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+func TestListInteropWPTMetrics(t *testing.T) {
+	testCases := []struct {
+		name              string
+		mockConfig        MockListWPTInteropMetricsConfig
+		expectedCallCount int // For the mock method
+		request           backend.ListInteropWPTMetricsRequestObject
+		expectedResponse  backend.ListInteropWPTMetricsResponseObject
+		expectedError     error
+	}{
+		{
+			name: "Success Case - no optional params - use defaults",
+			mockConfig: MockListWPTInteropMetricsConfig{
+				expectedFeatureIDs: []string{},
+				expectedBrowsers:   defaultBrowsers(),
+				expectedChannel:    "stable",
+				expectedMetric:     backend.SubtestCounts,
+				expectedStartAt:    time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+				expectedEndAt:      time.Date(2000, time.January, 10, 0, 0, 0, 0, time.UTC),
+				expectedPageSize:   100,
+				expectedPageToken:  nil,
+				err:                nil,
+				page: &backend.WPTInteropMetricsPage{
+					Data: []backend.WPTRunMetric{
+						{
+							RunTimestamp:    time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+							TestPassCount:   valuePtr[int64](1),
+							TotalTestsCount: valuePtr[int64](2),
+						},
+					},
+					Metadata: &backend.PageMetadata{
+						NextPageToken: nil,
+					},
+					Features: nil,
+				},
+			},
+			expectedCallCount: 1,
+			expectedResponse: backend.ListInteropWPTMetrics200JSONResponse{
+				Data: []backend.WPTRunMetric{
+					{
+						RunTimestamp:    time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+						TestPassCount:   valuePtr[int64](1),
+						TotalTestsCount: valuePtr[int64](2),
+					},
+				},
+				Metadata: &backend.PageMetadata{
+					NextPageToken: nil,
+				},
+				Features: nil,
+			},
+			request: backend.ListInteropWPTMetricsRequestObject{
+				Params: backend.ListInteropWPTMetricsParams{
+					StartAt:    openapi_types.Date{Time: time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)},
+					EndAt:      openapi_types.Date{Time: time.Date(2000, time.January, 10, 0, 0, 0, 0, time.UTC)},
+					PageToken:  nil,
+					PageSize:   nil,
+					FeatureIds: nil,
+					Browsers:   nil,
+				},
+				Channel:    backend.Stable,
+				MetricView: backend.SubtestCounts,
+			},
+			expectedError: nil,
+		},
+		{
+			name: "Success Case - include optional params",
+			mockConfig: MockListWPTInteropMetricsConfig{
+				expectedFeatureIDs: []string{"feature1"},
+				expectedBrowsers:   []backend.BrowserPathParam{backend.Chrome, backend.Firefox},
+				expectedChannel:    "experimental",
+				expectedMetric:     backend.TestCounts,
+				expectedStartAt:    time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+				expectedEndAt:      time.Date(2000, time.January, 10, 0, 0, 0, 0, time.UTC),
+				expectedPageSize:   50,
+				expectedPageToken:  inputPageToken,
+				err:                nil,
+				page: &backend.WPTInteropMetricsPage{
+					Data: []backend.WPTRunMetric{
+						{
+							RunTimestamp:    time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+							TestPassCount:   valuePtr[int64](1),
+							TotalTestsCount: valuePtr[int64](2),
+						},
+					},
+					Metadata: &backend.PageMetadata{
+						NextPageToken: nextPageToken,
+					},
+					Features: &[]backend.WPTFeatureRunMetrics{
+						{
+							FeatureId: "feature1",
+							Data: []backend.WPTRunMetric{
+								{
+									RunTimestamp:    time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+									TestPassCount:   valuePtr[int64](1),
+									TotalTestsCount: valuePtr[int64](2),
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedCallCount: 1,
+			expectedResponse: backend.ListInteropWPTMetrics200JSONResponse{
+				Data: []backend.WPTRunMetric{
+					{
+						RunTimestamp:    time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+						TestPassCount:   valuePtr[int64](1),
+						TotalTestsCount: valuePtr[int64](2),
+					},
+				},
+				Metadata: &backend.PageMetadata{
+					NextPageToken: nextPageToken,
+				},
+				Features: &[]backend.WPTFeatureRunMetrics{
+					{
+						FeatureId: "feature1",
+						Data: []backend.WPTRunMetric{
+							{
+								RunTimestamp:    time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+								TestPassCount:   valuePtr[int64](1),
+								TotalTestsCount: valuePtr[int64](2),
+							},
+						},
+					},
+				},
+			},
+			request: backend.ListInteropWPTMetricsRequestObject{
+				Params: backend.ListInteropWPTMetricsParams{
+					StartAt:    openapi_types.Date{Time: time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)},
+					EndAt:      openapi_types.Date{Time: time.Date(2000, time.January, 10, 0, 0, 0, 0, time.UTC)},
+					PageToken:  inputPageToken,
+					PageSize:   valuePtr[int](50),
+					FeatureIds: valuePtr[[]string]([]string{"feature1"}),
+					Browsers:   valuePtr[[]string]([]string{"firefox", "chrome", "firefox"}),
+				},
+				Channel:    backend.Experimental,
+				MetricView: backend.TestCounts,
+			},
+			expectedError: nil,
+		},
+		{
+			name: "400 case - unknown browser",
+			// nolint: exhaustruct
+			mockConfig:        MockListWPTInteropMetricsConfig{},
+			expectedCallCount: 0,
+			expectedResponse: backend.ListInteropWPTMetrics400JSONResponse{
+				Code:    400,
+				Message: "unknown browser: netscape",
+			},
+			request: backend.ListInteropWPTMetricsRequestObject{
+				Params: backend.ListInteropWPTMetricsParams{
+					StartAt:    openapi_types.Date{Time: time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)},
+					EndAt:      openapi_types.Date{Time: time.Date(2000, time.January, 10, 0, 0, 0, 0, time.UTC)},
+					PageToken:  nil,
+					PageSize:   nil,
+					FeatureIds: nil,
+					Browsers:   valuePtr[[]string]([]string{"chrome", "netscape"}),
+				},
+				Channel:    backend.Stable,
+				MetricView: backend.SubtestCounts,
+			},
+			expectedError: nil,
+		},
+		{
+			name: "500 case",
+			mockConfig: MockListWPTInteropMetricsConfig{
+				expectedFeatureIDs: []string{},
+				expectedBrowsers:   defaultBrowsers(),
+				expectedChannel:    "stable",
+				expectedMetric:     backend.SubtestCounts,
+				expectedStartAt:    time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+				expectedEndAt:      time.Date(2000, time.January, 10, 0, 0, 0, 0, time.UTC),
+				expectedPageSize:   100,
+				expectedPageToken:  nil,
+				page:               nil,
+				err:                errTest,
+			},
+			expectedCallCount: 1,
+			expectedResponse: backend.ListInteropWPTMetrics500JSONResponse{
+				Code:    500,
+				Message: "unable to get interop metrics",
+			},
+			request: backend.ListInteropWPTMetricsRequestObject{
+				Params: backend.ListInteropWPTMetricsParams{
+					StartAt:    openapi_types.Date{Time: time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)},
+					EndAt:      openapi_types.Date{Time: time.Date(2000, time.January, 10, 0, 0, 0, 0, time.UTC)},
+					PageToken:  nil,
+					PageSize:   nil,
+					FeatureIds: nil,
+					Browsers:   nil,
+				},
+				Channel:    backend.Stable,
+				MetricView: backend.SubtestCounts,
+			},
+			expectedError: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// nolint: exhaustruct
+			mockStorer := &MockWPTMetricsStorer{
+				interopCfg: tc.mockConfig,
+				t:          t,
+			}
+			myServer := Server{
+				wptMetricsStorer:         mockStorer,
+				metadataStorer:           nil,
+				subscriptionStorer:       nil,
+				callbackPolicy:           nil,
+				excludedFeatureKeyStorer: nil,
+				cacheClearer:             nil,
+			}
+
+			// Call the function under test
+			resp, err := myServer.ListInteropWPTMetrics(context.Background(), tc.request)
+
+			// Assertions
+			if mockStorer.callCountListWPTInteropMetrics != tc.expectedCallCount {
+				t.Errorf("Incorrect call count: expected %d, got %d",
+					tc.expectedCallCount,
+					mockStorer.callCountListWPTInteropMetrics)
+			}
+
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("Unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(tc.expectedResponse, resp) {
+				t.Errorf("Unexpected response: %v", resp)
+			}
+		})
+	}
+}
+
+func TestListInteropWPTMetricsCommaSeparatedBrowsers(t *testing.T) {
+	// nolint: exhaustruct
+	mockStorer := &MockWPTMetricsStorer{
+		interopCfg: MockListWPTInteropMetricsConfig{
+			expectedFeatureIDs: []string{},
+			expectedBrowsers:   []backend.BrowserPathParam{"chrome", "firefox"},
+			expectedChannel:    "stable",
+			expectedMetric:     backend.SubtestCounts,
+			expectedStartAt:    time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+			expectedEndAt:      time.Date(2000, time.January, 10, 0, 0, 0, 0, time.UTC),
+			expectedPageSize:   100,
+			expectedPageToken:  nil,
+			page: &backend.WPTInteropMetricsPage{
+				Data:     []backend.WPTRunMetric{},
+				Features: nil,
+				Metadata: nil,
+			},
+			err: nil,
+		},
+		t: t,
+	}
+	req := httptest.NewRequest(http.MethodGet,
+		"/v1/stats/wpt/interop/stable/subtest_counts?startAt=2000-01-01&endAt=2000-01-10&browsers=firefox,chrome", nil)
+	rr := serveTestRequest(t, mockStorer, req)
+	if rr.Code != http.StatusOK {
+		t.Errorf("unexpected status code %d. body %s", rr.Code, rr.Body.String())
+	}
+	if mockStorer.callCountListWPTInteropMetrics != 1 {
+		t.Errorf("Incorrect call count: expected 1, got %d", mockStorer.callCountListWPTInteropMetrics)
+	}
+}
@@ -24,6 +24,9 @@ import (
 
 	"github.com/GoogleChrome/webstatus.dev/lib/gcpspanner/searchtypes"
 	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/backend"
+	"github.com/GoogleChrome/webstatus.dev/lib/healthcheck"
+	"github.com/GoogleChrome/webstatus.dev/lib/httpmiddlewares"
+	"github.com/GoogleChrome/webstatus.dev/lib/webhooks"
 	"github.com/go-chi/chi/v5"
 )
 
@@ -45,6 +48,18 @@ type WPTMetricsStorer interface {
 		endAt time.Time,
 		pageSize int,
 		pageToken *string) ([]backend.WPTRunMetric, *string, error)
+	// ListFirstMetricsPageForFeatureIDsBrowserAndChannel returns the first page of each feature, keyed by feature ID.
+	// Features that do not exist are left out.
+	ListFirstMetricsPageForFeatureIDsBrowserAndChannel(
+		ctx context.Context,
+		featureIDs []string,
+		browser string,
+		channel string,
+		metricView backend.MetricViewPathParam,
+		startAt time.Time,
+		endAt time.Time,
+		pageSize int,
+	) (map[string]*backend.WPTRunMetricsPage, error)
 	ListMetricsOverTimeWithAggregatedTotals(
 		ctx context.Context,
 		featureIDs []string,
@@ -55,6 +70,16 @@ type WPTMetricsStorer interface {
 		pageSize int,
 		pageToken *string,
 	) ([]backend.WPTRunMetric, *string, error)
+	ListWPTInteropMetrics(
+		ctx context.Context,
+		featureIDs []string,
+		browsers []backend.BrowserPathParam,
+		channel string,
+		metricView backend.MetricViewPathParam,
+		startAt, endAt time.Time,
+		pageSize int,
+		pageToken *string,
+	) (*backend.WPTInteropMetricsPage, error)
 	FeaturesSearch(
 		ctx context.Context,
 		pageToken *string,
@@ -63,13 +88,23 @@ type WPTMetricsStorer interface {
 		sortOrder *backend.GetV1FeaturesParamsSort,
 		wptMetricType backend.WPTMetricView,
 		browsers []backend.BrowserPathParam,
+		asOf *time.Time,
+		facets []string,
 	) (*backend.FeaturePage, error)
 	GetFeature(
 		ctx context.Context,
 		featureID string,
 		wptMetricType backend.WPTMetricView,
 		browsers []backend.BrowserPathParam,
+		asOf *time.Time,
 	) (*backend.Feature, error)
+	// GetFeatures returns the features that exist, keyed by feature ID.
+	GetFeatures(
+		ctx context.Context,
+		featureIDs []string,
+		wptMetricType backend.WPTMetricView,
+		browsers []backend.BrowserPathParam,
+	) (map[string]*backend.Feature, error)
 	ListBrowserFeatureCountMetric(
 		ctx context.Context,
 		browser string,
@@ -82,11 +117,63 @@ type WPTMetricsStorer interface {
 		ctx context.Context,
 		featureID string,
 	) (*string, error)
+	// GetIDsFromFeatureKeys returns the IDs of the feature keys that exist, keyed by feature key.
+	GetIDsFromFeatureKeys(
+		ctx context.Context,
+		featureKeys []string,
+	) (map[string]string, error)
+	GetFeatureTimeline(
+		ctx context.Context,
+		featureID string,
+		wptMetricType backend.WPTMetricView,
+	) (*backend.FeatureTimeline, error)
+	GetFeatureCompatFeatures(
+		ctx context.Context,
+		featureID string,
+	) (*backend.FeatureCompatFeatures, error)
+	ListFeatureTimeToBaseline(
+		ctx context.Context,
+		searchNode *searchtypes.SearchNode,
+	) (*backend.TimeToBaselineStats, error)
+	GetBrowserCompatMatrix(
+		ctx context.Context,
+		browsers []backend.BrowserPathParam,
+		at time.Time,
+	) (*backend.BrowserCompatMatrix, error)
+}
+
+// SubscriptionStorer stores the webhook subscriptions of API clients.
+// A subscription is only visible to the client, identified by httpmiddlewares.ClientIdentity.ID, that created it.
+type SubscriptionStorer interface {
+	CreateSubscription(ctx context.Context, clientID string, subscription backend.Subscription) error
+	// GetSubscription returns gds.ErrEntityNotFound if the subscription does not exist or has a different client.
+	GetSubscription(ctx context.Context, clientID string, subscriptionID string) (*backend.Subscription, error)
+	// DeleteSubscription returns gds.ErrEntityNotFound if the subscription does not exist or has a different client.
+	DeleteSubscription(ctx context.Context, clientID string, subscriptionID string) error
+}
+
+// ExcludedFeatureKeyStorer manages the feature keys that are hidden from every query.
+// Each change is recorded in an audit log with the author.
+type ExcludedFeatureKeyStorer interface {
+	ListExcludedFeatureKeys(ctx context.Context) ([]backend.ExcludedFeatureKey, error)
+	// AddExcludedFeatureKey returns gcpspanner.ErrFeatureKeyAlreadyExcluded if the key is already excluded.
+	AddExcludedFeatureKey(ctx context.Context, featureKey, reason, author string) (*backend.ExcludedFeatureKey, error)
+	// RemoveExcludedFeatureKey returns gcpspanner.ErrQueryReturnedNoResults if the key is not excluded.
+	RemoveExcludedFeatureKey(ctx context.Context, featureKey, author string) error
+}
+
+// CacheClearer removes the cached responses after the data behind them changes outside of an ingestion.
+type CacheClearer interface {
+	Clear(ctx context.Context) error
 }
 
 type Server struct {
-	metadataStorer   WebFeatureMetadataStorer
-	wptMetricsStorer WPTMetricsStorer
+	metadataStorer           WebFeatureMetadataStorer
+	wptMetricsStorer         WPTMetricsStorer
+	subscriptionStorer       SubscriptionStorer
+	callbackPolicy           *webhooks.CallbackPolicy
+	excludedFeatureKeyStorer ExcludedFeatureKeyStorer
+	cacheClearer             CacheClearer
 }
 
 func defaultBrowsers() []backend.BrowserPathParam {
@@ -120,7 +207,12 @@ func NewHTTPServer(
 	port string,
 	metadataStorer WebFeatureMetadataStorer,
 	wptMetricsStorer WPTMetricsStorer,
-	middlewares []func(http.Handler) http.Handler) (*http.Server, error) {
+	subscriptionStorer SubscriptionStorer,
+	callbackPolicy *webhooks.CallbackPolicy,
+	excludedFeatureKeyStorer ExcludedFeatureKeyStorer,
+	cacheClearer CacheClearer,
+	middlewares []func(http.Handler) http.Handler,
+	readinessDependencies []healthcheck.Dependency) (*http.Server, error) {
 	_, err := backend.GetSwagger()
 	if err != nil {
 		return nil, fmt.Errorf("error loading swagger spec. %w", err)
@@ -128,24 +220,48 @@ func NewHTTPServer(
 
 	// Create an instance of our handler which satisfies the generated interface
 	srv := &Server{
-		metadataStorer:   metadataStorer,
-		wptMetricsStorer: wptMetricsStorer,
+		metadataStorer:           metadataStorer,
+		wptMetricsStorer:         wptMetricsStorer,
+		subscriptionStorer:       subscriptionStorer,
+		callbackPolicy:           callbackPolicy,
+		excludedFeatureKeyStorer: excludedFeatureKeyStorer,
+		cacheClearer:             cacheClearer,
 	}
 
-	srvStrictHandler := backend.NewStrictHandler(srv, nil)
+	// Enforce the security requirements of the operations in the OpenAPI spec.
+	srvStrictHandler := backend.NewStrictHandler(srv, []backend.StrictMiddlewareFunc{
+		httpmiddlewares.NewStrictAuthMiddleware(backend.BearerAuthScopes),
+	})
+
+	graphQLHandler, err := newGraphQLHandler(srv)
+	if err != nil {
+		return nil, fmt.Errorf("error loading graphql schema. %w", err)
+	}
 
 	// This is how you set up a basic chi router
 	r := chi.NewRouter()
-	r.Use(middlewares...)
 
-	// Use our validation middleware to check all requests against the
-	// OpenAPI schema.
-	// r.Use(middleware.OapiRequestValidatorWithOptions(swagger, &middleware.Options{
-	// 	SilenceServersWarning: true,
-	// }))
+	// The health checks are registered outside of the middlewares so that they are never cached or rate limited.
+	r.Method(http.MethodGet, "/healthz", healthcheck.NewLivenessHandler())
+	r.Method(http.MethodGet, "/readyz", healthcheck.NewReadinessHandler(
+		healthcheck.DefaultCheckTimeout, readinessDependencies))
+
+	r.Group(func(r chi.Router) {
+		r.Use(middlewares...)
+
+		// Use our validation middleware to check all requests against the
+		// OpenAPI schema.
+		// r.Use(middleware.OapiRequestValidatorWithOptions(swagger, &middleware.Options{
+		// 	SilenceServersWarning: true,
+		// }))
+
+		// We now register our web feature router above as the handler for the interface
+		backend.HandlerFromMux(srvStrictHandler, r)
 
-	// We now register our web feature router above as the handler for the interface
-	backend.HandlerFromMux(srvStrictHandler, r)
+		// The GraphQL API is not part of the OpenAPI spec. See graphql_schema.graphql.
+		r.Method(http.MethodGet, "/v1/graphql", graphQLHandler)
+		r.Method(http.MethodPost, "/v1/graphql", graphQLHandler)
+	})
 
 	// nolint:exhaustruct // No need to populate 3rd party struct
 	return &http.Server{
@@ -17,8 +17,11 @@ package httpserver
 import (
 	"context"
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"slices"
+	"sync"
 	"testing"
 	"time"
 
@@ -78,6 +81,19 @@ type MockListMetricsOverTimeWithAggregatedTotalsConfig struct {
 	err                error
 }
 
+type MockListWPTInteropMetricsConfig struct {
+	expectedFeatureIDs []string
+	expectedBrowsers   []backend.BrowserPathParam
+	expectedChannel    string
+	expectedMetric     backend.WPTMetricView
+	expectedStartAt    time.Time
+	expectedEndAt      time.Time
+	expectedPageSize   int
+	expectedPageToken  *string
+	page               *backend.WPTInteropMetricsPage
+	err                error
+}
+
 type MockFeaturesSearchConfig struct {
 	expectedPageToken     *string
 	expectedPageSize      int
@@ -85,6 +101,8 @@ type MockFeaturesSearchConfig struct {
 	expectedSortBy        *backend.GetV1FeaturesParamsSort
 	expectedWPTMetricView backend.WPTMetricView
 	expectedBrowsers      []backend.BrowserPathParam
+	expectedAsOf          *time.Time
+	expectedFacets        []string
 	page                  *backend.FeaturePage
 	err                   error
 }
@@ -93,6 +111,7 @@ type MockGetFeatureByIDConfig struct {
 	expectedFeatureID     string
 	expectedWPTMetricView backend.WPTMetricView
 	expectedBrowsers      []backend.BrowserPathParam
+	expectedAsOf          *time.Time
 	data                  *backend.Feature
 	err                   error
 }
@@ -103,6 +122,39 @@ type MockGetIDFromFeatureKeyConfig struct {
 	err                error
 }
 
+type MockGetIDsFromFeatureKeysConfig struct {
+	expectedFeatureKeys []string
+	result              map[string]string
+	err                 error
+}
+
+type MockGetFeatureTimelineConfig struct {
+	expectedFeatureID     string
+	expectedWPTMetricView backend.WPTMetricView
+	data                  *backend.FeatureTimeline
+	err                   error
+}
+
+type MockListFeatureTimeToBaselineConfig struct {
+	expectedSearchNode *searchtypes.SearchNode
+	data               *backend.TimeToBaselineStats
+	err                error
+}
+
+type MockGetBrowserCompatMatrixConfig struct {
+	expectedBrowsers []backend.BrowserPathParam
+	// The zero value skips the check. Used when the handler defaults to the current time.
+	expectedAt time.Time
+	data       *backend.BrowserCompatMatrix
+	err        error
+}
+
+type MockGetFeatureCompatFeaturesConfig struct {
+	expectedFeatureID string
+	data              *backend.FeatureCompatFeatures
+	err               error
+}
+
 type MockListBrowserFeatureCountMetricConfig struct {
 	expectedBrowser   string
 	expectedStartAt   time.Time
@@ -115,18 +167,35 @@ type MockListBrowserFeatureCountMetricConfig struct {
 }
 
 type MockWPTMetricsStorer struct {
-	featureCfg                                        MockListMetricsForFeatureIDBrowserAndChannelConfig
+	// mu guards the methods that handlers call concurrently.
+	mu         sync.Mutex
+	featureCfg MockListMetricsForFeatureIDBrowserAndChannelConfig
+	// featureCfgs is matched by feature, browser, channel and page token because the calls can happen in any order.
+	featureCfgs                                       []MockListMetricsForFeatureIDBrowserAndChannelConfig
 	aggregateCfg                                      MockListMetricsOverTimeWithAggregatedTotalsConfig
+	interopCfg                                        MockListWPTInteropMetricsConfig
 	featuresSearchCfg                                 MockFeaturesSearchConfig
+	featuresSearchPageCfgs                            []MockFeaturesSearchConfig
 	listBrowserFeatureCountMetricCfg                  MockListBrowserFeatureCountMetricConfig
 	getFeatureByIDConfig                              MockGetFeatureByIDConfig
+	getFeatureByIDConfigs                             []MockGetFeatureByIDConfig
 	getIDFromFeatureKeyConfig                         MockGetIDFromFeatureKeyConfig
+	getIDsFromFeatureKeysConfig                       MockGetIDsFromFeatureKeysConfig
+	getFeatureTimelineConfig                          MockGetFeatureTimelineConfig
+	getFeatureCompatFeaturesConfig                    MockGetFeatureCompatFeaturesConfig
+	listFeatureTimeToBaselineConfig                   MockListFeatureTimeToBaselineConfig
+	getBrowserCompatMatrixConfig                      MockGetBrowserCompatMatrixConfig
 	t                                                 *testing.T
 	callCountListBrowserFeatureCountMetric            int
 	callCountFeaturesSearch                           int
 	callCountListMetricsForFeatureIDBrowserAndChannel int
 	callCountListMetricsOverTimeWithAggregatedTotals  int
+	callCountListWPTInteropMetrics                    int
 	callCountGetFeature                               int
+	callCountGetFeatureTimeline                       int
+	callCountGetFeatureCompatFeatures                 int
+	callCountListFeatureTimeToBaseline                int
+	callCountGetBrowserCompatMatrix                   int
 }
 
 func (m *MockWPTMetricsStorer) GetIDFromFeatureKey(
@@ -140,27 +209,73 @@ func (m *MockWPTMetricsStorer) GetIDFromFeatureKey(
 	return m.getIDFromFeatureKeyConfig.result, m.getIDFromFeatureKeyConfig.err
 }
 
+func (m *MockWPTMetricsStorer) GetIDsFromFeatureKeys(
+	_ context.Context,
+	featureKeys []string,
+) (map[string]string, error) {
+	if !slices.Equal(featureKeys, m.getIDsFromFeatureKeysConfig.expectedFeatureKeys) {
+		m.t.Errorf("unexpected feature keys %v", featureKeys)
+	}
+
+	return m.getIDsFromFeatureKeysConfig.result, m.getIDsFromFeatureKeysConfig.err
+}
+
 func (m *MockWPTMetricsStorer) ListMetricsForFeatureIDBrowserAndChannel(_ context.Context,
 	featureID string, browser string, channel string,
 	metric backend.WPTMetricView,
 	startAt time.Time, endAt time.Time,
 	pageSize int, pageToken *string) ([]backend.WPTRunMetric, *string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.callCountListMetricsForFeatureIDBrowserAndChannel++
 
-	if featureID != m.featureCfg.expectedFeatureID ||
-		browser != m.featureCfg.expectedBrowser ||
-		channel != m.featureCfg.expectedChannel ||
-		metric != m.featureCfg.expectedMetric ||
-		!startAt.Equal(m.featureCfg.expectedStartAt) ||
-		!endAt.Equal(m.featureCfg.expectedEndAt) ||
-		pageSize != m.featureCfg.expectedPageSize ||
-		pageToken != m.featureCfg.expectedPageToken {
+	cfg := m.featureCfg
+	// Handlers that request multiple series use one config per call.
+	if len(m.featureCfgs) > 0 {
+		idx := slices.IndexFunc(m.featureCfgs, func(c MockListMetricsForFeatureIDBrowserAndChannelConfig) bool {
+			return c.expectedFeatureID == featureID && c.expectedBrowser == browser &&
+				c.expectedChannel == channel && c.expectedPageToken == pageToken
+		})
+		if idx == -1 {
+			m.t.Errorf("unexpected call to ListMetricsForFeatureIDBrowserAndChannel { %s, %s, %s, %v }",
+				featureID, browser, channel, pageToken)
+
+			return nil, nil, errTest
+		}
+		cfg = m.featureCfgs[idx]
+	}
+
+	if featureID != cfg.expectedFeatureID ||
+		browser != cfg.expectedBrowser ||
+		channel != cfg.expectedChannel ||
+		metric != cfg.expectedMetric ||
+		!startAt.Equal(cfg.expectedStartAt) ||
+		!endAt.Equal(cfg.expectedEndAt) ||
+		pageSize != cfg.expectedPageSize ||
+		pageToken != cfg.expectedPageToken {
 
 		m.t.Errorf("Incorrect arguments. Expected: %v, Got: { %s, %s, %s, %s, %s, %s, %d %v }",
-			m.featureCfg, featureID, browser, channel, metric, startAt, endAt, pageSize, pageToken)
+			cfg, featureID, browser, channel, metric, startAt, endAt, pageSize, pageToken)
 	}
 
-	return m.featureCfg.data, m.featureCfg.pageToken, m.featureCfg.err
+	return cfg.data, cfg.pageToken, cfg.err
+}
+
+// ListFirstMetricsPageForFeatureIDsBrowserAndChannel is only used by the GraphQL API,
+// which is tested with fakeGraphQLStorer.
+func (m *MockWPTMetricsStorer) ListFirstMetricsPageForFeatureIDsBrowserAndChannel(
+	_ context.Context,
+	featureIDs []string,
+	_ string,
+	_ string,
+	_ backend.WPTMetricView,
+	_ time.Time,
+	_ time.Time,
+	_ int,
+) (map[string]*backend.WPTRunMetricsPage, error) {
+	m.t.Errorf("unexpected call to ListFirstMetricsPageForFeatureIDsBrowserAndChannel %v", featureIDs)
+
+	return nil, errTest
 }
 
 func (m *MockWPTMetricsStorer) ListMetricsOverTimeWithAggregatedTotals(
@@ -191,6 +306,34 @@ func (m *MockWPTMetricsStorer) ListMetricsOverTimeWithAggregatedTotals(
 	return m.aggregateCfg.data, m.aggregateCfg.pageToken, m.aggregateCfg.err
 }
 
+func (m *MockWPTMetricsStorer) ListWPTInteropMetrics(
+	_ context.Context,
+	featureIDs []string,
+	browsers []backend.BrowserPathParam,
+	channel string,
+	metric backend.WPTMetricView,
+	startAt, endAt time.Time,
+	pageSize int,
+	pageToken *string,
+) (*backend.WPTInteropMetricsPage, error) {
+	m.callCountListWPTInteropMetrics++
+
+	if !slices.Equal(featureIDs, m.interopCfg.expectedFeatureIDs) ||
+		!slices.Equal(browsers, m.interopCfg.expectedBrowsers) ||
+		channel != m.interopCfg.expectedChannel ||
+		metric != m.interopCfg.expectedMetric ||
+		!startAt.Equal(m.interopCfg.expectedStartAt) ||
+		!endAt.Equal(m.interopCfg.expectedEndAt) ||
+		pageSize != m.interopCfg.expectedPageSize ||
+		pageToken != m.interopCfg.expectedPageToken {
+
+		m.t.Errorf("Incorrect arguments. Expected: %v, Got: { %v, %v, %s, %s, %s, %s, %d %v }",
+			m.interopCfg, featureIDs, browsers, channel, metric, startAt, endAt, pageSize, pageToken)
+	}
+
+	return m.interopCfg.page, m.interopCfg.err
+}
+
 func (m *MockWPTMetricsStorer) FeaturesSearch(
 	_ context.Context,
 	pageToken *string,
@@ -199,20 +342,35 @@ func (m *MockWPTMetricsStorer) FeaturesSearch(
 	sortBy *backend.GetV1FeaturesParamsSort,
 	view backend.WPTMetricView,
 	browsers []backend.BrowserPathParam,
+	asOf *time.Time,
+	facets []string,
 ) (*backend.FeaturePage, error) {
 	m.callCountFeaturesSearch++
 
-	if pageToken != m.featuresSearchCfg.expectedPageToken ||
-		pageSize != m.featuresSearchCfg.expectedPageSize ||
-		!reflect.DeepEqual(node, m.featuresSearchCfg.expectedSearchNode) ||
-		!reflect.DeepEqual(sortBy, m.featuresSearchCfg.expectedSortBy) ||
-		view != m.featuresSearchCfg.expectedWPTMetricView ||
-		!slices.Equal(browsers, m.featuresSearchCfg.expectedBrowsers) {
-		m.t.Errorf("Incorrect arguments. Expected: %v, Got: { %v %d %v %v %v %v }",
-			m.featuresSearchCfg, pageSize, pageToken, node, sortBy, view, browsers)
+	cfg := m.featuresSearchCfg
+	// Handlers that request multiple pages use one config per call.
+	if len(m.featuresSearchPageCfgs) > 0 {
+		if m.callCountFeaturesSearch > len(m.featuresSearchPageCfgs) {
+			m.t.Errorf("unexpected call %d to FeaturesSearch", m.callCountFeaturesSearch)
+
+			return nil, errTest
+		}
+		cfg = m.featuresSearchPageCfgs[m.callCountFeaturesSearch-1]
+	}
+
+	if pageToken != cfg.expectedPageToken ||
+		pageSize != cfg.expectedPageSize ||
+		!reflect.DeepEqual(node, cfg.expectedSearchNode) ||
+		!reflect.DeepEqual(sortBy, cfg.expectedSortBy) ||
+		view != cfg.expectedWPTMetricView ||
+		!slices.Equal(browsers, cfg.expectedBrowsers) ||
+		!reflect.DeepEqual(asOf, cfg.expectedAsOf) ||
+		!slices.Equal(facets, cfg.expectedFacets) {
+		m.t.Errorf("Incorrect arguments. Expected: %v, Got: { %v %d %v %v %v %v %v %v }",
+			cfg, pageSize, pageToken, node, sortBy, view, browsers, asOf, facets)
 	}
 
-	return m.featuresSearchCfg.page, m.featuresSearchCfg.err
+	return cfg.page, cfg.err
 }
 
 func (m *MockWPTMetricsStorer) GetFeature(
@@ -220,17 +378,103 @@ func (m *MockWPTMetricsStorer) GetFeature(
 	featureID string,
 	view backend.WPTMetricView,
 	browsers []backend.BrowserPathParam,
+	asOf *time.Time,
 ) (*backend.Feature, error) {
 	m.callCountGetFeature++
 
-	if featureID != m.getFeatureByIDConfig.expectedFeatureID ||
-		view != m.getFeatureByIDConfig.expectedWPTMetricView ||
-		!slices.Equal(browsers, m.getFeatureByIDConfig.expectedBrowsers) {
-		m.t.Errorf("Incorrect arguments. Expected: %v, Got: { %s %v %v }",
-			m.getFeatureByIDConfig, featureID, view, browsers)
+	cfg := m.getFeatureByIDConfig
+	// Handlers that request multiple features use one config per call.
+	if len(m.getFeatureByIDConfigs) > 0 {
+		if m.callCountGetFeature > len(m.getFeatureByIDConfigs) {
+			m.t.Errorf("unexpected call %d to GetFeature", m.callCountGetFeature)
+
+			return nil, errTest
+		}
+		cfg = m.getFeatureByIDConfigs[m.callCountGetFeature-1]
+	}
+
+	if featureID != cfg.expectedFeatureID ||
+		view != cfg.expectedWPTMetricView ||
+		!slices.Equal(browsers, cfg.expectedBrowsers) ||
+		!reflect.DeepEqual(asOf, cfg.expectedAsOf) {
+		m.t.Errorf("Incorrect arguments. Expected: %v, Got: { %s %v %v %v }",
+			cfg, featureID, view, browsers, asOf)
+	}
+
+	return cfg.data, cfg.err
+}
+
+// GetFeatures is only used by the GraphQL API, which is tested with fakeGraphQLStorer.
+func (m *MockWPTMetricsStorer) GetFeatures(
+	_ context.Context,
+	featureIDs []string,
+	_ backend.WPTMetricView,
+	_ []backend.BrowserPathParam,
+) (map[string]*backend.Feature, error) {
+	m.t.Errorf("unexpected call to GetFeatures %v", featureIDs)
+
+	return nil, errTest
+}
+
+func (m *MockWPTMetricsStorer) GetFeatureTimeline(
+	_ context.Context,
+	featureID string,
+	view backend.WPTMetricView,
+) (*backend.FeatureTimeline, error) {
+	m.callCountGetFeatureTimeline++
+
+	if featureID != m.getFeatureTimelineConfig.expectedFeatureID ||
+		view != m.getFeatureTimelineConfig.expectedWPTMetricView {
+		m.t.Errorf("Incorrect arguments. Expected: %v, Got: { %s %v }",
+			m.getFeatureTimelineConfig, featureID, view)
 	}
 
-	return m.getFeatureByIDConfig.data, m.getFeatureByIDConfig.err
+	return m.getFeatureTimelineConfig.data, m.getFeatureTimelineConfig.err
+}
+
+func (m *MockWPTMetricsStorer) ListFeatureTimeToBaseline(
+	_ context.Context,
+	searchNode *searchtypes.SearchNode,
+) (*backend.TimeToBaselineStats, error) {
+	m.callCountListFeatureTimeToBaseline++
+
+	if !reflect.DeepEqual(searchNode, m.listFeatureTimeToBaselineConfig.expectedSearchNode) {
+		m.t.Errorf("Incorrect arguments. Expected: %v, Got: { %v }",
+			m.listFeatureTimeToBaselineConfig, searchNode)
+	}
+
+	return m.listFeatureTimeToBaselineConfig.data, m.listFeatureTimeToBaselineConfig.err
+}
+
+func (m *MockWPTMetricsStorer) GetBrowserCompatMatrix(
+	_ context.Context,
+	browsers []backend.BrowserPathParam,
+	at time.Time,
+) (*backend.BrowserCompatMatrix, error) {
+	m.callCountGetBrowserCompatMatrix++
+
+	if !slices.Equal(browsers, m.getBrowserCompatMatrixConfig.expectedBrowsers) ||
+		(!m.getBrowserCompatMatrixConfig.expectedAt.IsZero() &&
+			!at.Equal(m.getBrowserCompatMatrixConfig.expectedAt)) {
+		m.t.Errorf("Incorrect arguments. Expected: %v, Got: { %v %s }",
+			m.getBrowserCompatMatrixConfig, browsers, at)
+	}
+
+	return m.getBrowserCompatMatrixConfig.data, m.getBrowserCompatMatrixConfig.err
+}
+
+func (m *MockWPTMetricsStorer) GetFeatureCompatFeatures(
+	_ context.Context,
+	featureID string,
+) (*backend.FeatureCompatFeatures, error) {
+	m.callCountGetFeatureCompatFeatures++
+
+	if featureID != m.getFeatureCompatFeaturesConfig.expectedFeatureID {
+		m.t.Errorf("Incorrect arguments. Expected: %v, Got: { %s }",
+			m.getFeatureCompatFeaturesConfig, featureID)
+	}
+
+	return m.getFeatureCompatFeaturesConfig.data, m.getFeatureCompatFeaturesConfig.err
 }
 
 func (m *MockWPTMetricsStorer) ListBrowserFeatureCountMetric(
@@ -284,3 +528,16 @@ var (
 	nextPageToken  = valuePtr[string]("next-page-token")
 	errTest        = errors.New("test error")
 )
+
+// serveTestRequest routes the request through the generated handlers so that the query parameters are parsed
+// the same way as in production.
+func serveTestRequest(t *testing.T, wptMetricsStorer WPTMetricsStorer, req *http.Request) *httptest.ResponseRecorder {
+	srv, err := NewHTTPServer("0", nil, wptMetricsStorer, nil, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("unable to create server: %v", err)
+	}
+	rr := httptest.NewRecorder()
+	srv.Handler.ServeHTTP(rr, req)
+
+	return rr
+}
@@ -0,0 +1,195 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/GoogleChrome/webstatus.dev/lib/gcpspanner/searchtypes"
+	"github.com/GoogleChrome/webstatus.dev/lib/gds"
+	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/backend"
+	"github.com/GoogleChrome/webstatus.dev/lib/httpmiddlewares"
+)
+
+// maxSubscriptionFeatureIDs limits the size of a subscription that lists features.
+// Larger sets of features should use a query instead.
+const maxSubscriptionFeatureIDs = 100
+
+// Subscriptions belong to the API key that created them rather than to its owner, which is only a label.
+// A rotated key starts without subscriptions.
+func apiKeyRequiredError() backend.BasicErrorModel {
+	return backend.BasicErrorModel{
+		Code:    http.StatusUnauthorized,
+		Message: "an api key is required to manage subscriptions",
+	}
+}
+
+// CreateSubscription implements backend.StrictServerInterface.
+// nolint:ireturn // Expected ireturn for openapi generation.
+func (s *Server) CreateSubscription(
+	ctx context.Context,
+	request backend.CreateSubscriptionRequestObject,
+) (backend.CreateSubscriptionResponseObject, error) {
+	identity, ok := httpmiddlewares.ClientIdentityFromContext(ctx)
+	if !ok {
+		return backend.CreateSubscription401JSONResponse(apiKeyRequiredError()), nil
+	}
+	if request.Body == nil {
+		return backend.CreateSubscription400JSONResponse{
+			Code:    http.StatusBadRequest,
+			Message: "missing request body",
+		}, nil
+	}
+	if err := s.validateSubscriptionRequest(ctx, *request.Body); err != nil {
+		return backend.CreateSubscription400JSONResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		}, nil
+	}
+
+	id, err := randomHex(16)
+	if err != nil {
+		slog.ErrorContext(ctx, "unable to generate subscription id", "error", err)
+
+		return backend.CreateSubscription500JSONResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "unable to create subscription",
+		}, nil
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		slog.ErrorContext(ctx, "unable to generate subscription secret", "error", err)
+
+		return backend.CreateSubscription500JSONResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "unable to create subscription",
+		}, nil
+	}
+	subscription := backend.Subscription{
+		Id:           id,
+		CallbackUrl:  request.Body.CallbackUrl,
+		Query:        request.Body.Query,
+		FeatureIds:   request.Body.FeatureIds,
+		WptThreshold: request.Body.WptThreshold,
+		CreatedAt:    time.Now().UTC(),
+		Secret:       &secret,
+	}
+	err = s.subscriptionStorer.CreateSubscription(ctx, identity.ID, subscription)
+	if err != nil {
+		slog.ErrorContext(ctx, "unable to create subscription", "error", err)
+
+		return backend.CreateSubscription500JSONResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "unable to create subscription",
+		}, nil
+	}
+
+	return backend.CreateSubscription201JSONResponse(subscription), nil
+}
+
+// GetSubscription implements backend.StrictServerInterface.
+// nolint:ireturn // Expected ireturn for openapi generation.
+func (s *Server) GetSubscription(
+	ctx context.Context,
+	request backend.GetSubscriptionRequestObject,
+) (backend.GetSubscriptionResponseObject, error) {
+	identity, ok := httpmiddlewares.ClientIdentityFromContext(ctx)
+	if !ok {
+		return backend.GetSubscription401JSONResponse(apiKeyRequiredError()), nil
+	}
+	subscription, err := s.subscriptionStorer.GetSubscription(ctx, identity.ID, request.SubscriptionId)
+	if errors.Is(err, gds.ErrEntityNotFound) {
+		return backend.GetSubscription404JSONResponse{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("unable to find subscription %s", request.SubscriptionId),
+		}, nil
+	} else if err != nil {
+		slog.ErrorContext(ctx, "unable to get subscription", "error", err)
+
+		return backend.GetSubscription500JSONResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "unable to get subscription",
+		}, nil
+	}
+
+	return backend.GetSubscription200JSONResponse(*subscription), nil
+}
+
+// DeleteSubscription implements backend.StrictServerInterface.
+// nolint:ireturn // Expected ireturn for openapi generation.
+func (s *Server) DeleteSubscription(
+	ctx context.Context,
+	request backend.DeleteSubscriptionRequestObject,
+) (backend.DeleteSubscriptionResponseObject, error) {
+	identity, ok := httpmiddlewares.ClientIdentityFromContext(ctx)
+	if !ok {
+		return backend.DeleteSubscription401JSONResponse(apiKeyRequiredError()), nil
+	}
+	err := s.subscriptionStorer.DeleteSubscription(ctx, identity.ID, request.SubscriptionId)
+	if errors.Is(err, gds.ErrEntityNotFound) {
+		return backend.DeleteSubscription404JSONResponse{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("unable to find subscription %s", request.SubscriptionId),
+		}, nil
+	} else if err != nil {
+		slog.ErrorContext(ctx, "unable to delete subscription", "error", err)
+
+		return backend.DeleteSubscription500JSONResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "unable to delete subscription",
+		}, nil
+	}
+
+	return backend.DeleteSubscription204Response{}, nil
+}
+
+func (s *Server) validateSubscriptionRequest(ctx context.Context, req backend.SubscriptionRequest) error {
+	if err := s.callbackPolicy.ValidateCallbackURL(ctx, req.CallbackUrl); err != nil {
+		return err
+	}
+	hasFeatureIDs := req.FeatureIds != nil && len(*req.FeatureIds) > 0
+	if (req.Query == nil) == !hasFeatureIDs {
+		return errors.New("exactly one of query or feature_ids is required")
+	}
+	if req.Query != nil {
+		if _, err := (searchtypes.FeaturesSearchQueryParser{}).Parse(*req.Query); err != nil {
+			return errors.New("query string does not match expected grammar")
+		}
+	}
+	if hasFeatureIDs && len(*req.FeatureIds) > maxSubscriptionFeatureIDs {
+		return fmt.Errorf("at most %d feature_ids are allowed", maxSubscriptionFeatureIDs)
+	}
+	if req.WptThreshold != nil && (*req.WptThreshold < 0 || *req.WptThreshold > 1) {
+		return errors.New("wpt_threshold must be between 0 and 1")
+	}
+
+	return nil
+}
+
+func randomHex(numBytes int) (string, error) {
+	b := make([]byte, numBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,379 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/GoogleChrome/webstatus.dev/lib/gds"
+	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/backend"
+	"github.com/GoogleChrome/webstatus.dev/lib/httpmiddlewares"
+	"github.com/GoogleChrome/webstatus.dev/lib/webhooks"
+)
+
+type MockSubscriptionStorer struct {
+	t                *testing.T
+	expectedClientID string
+	created          []backend.Subscription
+	subscription     *backend.Subscription
+	err              error
+}
+
+func (s *MockSubscriptionStorer) CreateSubscription(
+	_ context.Context, clientID string, subscription backend.Subscription) error {
+	if clientID != s.expectedClientID {
+		s.t.Errorf("unexpected client id %s", clientID)
+	}
+	s.created = append(s.created, subscription)
+
+	return s.err
+}
+
+func (s *MockSubscriptionStorer) GetSubscription(
+	_ context.Context, clientID string, _ string) (*backend.Subscription, error) {
+	if clientID != s.expectedClientID {
+		s.t.Errorf("unexpected client id %s", clientID)
+	}
+
+	return s.subscription, s.err
+}
+
+func (s *MockSubscriptionStorer) DeleteSubscription(_ context.Context, clientID string, _ string) error {
+	if clientID != s.expectedClientID {
+		s.t.Errorf("unexpected client id %s", clientID)
+	}
+
+	return s.err
+}
+
+type testResolver map[string]string
+
+func (r testResolver) LookupIPAddr(_ context.Context, host string) ([]net.IPAddr, error) {
+	ip, found := r[host]
+	if !found {
+		return nil, errTest
+	}
+
+	return []net.IPAddr{{IP: net.ParseIP(ip), Zone: ""}}, nil
+}
+
+// newTestCallbackPolicy allows loopback callbacks like local development does.
+func newTestCallbackPolicy() *webhooks.CallbackPolicy {
+	return webhooks.NewCallbackPolicy(testResolver{
+		"example.com":      "93.184.216.34",
+		"internal.example": "10.0.0.1",
+		"localhost":        "127.0.0.1",
+	}, true)
+}
+
+func contextWithTestIdentity() context.Context {
+	return httpmiddlewares.ContextWithClientIdentity(context.Background(), httpmiddlewares.ClientIdentity{
+		ID:        "hash",
+		Owner:     "ci",
+		QuotaTier: "",
+	})
+}
+
+func TestCreateSubscription(t *testing.T) {
+	testCases := []struct {
+		name             string
+		ctx              context.Context
+		body             *backend.SubscriptionRequest
+		expectedResponse backend.CreateSubscriptionResponseObject
+		expectedCreated  int
+	}{
+		{
+			name: "missing api key",
+			ctx:  context.Background(),
+			body: &backend.SubscriptionRequest{
+				CallbackUrl:  "https://example.com/hook",
+				Query:        valuePtr("baseline_status:limited"),
+				FeatureIds:   nil,
+				WptThreshold: nil,
+			},
+			expectedResponse: backend.CreateSubscription401JSONResponse{
+				Code:    401,
+				Message: "an api key is required to manage subscriptions",
+			},
+			expectedCreated: 0,
+		},
+		{
+			name: "http callback",
+			ctx:  contextWithTestIdentity(),
+			body: &backend.SubscriptionRequest{
+				CallbackUrl:  "http://example.com/hook",
+				Query:        valuePtr("baseline_status:limited"),
+				FeatureIds:   nil,
+				WptThreshold: nil,
+			},
+			expectedResponse: backend.CreateSubscription400JSONResponse{
+				Code:    400,
+				Message: "callback_url must use https",
+			},
+			expectedCreated: 0,
+		},
+		{
+			name: "private callback",
+			ctx:  contextWithTestIdentity(),
+			body: &backend.SubscriptionRequest{
+				CallbackUrl:  "https://internal.example/hook",
+				Query:        valuePtr("baseline_status:limited"),
+				FeatureIds:   nil,
+				WptThreshold: nil,
+			},
+			expectedResponse: backend.CreateSubscription400JSONResponse{
+				Code:    400,
+				Message: "callback_url must not resolve to a loopback or private address",
+			},
+			expectedCreated: 0,
+		},
+		{
+			name: "query and feature ids",
+			ctx:  contextWithTestIdentity(),
+			body: &backend.SubscriptionRequest{
+				CallbackUrl:  "https://example.com/hook",
+				Query:        valuePtr("baseline_status:limited"),
+				FeatureIds:   &[]string{"grid"},
+				WptThreshold: nil,
+			},
+			expectedResponse: backend.CreateSubscription400JSONResponse{
+				Code:    400,
+				Message: "exactly one of query or feature_ids is required",
+			},
+			expectedCreated: 0,
+		},
+		{
+			name: "invalid query",
+			ctx:  contextWithTestIdentity(),
+			body: &backend.SubscriptionRequest{
+				CallbackUrl:  "https://example.com/hook",
+				Query:        valuePtr("badterm:"),
+				FeatureIds:   nil,
+				WptThreshold: nil,
+			},
+			expectedResponse: backend.CreateSubscription400JSONResponse{
+				Code:    400,
+				Message: "query string does not match expected grammar",
+			},
+			expectedCreated: 0,
+		},
+		{
+			name: "invalid threshold",
+			ctx:  contextWithTestIdentity(),
+			body: &backend.SubscriptionRequest{
+				CallbackUrl:  "https://example.com/hook",
+				Query:        nil,
+				FeatureIds:   &[]string{"grid"},
+				WptThreshold: valuePtr(1.5),
+			},
+			expectedResponse: backend.CreateSubscription400JSONResponse{
+				Code:    400,
+				Message: "wpt_threshold must be between 0 and 1",
+			},
+			expectedCreated: 0,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// nolint: exhaustruct // Only the client id is checked.
+			mockStorer := &MockSubscriptionStorer{t: t, expectedClientID: "hash"}
+			myServer := Server{
+				wptMetricsStorer:         nil,
+				metadataStorer:           nil,
+				subscriptionStorer:       mockStorer,
+				callbackPolicy:           newTestCallbackPolicy(),
+				excludedFeatureKeyStorer: nil,
+				cacheClearer:             nil,
+			}
+			resp, err := myServer.CreateSubscription(tc.ctx, backend.CreateSubscriptionRequestObject{Body: tc.body})
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(tc.expectedResponse, resp) {
+				t.Errorf("unexpected response: %v", resp)
+			}
+			if len(mockStorer.created) != tc.expectedCreated {
+				t.Errorf("expected %d created subscriptions, received %d", tc.expectedCreated, len(mockStorer.created))
+			}
+		})
+	}
+}
+
+func TestCreateSubscriptionReturnsSecret(t *testing.T) {
+	// nolint: exhaustruct // Only the client id is checked.
+	mockStorer := &MockSubscriptionStorer{t: t, expectedClientID: "hash"}
+	myServer := Server{
+		wptMetricsStorer:         nil,
+		metadataStorer:           nil,
+		subscriptionStorer:       mockStorer,
+		callbackPolicy:           newTestCallbackPolicy(),
+		excludedFeatureKeyStorer: nil,
+		cacheClearer:             nil,
+	}
+	resp, err := myServer.CreateSubscription(contextWithTestIdentity(), backend.CreateSubscriptionRequestObject{
+		Body: &backend.SubscriptionRequest{
+			CallbackUrl:  "http://localhost:9000/hook",
+			Query:        nil,
+			FeatureIds:   &[]string{"grid"},
+			WptThreshold: valuePtr(0.9),
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	created, ok := resp.(backend.CreateSubscription201JSONResponse)
+	if !ok {
+		t.Fatalf("unexpected response: %v", resp)
+	}
+	if created.Id == "" || created.Secret == nil || len(*created.Secret) != 64 {
+		t.Errorf("expected an id and a secret. received %+v", created)
+	}
+	if len(mockStorer.created) != 1 || !reflect.DeepEqual(mockStorer.created[0], backend.Subscription(created)) {
+		t.Errorf("expected the returned subscription to be stored. received %+v", mockStorer.created)
+	}
+}
+
+func TestGetSubscription(t *testing.T) {
+	subscription := &backend.Subscription{
+		Id:           "sub1",
+		CallbackUrl:  "https://example.com/hook",
+		Query:        nil,
+		FeatureIds:   &[]string{"grid"},
+		WptThreshold: nil,
+		CreatedAt:    time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+		Secret:       nil,
+	}
+	testCases := []struct {
+		name             string
+		subscription     *backend.Subscription
+		err              error
+		expectedResponse backend.GetSubscriptionResponseObject
+	}{
+		{
+			name:             "found",
+			subscription:     subscription,
+			err:              nil,
+			expectedResponse: backend.GetSubscription200JSONResponse(*subscription),
+		},
+		{
+			name:         "not found",
+			subscription: nil,
+			err:          gds.ErrEntityNotFound,
+			expectedResponse: backend.GetSubscription404JSONResponse{
+				Code:    404,
+				Message: "unable to find subscription sub1",
+			},
+		},
+		{
+			name:         "500",
+			subscription: nil,
+			err:          errTest,
+			expectedResponse: backend.GetSubscription500JSONResponse{
+				Code:    500,
+				Message: "unable to get subscription",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// nolint: exhaustruct // Nothing is created.
+			mockStorer := &MockSubscriptionStorer{
+				t:                t,
+				expectedClientID: "hash",
+				subscription:     tc.subscription,
+				err:              tc.err,
+			}
+			myServer := Server{
+				wptMetricsStorer:         nil,
+				metadataStorer:           nil,
+				subscriptionStorer:       mockStorer,
+				callbackPolicy:           nil,
+				excludedFeatureKeyStorer: nil,
+				cacheClearer:             nil,
+			}
+			resp, err := myServer.GetSubscription(contextWithTestIdentity(),
+				backend.GetSubscriptionRequestObject{SubscriptionId: "sub1"})
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(tc.expectedResponse, resp) {
+				t.Errorf("unexpected response: %v", resp)
+			}
+		})
+	}
+}
+
+func TestSubscriptionsAreScopedByAPIKey(t *testing.T) {
+	// Both keys were issued with the same owner label. Only the key that is used selects the subscriptions.
+	ctx := httpmiddlewares.ContextWithClientIdentity(context.Background(), httpmiddlewares.ClientIdentity{
+		ID:        "other-hash",
+		Owner:     "ci",
+		QuotaTier: "",
+	})
+	// nolint: exhaustruct // Nothing is created.
+	mockStorer := &MockSubscriptionStorer{t: t, expectedClientID: "other-hash", err: gds.ErrEntityNotFound}
+	myServer := Server{
+		wptMetricsStorer:         nil,
+		metadataStorer:           nil,
+		subscriptionStorer:       mockStorer,
+		callbackPolicy:           nil,
+		excludedFeatureKeyStorer: nil,
+		cacheClearer:             nil,
+	}
+	resp, err := myServer.GetSubscription(ctx, backend.GetSubscriptionRequestObject{SubscriptionId: "sub1"})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	expected := backend.GetSubscription404JSONResponse{Code: 404, Message: "unable to find subscription sub1"}
+	if !reflect.DeepEqual(expected, resp) {
+		t.Errorf("unexpected response: %v", resp)
+	}
+}
+
+func TestDeleteSubscription(t *testing.T) {
+	// nolint: exhaustruct // Nothing is created.
+	mockStorer := &MockSubscriptionStorer{t: t, expectedClientID: "hash", err: gds.ErrEntityNotFound}
+	myServer := Server{
+		wptMetricsStorer:         nil,
+		metadataStorer:           nil,
+		subscriptionStorer:       mockStorer,
+		callbackPolicy:           nil,
+		excludedFeatureKeyStorer: nil,
+		cacheClearer:             nil,
+	}
+	resp, err := myServer.DeleteSubscription(contextWithTestIdentity(),
+		backend.DeleteSubscriptionRequestObject{SubscriptionId: "sub1"})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	expected := backend.DeleteSubscription404JSONResponse{Code: 404, Message: "unable to find subscription sub1"}
+	if !reflect.DeepEqual(expected, resp) {
+		t.Errorf("unexpected response: %v", resp)
+	}
+
+	mockStorer.err = nil
+	resp, err = myServer.DeleteSubscription(contextWithTestIdentity(),
+		backend.DeleteSubscriptionRequestObject{SubscriptionId: "sub1"})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(backend.DeleteSubscription204Response{}, resp) {
+		t.Errorf("unexpected response: %v", resp)
+	}
+}
@@ -232,6 +232,12 @@ export class FeaturePage extends LitElement {
             location
           ) as FeatureWPTMetricViewType;
           this.feature = await apiClient.getFeature(featureId, wptMetricView);
+          if (this.feature.redirected_from !== undefined) {
+            // The feature was renamed. Load the rest of the page and update
+            // the URL with the current feature ID.
+            this.featureId = this.feature.feature_id;
+            this.updateUrl();
+          }
           return this.feature;
         }
         return Promise.reject('api client and/or featureId not set');
@@ -96,6 +96,19 @@ resource "google_cloud_run_v2_service" "service" {
         name  = "CACHE_TTL"
         value = var.cache_duration
       }
+      # User tokens are issued by Firebase Authentication in the public project.
+      env {
+        name  = "AUTH_JWKS_URL"
+        value = "https://www.googleapis.com/service_accounts/v1/jwk/securetoken@system.gserviceaccount.com"
+      }
+      env {
+        name  = "AUTH_ISSUER"
+        value = "https://securetoken.google.com/${var.projects.public}"
+      }
+      env {
+        name  = "AUTH_AUDIENCE"
+        value = var.projects.public
+      }
       env {
         name  = "OTEL_EXPORTER_OTLP_ENDPOINT"
         value = "http://localhost:4318"
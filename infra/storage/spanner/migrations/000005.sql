@@ -0,0 +1,30 @@
+-- Copyright 2024 Google LLC
+--
+-- Licensed under the Apache License, Version 2.0 (the "License");
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--     http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+
+-- WPTRunFeatureTestResults contains the result of each individual test of a feature in a run.
+-- Keeping the per test results allows comparisons across browsers (e.g. tests passing in all browsers).
+CREATE TABLE IF NOT EXISTS WPTRunFeatureTestResults (
+    ID STRING(36) NOT NULL, -- From WPTRuns table.
+    WebFeatureID STRING(36) NOT NULL,
+    TestName STRING(MAX) NOT NULL,
+    Passed BOOL NOT NULL,
+    SubtestPass INT64,
+    TotalSubtests INT64,
+    FOREIGN KEY (WebFeatureID) REFERENCES WebFeatures(ID),
+    FOREIGN KEY (ID) REFERENCES WPTRuns(ID)
+) PRIMARY KEY (ID, WebFeatureID, TestName)
+,    INTERLEAVE IN PARENT WPTRuns ON DELETE CASCADE;
+
+-- Used to find aligned runs across browsers for a given revision.
+CREATE INDEX RunsByChannelRevision ON WPTRuns (Channel, FullRevisionHash, BrowserName, TimeStart DESC);
@@ -0,0 +1,31 @@
+-- Copyright 2024 Google LLC
+--
+-- Licensed under the Apache License, Version 2.0 (the "License");
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--     http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+
+-- Keys excluded through the admin API record why and by whom.
+-- Keys that were added by hand before this migration have NULL values.
+ALTER TABLE ExcludedFeatureKeys ADD COLUMN Reason STRING(MAX);
+ALTER TABLE ExcludedFeatureKeys ADD COLUMN Author STRING(MAX);
+ALTER TABLE ExcludedFeatureKeys ADD COLUMN CreatedAt TIMESTAMP OPTIONS (allow_commit_timestamp=true);
+
+-- ExcludedFeatureKeyAuditLogs records every change to ExcludedFeatureKeys made through the admin API.
+CREATE TABLE IF NOT EXISTS ExcludedFeatureKeyAuditLogs (
+    ID STRING(36) NOT NULL DEFAULT (GENERATE_UUID()),
+    FeatureKey STRING(64) NOT NULL,
+    Action STRING(16) NOT NULL, -- add or remove
+    Reason STRING(MAX),
+    Author STRING(MAX) NOT NULL,
+    CreatedAt TIMESTAMP NOT NULL OPTIONS (allow_commit_timestamp=true),
+) PRIMARY KEY (ID);
+
+CREATE INDEX ExcludedFeatureKeyAuditLogsByTime ON ExcludedFeatureKeyAuditLogs (CreatedAt DESC);
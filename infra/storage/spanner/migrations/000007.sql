@@ -0,0 +1,21 @@
+-- Copyright 2024 Google LLC
+--
+-- Licensed under the Apache License, Version 2.0 (the "License");
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--     http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+
+-- FeatureAliases maps previous keys of renamed web features to the current feature.
+-- Comes from the alias field of the web features repo.
+CREATE TABLE IF NOT EXISTS FeatureAliases (
+    Alias STRING(64) NOT NULL,
+    WebFeatureID STRING(36) NOT NULL,
+    FOREIGN KEY (WebFeatureID) REFERENCES WebFeatures(ID),
+) PRIMARY KEY (Alias);
@@ -0,0 +1,29 @@
+-- Copyright 2024 Google LLC
+--
+-- Licensed under the Apache License, Version 2.0 (the "License");
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--     http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+
+-- FeatureCompatFeatures contains the BCD keys that compose a web feature.
+-- Comes from the compat_features field of the web features repo.
+CREATE TABLE IF NOT EXISTS FeatureCompatFeatures (
+    WebFeatureID STRING(36) NOT NULL,
+    CompatFeatures ARRAY<STRING(256)>,
+    FOREIGN KEY (WebFeatureID) REFERENCES WebFeatures(ID),
+) PRIMARY KEY (WebFeatureID);
+
+-- BrowserCompatFeatureSupport contains the version of a browser that added support for a BCD key.
+-- Information from https://github.com/mdn/browser-compat-data
+CREATE TABLE IF NOT EXISTS BrowserCompatFeatureSupport (
+    CompatFeature STRING(256) NOT NULL, -- BCD key. Example: api.AbortController.abort
+    BrowserName STRING(64) NOT NULL, -- From BCD not wpt.fyi.
+    VersionAdded STRING(16), -- NULL when the browser does not fully support the key. Can be a range such as ≤79.
+) PRIMARY KEY (CompatFeature, BrowserName);
@@ -0,0 +1,207 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package backendclient is a Go client for the webstatus.dev API.
+//
+// Client embeds the client generated from openapi/backend/openapi.yaml, so every endpoint is available
+// through the generated *WithResponse methods. In addition, the Iterate* methods of the paginated endpoints
+// return an Iterator that follows the next_page_token of each page.
+package backendclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/backend"
+)
+
+// Client is a client for the webstatus.dev API.
+type Client struct {
+	backend.ClientWithResponsesInterface
+}
+
+// NewClient returns a Client for the API hosted at server. e.g. https://api.webstatus.dev.
+func NewClient(server string, opts ...backend.ClientOption) (*Client, error) {
+	client, err := backend.NewClientWithResponses(server, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{ClientWithResponsesInterface: client}, nil
+}
+
+// APIError is returned by the iterators when the API responds with an error.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("webstatus.dev API returned status %d: %s", e.StatusCode, e.Message)
+}
+
+// newAPIError uses the message of the first error body that was decoded, if any.
+func newAPIError(statusCode int, models ...*backend.BasicErrorModel) error {
+	for _, model := range models {
+		if model != nil {
+			return &APIError{StatusCode: statusCode, Message: model.Message}
+		}
+	}
+
+	return &APIError{StatusCode: statusCode, Message: http.StatusText(statusCode)}
+}
+
+func nextPageToken(metadata *backend.PageMetadata) *string {
+	if metadata == nil {
+		return nil
+	}
+
+	return metadata.NextPageToken
+}
+
+// IterateFeatures returns an iterator over the features that match params.
+// The page token of params is ignored and params.PageSize is used as the size of each page.
+func (c *Client) IterateFeatures(
+	ctx context.Context,
+	params *backend.GetV1FeaturesParams,
+) *Iterator[backend.Feature] {
+	var pageParams backend.GetV1FeaturesParams
+	if params != nil {
+		pageParams = *params
+	}
+
+	return newIterator(ctx, func(ctx context.Context, pageToken *string) ([]backend.Feature, *string, error) {
+		pageParams.PageToken = pageToken
+		resp, err := c.GetV1FeaturesWithResponse(ctx, &pageParams)
+		if err != nil {
+			return nil, nil, err
+		}
+		if resp.JSON200 == nil {
+			return nil, nil, newAPIError(resp.StatusCode(), resp.JSON400, resp.JSON404, resp.JSON429, resp.JSON500)
+		}
+
+		return resp.JSON200.Data, resp.JSON200.Metadata.NextPageToken, nil
+	})
+}
+
+// IterateFeatureWPTMetrics returns an iterator over the WPT metrics of a feature.
+func (c *Client) IterateFeatureWPTMetrics(
+	ctx context.Context,
+	featureID string,
+	browser backend.BrowserPathParam,
+	channel backend.ChannelPathParam,
+	metricView backend.MetricViewPathParam,
+	params backend.ListFeatureWPTMetricsParams,
+) *Iterator[backend.WPTRunMetric] {
+	return newIterator(ctx, func(ctx context.Context, pageToken *string) ([]backend.WPTRunMetric, *string, error) {
+		params.PageToken = pageToken
+		resp, err := c.ListFeatureWPTMetricsWithResponse(ctx, featureID, browser, channel, metricView, &params)
+		if err != nil {
+			return nil, nil, err
+		}
+		if resp.JSON200 == nil {
+			return nil, nil, newAPIError(resp.StatusCode(), resp.JSON400, resp.JSON404, resp.JSON429, resp.JSON500)
+		}
+
+		return resp.JSON200.Data, nextPageToken(resp.JSON200.Metadata), nil
+	})
+}
+
+// IterateAggregatedWPTMetrics returns an iterator over the WPT metrics of a browser and channel.
+func (c *Client) IterateAggregatedWPTMetrics(
+	ctx context.Context,
+	browser backend.BrowserPathParam,
+	channel backend.ChannelPathParam,
+	metricView backend.MetricViewPathParam,
+	params backend.ListAggregatedWPTMetricsParams,
+) *Iterator[backend.WPTRunMetric] {
+	return newIterator(ctx, func(ctx context.Context, pageToken *string) ([]backend.WPTRunMetric, *string, error) {
+		params.PageToken = pageToken
+		resp, err := c.ListAggregatedWPTMetricsWithResponse(ctx, browser, channel, metricView, &params)
+		if err != nil {
+			return nil, nil, err
+		}
+		if resp.JSON200 == nil {
+			return nil, nil, newAPIError(resp.StatusCode(), resp.JSON400, resp.JSON404, resp.JSON429, resp.JSON500)
+		}
+
+		return resp.JSON200.Data, nextPageToken(resp.JSON200.Metadata), nil
+	})
+}
+
+// IterateInteropWPTMetrics returns an iterator over the WPT interop metrics of a channel.
+func (c *Client) IterateInteropWPTMetrics(
+	ctx context.Context,
+	channel backend.ChannelPathParam,
+	metricView backend.MetricViewPathParam,
+	params backend.ListInteropWPTMetricsParams,
+) *Iterator[backend.WPTRunMetric] {
+	return newIterator(ctx, func(ctx context.Context, pageToken *string) ([]backend.WPTRunMetric, *string, error) {
+		params.PageToken = pageToken
+		resp, err := c.ListInteropWPTMetricsWithResponse(ctx, channel, metricView, &params)
+		if err != nil {
+			return nil, nil, err
+		}
+		if resp.JSON200 == nil {
+			return nil, nil, newAPIError(resp.StatusCode(), resp.JSON400, resp.JSON429, resp.JSON500)
+		}
+
+		return resp.JSON200.Data, nextPageToken(resp.JSON200.Metadata), nil
+	})
+}
+
+// IterateAggregatedFeatureSupport returns an iterator over the number of features supported by a browser
+// at each of its releases.
+func (c *Client) IterateAggregatedFeatureSupport(
+	ctx context.Context,
+	browser backend.BrowserPathParam,
+	params backend.ListAggregatedFeatureSupportParams,
+) *Iterator[backend.BrowserReleaseFeatureMetric] {
+	return newIterator(ctx,
+		func(ctx context.Context, pageToken *string) ([]backend.BrowserReleaseFeatureMetric, *string, error) {
+			params.PageToken = pageToken
+			resp, err := c.ListAggregatedFeatureSupportWithResponse(ctx, browser, &params)
+			if err != nil {
+				return nil, nil, err
+			}
+			if resp.JSON200 == nil {
+				return nil, nil, newAPIError(resp.StatusCode(), resp.JSON400, resp.JSON404, resp.JSON429, resp.JSON500)
+			}
+
+			return resp.JSON200.Data, nextPageToken(resp.JSON200.Metadata), nil
+		})
+}
+
+// IterateFeatureLagMetrics returns an iterator over the number of features a browser lags behind
+// the browsers in params.
+func (c *Client) IterateFeatureLagMetrics(
+	ctx context.Context,
+	browser backend.BrowserPathParam,
+	params backend.ListFeatureLagMetricsParams,
+) *Iterator[backend.BrowserReleaseFeatureMetric] {
+	return newIterator(ctx,
+		func(ctx context.Context, pageToken *string) ([]backend.BrowserReleaseFeatureMetric, *string, error) {
+			params.PageToken = pageToken
+			resp, err := c.ListFeatureLagMetricsWithResponse(ctx, browser, &params)
+			if err != nil {
+				return nil, nil, err
+			}
+			if resp.JSON200 == nil {
+				return nil, nil, newAPIError(resp.StatusCode(), resp.JSON400, resp.JSON404, resp.JSON429, resp.JSON500)
+			}
+
+			return resp.JSON200.Data, nextPageToken(resp.JSON200.Metadata), nil
+		})
+}
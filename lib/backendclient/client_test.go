@@ -0,0 +1,118 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backendclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/backend"
+)
+
+func newTestServer(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	client, err := NewClient(srv.URL)
+	if err != nil {
+		t.Fatalf("unable to create client %v", err)
+	}
+
+	return client
+}
+
+func writeJSON(t *testing.T, w http.ResponseWriter, statusCode int, body any) {
+	t.Helper()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		t.Errorf("unable to write response %v", err)
+	}
+}
+
+func TestIterateFeatures(t *testing.T) {
+	var requests []string
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RawQuery)
+		if r.URL.Path != "/v1/features" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		//nolint: exhaustruct // Only the fields used by the iterator are needed.
+		page := backend.FeaturePage{
+			Data: []backend.Feature{{FeatureId: "feature1", Name: "Feature 1"}},
+			Metadata: backend.PageMetadataWithTotal{
+				NextPageToken: valuePtr("token1"),
+				Total:         2,
+			},
+		}
+		if r.URL.Query().Get("page_token") == "token1" {
+			//nolint: exhaustruct // Only the fields used by the iterator are needed.
+			page = backend.FeaturePage{
+				Data: []backend.Feature{{FeatureId: "feature2", Name: "Feature 2"}},
+				Metadata: backend.PageMetadataWithTotal{
+					NextPageToken: nil,
+					Total:         2,
+				},
+			}
+		}
+		writeJSON(t, w, http.StatusOK, page)
+	})
+
+	//nolint: exhaustruct // Only the fields used by the test are needed.
+	features, err := client.IterateFeatures(context.Background(), &backend.GetV1FeaturesParams{
+		Q:        valuePtr("baseline_status:widely"),
+		PageSize: valuePtr(1),
+	}).All()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	ids := make([]string, 0, len(features))
+	for _, feature := range features {
+		ids = append(ids, feature.FeatureId)
+	}
+	if !slices.Equal(ids, []string{"feature1", "feature2"}) {
+		t.Errorf("unexpected features %v", ids)
+	}
+	expectedRequests := []string{
+		"page_size=1&q=baseline_status%3Awidely",
+		"page_size=1&page_token=token1&q=baseline_status%3Awidely",
+	}
+	if !slices.Equal(requests, expectedRequests) {
+		t.Errorf("unexpected requests. expected %v received %v", expectedRequests, requests)
+	}
+}
+
+func TestIterateFeaturesAPIError(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(t, w, http.StatusTooManyRequests, backend.BasicErrorModel{
+			Code:    http.StatusTooManyRequests,
+			Message: "rate limit exceeded",
+		})
+	})
+
+	_, err := client.IterateFeatures(context.Background(), nil).Next()
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected APIError, received %v", err)
+	}
+	if apiErr.StatusCode != http.StatusTooManyRequests || apiErr.Message != "rate limit exceeded" {
+		t.Errorf("unexpected error %+v", apiErr)
+	}
+}
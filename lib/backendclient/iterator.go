@@ -0,0 +1,92 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backendclient
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/api/iterator"
+)
+
+// pageFetcher returns the items of the page for the given token and the token of the next page.
+// A nil or empty next page token means that there are no more pages.
+type pageFetcher[T any] func(ctx context.Context, pageToken *string) ([]T, *string, error)
+
+// Iterator returns the items of a paginated endpoint one at a time.
+// Pages are requested as needed by following the next_page_token of the previous page.
+type Iterator[T any] struct {
+	ctx       context.Context
+	fetch     pageFetcher[T]
+	items     []T
+	pageToken *string
+	started   bool
+	err       error
+}
+
+func newIterator[T any](ctx context.Context, fetch pageFetcher[T]) *Iterator[T] {
+	return &Iterator[T]{
+		ctx:       ctx,
+		fetch:     fetch,
+		items:     nil,
+		pageToken: nil,
+		started:   false,
+		err:       nil,
+	}
+}
+
+// Next returns the next item. It returns iterator.Done when there are no more items.
+// Once an error is returned, every following call returns the same error.
+func (it *Iterator[T]) Next() (T, error) {
+	var zero T
+	for len(it.items) == 0 {
+		if it.err != nil {
+			return zero, it.err
+		}
+		if it.started && (it.pageToken == nil || *it.pageToken == "") {
+			it.err = iterator.Done
+
+			return zero, it.err
+		}
+		items, nextPageToken, err := it.fetch(it.ctx, it.pageToken)
+		if err != nil {
+			it.err = err
+
+			return zero, it.err
+		}
+		it.started = true
+		it.items = items
+		it.pageToken = nextPageToken
+	}
+	item := it.items[0]
+	it.items = it.items[1:]
+
+	return item, nil
+}
+
+// All reads the remaining items of the iterator. It returns an empty slice when there are no items.
+func (it *Iterator[T]) All() ([]T, error) {
+	items := []T{}
+	for {
+		item, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			return items, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backendclient
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"google.golang.org/api/iterator"
+)
+
+func valuePtr[T any](in T) *T { return &in }
+
+type testPage struct {
+	items         []int
+	nextPageToken *string
+}
+
+// fakePages returns a pageFetcher that serves the pages keyed by page token.
+// The first page is keyed by the empty string.
+func fakePages(pages map[string]testPage, err error, requestedTokens *[]string) pageFetcher[int] {
+	return func(_ context.Context, pageToken *string) ([]int, *string, error) {
+		token := ""
+		if pageToken != nil {
+			token = *pageToken
+		}
+		*requestedTokens = append(*requestedTokens, token)
+		page, found := pages[token]
+		if !found {
+			return nil, nil, err
+		}
+
+		return page.items, page.nextPageToken, nil
+	}
+}
+
+var errTestFetch = errors.New("fetch failed")
+
+func TestIterator(t *testing.T) {
+	testCases := []struct {
+		name                    string
+		pages                   map[string]testPage
+		expectedItems           []int
+		expectedRequestedTokens []string
+		expectedError           error
+	}{
+		{
+			name: "single page",
+			pages: map[string]testPage{
+				"": {items: []int{1, 2}, nextPageToken: nil},
+			},
+			expectedItems:           []int{1, 2},
+			expectedRequestedTokens: []string{""},
+			expectedError:           iterator.Done,
+		},
+		{
+			name: "multiple pages",
+			pages: map[string]testPage{
+				"":      {items: []int{1, 2}, nextPageToken: valuePtr("page2")},
+				"page2": {items: []int{3}, nextPageToken: valuePtr("page3")},
+				"page3": {items: []int{4}, nextPageToken: valuePtr("")},
+			},
+			expectedItems:           []int{1, 2, 3, 4},
+			expectedRequestedTokens: []string{"", "page2", "page3"},
+			expectedError:           iterator.Done,
+		},
+		{
+			name: "empty page with a next page token",
+			pages: map[string]testPage{
+				"":      {items: nil, nextPageToken: valuePtr("page2")},
+				"page2": {items: []int{1}, nextPageToken: nil},
+			},
+			expectedItems:           []int{1},
+			expectedRequestedTokens: []string{"", "page2"},
+			expectedError:           iterator.Done,
+		},
+		{
+			name:                    "no items",
+			pages:                   map[string]testPage{"": {items: nil, nextPageToken: nil}},
+			expectedItems:           nil,
+			expectedRequestedTokens: []string{""},
+			expectedError:           iterator.Done,
+		},
+		{
+			name: "error on second page",
+			pages: map[string]testPage{
+				"": {items: []int{1}, nextPageToken: valuePtr("page2")},
+			},
+			expectedItems:           []int{1},
+			expectedRequestedTokens: []string{"", "page2"},
+			expectedError:           errTestFetch,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var requestedTokens []string
+			it := newIterator(context.Background(), fakePages(tc.pages, errTestFetch, &requestedTokens))
+			var items []int
+			var err error
+			for {
+				var item int
+				item, err = it.Next()
+				if err != nil {
+					break
+				}
+				items = append(items, item)
+			}
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("unexpected error. expected %v received %v", tc.expectedError, err)
+			}
+			if !slices.Equal(items, tc.expectedItems) {
+				t.Errorf("unexpected items. expected %v received %v", tc.expectedItems, items)
+			}
+			if !slices.Equal(requestedTokens, tc.expectedRequestedTokens) {
+				t.Errorf("unexpected requested tokens. expected %v received %v",
+					tc.expectedRequestedTokens, requestedTokens)
+			}
+			// The iterator keeps returning the same error without fetching again.
+			_, err = it.Next()
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("unexpected error on second call. expected %v received %v", tc.expectedError, err)
+			}
+			if len(requestedTokens) != len(tc.expectedRequestedTokens) {
+				t.Error("expected no more requests after the iterator stopped")
+			}
+		})
+	}
+}
+
+func TestIteratorAll(t *testing.T) {
+	var requestedTokens []string
+	it := newIterator(context.Background(), fakePages(map[string]testPage{
+		"":      {items: []int{1, 2}, nextPageToken: valuePtr("page2")},
+		"page2": {items: []int{3}, nextPageToken: nil},
+	}, errTestFetch, &requestedTokens))
+	items, err := it.All()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !slices.Equal(items, []int{1, 2, 3}) {
+		t.Errorf("unexpected items %v", items)
+	}
+
+	it = newIterator(context.Background(), fakePages(nil, errTestFetch, &requestedTokens))
+	items, err = it.All()
+	if !errors.Is(err, errTestFetch) {
+		t.Errorf("unexpected error %v", err)
+	}
+	if items != nil {
+		t.Errorf("expected no items, received %v", items)
+	}
+}
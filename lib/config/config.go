@@ -0,0 +1,323 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package config loads the typed settings of a binary from flags, environment variables and an optional file.
+//
+// Settings are the fields of a struct with an `env` tag. The value of the field before loading is its default.
+// Other supported tags:
+//   - `required:"true"` fails the load if the setting is still the zero value (or nil) after loading.
+//   - `secret:"true"` redacts the value when printed.
+//   - `usage:"..."` describes the setting in the flag help.
+//
+// Nested structs without an `env` tag are searched for settings too, so groups of settings can be shared.
+// Each setting can be set with a flag named after the environment variable, e.g. PROJECT_ID is --project-id.
+// Flags take precedence over environment variables, which take precedence over the file given by
+// --config-file or CONFIG_FILE. The file is a JSON object keyed by the environment variable names.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrPrintedConfig is returned by Load after printing the configuration for --print-config.
+var ErrPrintedConfig = errors.New("configuration printed")
+
+// ValidationError lists every problem found while loading the configuration.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration: " + strings.Join(e.Problems, "; ")
+}
+
+// Validator can be implemented by a settings struct to check the settings after loading,
+// such as settings that depend on each other.
+type Validator interface {
+	Validate() []error
+}
+
+const (
+	configFileEnv   = "CONFIG_FILE"
+	configFileFlag  = "config-file"
+	printConfigFlag = "print-config"
+	redacted        = "REDACTED"
+)
+
+// nolint: gochecknoglobals // WONTFIX. Type used to identify duration fields.
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// setting is a single field with an env tag.
+type setting struct {
+	env      string
+	usage    string
+	required bool
+	secret   bool
+	value    reflect.Value
+}
+
+func (s setting) flagName() string {
+	return strings.ReplaceAll(strings.ToLower(s.env), "_", "-")
+}
+
+// Load populates cfg, which must be a pointer to a struct, from the args, environment and file.
+// If --print-config is in the args, the configuration is written to out and ErrPrintedConfig is returned.
+func Load(cfg any, args []string, lookupEnv func(string) (string, bool), out io.Writer) error {
+	root := reflect.ValueOf(cfg)
+	if root.Kind() != reflect.Pointer || root.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("config must be a pointer to a struct. received %T", cfg)
+	}
+	settings, err := collectSettings(root.Elem())
+	if err != nil {
+		return err
+	}
+
+	flagValues := make(map[string]string)
+	flagSet := flag.NewFlagSet("config", flag.ContinueOnError)
+	flagSet.SetOutput(out)
+	for _, s := range settings {
+		env := s.env
+		flagSet.Func(s.flagName(), s.usage, func(value string) error {
+			flagValues[env] = value
+
+			return nil
+		})
+	}
+	configFile := flagSet.String(configFileFlag, "", "JSON file with settings keyed by environment variable name")
+	printConfig := flagSet.Bool(printConfigFlag, false, "print the configuration with secrets redacted and exit")
+	err = flagSet.Parse(args)
+	if err != nil {
+		return &ValidationError{Problems: []string{err.Error()}}
+	}
+
+	var problems []string
+	fileValues := make(map[string]string)
+	if *configFile == "" {
+		*configFile, _ = lookupEnv(configFileEnv)
+	}
+	if *configFile != "" {
+		fileValues, err = readConfigFile(*configFile)
+		if err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	for _, s := range settings {
+		raw, found := flagValues[s.env]
+		if !found {
+			raw, found = lookupEnv(s.env)
+		}
+		if !found {
+			raw, found = fileValues[s.env]
+		}
+		if found {
+			err := setValue(s.value, raw)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %s", s.env, err.Error()))
+
+				continue
+			}
+		}
+		if s.required && s.value.IsZero() {
+			problems = append(problems, fmt.Sprintf("%s is required", s.env))
+		}
+	}
+
+	for _, err := range validate(root.Elem()) {
+		problems = append(problems, err.Error())
+	}
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+
+	if *printConfig {
+		err := Print(out, cfg)
+		if err != nil {
+			return err
+		}
+
+		return ErrPrintedConfig
+	}
+
+	return nil
+}
+
+// MustLoad loads cfg from the process's arguments and environment.
+// It exits the process if the configuration is invalid or was printed.
+func MustLoad(cfg any) {
+	err := Load(cfg, os.Args[1:], os.LookupEnv, os.Stdout)
+	if errors.Is(err, ErrPrintedConfig) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
+
+// Print writes every setting of cfg as ENV=value, one per line, with secrets redacted.
+func Print(out io.Writer, cfg any) error {
+	settings, err := collectSettings(reflect.ValueOf(cfg).Elem())
+	if err != nil {
+		return err
+	}
+	for _, s := range settings {
+		value := formatValue(s.value)
+		if s.secret && !s.value.IsZero() {
+			value = redacted
+		}
+		_, err := fmt.Fprintf(out, "%s=%s\n", s.env, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func collectSettings(value reflect.Value) ([]setting, error) {
+	var settings []setting
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		env, found := field.Tag.Lookup("env")
+		if !found {
+			if field.Type.Kind() == reflect.Struct {
+				nested, err := collectSettings(value.Field(i))
+				if err != nil {
+					return nil, err
+				}
+				settings = append(settings, nested...)
+			}
+
+			continue
+		}
+		if !field.IsExported() {
+			return nil, fmt.Errorf("setting %s must be exported", env)
+		}
+		if !isSupportedType(field.Type) {
+			return nil, fmt.Errorf("unsupported type %s for setting %s", field.Type, env)
+		}
+		settings = append(settings, setting{
+			env:      env,
+			usage:    field.Tag.Get("usage"),
+			required: field.Tag.Get("required") == "true",
+			secret:   field.Tag.Get("secret") == "true",
+			value:    value.Field(i),
+		})
+	}
+
+	return settings, nil
+}
+
+func isSupportedType(t reflect.Type) bool {
+	if t == durationType {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Int, reflect.Bool:
+		return true
+	case reflect.Pointer:
+		return t.Elem().Kind() == reflect.String
+	default:
+		return false
+	}
+}
+
+func setValue(value reflect.Value, raw string) error {
+	if value.Type() == durationType {
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", raw)
+		}
+		value.SetInt(int64(duration))
+
+		return nil
+	}
+	// nolint:exhaustive // Only the types allowed by isSupportedType are handled.
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Pointer:
+		value.Set(reflect.ValueOf(&raw))
+	case reflect.Int:
+		number, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		value.SetInt(int64(number))
+	case reflect.Bool:
+		boolean, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		value.SetBool(boolean)
+	}
+
+	return nil
+}
+
+func formatValue(value reflect.Value) string {
+	if value.Type() == durationType {
+		return time.Duration(value.Int()).String()
+	}
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return "<unset>"
+		}
+
+		return value.Elem().String()
+	}
+
+	return fmt.Sprint(value.Interface())
+}
+
+// validate calls Validate on the struct and every nested struct that implements Validator.
+func validate(value reflect.Value) []error {
+	var errs []error
+	if validator, ok := value.Addr().Interface().(Validator); ok {
+		errs = append(errs, validator.Validate()...)
+	}
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if _, found := field.Tag.Lookup("env"); found || field.Type.Kind() != reflect.Struct {
+			continue
+		}
+		errs = append(errs, validate(value.Field(i))...)
+	}
+
+	return errs
+}
+
+func readConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read config file %s: %s", path, err.Error())
+	}
+	values := make(map[string]string)
+	err = json.Unmarshal(data, &values)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse config file %s: %s", path, err.Error())
+	}
+
+	return values, nil
+}
@@ -0,0 +1,214 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testConfig struct {
+	GCP
+	Datastore Datastore
+	Redis     Redis
+	GitHub    GitHub
+	Telemetry OpenTelemetry
+	PageLimit int  `env:"PAGE_LIMIT" usage:"page limit"`
+	Verbose   bool `env:"VERBOSE"`
+}
+
+func newTestConfig() testConfig {
+	return testConfig{
+		GCP:       GCP{ProjectID: ""},
+		Datastore: Datastore{Database: nil},
+		Redis:     NewRedis(),
+		GitHub:    GitHub{Token: ""},
+		Telemetry: OpenTelemetry{ServiceName: "", ProjectID: ""},
+		PageLimit: 100,
+		Verbose:   false,
+	}
+}
+
+func envLookup(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, found := env[key]
+
+		return value, found
+	}
+}
+
+func validEnv() map[string]string {
+	return map[string]string{
+		"PROJECT_ID":         "project",
+		"DATASTORE_DATABASE": "",
+		"REDISHOST":          "redis",
+		"CACHE_TTL":          "5m",
+	}
+}
+
+func valuePtr[T any](in T) *T { return &in }
+
+func TestLoad(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.json")
+	err := os.WriteFile(configFile, []byte(`{"PAGE_LIMIT": "5", "REDISHOST": "file-redis"}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedDefaults := newTestConfig()
+	expectedDefaults.ProjectID = "project"
+	expectedDefaults.Datastore.Database = valuePtr("")
+	expectedDefaults.Redis.Host = "redis"
+	expectedDefaults.Redis.CacheTTL = 5 * time.Minute
+
+	testCases := []struct {
+		name             string
+		args             []string
+		env              map[string]string
+		expectedConfig   *testConfig
+		expectedProblems []string
+	}{
+		{
+			name:             "defaults",
+			args:             nil,
+			env:              validEnv(),
+			expectedConfig:   &expectedDefaults,
+			expectedProblems: nil,
+		},
+		{
+			name: "flags override environment which overrides file",
+			args: []string{"--project-id", "flag-project", "--verbose=true", "--config-file", configFile},
+			env:  validEnv(),
+			expectedConfig: func() *testConfig {
+				cfg := expectedDefaults
+				cfg.ProjectID = "flag-project"
+				cfg.Verbose = true
+				cfg.PageLimit = 5
+
+				return &cfg
+			}(),
+			expectedProblems: nil,
+		},
+		{
+			name: "every problem is listed",
+			args: nil,
+			env: map[string]string{
+				"CACHE_TTL":         "five minutes",
+				"CACHE_CONNECTIONS": "0",
+				"PAGE_LIMIT":        "many",
+				"OTEL_SERVICE_NAME": "backend",
+			},
+			expectedConfig: nil,
+			expectedProblems: []string{
+				"PROJECT_ID is required",
+				"DATASTORE_DATABASE is required",
+				"REDISHOST is required",
+				`CACHE_TTL: invalid duration "five minutes"`,
+				`PAGE_LIMIT: invalid integer "many"`,
+				"CACHE_CONNECTIONS must be positive",
+				"OTEL_GCP_PROJECT_ID is required when OTEL_SERVICE_NAME is set",
+			},
+		},
+		{
+			name:             "missing config file",
+			args:             []string{"--config-file", filepath.Join(t.TempDir(), "missing.json")},
+			env:              validEnv(),
+			expectedConfig:   nil,
+			expectedProblems: []string{"unable to read config file"},
+		},
+		{
+			name:             "unknown flag",
+			args:             []string{"--unknown"},
+			env:              validEnv(),
+			expectedConfig:   nil,
+			expectedProblems: []string{"flag provided but not defined: -unknown"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := newTestConfig()
+			var out bytes.Buffer
+			err := Load(&cfg, tc.args, envLookup(tc.env), &out)
+			if tc.expectedProblems == nil {
+				if err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+				if !reflect.DeepEqual(&cfg, tc.expectedConfig) {
+					t.Errorf("unexpected config.\nexpected %+v\nreceived %+v", tc.expectedConfig, cfg)
+				}
+
+				return
+			}
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected validation error. received %v", err)
+			}
+			if len(validationErr.Problems) != len(tc.expectedProblems) {
+				t.Fatalf("unexpected problems %v", validationErr.Problems)
+			}
+			for i, problem := range tc.expectedProblems {
+				if !bytes.HasPrefix([]byte(validationErr.Problems[i]), []byte(problem)) {
+					t.Errorf("unexpected problem %q. expected %q", validationErr.Problems[i], problem)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadPrintConfig(t *testing.T) {
+	cfg := newTestConfig()
+	env := validEnv()
+	env["GITHUB_TOKEN"] = "secret-token"
+	var out bytes.Buffer
+	err := Load(&cfg, []string{"--print-config"}, envLookup(env), &out)
+	if !errors.Is(err, ErrPrintedConfig) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	expected := `PROJECT_ID=project
+DATASTORE_DATABASE=
+REDISHOST=redis
+REDISPORT=6379
+CACHE_TTL=5m0s
+CACHE_CONNECTIONS=10
+GITHUB_TOKEN=REDACTED
+OTEL_SERVICE_NAME=
+OTEL_GCP_PROJECT_ID=
+PAGE_LIMIT=100
+VERBOSE=false
+`
+	if out.String() != expected {
+		t.Errorf("unexpected output.\nexpected %s\nreceived %s", expected, out.String())
+	}
+}
+
+func TestLoadUnsupportedConfig(t *testing.T) {
+	type badConfig struct {
+		Rate float64 `env:"RATE"`
+	}
+	err := Load(&badConfig{Rate: 0}, nil, envLookup(nil), &bytes.Buffer{})
+	if err == nil {
+		t.Error("expected error for unsupported type")
+	}
+	err = Load(badConfig{Rate: 0}, nil, envLookup(nil), &bytes.Buffer{})
+	if err == nil {
+		t.Error("expected error for non pointer config")
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+// The settings below are shared by multiple binaries.
+
+// GCP contains the settings of the Google Cloud project.
+type GCP struct {
+	ProjectID string `env:"PROJECT_ID" required:"true" usage:"Google Cloud project ID"`
+}
+
+// Spanner contains the settings to connect to Spanner.
+type Spanner struct {
+	Instance string `env:"SPANNER_INSTANCE" required:"true" usage:"Spanner instance"`
+	Database string `env:"SPANNER_DATABASE" required:"true" usage:"Spanner database"`
+	// The Spanner client library reads the emulator host from the environment itself,
+	// so this setting only has an effect when set as an environment variable.
+	EmulatorHost string `env:"SPANNER_EMULATOR_HOST" usage:"Spanner emulator host, for local runs"`
+}
+
+// Datastore contains the settings to connect to Datastore.
+type Datastore struct {
+	// An empty value is the default database. It is a pointer to require it to be set explicitly.
+	Database *string `env:"DATASTORE_DATABASE" required:"true" usage:"Datastore database. Empty for the default"`
+}
+
+// Redis contains the settings of the redis cache.
+type Redis struct {
+	Host        string        `env:"REDISHOST" required:"true" usage:"Redis host"`
+	Port        string        `env:"REDISPORT" usage:"Redis port"`
+	CacheTTL    time.Duration `env:"CACHE_TTL" required:"true" usage:"How long cached responses are kept"`
+	Connections int           `env:"CACHE_CONNECTIONS" usage:"Maximum number of idle redis connections"`
+}
+
+// NewRedis returns the Redis settings with their defaults.
+func NewRedis() Redis {
+	return Redis{
+		Host:        "",
+		Port:        "6379",
+		CacheTTL:    0,
+		Connections: 10,
+	}
+}
+
+func (r *Redis) Validate() []error {
+	if r.Connections <= 0 {
+		return []error{errors.New("CACHE_CONNECTIONS must be positive")}
+	}
+
+	return nil
+}
+
+// GitHub contains the settings to use the GitHub API.
+type GitHub struct {
+	// Optional. Requests are unauthenticated, with a lower rate limit, without it.
+	Token string `env:"GITHUB_TOKEN" secret:"true" usage:"GitHub token"`
+}
+
+// Webhooks contains the settings of the subscription callbacks.
+type Webhooks struct {
+	// Only for local development, where the receivers run next to the services.
+	AllowLoopback bool `env:"WEBHOOKS_ALLOW_LOOPBACK" usage:"Allow callbacks to loopback addresses over http"`
+}
+
+// OpenTelemetry contains the settings to export telemetry. Telemetry is disabled without a service name.
+type OpenTelemetry struct {
+	ServiceName string `env:"OTEL_SERVICE_NAME" usage:"Service name for telemetry. Empty disables telemetry"`
+	ProjectID   string `env:"OTEL_GCP_PROJECT_ID" usage:"Google Cloud project ID to export telemetry to"`
+}
+
+// Enabled returns if telemetry should be set up.
+func (o OpenTelemetry) Enabled() bool {
+	return o.ServiceName != ""
+}
+
+func (o *OpenTelemetry) Validate() []error {
+	if o.Enabled() && o.ProjectID == "" {
+		return []error{errors.New("OTEL_GCP_PROJECT_ID is required when OTEL_SERVICE_NAME is set")}
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcpspanner
+
+import (
+	"context"
+	"errors"
+
+	"cloud.google.com/go/spanner"
+)
+
+const browserCompatFeatureSupportTable = "BrowserCompatFeatureSupport"
+
+// browserCompatFeatureSupportBatchSize is the number of rows written per transaction.
+// BCD has tens of thousands of keys, which is too many for one transaction or one transaction per row.
+const browserCompatFeatureSupportBatchSize = 1000
+
+// BrowserCompatFeatureSupport contains the version of a browser that added support for a BCD key.
+type BrowserCompatFeatureSupport struct {
+	CompatFeature string `spanner:"CompatFeature"`
+	BrowserName   string `spanner:"BrowserName"`
+	// VersionAdded is nil if the browser does not fully support the BCD key.
+	VersionAdded *string `spanner:"VersionAdded"`
+}
+
+// UpsertBrowserCompatFeatureSupports stores the browser support of BCD keys.
+// Existing rows are overwritten so that support that is later removed from BCD is also removed here.
+func (c *Client) UpsertBrowserCompatFeatureSupports(
+	ctx context.Context, supports []BrowserCompatFeatureSupport) error {
+	for start := 0; start < len(supports); start += browserCompatFeatureSupportBatchSize {
+		batch := supports[start:min(start+browserCompatFeatureSupportBatchSize, len(supports))]
+		_, err := c.ReadWriteTransaction(ctx, func(_ context.Context, txn *spanner.ReadWriteTransaction) error {
+			mutations := make([]*spanner.Mutation, 0, len(batch))
+			for _, support := range batch {
+				m, err := spanner.InsertOrUpdateStruct(browserCompatFeatureSupportTable, support)
+				if err != nil {
+					return errors.Join(ErrInternalQueryFailure, err)
+				}
+				mutations = append(mutations, m)
+			}
+
+			return txn.BufferWrite(mutations)
+		})
+		if err != nil {
+			return errors.Join(ErrInternalQueryFailure, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcpspanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/api/iterator"
+)
+
+// Helper method to get all the browser support in a stable order.
+func (c *Client) readAllBrowserCompatFeatureSupports(
+	ctx context.Context, t *testing.T) []BrowserCompatFeatureSupport {
+	stmt := spanner.NewStatement(`
+	SELECT CompatFeature, BrowserName, VersionAdded
+	FROM BrowserCompatFeatureSupport
+	ORDER BY CompatFeature, BrowserName`)
+	it := c.Single().Query(ctx, stmt)
+	defer it.Stop()
+
+	var ret []BrowserCompatFeatureSupport
+	for {
+		row, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error. %s", err.Error())
+		}
+		var support BrowserCompatFeatureSupport
+		if err := row.ToStruct(&support); err != nil {
+			t.Fatalf("unexpected error. %s", err.Error())
+		}
+		ret = append(ret, support)
+	}
+
+	return ret
+}
+
+func TestUpsertBrowserCompatFeatureSupports(t *testing.T) {
+	client := getTestDatabase(t)
+	ctx := context.Background()
+
+	// Use more rows than a single batch.
+	var supports []BrowserCompatFeatureSupport
+	for i := 0; i < browserCompatFeatureSupportBatchSize+1; i++ {
+		supports = append(supports, BrowserCompatFeatureSupport{
+			CompatFeature: fmt.Sprintf("api.Feature%04d", i),
+			BrowserName:   "chrome",
+			VersionAdded:  valuePtr("100"),
+		})
+	}
+	err := client.UpsertBrowserCompatFeatureSupports(ctx, supports)
+	if err != nil {
+		t.Fatalf("unexpected error during insert. %s", err.Error())
+	}
+	result := client.readAllBrowserCompatFeatureSupports(ctx, t)
+	if !reflect.DeepEqual(supports, result) {
+		t.Errorf("unexpected rows. expected %d rows received %d rows", len(supports), len(result))
+	}
+
+	// Support that is removed from BCD is removed.
+	err = client.UpsertBrowserCompatFeatureSupports(ctx, []BrowserCompatFeatureSupport{
+		{CompatFeature: "api.Feature0000", BrowserName: "chrome", VersionAdded: nil},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error during update. %s", err.Error())
+	}
+	result = client.readAllBrowserCompatFeatureSupports(ctx, t)
+	if result[0].VersionAdded != nil {
+		t.Errorf("expected support to be removed. received %s", *result[0].VersionAdded)
+	}
+	if len(result) != len(supports) {
+		t.Errorf("unexpected number of rows. expected %d received %d", len(supports), len(result))
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcpspanner
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/api/iterator"
+)
+
+// BrowserPairFeatureGap contains the number of features available in
+// BrowserA but not in BrowserB.
+type BrowserPairFeatureGap struct {
+	BrowserA     string `spanner:"BrowserA"`
+	BrowserB     string `spanner:"BrowserB"`
+	FeatureCount int64  `spanner:"FeatureCount"`
+}
+
+// browserCompatMatrixQuery counts, for every ordered pair of the given
+// browsers, the features available in the first browser but not the second.
+// A feature is available if it shipped in a release on or before @at.
+// Excluded features are not counted.
+const browserCompatMatrixQuery = `
+WITH AvailableFeatures AS (
+	SELECT DISTINCT
+		bfa.BrowserName,
+		bfa.WebFeatureID
+	FROM BrowserFeatureAvailabilities bfa
+	JOIN BrowserReleases br
+		ON bfa.BrowserName = br.BrowserName AND bfa.BrowserVersion = br.BrowserVersion
+	JOIN WebFeatures wf ON wf.ID = bfa.WebFeatureID
+	LEFT OUTER JOIN ExcludedFeatureKeys efk ON wf.FeatureKey = efk.FeatureKey
+	WHERE bfa.BrowserName IN UNNEST(@browserNames)
+		AND br.ReleaseDate <= @at
+		AND ` + removeExcludedKeyFilter + `
+)
+SELECT
+	pairs.BrowserA,
+	pairs.BrowserB,
+	(
+		SELECT COUNT(*)
+		FROM AvailableFeatures a
+		WHERE a.BrowserName = pairs.BrowserA
+			AND NOT EXISTS (
+				SELECT 1
+				FROM AvailableFeatures b
+				WHERE b.BrowserName = pairs.BrowserB AND b.WebFeatureID = a.WebFeatureID
+			)
+	) AS FeatureCount
+FROM (
+	SELECT browserA AS BrowserA, browserB AS BrowserB
+	FROM UNNEST(@browserNames) AS browserA
+	CROSS JOIN UNNEST(@browserNames) AS browserB
+	WHERE browserA != browserB
+) pairs
+ORDER BY pairs.BrowserA ASC, pairs.BrowserB ASC`
+
+// ListBrowserCompatMatrix returns the feature gap for every ordered pair of
+// browsers as of the given time.
+func (c *Client) ListBrowserCompatMatrix(
+	ctx context.Context,
+	browsers []string,
+	at time.Time,
+) ([]BrowserPairFeatureGap, error) {
+	stmt := spanner.NewStatement(browserCompatMatrixQuery)
+	stmt.Params = map[string]interface{}{
+		"browserNames": browsers,
+		"at":           at,
+	}
+
+	txn := c.Single()
+	defer txn.Close()
+	it := txn.Query(ctx, stmt)
+	defer it.Stop()
+
+	var gaps []BrowserPairFeatureGap
+	for {
+		row, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, errors.Join(ErrInternalQueryFailure, err)
+		}
+		var gap BrowserPairFeatureGap
+		if err := row.ToStruct(&gap); err != nil {
+			return nil, errors.Join(ErrInternalQueryFailure, err)
+		}
+		gaps = append(gaps, gap)
+	}
+
+	return gaps, nil
+}
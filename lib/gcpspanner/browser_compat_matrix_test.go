@@ -0,0 +1,86 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcpspanner
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestListBrowserCompatMatrix(t *testing.T) {
+	client := getTestDatabase(t)
+	ctx := context.Background()
+	setupRequiredTablesForBrowserFeatureAvailability(ctx, client, t)
+	for _, availability := range getSampleBrowserAvailabilities() {
+		err := client.InsertBrowserFeatureAvailability(
+			ctx, availability.FeatureKey, availability.BrowserFeatureAvailability)
+		if err != nil {
+			t.Errorf("unexpected error during insert of availabilities. %s", err.Error())
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		browsers []string
+		at       time.Time
+		expected []BrowserPairFeatureGap
+	}{
+		{
+			name:     "before any barBrowser availability",
+			browsers: []string{"fooBrowser", "barBrowser"},
+			at:       time.Date(2000, time.January, 15, 0, 0, 0, 0, time.UTC),
+			expected: []BrowserPairFeatureGap{
+				{BrowserA: "barBrowser", BrowserB: "fooBrowser", FeatureCount: 0},
+				{BrowserA: "fooBrowser", BrowserB: "barBrowser", FeatureCount: 1},
+			},
+		},
+		{
+			name:     "release date is inclusive and browsers without data",
+			browsers: []string{"fooBrowser", "barBrowser", "bazBrowser"},
+			at:       time.Date(2000, time.February, 1, 0, 0, 0, 0, time.UTC),
+			expected: []BrowserPairFeatureGap{
+				{BrowserA: "barBrowser", BrowserB: "bazBrowser", FeatureCount: 0},
+				{BrowserA: "barBrowser", BrowserB: "fooBrowser", FeatureCount: 0},
+				{BrowserA: "bazBrowser", BrowserB: "barBrowser", FeatureCount: 0},
+				{BrowserA: "bazBrowser", BrowserB: "fooBrowser", FeatureCount: 0},
+				{BrowserA: "fooBrowser", BrowserB: "barBrowser", FeatureCount: 2},
+				{BrowserA: "fooBrowser", BrowserB: "bazBrowser", FeatureCount: 2},
+			},
+		},
+		{
+			name:     "all features available everywhere",
+			browsers: []string{"fooBrowser", "barBrowser"},
+			at:       time.Date(2000, time.April, 1, 0, 0, 0, 0, time.UTC),
+			expected: []BrowserPairFeatureGap{
+				{BrowserA: "barBrowser", BrowserB: "fooBrowser", FeatureCount: 0},
+				{BrowserA: "fooBrowser", BrowserB: "barBrowser", FeatureCount: 0},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gaps, err := client.ListBrowserCompatMatrix(ctx, tc.browsers, tc.at)
+			if err != nil {
+				t.Fatalf("unexpected error. %s", err.Error())
+			}
+			if !slices.Equal(tc.expected, gaps) {
+				t.Errorf("unequal gaps.\nexpected %+v\nreceived %+v", tc.expected, gaps)
+			}
+		})
+	}
+}
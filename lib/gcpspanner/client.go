@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"math/big"
 	"time"
 
 	"cloud.google.com/go/spanner"
@@ -72,6 +73,18 @@ func (c *Client) SetFeatureSearchBaseQuery(query FeatureSearchBaseQuery) {
 	c.featureSearchQuery = query
 }
 
+// Ping runs a trivial query to check that a session can be used to reach the database.
+func (c *Client) Ping(ctx context.Context) error {
+	it := c.Single().Query(ctx, spanner.NewStatement("SELECT 1"))
+	defer it.Stop()
+	_, err := it.Next()
+	if err != nil {
+		return errors.Join(ErrInternalQueryFailure, err)
+	}
+
+	return nil
+}
+
 // WPTRunCursor: Represents a point for resuming queries based on the last
 // TimeStart and ExternalRunID. Useful for pagination.
 type WPTRunCursor struct {
@@ -79,6 +92,13 @@ type WPTRunCursor struct {
 	LastRunID     int64     `json:"last_run_id"`
 }
 
+// WPTInteropCursor: Represents a point for resuming interop queries based on
+// the last TimeStart and WPT revision. Useful for pagination.
+type WPTInteropCursor struct {
+	LastTimeStart time.Time `json:"last_time_start"`
+	LastRevision  string    `json:"last_revision"`
+}
+
 // FeatureResultOffsetCursor: A numerical offset from the start of the result set. Enables the construction of
 // human-friendly URLs specifying an exact page offset.
 // Disclaimer: External users should be aware that the format of this token is subject to change and should not be
@@ -87,25 +107,62 @@ type FeatureResultOffsetCursor struct {
 	Offset int `json:"offset"`
 }
 
+// FeatureResultKeysetCursor: Represents a point for resuming feature search queries based on the last row of the
+// previous page. It stores the values of the sort columns for that row along with the feature key, which is the
+// tie breaker for every sort. Only the values for the columns of the given SortTarget are set.
+// The direction and the browser of the sort are stored too so that the cursor is only accepted by the same sort.
+// Unlike FeatureResultOffsetCursor, the next page does not shift when features are added or removed between requests
+// and the database does not need to skip over the rows of the previous pages.
+type FeatureResultKeysetCursor struct {
+	SortTarget         FeaturesSearchSortTarget `json:"sort_target"`
+	Ascending          bool                     `json:"ascending"`
+	BrowserTarget      *string                  `json:"browser_target,omitempty"`
+	LastFeatureKey     string                   `json:"last_feature_key"`
+	LastName           *string                  `json:"last_name,omitempty"`
+	LastLowDate        *time.Time               `json:"last_low_date,omitempty"`
+	LastHighDate       *time.Time               `json:"last_high_date,omitempty"`
+	LastStatus         *string                  `json:"last_status,omitempty"`
+	LastSortMetric     *big.Rat                 `json:"last_sort_metric,omitempty"`
+	LastSortImplStatus *string                  `json:"last_sort_impl_status,omitempty"`
+	LastAvailableDate  *time.Time               `json:"last_available_date,omitempty"`
+	LastSpec           *string                  `json:"last_spec,omitempty"`
+}
+
 // decodeWPTRunCursor provides a wrapper around the generic decodeCursor.
 func decodeWPTRunCursor(cursor string) (*WPTRunCursor, error) {
 	return decodeCursor[WPTRunCursor](cursor)
 }
 
+// decodeWPTInteropCursor provides a wrapper around the generic decodeCursor.
+func decodeWPTInteropCursor(cursor string) (*WPTInteropCursor, error) {
+	return decodeCursor[WPTInteropCursor](cursor)
+}
+
 // decodeInputFeatureResultCursor provides a wrapper around the generic decodeCursor.
+// It returns either a keyset cursor or an offset cursor. Offset cursors are still accepted so that tokens handed out
+// before the switch to keyset cursors continue to work.
 func decodeInputFeatureResultCursor(
-	cursor string) (*FeatureResultOffsetCursor, error) {
+	cursor string) (*FeatureResultOffsetCursor, *FeatureResultKeysetCursor, error) {
+	// Try for the keyset based cursor first
+	keysetCursor, err := decodeCursor[FeatureResultKeysetCursor](cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+	if keysetCursor != nil && keysetCursor.SortTarget != "" {
+		return nil, keysetCursor, nil
+	}
+
 	// Try for the offset based cursor
 	offsetCursor, err := decodeCursor[FeatureResultOffsetCursor](cursor)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if offsetCursor == nil || offsetCursor.Offset < 0 {
-		return nil, ErrInvalidCursorFormat
+		return nil, nil, ErrInvalidCursorFormat
 	}
 
-	return offsetCursor, nil
+	return offsetCursor, nil, nil
 }
 
 // decodeCursor: Decodes a base64-encoded cursor string into a Cursor struct.
@@ -152,6 +209,11 @@ func encodeWPTRunCursor(timeStart time.Time, id int64) string {
 	return encodeCursor[WPTRunCursor](WPTRunCursor{LastTimeStart: timeStart, LastRunID: id})
 }
 
+// encodeWPTInteropCursor provides a wrapper around the generic encodeCursor.
+func encodeWPTInteropCursor(timeStart time.Time, revision string) string {
+	return encodeCursor[WPTInteropCursor](WPTInteropCursor{LastTimeStart: timeStart, LastRevision: revision})
+}
+
 // encodeCursor: Encodes a Cursor into a base64-encoded string.
 // Returns an empty string if is unable to create a token.
 // TODO: Pass in context to be used by slog.ErrorContext.
@@ -172,3 +234,8 @@ func encodeFeatureResultOffsetCursor(offset int) string {
 		Offset: offset,
 	})
 }
+
+// encodeFeatureResultKeysetCursor provides a wrapper around the generic encodeCursor.
+func encodeFeatureResultKeysetCursor(cursor FeatureResultKeysetCursor) string {
+	return encodeCursor(cursor)
+}
@@ -97,6 +97,14 @@ func TestGetTestDatabase(t *testing.T) {
 	}
 }
 
+func TestPing(t *testing.T) {
+	client := getTestDatabase(t)
+	err := client.Ping(context.Background())
+	if err != nil {
+		t.Errorf("unexpected error %s", err.Error())
+	}
+}
+
 func TestNewSpannerClient_Bad(t *testing.T) {
 	testCases := []struct {
 		testName      string
@@ -205,3 +213,70 @@ func TestEncodeWPTRunCursor(t *testing.T) {
 		t.Errorf("unexpected wpt run cursor. received %s, expected %s", value, expected)
 	}
 }
+
+func TestDecodeInputFeatureResultCursor(t *testing.T) {
+	keysetCursor := FeatureResultKeysetCursor{
+		SortTarget:         NameSort,
+		Ascending:          true,
+		BrowserTarget:      nil,
+		LastFeatureKey:     "feature1",
+		LastName:           valuePtr("Feature 1"),
+		LastLowDate:        nil,
+		LastHighDate:       nil,
+		LastStatus:         nil,
+		LastSortMetric:     nil,
+		LastSortImplStatus: nil,
+		LastAvailableDate:  nil,
+		LastSpec:           nil,
+	}
+	testCases := []struct {
+		name                 string
+		input                string
+		expectedOffsetCursor *FeatureResultOffsetCursor
+		expectedKeysetCursor *FeatureResultKeysetCursor
+		expectedError        error
+	}{
+		{
+			name:                 "keyset cursor",
+			input:                encodeFeatureResultKeysetCursor(keysetCursor),
+			expectedOffsetCursor: nil,
+			expectedKeysetCursor: &keysetCursor,
+			expectedError:        nil,
+		},
+		{
+			name:                 "legacy offset cursor",
+			input:                encodeFeatureResultOffsetCursor(2),
+			expectedOffsetCursor: &FeatureResultOffsetCursor{Offset: 2},
+			expectedKeysetCursor: nil,
+			expectedError:        nil,
+		},
+		{
+			name:                 "negative offset cursor",
+			input:                encodeFeatureResultOffsetCursor(-1),
+			expectedOffsetCursor: nil,
+			expectedKeysetCursor: nil,
+			expectedError:        ErrInvalidCursorFormat,
+		},
+		{
+			name:                 "invalid base64",
+			input:                "not-base64",
+			expectedOffsetCursor: nil,
+			expectedKeysetCursor: nil,
+			expectedError:        ErrInvalidCursorFormat,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			offsetCursor, keysetCursor, err := decodeInputFeatureResultCursor(tc.input)
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("unexpected error. expected %v received %v", tc.expectedError, err)
+			}
+			if !reflect.DeepEqual(tc.expectedOffsetCursor, offsetCursor) {
+				t.Errorf("unequal offset cursors expected %+v. received %+v", tc.expectedOffsetCursor, offsetCursor)
+			}
+			if !reflect.DeepEqual(tc.expectedKeysetCursor, keysetCursor) {
+				t.Errorf("unequal keyset cursors expected %+v. received %+v", tc.expectedKeysetCursor, keysetCursor)
+			}
+		})
+	}
+}
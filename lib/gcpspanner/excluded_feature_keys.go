@@ -0,0 +1,158 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcpspanner
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	excludedFeatureKeysTable         = "ExcludedFeatureKeys"
+	excludedFeatureKeyAuditLogsTable = "ExcludedFeatureKeyAuditLogs"
+)
+
+// ErrFeatureKeyAlreadyExcluded indicates that the feature key is already in ExcludedFeatureKeys.
+var ErrFeatureKeyAlreadyExcluded = errors.New("feature key is already excluded")
+
+// Actions recorded in ExcludedFeatureKeyAuditLogs.
+const (
+	ExcludedFeatureKeyAuditActionAdd    = "add"
+	ExcludedFeatureKeyAuditActionRemove = "remove"
+)
+
+// ExcludedFeatureKey is a feature that is hidden from every query.
+// Reason, Author and CreatedAt are nil for keys that were excluded by hand.
+type ExcludedFeatureKey struct {
+	FeatureKey string     `spanner:"FeatureKey"`
+	Reason     *string    `spanner:"Reason"`
+	Author     *string    `spanner:"Author"`
+	CreatedAt  *time.Time `spanner:"CreatedAt"`
+}
+
+// ExcludedFeatureKeyAuditLog is a change to ExcludedFeatureKeys.
+type ExcludedFeatureKeyAuditLog struct {
+	FeatureKey string    `spanner:"FeatureKey"`
+	Action     string    `spanner:"Action"`
+	Reason     *string   `spanner:"Reason"`
+	Author     string    `spanner:"Author"`
+	CreatedAt  time.Time `spanner:"CreatedAt"`
+}
+
+func newExcludedFeatureKeyAuditLogMutation(
+	featureKey, action string, reason *string, author string) *spanner.Mutation {
+	return spanner.InsertMap(excludedFeatureKeyAuditLogsTable, map[string]interface{}{
+		"FeatureKey": featureKey,
+		"Action":     action,
+		"Reason":     reason,
+		"Author":     author,
+		"CreatedAt":  spanner.CommitTimestamp,
+	})
+}
+
+// AddExcludedFeatureKey excludes the feature key from every query and records the change in the audit log.
+// It returns ErrFeatureKeyAlreadyExcluded if the key is already excluded.
+func (c *Client) AddExcludedFeatureKey(
+	ctx context.Context, featureKey, reason, author string) (*ExcludedFeatureKey, error) {
+	createdAt, err := c.ReadWriteTransaction(ctx, func(ctx context.Context, txn *spanner.ReadWriteTransaction) error {
+		_, err := txn.ReadRow(ctx, excludedFeatureKeysTable, spanner.Key{featureKey}, []string{"FeatureKey"})
+		if err == nil {
+			return ErrFeatureKeyAlreadyExcluded
+		} else if spanner.ErrCode(err) != codes.NotFound {
+			return errors.Join(ErrInternalQueryFailure, err)
+		}
+
+		return txn.BufferWrite([]*spanner.Mutation{
+			spanner.InsertMap(excludedFeatureKeysTable, map[string]interface{}{
+				"FeatureKey": featureKey,
+				"Reason":     reason,
+				"Author":     author,
+				"CreatedAt":  spanner.CommitTimestamp,
+			}),
+			newExcludedFeatureKeyAuditLogMutation(featureKey, ExcludedFeatureKeyAuditActionAdd, &reason, author),
+		})
+	})
+	if errors.Is(err, ErrFeatureKeyAlreadyExcluded) {
+		return nil, err
+	} else if err != nil {
+		return nil, errors.Join(ErrInternalQueryFailure, err)
+	}
+
+	return &ExcludedFeatureKey{
+		FeatureKey: featureKey,
+		Reason:     &reason,
+		Author:     &author,
+		CreatedAt:  &createdAt,
+	}, nil
+}
+
+// RemoveExcludedFeatureKey includes the feature key in queries again and records the change in the audit log.
+// It returns ErrQueryReturnedNoResults if the key is not excluded.
+func (c *Client) RemoveExcludedFeatureKey(ctx context.Context, featureKey, author string) error {
+	_, err := c.ReadWriteTransaction(ctx, func(ctx context.Context, txn *spanner.ReadWriteTransaction) error {
+		_, err := txn.ReadRow(ctx, excludedFeatureKeysTable, spanner.Key{featureKey}, []string{"FeatureKey"})
+		if spanner.ErrCode(err) == codes.NotFound {
+			return ErrQueryReturnedNoResults
+		} else if err != nil {
+			return errors.Join(ErrInternalQueryFailure, err)
+		}
+
+		return txn.BufferWrite([]*spanner.Mutation{
+			spanner.Delete(excludedFeatureKeysTable, spanner.Key{featureKey}),
+			newExcludedFeatureKeyAuditLogMutation(featureKey, ExcludedFeatureKeyAuditActionRemove, nil, author),
+		})
+	})
+	if errors.Is(err, ErrQueryReturnedNoResults) {
+		return err
+	} else if err != nil {
+		return errors.Join(ErrInternalQueryFailure, err)
+	}
+
+	return nil
+}
+
+// ListExcludedFeatureKeys returns every excluded feature key, ordered by key.
+func (c *Client) ListExcludedFeatureKeys(ctx context.Context) ([]ExcludedFeatureKey, error) {
+	txn := c.Single()
+	defer txn.Close()
+	it := txn.Query(ctx, spanner.NewStatement(`
+		SELECT FeatureKey, Reason, Author, CreatedAt
+		FROM ExcludedFeatureKeys
+		ORDER BY FeatureKey`))
+	defer it.Stop()
+
+	var keys []ExcludedFeatureKey
+	for {
+		row, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, errors.Join(ErrInternalQueryFailure, err)
+		}
+		var key ExcludedFeatureKey
+		if err := row.ToStruct(&key); err != nil {
+			return nil, errors.Join(ErrInternalQueryFailure, err)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
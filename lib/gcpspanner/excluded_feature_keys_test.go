@@ -0,0 +1,124 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcpspanner
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/api/iterator"
+)
+
+// Test helper to read the audit log.
+func (c *Client) ListExcludedFeatureKeyAuditLogs(ctx context.Context) ([]ExcludedFeatureKeyAuditLog, error) {
+	txn := c.Single()
+	defer txn.Close()
+	it := txn.Query(ctx, spanner.NewStatement(`
+		SELECT FeatureKey, Action, Reason, Author, CreatedAt
+		FROM ExcludedFeatureKeyAuditLogs
+		ORDER BY CreatedAt DESC, FeatureKey`))
+	defer it.Stop()
+
+	var logs []ExcludedFeatureKeyAuditLog
+	for {
+		row, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, errors.Join(ErrInternalQueryFailure, err)
+		}
+		var log ExcludedFeatureKeyAuditLog
+		if err := row.ToStruct(&log); err != nil {
+			return nil, errors.Join(ErrInternalQueryFailure, err)
+		}
+		logs = append(logs, log)
+	}
+
+	return logs, nil
+}
+
+func TestExcludedFeatureKeys(t *testing.T) {
+	client := getTestDatabase(t)
+	ctx := context.Background()
+
+	// Keys excluded by hand do not have a reason or author.
+	if err := client.InsertExcludedFeatureKey(ctx, "manual"); err != nil {
+		t.Fatalf("unexpected error inserting key. %s", err.Error())
+	}
+	added, err := client.AddExcludedFeatureKey(ctx, "bogus", "not a real feature", "admin@example.com")
+	if err != nil {
+		t.Fatalf("unexpected error adding key. %s", err.Error())
+	}
+	_, err = client.AddExcludedFeatureKey(ctx, "bogus", "again", "admin@example.com")
+	if !errors.Is(err, ErrFeatureKeyAlreadyExcluded) {
+		t.Errorf("expected already excluded error. received %v", err)
+	}
+
+	keys, err := client.ListExcludedFeatureKeys(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error listing keys. %s", err.Error())
+	}
+	if len(keys) != 2 {
+		t.Fatalf("expected 2 keys. received %d", len(keys))
+	}
+	// The commit timestamp is returned as the creation time.
+	if keys[0].FeatureKey != "bogus" || keys[0].CreatedAt == nil || !keys[0].CreatedAt.Equal(*added.CreatedAt) ||
+		!reflect.DeepEqual(keys[0].Reason, added.Reason) || !reflect.DeepEqual(keys[0].Author, added.Author) {
+		t.Errorf("unexpected added key %+v", keys[0])
+	}
+	if !reflect.DeepEqual(added.Reason, valuePtr("not a real feature")) ||
+		!reflect.DeepEqual(added.Author, valuePtr("admin@example.com")) {
+		t.Errorf("unexpected returned key %+v", added)
+	}
+	if !reflect.DeepEqual(keys[1], ExcludedFeatureKey{FeatureKey: "manual", Reason: nil, Author: nil, CreatedAt: nil}) {
+		t.Errorf("unexpected manual key %+v", keys[1])
+	}
+
+	if err := client.RemoveExcludedFeatureKey(ctx, "bogus", "other@example.com"); err != nil {
+		t.Fatalf("unexpected error removing key. %s", err.Error())
+	}
+	err = client.RemoveExcludedFeatureKey(ctx, "bogus", "other@example.com")
+	if !errors.Is(err, ErrQueryReturnedNoResults) {
+		t.Errorf("expected no results error. received %v", err)
+	}
+	keys, err = client.ListExcludedFeatureKeys(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error listing keys. %s", err.Error())
+	}
+	if len(keys) != 1 || keys[0].FeatureKey != "manual" {
+		t.Errorf("unexpected keys after removal %+v", keys)
+	}
+
+	// Only the successful changes are recorded.
+	logs, err := client.ListExcludedFeatureKeyAuditLogs(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error listing audit logs. %s", err.Error())
+	}
+	if len(logs) != 2 {
+		t.Fatalf("expected 2 audit logs. received %d", len(logs))
+	}
+	if logs[0].Action != ExcludedFeatureKeyAuditActionRemove || logs[0].Author != "other@example.com" ||
+		logs[0].Reason != nil {
+		t.Errorf("unexpected remove log %+v", logs[0])
+	}
+	if logs[1].Action != ExcludedFeatureKeyAuditActionAdd || logs[1].Author != "admin@example.com" ||
+		!reflect.DeepEqual(logs[1].Reason, valuePtr("not a real feature")) {
+		t.Errorf("unexpected add log %+v", logs[1])
+	}
+}
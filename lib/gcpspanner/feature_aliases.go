@@ -0,0 +1,98 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcpspanner
+
+import (
+	"context"
+	"errors"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/api/iterator"
+)
+
+const featureAliasesTable = "FeatureAliases"
+
+// SpannerFeatureAlias is a previous key of a web feature stored in spanner.
+// Columns come from the ../../infra/storage/spanner/migrations/*.sql files.
+type SpannerFeatureAlias struct {
+	Alias        string `spanner:"Alias"`
+	WebFeatureID string `spanner:"WebFeatureID"`
+}
+
+// UpsertFeatureAlias records that alias is a previous key of the feature with the given feature key.
+// If the alias already exists, it is moved to the given feature.
+func (c *Client) UpsertFeatureAlias(ctx context.Context, featureKey string, alias string) error {
+	id, err := c.GetIDFromFeatureKey(ctx, NewFeatureKeyFilter(featureKey))
+	if err != nil {
+		return err
+	}
+	if id == nil {
+		return ErrInternalQueryFailure
+	}
+	_, err = c.ReadWriteTransaction(ctx, func(_ context.Context, txn *spanner.ReadWriteTransaction) error {
+		m, err := spanner.InsertOrUpdateStruct(featureAliasesTable, SpannerFeatureAlias{
+			Alias:        alias,
+			WebFeatureID: *id,
+		})
+		if err != nil {
+			return errors.Join(ErrInternalQueryFailure, err)
+		}
+
+		return txn.BufferWrite([]*spanner.Mutation{m})
+	})
+	if err != nil {
+		return errors.Join(ErrInternalQueryFailure, err)
+	}
+
+	return nil
+}
+
+// GetFeatureKeysFromAliases returns the current feature key of each alias, keyed by alias.
+// Aliases that do not exist are left out of the returned map.
+func (c *Client) GetFeatureKeysFromAliases(ctx context.Context, aliases []string) (map[string]string, error) {
+	stmt := spanner.NewStatement(`
+	SELECT
+		fa.Alias, wf.FeatureKey
+	FROM FeatureAliases fa
+	JOIN WebFeatures wf ON wf.ID = fa.WebFeatureID
+	WHERE fa.Alias IN UNNEST(@aliases)
+	`)
+	stmt.Params = map[string]interface{}{
+		"aliases": aliases,
+	}
+
+	txn := c.Single()
+	defer txn.Close()
+	it := txn.Query(ctx, stmt)
+	defer it.Stop()
+
+	featureKeys := make(map[string]string, len(aliases))
+	for {
+		row, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, errors.Join(ErrInternalQueryFailure, err)
+		}
+		var alias, featureKey string
+		if err := row.Columns(&alias, &featureKey); err != nil {
+			return nil, errors.Join(ErrInternalQueryFailure, err)
+		}
+		featureKeys[alias] = featureKey
+	}
+
+	return featureKeys, nil
+}
@@ -0,0 +1,109 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcpspanner
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func setupRequiredTablesForFeatureAliases(ctx context.Context,
+	client *Client, t *testing.T) map[string]string {
+	ids := make(map[string]string)
+	for _, feature := range getSampleFeatures() {
+		id, err := client.UpsertWebFeature(ctx, feature)
+		if err != nil {
+			t.Fatalf("unexpected error during insert of features. %s", err.Error())
+		}
+		ids[feature.FeatureKey] = *id
+	}
+
+	return ids
+}
+
+func assertFeatureKeyOrAliasResolvesTo(ctx context.Context, t *testing.T,
+	client *Client, key string, expectedID string) {
+	t.Helper()
+	id, err := client.GetIDFromFeatureKey(ctx, NewFeatureKeyOrAliasFilter(key))
+	if err != nil {
+		t.Fatalf("unexpected error. %s", err.Error())
+	}
+	if *id != expectedID {
+		t.Errorf("expected %s to resolve to %s. received %s", key, expectedID, *id)
+	}
+}
+
+func TestUpsertFeatureAlias(t *testing.T) {
+	client := getTestDatabase(t)
+	ctx := context.Background()
+	ids := setupRequiredTablesForFeatureAliases(ctx, client, t)
+
+	err := client.UpsertFeatureAlias(ctx, "feature1", "old-feature1")
+	if err != nil {
+		t.Fatalf("unexpected error during insert. %s", err.Error())
+	}
+
+	// Keys and aliases both resolve.
+	assertFeatureKeyOrAliasResolvesTo(ctx, t, client, "feature1", ids["feature1"])
+	assertFeatureKeyOrAliasResolvesTo(ctx, t, client, "old-feature1", ids["feature1"])
+
+	// The alias only resolves when asked to.
+	_, err = client.GetIDFromFeatureKey(ctx, NewFeatureKeyFilter("old-feature1"))
+	if !errors.Is(err, ErrQueryReturnedNoResults) {
+		t.Errorf("unexpected error. %s", err)
+	}
+
+	// The feature is returned with its current key.
+	result, err := client.GetFeature(ctx, NewFeatureKeyOrAliasFilter("old-feature1"), defaultWPTMetricView(),
+		getDefaultTestBrowserList(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error. %s", err.Error())
+	}
+	if result.FeatureKey != "feature1" || result.Name != "Feature 1" {
+		t.Errorf("unexpected feature %+v", result)
+	}
+
+	// Aliases take precedence over a feature that still exists under its previous key.
+	err = client.UpsertFeatureAlias(ctx, "feature2", "feature3")
+	if err != nil {
+		t.Fatalf("unexpected error during insert. %s", err.Error())
+	}
+	assertFeatureKeyOrAliasResolvesTo(ctx, t, client, "feature3", ids["feature2"])
+
+	// Upserting an existing alias moves it to the other feature.
+	err = client.UpsertFeatureAlias(ctx, "feature4", "old-feature1")
+	if err != nil {
+		t.Fatalf("unexpected error during update. %s", err.Error())
+	}
+	assertFeatureKeyOrAliasResolvesTo(ctx, t, client, "old-feature1", ids["feature4"])
+
+	// Aliases are looked up together.
+	featureKeys, err := client.GetFeatureKeysFromAliases(ctx, []string{"old-feature1", "feature3", "feature1"})
+	if err != nil {
+		t.Fatalf("unexpected error. %s", err.Error())
+	}
+	expectedFeatureKeys := map[string]string{"old-feature1": "feature4", "feature3": "feature2"}
+	if !reflect.DeepEqual(featureKeys, expectedFeatureKeys) {
+		t.Errorf("unexpected feature keys %v", featureKeys)
+	}
+
+	// The feature of the alias must exist.
+	err = client.UpsertFeatureAlias(ctx, "nopefeature", "old-nopefeature")
+	if !errors.Is(err, ErrQueryReturnedNoResults) {
+		t.Errorf("unexpected error. %s", err)
+	}
+}
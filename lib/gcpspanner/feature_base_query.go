@@ -43,6 +43,8 @@ var (
 	gcpFSPassRateForBrowserTemplate BaseQueryTemplate
 	// gcpFSBrowserImplementationStatusTemplate is the compiled version of gcpFSBrowserImplementationStatusRawTemplate.
 	gcpFSBrowserImplementationStatusTemplate BaseQueryTemplate
+	// gcpFSBrowserAvailableDateTemplate is the compiled version of gcpFSBrowserAvailableDateRawTemplate.
+	gcpFSBrowserAvailableDateTemplate BaseQueryTemplate
 
 	// localFSMetricsSubQueryTemplate is the compiled version of localFSMetricsSubQueryRawTemplate.
 	localFSMetricsSubQueryTemplate BaseQueryTemplate
@@ -54,6 +56,18 @@ var (
 	localFSPassRateForBrowserTemplate BaseQueryTemplate
 	// localFSBrowserImplementationStatusTemplate is the compiled version of localFSBrowserImplementationStatusRawTemplate.
 	localFSBrowserImplementationStatusTemplate BaseQueryTemplate
+	// localFSBrowserAvailableDateTemplate is the compiled version of localFSBrowserAvailableDateRawTemplate.
+	localFSBrowserAvailableDateTemplate BaseQueryTemplate
+
+	// gcpFSImplementationStatusTemplate is the compiled version of gcpFSImplementationStatusRawTemplate.
+	gcpFSImplementationStatusTemplate BaseQueryTemplate
+	// localFSImplementationStatusTemplate is the compiled version of localFSImplementationStatusRawTemplate.
+	localFSImplementationStatusTemplate BaseQueryTemplate
+
+	// commonFSBaselineStatusFacetTemplate is the compiled version of commonFSBaselineStatusFacetRawTemplate.
+	commonFSBaselineStatusFacetTemplate BaseQueryTemplate
+	// commonFSAvailableOnFacetTemplate is the compiled version of commonFSAvailableOnFacetRawTemplate.
+	commonFSAvailableOnFacetTemplate BaseQueryTemplate
 )
 
 func init() {
@@ -62,14 +76,26 @@ func init() {
 	gcpFSSelectQueryTemplate = NewQueryTemplate(gcpFSSelectQueryRawTemplate)
 	gcpFSPassRateForBrowserTemplate = NewQueryTemplate(gcpFSPassRateForBrowserRawTemplate)
 	gcpFSBrowserImplementationStatusTemplate = NewQueryTemplate(gcpFSBrowserImplementationStatusRawTemplate)
+	gcpFSBrowserAvailableDateTemplate = NewQueryTemplate(gcpFSBrowserAvailableDateRawTemplate)
 
 	localFSMetricsSubQueryTemplate = NewQueryTemplate(localFSMetricsSubQueryRawTemplate)
 	localFSCountQueryTemplate = NewQueryTemplate(localFSCountQueryRawTemplate)
 	localFSSelectQueryTemplate = NewQueryTemplate(localFSSelectQueryRawTemplate)
 	localFSPassRateForBrowserTemplate = NewQueryTemplate(localFSPassRateForBrowserRawTemplate)
 	localFSBrowserImplementationStatusTemplate = NewQueryTemplate(localFSBrowserImplementationStatusRawTemplate)
+	localFSBrowserAvailableDateTemplate = NewQueryTemplate(localFSBrowserAvailableDateRawTemplate)
+
+	gcpFSImplementationStatusTemplate = NewQueryTemplate(gcpFSImplementationStatusRawTemplate)
+	localFSImplementationStatusTemplate = NewQueryTemplate(localFSImplementationStatusRawTemplate)
+
+	commonFSBaselineStatusFacetTemplate = NewQueryTemplate(commonFSBaselineStatusFacetRawTemplate)
+	commonFSAvailableOnFacetTemplate = NewQueryTemplate(commonFSAvailableOnFacetRawTemplate)
 }
 
+// asOfDateParamName is the name of the parameter used by the templates when
+// evaluating a query at a point in time.
+const asOfDateParamName = "asOfDate"
+
 type BaseQueryTemplate struct {
 	tmpl *template.Template
 }
@@ -109,12 +135,16 @@ type CommonFSSelectTemplateData struct {
 	Offset               int
 	PageSize             int
 	OptionalJoins        []JoinData
+	// SortColumns are extra columns from the optional joins that are returned in order to build the next page token.
+	SortColumns []string
 }
 
 // JoinData contains template data for the optional joins.
 type JoinData struct {
 	Alias    string
 	Template string
+	// Column is the name of the column returned by the template.
+	Column string
 }
 
 // GCPFSSelectTemplateData contains the template data for gcpFSSelectQueryTemplate.
@@ -126,6 +156,7 @@ type GCPFSSelectTemplateData struct {
 type LocalFSSelectTemplateData struct {
 	CommonFSSelectTemplateData
 	PassRateColumn string
+	AsOf           bool
 }
 
 // GCPFSBrowserMetricTemplateData contains the template data for gcpFSPassRateForBrowserTemplate.
@@ -143,11 +174,24 @@ type LocalFSBrowserMetricTemplateData struct {
 // GCPFSBrowserImplStatusTemplateData contains the template data for gcpFSBrowserImplementationStatusTemplate.
 type GCPFSBrowserImplStatusTemplateData struct {
 	BrowserNameParam string
+	AsOf             bool
 }
 
 // LocalFSBrowserImplStatusTemplateData contains the template data for localFSBrowserImplementationStatusTemplate.
 type LocalFSBrowserImplStatusTemplateData struct {
 	BrowserNameParam string
+	AsOf             bool
+}
+
+// FSBrowserAvailableDateTemplateData contains the template data for the browser available date templates.
+type FSBrowserAvailableDateTemplateData struct {
+	BrowserNameParam string
+	AsOf             bool
+}
+
+// FSImplementationStatusTemplateData contains the template data for the implementation status templates.
+type FSImplementationStatusTemplateData struct {
+	AsOf bool
 }
 
 // GCPFSMetricsTemplateData contains the template data for gcpFSMetricsSubQueryTemplate.
@@ -157,6 +201,7 @@ type GCPFSMetricsTemplateData struct {
 	PassRateColumn string
 	ChannelParam   string
 	MetricIndex    string
+	AsOf           bool
 }
 
 // LocalFSMetricsTemplateData contains the template for localFSMetricsSubQueryTemplate.
@@ -172,6 +217,13 @@ type CommonFSCountTemplateData struct {
 	Filters           []string
 }
 
+// FSFacetTemplateData contains the template data for the facet query templates.
+type FSFacetTemplateData struct {
+	BaseQueryFragment string
+	Filters           []string
+	AsOf              bool
+}
+
 // GCPFSCountTemplateData contains the template for gcpFSCountQueryTemplate.
 type GCPFSCountTemplateData struct {
 	CommonFSCountTemplateData
@@ -208,6 +260,8 @@ func metricsPassRateIndex(metricView WPTMetricView) string {
 
 type FeatureSearchCountArgs struct {
 	Filters []string
+	// AsOf evaluates the baseline status at the time given by the asOfDate parameter.
+	AsOf bool
 }
 
 // SortByBrowserImplDetails contains parameter data for the Implementation Status templates.
@@ -224,7 +278,12 @@ type FeatureSearchQueryArgs struct {
 	SortClause              string
 	SortByStableBrowserImpl *SortByBrowserImplDetails
 	SortByExpBrowserImpl    *SortByBrowserImplDetails
-	Browsers                []string
+	// SortByBrowserAvailableDate sorts by the release date of the first availability in the browser.
+	SortByBrowserAvailableDate *SortByBrowserImplDetails
+	Browsers                   []string
+	// AsOf evaluates the query at the time given by the asOfDate parameter
+	// instead of the current time.
+	AsOf bool
 }
 
 // FeatureSearchBaseQuery contains the base query for all feature search
@@ -243,6 +302,27 @@ type FeatureSearchBaseQuery interface {
 
 	// CountQuery generates the base query to return only the count of items.
 	CountQuery(args FeatureSearchCountArgs) string
+
+	// FacetQuery generates a query to return the count of items per value of the facet.
+	// Each row includes the facet value and the number of features with that value.
+	FacetQuery(facet FeatureSearchFacet, args FeatureSearchCountArgs) string
+}
+
+// buildFacetQuery generates the facet query for a given base query fragment.
+func buildFacetQuery(baseQueryFragment string, facet FeatureSearchFacet, args FeatureSearchCountArgs) string {
+	data := FSFacetTemplateData{
+		BaseQueryFragment: baseQueryFragment,
+		Filters:           args.Filters,
+		AsOf:              args.AsOf,
+	}
+	switch facet {
+	case BaselineStatusFacet:
+		return commonFSBaselineStatusFacetTemplate.Execute(data)
+	case AvailableOnFacet:
+		return commonFSAvailableOnFacetTemplate.Execute(data)
+	}
+
+	return ""
 }
 
 // GCPFeatureSearchBaseQuery provides a base query that is optimal for GCP Spanner to retrieve the information
@@ -289,17 +369,27 @@ type LatestRunResult struct {
 // Useful for building the filter per channel in the Query method of GCPFeatureSearchBaseQuery.
 type LatestRunResultsGroupedByChannel map[string][]LatestRunResult
 
-func (f GCPFeatureSearchBaseQuery) buildBaseQueryFragment() string { return gcpFSBaseQueryTemplate }
+func (f GCPFeatureSearchBaseQuery) buildBaseQueryFragment(asOf bool) string {
+	if asOf {
+		return gcpFSAsOfBaseQueryTemplate
+	}
+
+	return gcpFSBaseQueryTemplate
+}
 
 func (f GCPFeatureSearchBaseQuery) CountQuery(args FeatureSearchCountArgs) string {
 	return gcpFSCountQueryTemplate.Execute(GCPFSCountTemplateData{
 		CommonFSCountTemplateData: CommonFSCountTemplateData{
-			BaseQueryFragment: f.buildBaseQueryFragment(),
+			BaseQueryFragment: f.buildBaseQueryFragment(args.AsOf),
 			Filters:           args.Filters,
 		},
 	})
 }
 
+func (f GCPFeatureSearchBaseQuery) FacetQuery(facet FeatureSearchFacet, args FeatureSearchCountArgs) string {
+	return buildFacetQuery(f.buildBaseQueryFragment(args.AsOf), facet, args)
+}
+
 const (
 	// commonFSBaseQueryTemplate provides the core of a Spanner query, joining
 	// the WebFeatures table with FeatureBaselineStatus for status information.
@@ -312,6 +402,31 @@ LEFT OUTER JOIN FeatureSpecs fs ON wf.ID = fs.WebFeatureID
 	gcpFSBaseQueryTemplate   = commonFSBaseQueryTemplate
 	localFSBaseQueryTemplate = commonFSBaseQueryTemplate
 
+	// commonFSAsOfBaseQueryTemplate is the same as commonFSBaseQueryTemplate but
+	// computes the baseline status at the time of the asOfDate parameter.
+	// Dates after the asOfDate are hidden and the status is derived from the
+	// remaining dates. Features without a status remain without a status.
+	commonFSAsOfBaseQueryTemplate = `
+FROM WebFeatures wf
+LEFT OUTER JOIN (
+	SELECT
+		WebFeatureID,
+		CASE
+			WHEN HighDate IS NOT NULL AND HighDate <= @asOfDate THEN 'high'
+			WHEN LowDate IS NOT NULL AND LowDate <= @asOfDate THEN 'low'
+			WHEN Status IS NULL THEN NULL
+			ELSE 'none'
+		END AS Status,
+		IF(LowDate <= @asOfDate, LowDate, NULL) AS LowDate,
+		IF(HighDate <= @asOfDate, HighDate, NULL) AS HighDate
+	FROM FeatureBaselineStatus
+) fbs ON wf.ID = fbs.WebFeatureID
+LEFT OUTER JOIN ExcludedFeatureKeys efk ON wf.FeatureKey = efk.FeatureKey
+LEFT OUTER JOIN FeatureSpecs fs ON wf.ID = fs.WebFeatureID
+`
+	gcpFSAsOfBaseQueryTemplate   = commonFSAsOfBaseQueryTemplate
+	localFSAsOfBaseQueryTemplate = commonFSAsOfBaseQueryTemplate
+
 	// commonFSImplementationStatusRawTemplate returns an array of structs that represent the implementation status.
 	commonFSImplementationStatusRawTemplate = `
 COALESCE(
@@ -336,6 +451,9 @@ COALESCE(
 		LEFT JOIN BrowserReleases br
 			ON bfa.BrowserName = br.BrowserName AND bfa.BrowserVersion = br.BrowserVersion
 		WHERE bfa.WebFeatureID = wf.ID
+		{{ if .AsOf }}
+			AND br.ReleaseDate <= @asOfDate
+		{{ end }}
 	),
 	(
 		SELECT ARRAY(
@@ -359,6 +477,15 @@ COALESCE(
 			FROM BrowserFeatureAvailabilities bfa
 			WHERE bfa.WebFeatureID = wf.ID
 				AND BrowserName = @{{ .BrowserNameParam }}
+			{{ if .AsOf }}
+				AND EXISTS (
+					SELECT 1
+					FROM BrowserReleases br
+					WHERE br.BrowserName = bfa.BrowserName
+						AND br.BrowserVersion = bfa.BrowserVersion
+						AND br.ReleaseDate <= @asOfDate
+				)
+			{{ end }}
 			LIMIT 1),
 		'unavailable' -- Default if no match
 	) AS ImplementationStatus
@@ -367,6 +494,24 @@ COALESCE(
 	gcpFSBrowserImplementationStatusRawTemplate   = commonFSBrowserImplementationStatusRawTemplate
 	localFSBrowserImplementationStatusRawTemplate = commonFSBrowserImplementationStatusRawTemplate
 
+	// commonFSBrowserAvailableDateRawTemplate returns the release date of the first browser release in which
+	// the feature is available for a given browser. It is NULL if the feature is not available in the browser.
+	commonFSBrowserAvailableDateRawTemplate = `
+(
+	SELECT MIN(br.ReleaseDate)
+	FROM BrowserFeatureAvailabilities bfa
+	JOIN BrowserReleases br
+		ON bfa.BrowserName = br.BrowserName AND bfa.BrowserVersion = br.BrowserVersion
+	WHERE bfa.WebFeatureID = wf.ID
+		AND bfa.BrowserName = @{{ .BrowserNameParam }}
+	{{ if .AsOf }}
+		AND br.ReleaseDate <= @asOfDate
+	{{ end }}
+) AS SortAvailableDate
+	`
+	gcpFSBrowserAvailableDateRawTemplate   = commonFSBrowserAvailableDateRawTemplate
+	localFSBrowserAvailableDateRawTemplate = commonFSBrowserAvailableDateRawTemplate
+
 	// commonCountQueryRawTemplate returns the count of items, using the base query fragment
 	// for consistency.
 	commonCountQueryRawTemplate = `
@@ -380,6 +525,37 @@ WHERE 1=1
 	gcpFSCountQueryRawTemplate   = commonCountQueryRawTemplate
 	localFSCountQueryRawTemplate = commonCountQueryRawTemplate
 
+	// commonFSBaselineStatusFacetRawTemplate returns the number of matching features per baseline status.
+	// Features without a baseline status are not counted.
+	commonFSBaselineStatusFacetRawTemplate = `
+SELECT fbs.Status AS Value, COUNT(*) AS Count
+{{ .BaseQueryFragment }}
+WHERE fbs.Status IS NOT NULL
+{{ range .Filters }}
+	AND {{ . }}
+{{ end }}
+GROUP BY fbs.Status
+ORDER BY Value
+`
+
+	// commonFSAvailableOnFacetRawTemplate returns the number of matching features available per browser.
+	commonFSAvailableOnFacetRawTemplate = `
+SELECT facet_bfa.BrowserName AS Value, COUNT(DISTINCT wf.ID) AS Count
+{{ .BaseQueryFragment }}
+JOIN BrowserFeatureAvailabilities facet_bfa ON facet_bfa.WebFeatureID = wf.ID
+{{ if .AsOf }}
+JOIN BrowserReleases facet_br
+	ON facet_bfa.BrowserName = facet_br.BrowserName AND facet_bfa.BrowserVersion = facet_br.BrowserVersion
+	AND facet_br.ReleaseDate <= @asOfDate
+{{ end }}
+WHERE 1=1
+{{ range .Filters }}
+	AND {{ . }}
+{{ end }}
+GROUP BY facet_bfa.BrowserName
+ORDER BY Value
+`
+
 	// gcpFSSelectQueryRawTemplate builds the core SELECT query. It retrieves feature
 	// information, baseline status, and aggregated metrics.
 	gcpFSSelectQueryRawTemplate = `
@@ -394,6 +570,9 @@ SELECT
 	{{ .StableMetrics }},
 	{{ .ExperimentalMetrics }},
 	{{ .ImplementationStatus }}
+	{{- range .SortColumns }},
+	{{ . }}
+	{{- end }}
 {{ .BaseQueryFragment }}
 {{ if .OptionalJoins }}
 	{{ range $index, $join := .OptionalJoins }}
@@ -429,6 +608,9 @@ WITH
 			BrowserName,
 			MAX(TimeStart) AS LatestTimeStart
 		FROM WPTRunFeatureMetrics
+		{{ if .AsOf }}
+		WHERE TimeStart <= @asOfDate
+		{{ end }}
 		GROUP BY WebFeatureID, Channel, BrowserName
 	),
 	MetricsAggregation AS (
@@ -456,6 +638,9 @@ SELECT
 	{{ .StableMetrics }},
 	{{ .ExperimentalMetrics }},
 	{{ .ImplementationStatus }}
+	{{- range .SortColumns }},
+	{{ . }}
+	{{- end }}
 {{ .BaseQueryFragment }}
 {{ if .OptionalJoins }}
 	{{ range $index, $join := .OptionalJoins }}
@@ -498,6 +683,9 @@ OFFSET {{ .Offset }}
 				WHERE metrics2.WebFeatureID = wf.ID
 					AND metrics2.Channel = @{{ .ChannelParam }}
 					AND metrics2.BrowserName = @{{ .BrowserNameParam }}
+					{{ if .AsOf }}
+					AND metrics2.TimeStart <= @asOfDate
+					{{ end }}
 			)
 ) AS SortMetric
 `
@@ -532,6 +720,9 @@ COALESCE(
 							WHERE metrics2.WebFeatureID = wf.ID
 								AND metrics2.Channel = @{{ $.ChannelParam }}
 								AND metrics2.BrowserName = "{{ $browser }}"
+								{{ if $.AsOf }}
+								AND metrics2.TimeStart <= @asOfDate
+								{{ end }}
 						)
 				) AS PassRate,
 				(
@@ -551,6 +742,9 @@ COALESCE(
 							WHERE metrics2.WebFeatureID = wf.ID
 								AND metrics2.Channel = @{{ $.ChannelParam }}
 								AND metrics2.BrowserName = "{{ $browser }}"
+								{{ if $.AsOf }}
+								AND metrics2.TimeStart <= @asOfDate
+								{{ end }}
 						)
 				) AS FeatureRunDetails
 			{{- end }}
@@ -623,6 +817,7 @@ func (f GCPFeatureSearchBaseQuery) Query(args FeatureSearchQueryArgs) (
 		PassRateColumn: metricsPassRateColumn(args.MetricView),
 		MetricIndex:    metricsPassRateIndex(args.MetricView),
 		ChannelParam:   stableParamName,
+		AsOf:           args.AsOf,
 	}
 	stableMetrics := gcpFSMetricsSubQueryTemplate.Execute(stableMetricsData)
 
@@ -632,6 +827,7 @@ func (f GCPFeatureSearchBaseQuery) Query(args FeatureSearchQueryArgs) (
 		PassRateColumn: metricsPassRateColumn(args.MetricView),
 		MetricIndex:    metricsPassRateIndex(args.MetricView),
 		ChannelParam:   experimentalParamName,
+		AsOf:           args.AsOf,
 	}
 	experimentalMetrics := gcpFSMetricsSubQueryTemplate.Execute(experimentalMetricsData)
 
@@ -645,14 +841,17 @@ func (f GCPFeatureSearchBaseQuery) Query(args FeatureSearchQueryArgs) (
 					BrowserNameParam:         browserNameParamName,
 					GCPFSMetricsTemplateData: stableMetricsData,
 				}),
-			Alias: derviedTableSortMetrics,
+			Alias:  derviedTableSortMetrics,
+			Column: "SortMetric",
 		})
 		optionalJoins = append(optionalJoins, JoinData{
 			Template: gcpFSBrowserImplementationStatusTemplate.Execute(
 				GCPFSBrowserImplStatusTemplateData{
 					BrowserNameParam: browserNameParamName,
+					AsOf:             args.AsOf,
 				}),
-			Alias: derviedTableSortImpl,
+			Alias:  derviedTableSortImpl,
+			Column: "SortImplStatus",
 		})
 	} else if args.SortByExpBrowserImpl != nil {
 		browserNameParamName := "sortExpBrowserNameMetricParam"
@@ -663,33 +862,60 @@ func (f GCPFeatureSearchBaseQuery) Query(args FeatureSearchQueryArgs) (
 					BrowserNameParam:         browserNameParamName,
 					GCPFSMetricsTemplateData: experimentalMetricsData,
 				}),
-			Alias: derviedTableSortMetrics,
+			Alias:  derviedTableSortMetrics,
+			Column: "SortMetric",
 		})
 		optionalJoins = append(optionalJoins, JoinData{
 			Template: gcpFSBrowserImplementationStatusTemplate.Execute(
 				GCPFSBrowserImplStatusTemplateData{
 					BrowserNameParam: browserNameParamName,
+					AsOf:             args.AsOf,
+				}),
+			Alias:  derviedTableSortImpl,
+			Column: "SortImplStatus",
+		})
+	} else if args.SortByBrowserAvailableDate != nil {
+		browserNameParamName := "sortAvailableDateBrowserNameParam"
+		params[browserNameParamName] = args.SortByBrowserAvailableDate.BrowserName
+		optionalJoins = append(optionalJoins, JoinData{
+			Template: gcpFSBrowserAvailableDateTemplate.Execute(
+				FSBrowserAvailableDateTemplateData{
+					BrowserNameParam: browserNameParamName,
+					AsOf:             args.AsOf,
 				}),
-			Alias: derviedTableSortImpl,
+			Alias:  derviedTableSortAvailableDate,
+			Column: "SortAvailableDate",
 		})
 	}
 
 	return gcpFSSelectQueryTemplate.Execute(GCPFSSelectTemplateData{
 		CommonFSSelectTemplateData: CommonFSSelectTemplateData{
-			BaseQueryFragment:    f.buildBaseQueryFragment(),
-			StableMetrics:        stableMetrics,
-			ExperimentalMetrics:  experimentalMetrics,
-			ImplementationStatus: gcpFSImplementationStatusRawTemplate,
-			Filters:              args.Filters,
-			PageFilters:          args.PageFilters,
-			Offset:               args.Offset,
-			SortClause:           args.SortClause,
-			PageSize:             args.PageSize,
-			OptionalJoins:        optionalJoins,
+			BaseQueryFragment:   f.buildBaseQueryFragment(args.AsOf),
+			StableMetrics:       stableMetrics,
+			ExperimentalMetrics: experimentalMetrics,
+			ImplementationStatus: gcpFSImplementationStatusTemplate.Execute(
+				FSImplementationStatusTemplateData{AsOf: args.AsOf}),
+			Filters:       args.Filters,
+			PageFilters:   args.PageFilters,
+			Offset:        args.Offset,
+			SortClause:    args.SortClause,
+			PageSize:      args.PageSize,
+			OptionalJoins: optionalJoins,
+			SortColumns:   optionalJoinSortColumns(optionalJoins),
 		},
 	}), params
 }
 
+// optionalJoinSortColumns returns the sort columns that come from the optional joins.
+func optionalJoinSortColumns(optionalJoins []JoinData) []string {
+	var columns []string
+	for _, join := range optionalJoins {
+		columns = append(columns, join.Alias+"."+join.Column)
+	}
+
+	return columns
+}
+
 // LocalFeatureBaseQuery is a version of the base query that works well on the local emulator.
 // For some reason, the local emulator takes forever for the GCPFeatureSearchBaseQuery as the number
 // of features and metrics grows. But GCPFeatureSearchBaseQuery works extremely well on GCP.
@@ -699,17 +925,27 @@ func (f GCPFeatureSearchBaseQuery) Query(args FeatureSearchQueryArgs) (
 // TODO. Consolidate to using either LocalFeatureBaseQuery to reduce the maintenance burden.
 type LocalFeatureBaseQuery struct{}
 
-func (f LocalFeatureBaseQuery) buildBaseQueryFragment() string { return localFSBaseQueryTemplate }
+func (f LocalFeatureBaseQuery) buildBaseQueryFragment(asOf bool) string {
+	if asOf {
+		return localFSAsOfBaseQueryTemplate
+	}
+
+	return localFSBaseQueryTemplate
+}
 
 func (f LocalFeatureBaseQuery) CountQuery(args FeatureSearchCountArgs) string {
 	return localFSCountQueryTemplate.Execute(LocalFSCountTemplateData{
 		CommonFSCountTemplateData: CommonFSCountTemplateData{
-			BaseQueryFragment: f.buildBaseQueryFragment(),
+			BaseQueryFragment: f.buildBaseQueryFragment(args.AsOf),
 			Filters:           args.Filters,
 		},
 	})
 }
 
+func (f LocalFeatureBaseQuery) FacetQuery(facet FeatureSearchFacet, args FeatureSearchCountArgs) string {
+	return buildFacetQuery(f.buildBaseQueryFragment(args.AsOf), facet, args)
+}
+
 // Query is a version of the base query that works on the local emulator.
 // It leverages a common table expression CTE to help query the metrics.
 func (f LocalFeatureBaseQuery) Query(args FeatureSearchQueryArgs) (
@@ -746,14 +982,17 @@ func (f LocalFeatureBaseQuery) Query(args FeatureSearchQueryArgs) (
 					BrowserNameParam:           browserNameParamName,
 					LocalFSMetricsTemplateData: stableMetricsData,
 				}),
-			Alias: derviedTableSortMetrics,
+			Alias:  derviedTableSortMetrics,
+			Column: "SortMetric",
 		})
 		optionalJoins = append(optionalJoins, JoinData{
 			Template: localFSBrowserImplementationStatusTemplate.Execute(
 				LocalFSBrowserImplStatusTemplateData{
 					BrowserNameParam: browserNameParamName,
+					AsOf:             args.AsOf,
 				}),
-			Alias: derviedTableSortImpl,
+			Alias:  derviedTableSortImpl,
+			Column: "SortImplStatus",
 		})
 	} else if args.SortByExpBrowserImpl != nil {
 		browserNameParamName := "sortExpBrowserNameMetricParam"
@@ -764,30 +1003,48 @@ func (f LocalFeatureBaseQuery) Query(args FeatureSearchQueryArgs) (
 					BrowserNameParam:           browserNameParamName,
 					LocalFSMetricsTemplateData: experimentalMetricsData,
 				}),
-			Alias: derviedTableSortMetrics,
+			Alias:  derviedTableSortMetrics,
+			Column: "SortMetric",
 		})
 		optionalJoins = append(optionalJoins, JoinData{
 			Template: localFSBrowserImplementationStatusTemplate.Execute(
 				LocalFSBrowserImplStatusTemplateData{
 					BrowserNameParam: browserNameParamName,
+					AsOf:             args.AsOf,
+				}),
+			Alias:  derviedTableSortImpl,
+			Column: "SortImplStatus",
+		})
+	} else if args.SortByBrowserAvailableDate != nil {
+		browserNameParamName := "sortAvailableDateBrowserNameParam"
+		params[browserNameParamName] = args.SortByBrowserAvailableDate.BrowserName
+		optionalJoins = append(optionalJoins, JoinData{
+			Template: localFSBrowserAvailableDateTemplate.Execute(
+				FSBrowserAvailableDateTemplateData{
+					BrowserNameParam: browserNameParamName,
+					AsOf:             args.AsOf,
 				}),
-			Alias: derviedTableSortImpl,
+			Alias:  derviedTableSortAvailableDate,
+			Column: "SortAvailableDate",
 		})
 	}
 
 	return localFSSelectQueryTemplate.Execute(LocalFSSelectTemplateData{
 		PassRateColumn: metricsPassRateColumn(args.MetricView),
+		AsOf:           args.AsOf,
 		CommonFSSelectTemplateData: CommonFSSelectTemplateData{
-			BaseQueryFragment:    f.buildBaseQueryFragment(),
-			StableMetrics:        stableMetrics,
-			ExperimentalMetrics:  experimentalMetrics,
-			ImplementationStatus: localFSImplementationStatusRawTemplate,
-			PageFilters:          args.PageFilters,
-			Filters:              args.Filters,
-			SortClause:           args.SortClause,
-			Offset:               args.Offset,
-			PageSize:             args.PageSize,
-			OptionalJoins:        optionalJoins,
+			BaseQueryFragment:   f.buildBaseQueryFragment(args.AsOf),
+			StableMetrics:       stableMetrics,
+			ExperimentalMetrics: experimentalMetrics,
+			ImplementationStatus: localFSImplementationStatusTemplate.Execute(
+				FSImplementationStatusTemplateData{AsOf: args.AsOf}),
+			PageFilters:   args.PageFilters,
+			Filters:       args.Filters,
+			SortClause:    args.SortClause,
+			Offset:        args.Offset,
+			PageSize:      args.PageSize,
+			OptionalJoins: optionalJoins,
+			SortColumns:   optionalJoinSortColumns(optionalJoins),
 		},
 	}), params
 }
@@ -0,0 +1,128 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcpspanner
+
+import (
+	"context"
+	"errors"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/api/iterator"
+)
+
+const featureCompatFeaturesTable = "FeatureCompatFeatures"
+
+// SpannerFeatureCompatFeatures is a wrapper for the BCD keys of a feature stored in spanner.
+type SpannerFeatureCompatFeatures struct {
+	WebFeatureID   string   `spanner:"WebFeatureID"`
+	CompatFeatures []string `spanner:"CompatFeatures"`
+}
+
+// UpsertFeatureCompatFeatures stores the BCD keys that compose the feature with the given feature key.
+// If the feature already has BCD keys, they are replaced.
+func (c *Client) UpsertFeatureCompatFeatures(ctx context.Context, featureKey string, compatFeatures []string) error {
+	id, err := c.GetIDFromFeatureKey(ctx, NewFeatureKeyFilter(featureKey))
+	if err != nil {
+		return err
+	}
+	if id == nil {
+		return ErrInternalQueryFailure
+	}
+	_, err = c.ReadWriteTransaction(ctx, func(_ context.Context, txn *spanner.ReadWriteTransaction) error {
+		m, err := spanner.InsertOrUpdateStruct(featureCompatFeaturesTable, SpannerFeatureCompatFeatures{
+			WebFeatureID:   *id,
+			CompatFeatures: compatFeatures,
+		})
+		if err != nil {
+			return errors.Join(ErrInternalQueryFailure, err)
+		}
+
+		return txn.BufferWrite([]*spanner.Mutation{m})
+	})
+	if err != nil {
+		return errors.Join(ErrInternalQueryFailure, err)
+	}
+
+	return nil
+}
+
+// CompatFeatureBrowserSupport contains the version of a browser that added support for a BCD key.
+type CompatFeatureBrowserSupport struct {
+	BrowserName string `spanner:"BrowserName"`
+	// VersionAdded is nil if the browser does not fully support the BCD key.
+	VersionAdded *string `spanner:"VersionAdded"`
+}
+
+// FeatureCompatFeature is a BCD key of a feature along with its support in each browser.
+type FeatureCompatFeature struct {
+	CompatFeature string `spanner:"CompatFeature"`
+	// BrowserSupport is empty if the BCD key has not been ingested from BCD yet.
+	BrowserSupport []*CompatFeatureBrowserSupport `spanner:"BrowserSupport"`
+}
+
+const featureCompatFeaturesQuery = `
+SELECT
+	ck AS CompatFeature,
+	ARRAY(
+		SELECT AS STRUCT
+			s.BrowserName,
+			s.VersionAdded
+		FROM BrowserCompatFeatureSupport s
+		WHERE s.CompatFeature = ck
+		ORDER BY s.BrowserName
+	) AS BrowserSupport
+FROM FeatureCompatFeatures fcf
+CROSS JOIN UNNEST(fcf.CompatFeatures) AS ck
+WHERE fcf.WebFeatureID = @webFeatureID
+ORDER BY ck`
+
+// GetFeatureCompatFeatures returns the BCD keys of the feature with the given feature key, sorted by key.
+// The key can also be an alias of a renamed feature.
+func (c *Client) GetFeatureCompatFeatures(ctx context.Context, featureKey string) ([]FeatureCompatFeature, error) {
+	id, err := c.GetIDFromFeatureKey(ctx, NewFeatureKeyOrAliasFilter(featureKey))
+	if err != nil {
+		return nil, err
+	}
+	if id == nil {
+		return nil, ErrInternalQueryFailure
+	}
+	stmt := spanner.NewStatement(featureCompatFeaturesQuery)
+	stmt.Params = map[string]interface{}{
+		"webFeatureID": *id,
+	}
+
+	txn := c.Single()
+	defer txn.Close()
+	it := txn.Query(ctx, stmt)
+	defer it.Stop()
+
+	ret := []FeatureCompatFeature{}
+	for {
+		row, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, errors.Join(ErrInternalQueryFailure, err)
+		}
+		var compatFeature FeatureCompatFeature
+		if err := row.ToStruct(&compatFeature); err != nil {
+			return nil, errors.Join(ErrInternalQueryFailure, err)
+		}
+		ret = append(ret, compatFeature)
+	}
+
+	return ret, nil
+}
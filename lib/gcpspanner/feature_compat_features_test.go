@@ -0,0 +1,124 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcpspanner
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func setupRequiredTablesForFeatureCompatFeatures(ctx context.Context,
+	client *Client, t *testing.T) {
+	for _, feature := range getSampleFeatures() {
+		_, err := client.UpsertWebFeature(ctx, feature)
+		if err != nil {
+			t.Fatalf("unexpected error during insert of features. %s", err.Error())
+		}
+	}
+	err := client.UpsertBrowserCompatFeatureSupports(ctx, []BrowserCompatFeatureSupport{
+		{CompatFeature: "api.Feature1", BrowserName: "chrome", VersionAdded: valuePtr("100")},
+		{CompatFeature: "api.Feature1", BrowserName: "safari", VersionAdded: nil},
+		{CompatFeature: "api.Feature1.sub", BrowserName: "chrome", VersionAdded: valuePtr("≤79")},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error during insert of browser support. %s", err.Error())
+	}
+}
+
+func TestFeatureCompatFeatures(t *testing.T) {
+	client := getTestDatabase(t)
+	ctx := context.Background()
+	setupRequiredTablesForFeatureCompatFeatures(ctx, client, t)
+
+	err := client.UpsertFeatureCompatFeatures(ctx, "feature1",
+		[]string{"api.Feature1.sub", "api.Feature1", "api.NotInBCD"})
+	if err != nil {
+		t.Fatalf("unexpected error during insert. %s", err.Error())
+	}
+
+	expected := []FeatureCompatFeature{
+		{
+			CompatFeature: "api.Feature1",
+			BrowserSupport: []*CompatFeatureBrowserSupport{
+				{BrowserName: "chrome", VersionAdded: valuePtr("100")},
+				{BrowserName: "safari", VersionAdded: nil},
+			},
+		},
+		{
+			CompatFeature: "api.Feature1.sub",
+			BrowserSupport: []*CompatFeatureBrowserSupport{
+				{BrowserName: "chrome", VersionAdded: valuePtr("≤79")},
+			},
+		},
+		{
+			CompatFeature:  "api.NotInBCD",
+			BrowserSupport: []*CompatFeatureBrowserSupport{},
+		},
+	}
+	result, err := client.GetFeatureCompatFeatures(ctx, "feature1")
+	if err != nil {
+		t.Fatalf("unexpected error. %s", err.Error())
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Errorf("unexpected result\nexpected %+v\nreceived %+v", expected, result)
+	}
+
+	// Upserting replaces the previous keys.
+	err = client.UpsertFeatureCompatFeatures(ctx, "feature1", []string{"api.Feature1.sub"})
+	if err != nil {
+		t.Fatalf("unexpected error during update. %s", err.Error())
+	}
+	result, err = client.GetFeatureCompatFeatures(ctx, "feature1")
+	if err != nil {
+		t.Fatalf("unexpected error. %s", err.Error())
+	}
+	if !reflect.DeepEqual(expected[1:2], result) {
+		t.Errorf("unexpected result\nexpected %+v\nreceived %+v", expected[1:2], result)
+	}
+
+	// Aliases of renamed features resolve to the renamed feature.
+	err = client.UpsertFeatureAlias(ctx, "feature1", "old-feature1")
+	if err != nil {
+		t.Fatalf("unexpected error during insert of alias. %s", err.Error())
+	}
+	result, err = client.GetFeatureCompatFeatures(ctx, "old-feature1")
+	if err != nil {
+		t.Fatalf("unexpected error. %s", err.Error())
+	}
+	if !reflect.DeepEqual(expected[1:2], result) {
+		t.Errorf("unexpected result\nexpected %+v\nreceived %+v", expected[1:2], result)
+	}
+
+	// Features without keys return an empty list.
+	result, err = client.GetFeatureCompatFeatures(ctx, "feature2")
+	if err != nil {
+		t.Fatalf("unexpected error. %s", err.Error())
+	}
+	if len(result) != 0 {
+		t.Errorf("expected no compat features. received %+v", result)
+	}
+
+	// The feature must exist.
+	_, err = client.GetFeatureCompatFeatures(ctx, "nopefeature")
+	if !errors.Is(err, ErrQueryReturnedNoResults) {
+		t.Errorf("unexpected error. %s", err)
+	}
+	err = client.UpsertFeatureCompatFeatures(ctx, "nopefeature", []string{"api.Feature1"})
+	if !errors.Is(err, ErrQueryReturnedNoResults) {
+		t.Errorf("unexpected error. %s", err)
+	}
+}
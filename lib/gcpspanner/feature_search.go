@@ -42,6 +42,11 @@ type SpannerFeatureResult struct {
 	LowDate                *time.Time                    `spanner:"LowDate"`
 	HighDate               *time.Time                    `spanner:"HighDate"`
 	SpecLinks              []string                      `spanner:"SpecLinks"`
+	// SortMetric and SortImplStatus are only returned when sorting by browser implementation.
+	SortMetric     *big.Rat `spanner:"SortMetric"`
+	SortImplStatus *string  `spanner:"SortImplStatus"`
+	// SortAvailableDate is only returned when sorting by browser available date.
+	SortAvailableDate *time.Time `spanner:"SortAvailableDate"`
 }
 
 // BrowserImplementationStatus is an enumeration of the possible implementation states for a feature in a browser.
@@ -86,11 +91,36 @@ type FeatureResult struct {
 	SpecLinks              []string                `spanner:"SpecLinks"`
 }
 
+// FeatureSearchFacet is an enumeration of the aggregations that can be requested alongside a feature search.
+type FeatureSearchFacet string
+
+const (
+	// BaselineStatusFacet counts the matching features per baseline status.
+	BaselineStatusFacet FeatureSearchFacet = "baseline_status"
+	// AvailableOnFacet counts the matching features available in each browser.
+	AvailableOnFacet FeatureSearchFacet = "available_on"
+)
+
+// FacetValueCount contains the number of features that match a search for a given facet value.
+type FacetValueCount struct {
+	Value string `spanner:"Value"`
+	Count int64  `spanner:"Count"`
+}
+
+// FeatureSearchFacets contains the facets requested for a feature search.
+// A facet that was not requested is nil.
+type FeatureSearchFacets struct {
+	BaselineStatus []FacetValueCount
+	AvailableOn    []FacetValueCount
+}
+
 // FeatureResultPage contains the details for the feature search request.
 type FeatureResultPage struct {
 	Total         int64
 	NextPageToken *string
 	Features      []FeatureResult
+	// Facets is only populated when facets are requested.
+	Facets *FeatureSearchFacets
 }
 
 func (c *Client) FeaturesSearch(
@@ -101,18 +131,26 @@ func (c *Client) FeaturesSearch(
 	sortOrder Sortable,
 	wptMetricView WPTMetricView,
 	browsers []string,
+	asOf *time.Time,
+	facets []FeatureSearchFacet,
 ) (*FeatureResultPage, error) {
 	// Build filterable
 	filterBuilder := NewFeatureSearchFilterBuilder()
+	filterBuilder.asOf = asOf != nil
 	filter := filterBuilder.Build(searchNode)
 
 	var offsetCursor *FeatureResultOffsetCursor
+	var keysetCursor *FeatureResultKeysetCursor
 	var err error
 	if pageToken != nil {
-		offsetCursor, err = decodeInputFeatureResultCursor(*pageToken)
+		offsetCursor, keysetCursor, err = decodeInputFeatureResultCursor(*pageToken)
 		if err != nil {
 			return nil, errors.Join(ErrInternalQueryFailure, err)
 		}
+		// A keyset cursor only makes sense for the sort that created it.
+		if keysetCursor != nil && !keysetCursor.matchesSort(sortOrder) {
+			return nil, errors.Join(ErrInternalQueryFailure, ErrInvalidCursorFormat)
+		}
 	}
 
 	txn := c.ReadOnlyTransaction()
@@ -121,8 +159,10 @@ func (c *Client) FeaturesSearch(
 	queryBuilder := FeatureSearchQueryBuilder{
 		baseQuery:     c.featureSearchQuery,
 		offsetCursor:  offsetCursor,
+		keysetCursor:  keysetCursor,
 		wptMetricView: wptMetricView,
 		browsers:      browsers,
+		asOf:          asOf,
 	}
 
 	// Get the total
@@ -131,8 +171,17 @@ func (c *Client) FeaturesSearch(
 		return nil, errors.Join(ErrInternalQueryFailure, err)
 	}
 
+	// Get the facets
+	var facetResults *FeatureSearchFacets
+	if len(facets) > 0 {
+		facetResults, err = c.getFeatureSearchFacets(ctx, queryBuilder, filter, facets, txn)
+		if err != nil {
+			return nil, errors.Join(ErrInternalQueryFailure, err)
+		}
+	}
+
 	// Get the results
-	results, err := c.getFeatureResult(
+	results, lastRow, err := c.getFeatureResult(
 		ctx,
 		queryBuilder,
 		filter,
@@ -147,14 +196,11 @@ func (c *Client) FeaturesSearch(
 		Features:      results,
 		Total:         total,
 		NextPageToken: nil,
+		Facets:        facetResults,
 	}
 
-	if len(results) == pageSize {
-		previousOffset := 0
-		if offsetCursor != nil {
-			previousOffset = offsetCursor.Offset
-		}
-		token := encodeFeatureResultOffsetCursor(previousOffset + pageSize)
+	if len(results) == pageSize && lastRow != nil {
+		token := encodeFeatureResultKeysetCursor(newFeatureResultKeysetCursor(sortOrder, *lastRow))
 		page.NextPageToken = &token
 
 		return &page, nil
@@ -185,31 +231,70 @@ func (c *Client) getTotalFeatureCount(
 	return count, nil
 }
 
+func (c *Client) getFeatureSearchFacets(
+	ctx context.Context,
+	queryBuilder FeatureSearchQueryBuilder,
+	filter *FeatureSearchCompiledFilter,
+	facets []FeatureSearchFacet,
+	txn *spanner.ReadOnlyTransaction) (*FeatureSearchFacets, error) {
+	ret := new(FeatureSearchFacets)
+	for _, facet := range facets {
+		stmt := queryBuilder.FacetQueryBuild(filter, facet)
+		// Initialize to an empty slice so that a requested facet without any matches is not nil.
+		counts := []FacetValueCount{}
+		err := txn.Query(ctx, stmt).Do(func(row *spanner.Row) error {
+			var count FacetValueCount
+			if err := row.ToStruct(&count); err != nil {
+				return err
+			}
+			counts = append(counts, count)
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		switch facet {
+		case BaselineStatusFacet:
+			ret.BaselineStatus = counts
+		case AvailableOnFacet:
+			ret.AvailableOn = counts
+		}
+	}
+
+	return ret, nil
+}
+
+// getFeatureResult returns the features for the page along with the last row of the page.
+// The last row is used to build the next page token.
 func (c *Client) getFeatureResult(
 	ctx context.Context,
 	queryBuilder FeatureSearchQueryBuilder,
 	filter *FeatureSearchCompiledFilter,
 	sortOrder Sortable,
 	pageSize int,
-	txn *spanner.ReadOnlyTransaction) ([]FeatureResult, error) {
+	txn *spanner.ReadOnlyTransaction) ([]FeatureResult, *SpannerFeatureResult, error) {
 	stmt := queryBuilder.Build(filter, sortOrder, pageSize)
 
 	it := txn.Query(ctx, stmt)
 	defer it.Stop()
 
 	var results []FeatureResult
+	var lastRow *SpannerFeatureResult
 	for {
 		row, err := it.Next()
 		if errors.Is(err, iterator.Done) {
 			break
 		}
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		var result SpannerFeatureResult
 		if err := row.ToStruct(&result); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
+		lastRow = &result
 
 		stableMetrics := convertSpannerMetrics(result.StableMetrics)
 		experimentalMetrics := convertSpannerMetrics(result.ExperimentalMetrics)
@@ -239,7 +324,7 @@ func (c *Client) getFeatureResult(
 		results = append(results, actualResult)
 	}
 
-	return results, nil
+	return results, lastRow, nil
 }
 
 // convertSpannerMetrics converts a slice of SpannerFeatureResultMetric to FeatureResultMetric.
@@ -17,6 +17,7 @@ package gcpspanner
 import (
 	"fmt"
 	"maps"
+	"slices"
 	"strings"
 	"time"
 
@@ -27,12 +28,15 @@ import (
 type FeatureSearchFilterBuilder struct {
 	paramCounter int
 	params       map[string]interface{}
+	// asOf limits the availability filters to releases on or before the asOfDate parameter.
+	asOf bool
 }
 
 func NewFeatureSearchFilterBuilder() *FeatureSearchFilterBuilder {
 	return &FeatureSearchFilterBuilder{
 		paramCounter: 0,
 		params:       nil,
+		asOf:         false,
 	}
 }
 
@@ -182,6 +186,13 @@ func searchOperatorToSpannerStringPatternOperator(in searchtypes.SearchOperator)
 func (b *FeatureSearchFilterBuilder) availabilityFilter(browser string, op searchtypes.SearchOperator) string {
 	paramName := b.addParamGetName(browser)
 
+	if b.asOf {
+		return fmt.Sprintf(`wf.ID %s (SELECT bfa.WebFeatureID FROM BrowserFeatureAvailabilities bfa
+JOIN BrowserReleases br ON bfa.BrowserName = br.BrowserName AND bfa.BrowserVersion = br.BrowserVersion
+WHERE bfa.BrowserName = @%s AND br.ReleaseDate <= @%s)`,
+			searchOperatorToSpannerListOperator(op), paramName, asOfDateParamName)
+	}
+
 	return fmt.Sprintf(`wf.ID %s (SELECT WebFeatureID FROM BrowserFeatureAvailabilities
 WHERE BrowserName = @%s)`, searchOperatorToSpannerListOperator(op), paramName)
 }
@@ -256,8 +267,11 @@ type Filterable interface {
 type FeatureSearchQueryBuilder struct {
 	baseQuery     FeatureSearchBaseQuery
 	offsetCursor  *FeatureResultOffsetCursor
+	keysetCursor  *FeatureResultKeysetCursor
 	wptMetricView WPTMetricView
 	browsers      []string
+	// asOf is the optional point in time to evaluate the features at.
+	asOf *time.Time
 }
 
 func (q FeatureSearchQueryBuilder) CountQueryBuild(
@@ -265,12 +279,16 @@ func (q FeatureSearchQueryBuilder) CountQueryBuild(
 	filterParams := make(map[string]interface{})
 	args := FeatureSearchCountArgs{
 		Filters: nil,
+		AsOf:    q.asOf != nil,
 	}
 	args.Filters = defaultFeatureSearchFilters()
 	if filter != nil {
 		args.Filters = append(args.Filters, filter.filters...)
 		maps.Copy(filterParams, filter.Params())
 	}
+	if q.asOf != nil {
+		filterParams[asOfDateParamName] = *q.asOf
+	}
 
 	sql := q.baseQuery.CountQuery(args)
 
@@ -280,12 +298,36 @@ func (q FeatureSearchQueryBuilder) CountQueryBuild(
 	return stmt
 }
 
+func (q FeatureSearchQueryBuilder) FacetQueryBuild(
+	filter *FeatureSearchCompiledFilter, facet FeatureSearchFacet) spanner.Statement {
+	filterParams := make(map[string]interface{})
+	args := FeatureSearchCountArgs{
+		Filters: nil,
+		AsOf:    q.asOf != nil,
+	}
+	args.Filters = defaultFeatureSearchFilters()
+	if filter != nil {
+		args.Filters = append(args.Filters, filter.filters...)
+		maps.Copy(filterParams, filter.Params())
+	}
+	if q.asOf != nil {
+		filterParams[asOfDateParamName] = *q.asOf
+	}
+
+	sql := q.baseQuery.FacetQuery(facet, args)
+
+	stmt := spanner.NewStatement(sql)
+	stmt.Params = filterParams
+
+	return stmt
+}
+
 func (q FeatureSearchQueryBuilder) Build(
 	filter *FeatureSearchCompiledFilter,
 	sort Sortable,
 	pageSize int) spanner.Statement {
 
-	var stableBrowserImplDetails, expBrowserImplDetails *SortByBrowserImplDetails
+	var stableBrowserImplDetails, expBrowserImplDetails, availableDateDetails *SortByBrowserImplDetails
 
 	switch sort.SortTarget() {
 	case StableImplSort:
@@ -296,7 +338,11 @@ func (q FeatureSearchQueryBuilder) Build(
 		expBrowserImplDetails = &SortByBrowserImplDetails{
 			BrowserName: sort.BrowserTarget(),
 		}
-	case IDSort, NameSort, StatusSort:
+	case BrowserAvailableDateSort:
+		availableDateDetails = &SortByBrowserImplDetails{
+			BrowserName: sort.BrowserTarget(),
+		}
+	case IDSort, NameSort, StatusSort, BaselineLowDateSort, BaselineHighDateSort, SpecSort:
 		break // do nothing.
 	}
 
@@ -310,18 +356,30 @@ func (q FeatureSearchQueryBuilder) Build(
 		Browsers:    q.browsers,
 		SortClause:  sort.Clause(),
 		// Special Sort Targets.
-		SortByStableBrowserImpl: stableBrowserImplDetails,
-		SortByExpBrowserImpl:    expBrowserImplDetails,
+		SortByStableBrowserImpl:    stableBrowserImplDetails,
+		SortByExpBrowserImpl:       expBrowserImplDetails,
+		SortByBrowserAvailableDate: availableDateDetails,
+		AsOf:                       q.asOf != nil,
 	}
 
 	if q.offsetCursor != nil {
 		queryArgs.Offset = q.offsetCursor.Offset
 	}
+	if q.keysetCursor != nil {
+		pageFilter, pageFilterParams := buildKeysetPageFilter(sort, *q.keysetCursor)
+		if pageFilter != "" {
+			queryArgs.PageFilters = append(queryArgs.PageFilters, pageFilter)
+			maps.Copy(filterParams, pageFilterParams)
+		}
+	}
 	queryArgs.Filters = defaultFeatureSearchFilters()
 	if filter != nil {
 		queryArgs.Filters = append(queryArgs.Filters, filter.filters...)
 		maps.Copy(filterParams, filter.Params())
 	}
+	if q.asOf != nil {
+		filterParams[asOfDateParamName] = *q.asOf
+	}
 
 	sql, params := q.baseQuery.Query(queryArgs)
 	maps.Copy(filterParams, params)
@@ -335,7 +393,9 @@ func (q FeatureSearchQueryBuilder) Build(
 
 // Sortable is a basic class that all/most sortables can include.
 type Sortable struct {
-	clause         string
+	clause string
+	// columns are the columns used in the clause, in order. The last column is the tie breaker.
+	columns        []FeatureSearchColumn
 	sortTarget     FeaturesSearchSortTarget
 	ascendingOrder bool
 	browserTarget  *string
@@ -364,6 +424,164 @@ func buildFullClause(sortableClauses []string, tieBreakerColumn FeatureSearchCol
 	return strings.Join(append(sortableClauses, string(tieBreakerColumn)), ", ")
 }
 
+// buildKeysetPageFilter generates a filter that only matches the rows that come after the row described by the
+// cursor for the given sort.
+// For columns (a, b, tiebreaker), the filter looks like:
+//
+//	(a > @a) OR (a = @a AND b > @b) OR (a = @a AND b = @b AND tiebreaker > @tiebreaker)
+//
+// Spanner places NULL values first in ascending order and last in descending order. The cursor values are known
+// ahead of time so the NULL handling for each column is generated directly into the filter.
+// The tie breaker is always sorted in ascending order.
+func buildKeysetPageFilter(sort Sortable, cursor FeatureResultKeysetCursor) (string, map[string]interface{}) {
+	params := make(map[string]interface{})
+	var equalConditions []string
+	var disjuncts []string
+	for idx, column := range sort.columns {
+		isAscending := sort.ascendingOrder
+		if idx == len(sort.columns)-1 {
+			isAscending = true
+		}
+		value := cursor.columnValue(column)
+		paramName := fmt.Sprintf("keysetParam%d", idx)
+		if value != nil {
+			params[paramName] = value
+		}
+		afterCondition := buildKeysetAfterCondition(column.ToFilterColumn(), paramName, value != nil, isAscending)
+		if afterCondition != "" {
+			conditions := append(slices.Clone(equalConditions), afterCondition)
+			disjuncts = append(disjuncts, "("+strings.Join(conditions, " AND ")+")")
+		}
+		equalConditions = append(equalConditions,
+			buildKeysetEqualCondition(column.ToFilterColumn(), paramName, value != nil))
+	}
+
+	if len(disjuncts) == 0 {
+		return "", nil
+	}
+
+	return "(" + strings.Join(disjuncts, " OR ") + ")", params
+}
+
+// buildKeysetAfterCondition returns the condition for the values that come strictly after the cursor value for the
+// column. It returns an empty string if no value can come after the cursor value.
+func buildKeysetAfterCondition(column string, paramName string, hasValue bool, isAscending bool) string {
+	switch {
+	case !hasValue && isAscending:
+		return fmt.Sprintf("%s IS NOT NULL", column)
+	case !hasValue && !isAscending:
+		// NULL values are last in descending order.
+		return ""
+	case isAscending:
+		return fmt.Sprintf("%s > @%s", column, paramName)
+	default:
+		return fmt.Sprintf("(%s < @%s OR %s IS NULL)", column, paramName, column)
+	}
+}
+
+// buildKeysetEqualCondition returns the condition for the values that are equal to the cursor value for the column.
+func buildKeysetEqualCondition(column string, paramName string, hasValue bool) string {
+	if !hasValue {
+		return fmt.Sprintf("%s IS NULL", column)
+	}
+
+	return fmt.Sprintf("%s = @%s", column, paramName)
+}
+
+// newFeatureResultKeysetCursor creates a cursor that points to the given row for the sort.
+func newFeatureResultKeysetCursor(sort Sortable, row SpannerFeatureResult) FeatureResultKeysetCursor {
+	cursor := FeatureResultKeysetCursor{
+		SortTarget:         sort.SortTarget(),
+		Ascending:          sort.ascendingOrder,
+		BrowserTarget:      sort.browserTarget,
+		LastFeatureKey:     row.FeatureKey,
+		LastName:           nil,
+		LastLowDate:        nil,
+		LastHighDate:       nil,
+		LastStatus:         nil,
+		LastSortMetric:     nil,
+		LastSortImplStatus: nil,
+		LastAvailableDate:  nil,
+		LastSpec:           nil,
+	}
+	switch sort.SortTarget() {
+	case NameSort:
+		cursor.LastName = &row.Name
+	case StatusSort:
+		cursor.LastLowDate = row.LowDate
+		cursor.LastHighDate = row.HighDate
+		cursor.LastStatus = row.Status
+	case StableImplSort, ExperimentalImplSort:
+		cursor.LastSortMetric = row.SortMetric
+		cursor.LastSortImplStatus = row.SortImplStatus
+	case BaselineLowDateSort:
+		cursor.LastLowDate = row.LowDate
+	case BaselineHighDateSort:
+		cursor.LastHighDate = row.HighDate
+	case BrowserAvailableDateSort:
+		cursor.LastAvailableDate = row.SortAvailableDate
+	case SpecSort:
+		if len(row.SpecLinks) > 0 {
+			cursor.LastSpec = &row.SpecLinks[0]
+		}
+	case IDSort:
+		break // do nothing.
+	}
+
+	return cursor
+}
+
+// matchesSort reports whether the cursor was created by the same sort target, direction and browser.
+func (c FeatureResultKeysetCursor) matchesSort(sort Sortable) bool {
+	browserTarget := ""
+	if c.BrowserTarget != nil {
+		browserTarget = *c.BrowserTarget
+	}
+
+	return c.SortTarget == sort.SortTarget() &&
+		c.Ascending == sort.ascendingOrder &&
+		browserTarget == sort.BrowserTarget()
+}
+
+// columnValue returns the value of the cursor for the column. It returns nil if the value is NULL.
+func (c FeatureResultKeysetCursor) columnValue(column FeatureSearchColumn) interface{} {
+	switch column {
+	case featureSearchFeatureKeyColumn:
+		return c.LastFeatureKey
+	case featureSearchFeatureNameColumn:
+		return valueOrNil(c.LastName)
+	case featureSearchLowDateColumn:
+		return valueOrNil(c.LastLowDate)
+	case featureSearchHighDateColumn:
+		return valueOrNil(c.LastHighDate)
+	case featureSearchStatusColumn:
+		return valueOrNil(c.LastStatus)
+	case featureSearcBrowserMetricColumn:
+		if c.LastSortMetric == nil {
+			return nil
+		}
+
+		return c.LastSortMetric
+	case featureSearcBrowserImplColumn:
+		return valueOrNil(c.LastSortImplStatus)
+	case featureSearchAvailableDateColumn:
+		return valueOrNil(c.LastAvailableDate)
+	case featureSearchSpecColumn:
+		return valueOrNil(c.LastSpec)
+	}
+
+	return nil
+}
+
+// valueOrNil dereferences the pointer. It returns an untyped nil if the pointer is nil.
+func valueOrNil[T any](in *T) interface{} {
+	if in == nil {
+		return nil
+	}
+
+	return *in
+}
+
 func buildSortableOrderClause(isAscending bool, column FeatureSearchColumn) string {
 	direction := "ASC"
 	if !isAscending {
@@ -384,7 +602,9 @@ func (f FeatureSearchColumn) ToFilterColumn() string {
 		featureSearchLowDateColumn,
 		featureSearchHighDateColumn,
 		featureSearcBrowserImplColumn,
-		featureSearchStatusColumn:
+		featureSearchStatusColumn,
+		featureSearchAvailableDateColumn,
+		featureSearchSpecColumn:
 		return string(f)
 	}
 
@@ -396,11 +616,15 @@ func (f FeatureSearchColumn) ToFilterColumn() string {
 type FeaturesSearchSortTarget string
 
 const (
-	IDSort               FeaturesSearchSortTarget = "id"
-	NameSort             FeaturesSearchSortTarget = "name"
-	StatusSort           FeaturesSearchSortTarget = "status"
-	StableImplSort       FeaturesSearchSortTarget = "stable_browser_impl"
-	ExperimentalImplSort FeaturesSearchSortTarget = "experimental_browser_impl"
+	IDSort                   FeaturesSearchSortTarget = "id"
+	NameSort                 FeaturesSearchSortTarget = "name"
+	StatusSort               FeaturesSearchSortTarget = "status"
+	StableImplSort           FeaturesSearchSortTarget = "stable_browser_impl"
+	ExperimentalImplSort     FeaturesSearchSortTarget = "experimental_browser_impl"
+	BaselineLowDateSort      FeaturesSearchSortTarget = "baseline_low_date"
+	BaselineHighDateSort     FeaturesSearchSortTarget = "baseline_high_date"
+	BrowserAvailableDateSort FeaturesSearchSortTarget = "browser_available_date"
+	SpecSort                 FeaturesSearchSortTarget = "spec"
 )
 
 const (
@@ -411,11 +635,16 @@ const (
 	featureSearchHighDateColumn     FeatureSearchColumn = "HighDate"
 	featureSearcBrowserMetricColumn FeatureSearchColumn = "sort_metric_calcs.SortMetric"
 	featureSearcBrowserImplColumn   FeatureSearchColumn = "sort_impl_calcs.SortImplStatus"
+	// featureSearchAvailableDateColumn is the release date of the first availability in the browser.
+	featureSearchAvailableDateColumn FeatureSearchColumn = "sort_available_date_calcs.SortAvailableDate"
+	// featureSearchSpecColumn is the first spec link of the feature.
+	featureSearchSpecColumn FeatureSearchColumn = "fs.Links[SAFE_OFFSET(0)]"
 )
 
 const (
-	derviedTableSortMetrics = "sort_metric_calcs"
-	derviedTableSortImpl    = "sort_impl_calcs"
+	derviedTableSortMetrics       = "sort_metric_calcs"
+	derviedTableSortImpl          = "sort_impl_calcs"
+	derviedTableSortAvailableDate = "sort_available_date_calcs"
 )
 
 // NewFeatureNameSort returns a Sortable specifically for the Name column.
@@ -424,6 +653,7 @@ func NewFeatureNameSort(isAscending bool) Sortable {
 		clause: buildFullClause(
 			[]string{buildSortableOrderClause(isAscending, featureSearchFeatureNameColumn)},
 			featureSearchFeatureKeyColumn),
+		columns:        []FeatureSearchColumn{featureSearchFeatureNameColumn, featureSearchFeatureKeyColumn},
 		ascendingOrder: isAscending,
 		sortTarget:     NameSort,
 		browserTarget:  nil,
@@ -441,6 +671,12 @@ func NewBaselineStatusSort(isAscending bool) Sortable {
 			},
 			featureSearchFeatureKeyColumn,
 		),
+		columns: []FeatureSearchColumn{
+			featureSearchLowDateColumn,
+			featureSearchHighDateColumn,
+			featureSearchStatusColumn,
+			featureSearchFeatureKeyColumn,
+		},
 		ascendingOrder: isAscending,
 		sortTarget:     StatusSort,
 		browserTarget:  nil,
@@ -471,8 +707,68 @@ func NewBrowserImplSort(isAscending bool, browserName string, isStable bool) Sor
 			},
 			featureSearchFeatureKeyColumn,
 		),
+		columns: []FeatureSearchColumn{
+			featureSearcBrowserMetricColumn,
+			featureSearcBrowserImplColumn,
+			featureSearchFeatureKeyColumn,
+		},
 		browserTarget:  &browserName,
 		ascendingOrder: isAscending,
 		sortTarget:     sortTarget,
 	}
 }
+
+// NewBaselineLowDateSort returns a Sortable specifically for the LowDate column.
+func NewBaselineLowDateSort(isAscending bool) Sortable {
+	return Sortable{
+		clause: buildFullClause(
+			[]string{buildSortableOrderClause(isAscending, featureSearchLowDateColumn)},
+			featureSearchFeatureKeyColumn),
+		columns:        []FeatureSearchColumn{featureSearchLowDateColumn, featureSearchFeatureKeyColumn},
+		ascendingOrder: isAscending,
+		sortTarget:     BaselineLowDateSort,
+		browserTarget:  nil,
+	}
+}
+
+// NewBaselineHighDateSort returns a Sortable specifically for the HighDate column.
+func NewBaselineHighDateSort(isAscending bool) Sortable {
+	return Sortable{
+		clause: buildFullClause(
+			[]string{buildSortableOrderClause(isAscending, featureSearchHighDateColumn)},
+			featureSearchFeatureKeyColumn),
+		columns:        []FeatureSearchColumn{featureSearchHighDateColumn, featureSearchFeatureKeyColumn},
+		ascendingOrder: isAscending,
+		sortTarget:     BaselineHighDateSort,
+		browserTarget:  nil,
+	}
+}
+
+// NewBrowserAvailableDateSort creates a Sortable configuration for ordering Web Features by the release date
+// of the first browser release where the feature is available for the specified browser.
+// Features that are not available in the browser have no date.
+func NewBrowserAvailableDateSort(isAscending bool, browserName string) Sortable {
+	return Sortable{
+		clause: buildFullClause(
+			[]string{buildSortableOrderClause(isAscending, featureSearchAvailableDateColumn)},
+			featureSearchFeatureKeyColumn),
+		columns:        []FeatureSearchColumn{featureSearchAvailableDateColumn, featureSearchFeatureKeyColumn},
+		browserTarget:  &browserName,
+		ascendingOrder: isAscending,
+		sortTarget:     BrowserAvailableDateSort,
+	}
+}
+
+// NewSpecSort returns a Sortable for ordering Web Features by their first spec link.
+// Features without a spec have no value.
+func NewSpecSort(isAscending bool) Sortable {
+	return Sortable{
+		clause: buildFullClause(
+			[]string{buildSortableOrderClause(isAscending, featureSearchSpecColumn)},
+			featureSearchFeatureKeyColumn),
+		columns:        []FeatureSearchColumn{featureSearchSpecColumn, featureSearchFeatureKeyColumn},
+		ascendingOrder: isAscending,
+		sortTarget:     SpecSort,
+		browserTarget:  nil,
+	}
+}
@@ -371,3 +371,209 @@ WHERE BrowserName = @param0)) AND ((fbs.Status = @param1) OR ((wf.Name_Lowercase
 		})
 	}
 }
+
+func TestBuildAsOf(t *testing.T) {
+	b := NewFeatureSearchFilterBuilder()
+	b.asOf = true
+	filter := b.Build(simpleAvailableOnQuery.InputTree)
+	expectedClauses := []string{
+		`(wf.ID IN (SELECT bfa.WebFeatureID FROM BrowserFeatureAvailabilities bfa
+JOIN BrowserReleases br ON bfa.BrowserName = br.BrowserName AND bfa.BrowserVersion = br.BrowserVersion
+WHERE bfa.BrowserName = @param0 AND br.ReleaseDate <= @asOfDate))`,
+	}
+	if !slices.Equal[[]string](filter.Filters(), expectedClauses) {
+		t.Errorf("\nexpected clause [%s]\n  actual clause [%s]", expectedClauses, filter.Filters())
+	}
+	expectedParams := map[string]interface{}{
+		"param0": "chrome",
+	}
+	if !reflect.DeepEqual(expectedParams, filter.Params()) {
+		t.Errorf("expected params (%+v) actual params (%+v)", expectedParams, filter.Params())
+	}
+}
+
+func TestBuildKeysetPageFilter(t *testing.T) {
+	lowDate := time.Date(2000, time.January, 4, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name           string
+		sort           Sortable
+		cursor         FeatureResultKeysetCursor
+		expectedClause string
+		expectedParams map[string]interface{}
+	}{
+		{
+			name: "name ascending",
+			sort: NewFeatureNameSort(true),
+			cursor: FeatureResultKeysetCursor{
+				SortTarget:         NameSort,
+				Ascending:          true,
+				BrowserTarget:      nil,
+				LastFeatureKey:     "feature1",
+				LastName:           valuePtr("Feature 1"),
+				LastLowDate:        nil,
+				LastHighDate:       nil,
+				LastStatus:         nil,
+				LastSortMetric:     nil,
+				LastSortImplStatus: nil,
+				LastAvailableDate:  nil,
+				LastSpec:           nil,
+			},
+			expectedClause: "((wf.Name > @keysetParam0) OR " +
+				"(wf.Name = @keysetParam0 AND wf.FeatureKey > @keysetParam1))",
+			expectedParams: map[string]interface{}{
+				"keysetParam0": "Feature 1",
+				"keysetParam1": "feature1",
+			},
+		},
+		{
+			name: "name descending",
+			sort: NewFeatureNameSort(false),
+			cursor: FeatureResultKeysetCursor{
+				SortTarget:         NameSort,
+				Ascending:          false,
+				BrowserTarget:      nil,
+				LastFeatureKey:     "feature1",
+				LastName:           valuePtr("Feature 1"),
+				LastLowDate:        nil,
+				LastHighDate:       nil,
+				LastStatus:         nil,
+				LastSortMetric:     nil,
+				LastSortImplStatus: nil,
+				LastAvailableDate:  nil,
+				LastSpec:           nil,
+			},
+			expectedClause: "(((wf.Name < @keysetParam0 OR wf.Name IS NULL)) OR " +
+				"(wf.Name = @keysetParam0 AND wf.FeatureKey > @keysetParam1))",
+			expectedParams: map[string]interface{}{
+				"keysetParam0": "Feature 1",
+				"keysetParam1": "feature1",
+			},
+		},
+		{
+			name: "baseline status ascending with null values",
+			sort: NewBaselineStatusSort(true),
+			cursor: FeatureResultKeysetCursor{
+				SortTarget:         StatusSort,
+				Ascending:          true,
+				BrowserTarget:      nil,
+				LastFeatureKey:     "feature2",
+				LastName:           nil,
+				LastLowDate:        &lowDate,
+				LastHighDate:       nil,
+				LastStatus:         valuePtr("low"),
+				LastSortMetric:     nil,
+				LastSortImplStatus: nil,
+				LastAvailableDate:  nil,
+				LastSpec:           nil,
+			},
+			expectedClause: "((LowDate > @keysetParam0) OR " +
+				"(LowDate = @keysetParam0 AND HighDate IS NOT NULL) OR " +
+				"(LowDate = @keysetParam0 AND HighDate IS NULL AND Status > @keysetParam2) OR " +
+				"(LowDate = @keysetParam0 AND HighDate IS NULL AND Status = @keysetParam2 AND " +
+				"wf.FeatureKey > @keysetParam3))",
+			expectedParams: map[string]interface{}{
+				"keysetParam0": lowDate,
+				"keysetParam2": "low",
+				"keysetParam3": "feature2",
+			},
+		},
+		{
+			name: "baseline status descending with null values",
+			sort: NewBaselineStatusSort(false),
+			cursor: FeatureResultKeysetCursor{
+				SortTarget:         StatusSort,
+				Ascending:          false,
+				BrowserTarget:      nil,
+				LastFeatureKey:     "feature4",
+				LastName:           nil,
+				LastLowDate:        nil,
+				LastHighDate:       nil,
+				LastStatus:         nil,
+				LastSortMetric:     nil,
+				LastSortImplStatus: nil,
+				LastAvailableDate:  nil,
+				LastSpec:           nil,
+			},
+			expectedClause: "((LowDate IS NULL AND HighDate IS NULL AND Status IS NULL AND " +
+				"wf.FeatureKey > @keysetParam3))",
+			expectedParams: map[string]interface{}{
+				"keysetParam3": "feature4",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			clause, params := buildKeysetPageFilter(tc.sort, tc.cursor)
+			if clause != tc.expectedClause {
+				t.Errorf("\nexpected clause [%s]\n  actual clause [%s]", tc.expectedClause, clause)
+			}
+			if !reflect.DeepEqual(tc.expectedParams, params) {
+				t.Errorf("expected params (%+v) actual params (%+v)", tc.expectedParams, params)
+			}
+		})
+	}
+}
+
+func TestKeysetCursorMatchesSort(t *testing.T) {
+	testCases := []struct {
+		name          string
+		cursorSort    Sortable
+		requestedSort Sortable
+		expected      bool
+	}{
+		{
+			name:          "same sort",
+			cursorSort:    NewFeatureNameSort(true),
+			requestedSort: NewFeatureNameSort(true),
+			expected:      true,
+		},
+		{
+			name:          "different sort target",
+			cursorSort:    NewFeatureNameSort(true),
+			requestedSort: NewBaselineStatusSort(true),
+			expected:      false,
+		},
+		{
+			name:          "name ascending cursor with name descending sort",
+			cursorSort:    NewFeatureNameSort(true),
+			requestedSort: NewFeatureNameSort(false),
+			expected:      false,
+		},
+		{
+			name:          "same browser implementation sort",
+			cursorSort:    NewBrowserImplSort(false, "chrome", true),
+			requestedSort: NewBrowserImplSort(false, "chrome", true),
+			expected:      true,
+		},
+		{
+			name:          "chrome implementation cursor with safari implementation sort",
+			cursorSort:    NewBrowserImplSort(false, "chrome", true),
+			requestedSort: NewBrowserImplSort(false, "safari", true),
+			expected:      false,
+		},
+		{
+			name:          "chrome available date cursor with firefox available date sort",
+			cursorSort:    NewBrowserAvailableDateSort(true, "chrome"),
+			requestedSort: NewBrowserAvailableDateSort(true, "firefox"),
+			expected:      false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// nolint: exhaustruct // Only the feature key is needed.
+			row := SpannerFeatureResult{FeatureKey: "feature1"}
+			// Round trip through the page token to make sure the sort details survive encoding.
+			token := encodeFeatureResultKeysetCursor(newFeatureResultKeysetCursor(tc.cursorSort, row))
+			_, cursor, err := decodeInputFeatureResultCursor(token)
+			if err != nil {
+				t.Fatalf("unexpected error decoding cursor: %v", err)
+			}
+			if cursor == nil {
+				t.Fatal("expected a keyset cursor")
+			}
+			if matches := cursor.matchesSort(tc.requestedSort); matches != tc.expected {
+				t.Errorf("expected matchesSort to be %t. received %t", tc.expected, matches)
+			}
+		})
+	}
+}
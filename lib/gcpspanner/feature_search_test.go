@@ -514,6 +514,26 @@ func defaultSorting() Sortable {
 	return NewFeatureNameSort(true)
 }
 
+// keysetPageToken returns the page token that points to the given feature for the sort.
+func keysetPageToken(sort Sortable, feature FeatureResult) *string {
+	// nolint: exhaustruct // Only the columns used by the sorts are needed.
+	row := SpannerFeatureResult{
+		FeatureKey: feature.FeatureKey,
+		Name:       feature.Name,
+		Status:     feature.Status,
+		LowDate:    feature.LowDate,
+		HighDate:   feature.HighDate,
+		SpecLinks:  feature.SpecLinks,
+	}
+	for _, status := range feature.ImplementationStatuses {
+		if status.BrowserName == sort.BrowserTarget() {
+			row.SortAvailableDate = status.ImplementationDate
+		}
+	}
+
+	return valuePtr(encodeFeatureResultKeysetCursor(newFeatureResultKeysetCursor(sort, row)))
+}
+
 func defaultWPTMetricView() WPTMetricView {
 	// TODO. For now, default to the view mode. Switch to the subtest later.
 	return WPTTestView
@@ -715,6 +735,7 @@ func getFeatureSearchTestFeature(testFeatureID FeatureSearchTestFeatureID) Featu
 func testFeatureSearchAll(ctx context.Context, t *testing.T, client *Client) {
 	// Simple test to get all the features without filters.
 	expectedPage := FeatureResultPage{
+		Facets: nil,
 		Features: []FeatureResult{
 			getFeatureSearchTestFeature(FeatureSearchTestFId1),
 			getFeatureSearchTestFeature(FeatureSearchTestFId2),
@@ -731,6 +752,7 @@ func testFeatureSearchAll(ctx context.Context, t *testing.T, client *Client) {
 			pageSize:  100,
 			node:      nil,
 			sort:      defaultSorting(),
+			asOf:      nil,
 		},
 		&expectedPage,
 	)
@@ -749,8 +771,10 @@ func testFeatureSearchPagination(ctx context.Context, t *testing.T, client *Clie
 			pageSize:  2,
 			pageToken: nil, // First page does not need a page token.
 			expectedPage: &FeatureResultPage{
-				Total:         4,
-				NextPageToken: valuePtr(encodeFeatureResultOffsetCursor(2)),
+				Facets: nil,
+				Total:  4,
+				NextPageToken: keysetPageToken(defaultSorting(),
+					getFeatureSearchTestFeature(FeatureSearchTestFId2)),
 				Features: []FeatureResult{
 					getFeatureSearchTestFeature(FeatureSearchTestFId1),
 					getFeatureSearchTestFeature(FeatureSearchTestFId2),
@@ -761,16 +785,46 @@ func testFeatureSearchPagination(ctx context.Context, t *testing.T, client *Clie
 			name:     "page two",
 			pageSize: 2,
 			// The token should be made from the token of the previous page's last item
+			pageToken: keysetPageToken(defaultSorting(), getFeatureSearchTestFeature(FeatureSearchTestFId2)),
+			expectedPage: &FeatureResultPage{
+				Facets: nil,
+				Total:  4,
+				NextPageToken: keysetPageToken(defaultSorting(),
+					getFeatureSearchTestFeature(FeatureSearchTestFId4)),
+				Features: []FeatureResult{
+					getFeatureSearchTestFeature(FeatureSearchTestFId3),
+					getFeatureSearchTestFeature(FeatureSearchTestFId4),
+				},
+			},
+		},
+		{
+			name:     "page two with legacy offset token",
+			pageSize: 2,
+			// Offset tokens are still accepted. The next page token is a keyset token.
 			pageToken: valuePtr(encodeFeatureResultOffsetCursor(2)),
 			expectedPage: &FeatureResultPage{
-				Total:         4,
-				NextPageToken: valuePtr(encodeFeatureResultOffsetCursor(4)),
+				Facets: nil,
+				Total:  4,
+				NextPageToken: keysetPageToken(defaultSorting(),
+					getFeatureSearchTestFeature(FeatureSearchTestFId4)),
 				Features: []FeatureResult{
 					getFeatureSearchTestFeature(FeatureSearchTestFId3),
 					getFeatureSearchTestFeature(FeatureSearchTestFId4),
 				},
 			},
 		},
+		{
+			name:     "last page",
+			pageSize: 2,
+			// There are no features after the last feature.
+			pageToken: keysetPageToken(defaultSorting(), getFeatureSearchTestFeature(FeatureSearchTestFId4)),
+			expectedPage: &FeatureResultPage{
+				Facets:        nil,
+				Total:         4,
+				NextPageToken: nil,
+				Features:      nil,
+			},
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -780,6 +834,7 @@ func testFeatureSearchPagination(ctx context.Context, t *testing.T, client *Clie
 					pageSize:  tc.pageSize,
 					node:      nil,
 					sort:      defaultSorting(),
+					asOf:      nil,
 				},
 				tc.expectedPage,
 			)
@@ -837,6 +892,7 @@ func testFeatureCommonFilterCombos(ctx context.Context, t *testing.T, client *Cl
 				},
 			},
 			expectedPage: &FeatureResultPage{
+				Facets:        nil,
 				Total:         1,
 				NextPageToken: nil,
 				Features: []FeatureResult{
@@ -853,6 +909,7 @@ func testFeatureCommonFilterCombos(ctx context.Context, t *testing.T, client *Cl
 					pageSize:  100,
 					node:      tc.searchNode,
 					sort:      defaultSorting(),
+					asOf:      nil,
 				},
 				tc.expectedPage,
 			)
@@ -885,6 +942,7 @@ func testFeatureNotAvailableSearchFilters(ctx context.Context, t *testing.T, cli
 				},
 			},
 			expectedPage: &FeatureResultPage{
+				Facets:        nil,
 				Total:         2,
 				NextPageToken: nil,
 				Features: []FeatureResult{
@@ -902,6 +960,7 @@ func testFeatureNotAvailableSearchFilters(ctx context.Context, t *testing.T, cli
 					pageSize:  100,
 					node:      tc.searchNode,
 					sort:      defaultSorting(),
+					asOf:      nil,
 				},
 				tc.expectedPage,
 			)
@@ -934,6 +993,7 @@ func testFeatureAvailableSearchFilters(ctx context.Context, t *testing.T, client
 				},
 			},
 			expectedPage: &FeatureResultPage{
+				Facets:        nil,
 				Total:         2,
 				NextPageToken: nil,
 				Features: []FeatureResult{
@@ -976,6 +1036,7 @@ func testFeatureAvailableSearchFilters(ctx context.Context, t *testing.T, client
 				},
 			},
 			expectedPage: &FeatureResultPage{
+				Facets:        nil,
 				Total:         3,
 				NextPageToken: nil,
 				Features: []FeatureResult{
@@ -994,6 +1055,7 @@ func testFeatureAvailableSearchFilters(ctx context.Context, t *testing.T, client
 					pageSize:  100,
 					node:      tc.searchNode,
 					sort:      defaultSorting(),
+					asOf:      nil,
 				},
 				tc.expectedPage,
 			)
@@ -1026,6 +1088,7 @@ func testFeatureNameFilters(ctx context.Context, t *testing.T, client *Client) {
 	}
 
 	expectedPage := FeatureResultPage{
+		Facets:        nil,
 		Total:         4,
 		NextPageToken: nil,
 		Features:      expectedResults,
@@ -1037,6 +1100,7 @@ func testFeatureNameFilters(ctx context.Context, t *testing.T, client *Client) {
 			pageSize:  100,
 			node:      node,
 			sort:      defaultSorting(),
+			asOf:      nil,
 		},
 		&expectedPage,
 	)
@@ -1064,6 +1128,7 @@ func testFeatureNameFilters(ctx context.Context, t *testing.T, client *Client) {
 			pageSize:  100,
 			node:      node,
 			sort:      defaultSorting(),
+			asOf:      nil,
 		},
 		&expectedPage,
 	)
@@ -1073,6 +1138,7 @@ func testFeatureNameFilters(ctx context.Context, t *testing.T, client *Client) {
 		getFeatureSearchTestFeature(FeatureSearchTestFId4),
 	}
 	expectedPage = FeatureResultPage{
+		Facets:        nil,
 		Total:         1,
 		NextPageToken: nil,
 		Features:      expectedResults,
@@ -1099,6 +1165,7 @@ func testFeatureNameFilters(ctx context.Context, t *testing.T, client *Client) {
 			pageSize:  100,
 			node:      node,
 			sort:      defaultSorting(),
+			asOf:      nil,
 		},
 		&expectedPage,
 	)
@@ -1111,6 +1178,7 @@ func testFeatureBaselineStatusDateFilters(ctx context.Context, t *testing.T, cli
 		getFeatureSearchTestFeature(FeatureSearchTestFId2),
 	}
 	expectedPage := FeatureResultPage{
+		Facets:        nil,
 		Total:         2,
 		NextPageToken: nil,
 		Features:      expectedResults,
@@ -1152,6 +1220,7 @@ func testFeatureBaselineStatusDateFilters(ctx context.Context, t *testing.T, cli
 			pageSize:  100,
 			node:      node,
 			sort:      defaultSorting(),
+			asOf:      nil,
 		},
 		&expectedPage,
 	)
@@ -1161,6 +1230,7 @@ func testFeatureBaselineStatusDateFilters(ctx context.Context, t *testing.T, cli
 		getFeatureSearchTestFeature(FeatureSearchTestFId2),
 	}
 	expectedPage = FeatureResultPage{
+		Facets:        nil,
 		Total:         1,
 		NextPageToken: nil,
 		Features:      expectedResults,
@@ -1202,6 +1272,7 @@ func testFeatureBaselineStatusDateFilters(ctx context.Context, t *testing.T, cli
 			pageSize:  100,
 			node:      node,
 			sort:      defaultSorting(),
+			asOf:      nil,
 		},
 		&expectedPage,
 	)
@@ -1213,6 +1284,7 @@ func testFeatureBaselineStatusFilters(ctx context.Context, t *testing.T, client
 		getFeatureSearchTestFeature(FeatureSearchTestFId1),
 	}
 	expectedPage := FeatureResultPage{
+		Facets:        nil,
 		Total:         1,
 		NextPageToken: nil,
 		Features:      expectedResults,
@@ -1239,6 +1311,7 @@ func testFeatureBaselineStatusFilters(ctx context.Context, t *testing.T, client
 			pageSize:  100,
 			node:      node,
 			sort:      defaultSorting(),
+			asOf:      nil,
 		},
 		&expectedPage,
 	)
@@ -1248,6 +1321,7 @@ func testFeatureBaselineStatusFilters(ctx context.Context, t *testing.T, client
 		getFeatureSearchTestFeature(FeatureSearchTestFId2),
 	}
 	expectedPage = FeatureResultPage{
+		Facets:        nil,
 		Total:         1,
 		NextPageToken: nil,
 		Features:      expectedResults,
@@ -1274,6 +1348,7 @@ func testFeatureBaselineStatusFilters(ctx context.Context, t *testing.T, client
 			pageSize:  100,
 			node:      node,
 			sort:      defaultSorting(),
+			asOf:      nil,
 		},
 		&expectedPage,
 	)
@@ -1283,6 +1358,7 @@ func testFeatureBaselineStatusFilters(ctx context.Context, t *testing.T, client
 		getFeatureSearchTestFeature(FeatureSearchTestFId3),
 	}
 	expectedPage = FeatureResultPage{
+		Facets:        nil,
 		Total:         1,
 		NextPageToken: nil,
 		Features:      expectedResults,
@@ -1309,6 +1385,7 @@ func testFeatureBaselineStatusFilters(ctx context.Context, t *testing.T, client
 			pageSize:  100,
 			node:      node,
 			sort:      defaultSorting(),
+			asOf:      nil,
 		},
 		&expectedPage,
 	)
@@ -1327,8 +1404,10 @@ func testFeatureSearchSortAndPagination(ctx context.Context, t *testing.T, clien
 			sortable:  NewBaselineStatusSort(true),
 			pageToken: nil,
 			expectedPage: &FeatureResultPage{
-				Total:         4,
-				NextPageToken: valuePtr(encodeFeatureResultOffsetCursor(2)),
+				Facets: nil,
+				Total:  4,
+				NextPageToken: keysetPageToken(NewBaselineStatusSort(true),
+					getFeatureSearchTestFeature(FeatureSearchTestFId3)),
 				Features: []FeatureResult{
 					// nil status
 					getFeatureSearchTestFeature(FeatureSearchTestFId4),
@@ -1341,10 +1420,13 @@ func testFeatureSearchSortAndPagination(ctx context.Context, t *testing.T, clien
 			name:     "BaselineStatus asc - page 2",
 			sortable: NewBaselineStatusSort(true),
 			// Same page token as the next page token from the previous page.
-			pageToken: valuePtr(encodeFeatureResultOffsetCursor(2)),
+			pageToken: keysetPageToken(NewBaselineStatusSort(true),
+				getFeatureSearchTestFeature(FeatureSearchTestFId3)),
 			expectedPage: &FeatureResultPage{
-				Total:         4,
-				NextPageToken: valuePtr(encodeFeatureResultOffsetCursor(4)),
+				Facets: nil,
+				Total:  4,
+				NextPageToken: keysetPageToken(NewBaselineStatusSort(true),
+					getFeatureSearchTestFeature(FeatureSearchTestFId1)),
 				Features: []FeatureResult{
 					// high status low date 2000-01-04 high date 2000-01-31
 					getFeatureSearchTestFeature(FeatureSearchTestFId2),
@@ -1358,8 +1440,10 @@ func testFeatureSearchSortAndPagination(ctx context.Context, t *testing.T, clien
 			sortable:  NewBaselineStatusSort(false),
 			pageToken: nil,
 			expectedPage: &FeatureResultPage{
-				Total:         4,
-				NextPageToken: valuePtr(encodeFeatureResultOffsetCursor(2)),
+				Facets: nil,
+				Total:  4,
+				NextPageToken: keysetPageToken(NewBaselineStatusSort(false),
+					getFeatureSearchTestFeature(FeatureSearchTestFId2)),
 				Features: []FeatureResult{
 					// low status low date 2000-01-05
 					getFeatureSearchTestFeature(FeatureSearchTestFId1),
@@ -1372,10 +1456,13 @@ func testFeatureSearchSortAndPagination(ctx context.Context, t *testing.T, clien
 			name:     "BaselineStatus desc - page 2",
 			sortable: NewBaselineStatusSort(false),
 			// Same page token as the next page token from the previous page.
-			pageToken: valuePtr(encodeFeatureResultOffsetCursor(2)),
+			pageToken: keysetPageToken(NewBaselineStatusSort(false),
+				getFeatureSearchTestFeature(FeatureSearchTestFId2)),
 			expectedPage: &FeatureResultPage{
-				Total:         4,
-				NextPageToken: valuePtr(encodeFeatureResultOffsetCursor(4)),
+				Facets: nil,
+				Total:  4,
+				NextPageToken: keysetPageToken(NewBaselineStatusSort(false),
+					getFeatureSearchTestFeature(FeatureSearchTestFId4)),
 				Features: []FeatureResult{
 					// none status
 					getFeatureSearchTestFeature(FeatureSearchTestFId3),
@@ -1384,6 +1471,254 @@ func testFeatureSearchSortAndPagination(ctx context.Context, t *testing.T, clien
 				},
 			},
 		},
+		{
+			name:      "BaselineLowDate asc - page 1",
+			sortable:  NewBaselineLowDateSort(true),
+			pageToken: nil,
+			expectedPage: &FeatureResultPage{
+				Facets: nil,
+				Total:  4,
+				NextPageToken: keysetPageToken(NewBaselineLowDateSort(true),
+					getFeatureSearchTestFeature(FeatureSearchTestFId4)),
+				Features: []FeatureResult{
+					getFeatureSearchTestFeature(FeatureSearchTestFId3),
+					getFeatureSearchTestFeature(FeatureSearchTestFId4),
+				},
+			},
+		},
+		{
+			name:     "BaselineLowDate asc - page 2",
+			sortable: NewBaselineLowDateSort(true),
+			pageToken: keysetPageToken(NewBaselineLowDateSort(true),
+				getFeatureSearchTestFeature(FeatureSearchTestFId4)),
+			expectedPage: &FeatureResultPage{
+				Facets: nil,
+				Total:  4,
+				NextPageToken: keysetPageToken(NewBaselineLowDateSort(true),
+					getFeatureSearchTestFeature(FeatureSearchTestFId1)),
+				Features: []FeatureResult{
+					getFeatureSearchTestFeature(FeatureSearchTestFId2),
+					getFeatureSearchTestFeature(FeatureSearchTestFId1),
+				},
+			},
+		},
+		{
+			name:      "BaselineLowDate desc - page 1",
+			sortable:  NewBaselineLowDateSort(false),
+			pageToken: nil,
+			expectedPage: &FeatureResultPage{
+				Facets: nil,
+				Total:  4,
+				NextPageToken: keysetPageToken(NewBaselineLowDateSort(false),
+					getFeatureSearchTestFeature(FeatureSearchTestFId2)),
+				Features: []FeatureResult{
+					getFeatureSearchTestFeature(FeatureSearchTestFId1),
+					getFeatureSearchTestFeature(FeatureSearchTestFId2),
+				},
+			},
+		},
+		{
+			name:     "BaselineLowDate desc - page 2",
+			sortable: NewBaselineLowDateSort(false),
+			pageToken: keysetPageToken(NewBaselineLowDateSort(false),
+				getFeatureSearchTestFeature(FeatureSearchTestFId2)),
+			expectedPage: &FeatureResultPage{
+				Facets: nil,
+				Total:  4,
+				NextPageToken: keysetPageToken(NewBaselineLowDateSort(false),
+					getFeatureSearchTestFeature(FeatureSearchTestFId4)),
+				Features: []FeatureResult{
+					getFeatureSearchTestFeature(FeatureSearchTestFId3),
+					getFeatureSearchTestFeature(FeatureSearchTestFId4),
+				},
+			},
+		},
+		{
+			name:      "BaselineHighDate asc - page 1",
+			sortable:  NewBaselineHighDateSort(true),
+			pageToken: nil,
+			expectedPage: &FeatureResultPage{
+				Facets: nil,
+				Total:  4,
+				NextPageToken: keysetPageToken(NewBaselineHighDateSort(true),
+					getFeatureSearchTestFeature(FeatureSearchTestFId3)),
+				Features: []FeatureResult{
+					getFeatureSearchTestFeature(FeatureSearchTestFId1),
+					getFeatureSearchTestFeature(FeatureSearchTestFId3),
+				},
+			},
+		},
+		{
+			name:     "BaselineHighDate asc - page 2",
+			sortable: NewBaselineHighDateSort(true),
+			pageToken: keysetPageToken(NewBaselineHighDateSort(true),
+				getFeatureSearchTestFeature(FeatureSearchTestFId3)),
+			expectedPage: &FeatureResultPage{
+				Facets: nil,
+				Total:  4,
+				NextPageToken: keysetPageToken(NewBaselineHighDateSort(true),
+					getFeatureSearchTestFeature(FeatureSearchTestFId2)),
+				Features: []FeatureResult{
+					getFeatureSearchTestFeature(FeatureSearchTestFId4),
+					getFeatureSearchTestFeature(FeatureSearchTestFId2),
+				},
+			},
+		},
+		{
+			name:      "BaselineHighDate desc - page 1",
+			sortable:  NewBaselineHighDateSort(false),
+			pageToken: nil,
+			expectedPage: &FeatureResultPage{
+				Facets: nil,
+				Total:  4,
+				NextPageToken: keysetPageToken(NewBaselineHighDateSort(false),
+					getFeatureSearchTestFeature(FeatureSearchTestFId1)),
+				Features: []FeatureResult{
+					getFeatureSearchTestFeature(FeatureSearchTestFId2),
+					getFeatureSearchTestFeature(FeatureSearchTestFId1),
+				},
+			},
+		},
+		{
+			name:     "BaselineHighDate desc - page 2",
+			sortable: NewBaselineHighDateSort(false),
+			pageToken: keysetPageToken(NewBaselineHighDateSort(false),
+				getFeatureSearchTestFeature(FeatureSearchTestFId1)),
+			expectedPage: &FeatureResultPage{
+				Facets: nil,
+				Total:  4,
+				NextPageToken: keysetPageToken(NewBaselineHighDateSort(false),
+					getFeatureSearchTestFeature(FeatureSearchTestFId4)),
+				Features: []FeatureResult{
+					getFeatureSearchTestFeature(FeatureSearchTestFId3),
+					getFeatureSearchTestFeature(FeatureSearchTestFId4),
+				},
+			},
+		},
+		{
+			name:      "BrowserAvailableDate fooBrowser asc - page 1",
+			sortable:  NewBrowserAvailableDateSort(true, "fooBrowser"),
+			pageToken: nil,
+			expectedPage: &FeatureResultPage{
+				Facets: nil,
+				Total:  4,
+				NextPageToken: keysetPageToken(NewBrowserAvailableDateSort(true, "fooBrowser"),
+					getFeatureSearchTestFeature(FeatureSearchTestFId4)),
+				Features: []FeatureResult{
+					getFeatureSearchTestFeature(FeatureSearchTestFId2),
+					getFeatureSearchTestFeature(FeatureSearchTestFId4),
+				},
+			},
+		},
+		{
+			name:     "BrowserAvailableDate fooBrowser asc - page 2",
+			sortable: NewBrowserAvailableDateSort(true, "fooBrowser"),
+			pageToken: keysetPageToken(NewBrowserAvailableDateSort(true, "fooBrowser"),
+				getFeatureSearchTestFeature(FeatureSearchTestFId4)),
+			expectedPage: &FeatureResultPage{
+				Facets: nil,
+				Total:  4,
+				NextPageToken: keysetPageToken(NewBrowserAvailableDateSort(true, "fooBrowser"),
+					getFeatureSearchTestFeature(FeatureSearchTestFId3)),
+				Features: []FeatureResult{
+					getFeatureSearchTestFeature(FeatureSearchTestFId1),
+					getFeatureSearchTestFeature(FeatureSearchTestFId3),
+				},
+			},
+		},
+		{
+			name:      "BrowserAvailableDate fooBrowser desc - page 1",
+			sortable:  NewBrowserAvailableDateSort(false, "fooBrowser"),
+			pageToken: nil,
+			expectedPage: &FeatureResultPage{
+				Facets: nil,
+				Total:  4,
+				NextPageToken: keysetPageToken(NewBrowserAvailableDateSort(false, "fooBrowser"),
+					getFeatureSearchTestFeature(FeatureSearchTestFId1)),
+				Features: []FeatureResult{
+					getFeatureSearchTestFeature(FeatureSearchTestFId3),
+					getFeatureSearchTestFeature(FeatureSearchTestFId1),
+				},
+			},
+		},
+		{
+			name:     "BrowserAvailableDate fooBrowser desc - page 2",
+			sortable: NewBrowserAvailableDateSort(false, "fooBrowser"),
+			pageToken: keysetPageToken(NewBrowserAvailableDateSort(false, "fooBrowser"),
+				getFeatureSearchTestFeature(FeatureSearchTestFId1)),
+			expectedPage: &FeatureResultPage{
+				Facets: nil,
+				Total:  4,
+				NextPageToken: keysetPageToken(NewBrowserAvailableDateSort(false, "fooBrowser"),
+					getFeatureSearchTestFeature(FeatureSearchTestFId4)),
+				Features: []FeatureResult{
+					getFeatureSearchTestFeature(FeatureSearchTestFId2),
+					getFeatureSearchTestFeature(FeatureSearchTestFId4),
+				},
+			},
+		},
+		{
+			name:      "Spec asc - page 1",
+			sortable:  NewSpecSort(true),
+			pageToken: nil,
+			expectedPage: &FeatureResultPage{
+				Facets: nil,
+				Total:  4,
+				NextPageToken: keysetPageToken(NewSpecSort(true),
+					getFeatureSearchTestFeature(FeatureSearchTestFId4)),
+				Features: []FeatureResult{
+					getFeatureSearchTestFeature(FeatureSearchTestFId2),
+					getFeatureSearchTestFeature(FeatureSearchTestFId4),
+				},
+			},
+		},
+		{
+			name:     "Spec asc - page 2",
+			sortable: NewSpecSort(true),
+			pageToken: keysetPageToken(NewSpecSort(true),
+				getFeatureSearchTestFeature(FeatureSearchTestFId4)),
+			expectedPage: &FeatureResultPage{
+				Facets: nil,
+				Total:  4,
+				NextPageToken: keysetPageToken(NewSpecSort(true),
+					getFeatureSearchTestFeature(FeatureSearchTestFId3)),
+				Features: []FeatureResult{
+					getFeatureSearchTestFeature(FeatureSearchTestFId1),
+					getFeatureSearchTestFeature(FeatureSearchTestFId3),
+				},
+			},
+		},
+		{
+			name:      "Spec desc - page 1",
+			sortable:  NewSpecSort(false),
+			pageToken: nil,
+			expectedPage: &FeatureResultPage{
+				Facets: nil,
+				Total:  4,
+				NextPageToken: keysetPageToken(NewSpecSort(false),
+					getFeatureSearchTestFeature(FeatureSearchTestFId1)),
+				Features: []FeatureResult{
+					getFeatureSearchTestFeature(FeatureSearchTestFId3),
+					getFeatureSearchTestFeature(FeatureSearchTestFId1),
+				},
+			},
+		},
+		{
+			name:     "Spec desc - page 2",
+			sortable: NewSpecSort(false),
+			pageToken: keysetPageToken(NewSpecSort(false),
+				getFeatureSearchTestFeature(FeatureSearchTestFId1)),
+			expectedPage: &FeatureResultPage{
+				Facets: nil,
+				Total:  4,
+				NextPageToken: keysetPageToken(NewSpecSort(false),
+					getFeatureSearchTestFeature(FeatureSearchTestFId4)),
+				Features: []FeatureResult{
+					getFeatureSearchTestFeature(FeatureSearchTestFId2),
+					getFeatureSearchTestFeature(FeatureSearchTestFId4),
+				},
+			},
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -1393,6 +1728,7 @@ func testFeatureSearchSortAndPagination(ctx context.Context, t *testing.T, clien
 					pageSize:  2,
 					node:      nil,
 					sort:      tc.sortable,
+					asOf:      nil,
 				},
 				tc.expectedPage,
 			)
@@ -1404,6 +1740,144 @@ func testFeatureSearchComplexQueries(ctx context.Context, t *testing.T, client *
 	testFeatureSearchSortAndPagination(ctx, t, client)
 }
 
+func testFeatureSearchFacets(ctx context.Context, t *testing.T, client *Client) {
+	availableOnFooNode := &searchtypes.SearchNode{
+		Keyword: searchtypes.KeywordRoot,
+		Term:    nil,
+		Children: []*searchtypes.SearchNode{
+			{
+				Keyword: searchtypes.KeywordNone,
+				Term: &searchtypes.SearchTerm{
+					Identifier: searchtypes.IdentifierAvailableOn,
+					Value:      "fooBrowser",
+					Operator:   searchtypes.OperatorEq,
+				},
+				Children: nil,
+			},
+		},
+	}
+	testCases := []struct {
+		name           string
+		node           *searchtypes.SearchNode
+		facets         []FeatureSearchFacet
+		expectedFacets *FeatureSearchFacets
+	}{
+		{
+			name:           "no facets requested",
+			node:           nil,
+			facets:         nil,
+			expectedFacets: nil,
+		},
+		{
+			name:   "all facets without a query",
+			node:   nil,
+			facets: []FeatureSearchFacet{BaselineStatusFacet, AvailableOnFacet},
+			expectedFacets: &FeatureSearchFacets{
+				BaselineStatus: []FacetValueCount{
+					{Value: string(BaselineStatusHigh), Count: 1},
+					{Value: string(BaselineStatusLow), Count: 1},
+					{Value: string(BaselineStatusNone), Count: 1},
+				},
+				AvailableOn: []FacetValueCount{
+					{Value: "barBrowser", Count: 2},
+					{Value: "fooBrowser", Count: 2},
+				},
+			},
+		},
+		{
+			name:   "baseline status facet with a query",
+			node:   availableOnFooNode,
+			facets: []FeatureSearchFacet{BaselineStatusFacet},
+			expectedFacets: &FeatureSearchFacets{
+				BaselineStatus: []FacetValueCount{
+					{Value: string(BaselineStatusLow), Count: 1},
+					{Value: string(BaselineStatusNone), Count: 1},
+				},
+				AvailableOn: nil,
+			},
+		},
+		{
+			name:   "available on facet with a query",
+			node:   availableOnFooNode,
+			facets: []FeatureSearchFacet{AvailableOnFacet},
+			expectedFacets: &FeatureSearchFacets{
+				BaselineStatus: nil,
+				AvailableOn: []FacetValueCount{
+					{Value: "barBrowser", Count: 1},
+					{Value: "fooBrowser", Count: 2},
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			page, err := client.FeaturesSearch(
+				ctx,
+				nil,
+				100,
+				tc.node,
+				defaultSorting(),
+				defaultWPTMetricView(),
+				getDefaultTestBrowserList(),
+				nil,
+				tc.facets,
+			)
+			if err != nil {
+				t.Fatalf("unexpected error during search of features %s", err.Error())
+			}
+			if !reflect.DeepEqual(tc.expectedFacets, page.Facets) {
+				t.Errorf("unequal facets.\nexpected (%+v)\nreceived (%+v) ", tc.expectedFacets, page.Facets)
+			}
+		})
+	}
+}
+
+func testFeatureSearchAsOf(ctx context.Context, t *testing.T, client *Client) {
+	// On 2000-01-20, only fooBrowser 0.0.0 had been released with a feature (feature1).
+	// feature3 became available in fooBrowser 1.0.0 which was released on 2000-02-01.
+	asOf := time.Date(2000, time.January, 20, 0, 0, 0, 0, time.UTC)
+	feature1 := getFeatureSearchTestFeature(FeatureSearchTestFId1)
+	feature1.ImplementationStatuses = []*ImplementationStatus{
+		{
+			BrowserName:          "fooBrowser",
+			ImplementationStatus: Available,
+			ImplementationDate:   valuePtr(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)),
+		},
+	}
+	expectedPage := FeatureResultPage{
+		Facets:        nil,
+		Total:         1,
+		NextPageToken: nil,
+		Features:      []FeatureResult{feature1},
+	}
+	node := &searchtypes.SearchNode{
+		Keyword: searchtypes.KeywordRoot,
+		Term:    nil,
+		Children: []*searchtypes.SearchNode{
+			{
+				Keyword: searchtypes.KeywordNone,
+				Term: &searchtypes.SearchTerm{
+					Identifier: searchtypes.IdentifierAvailableOn,
+					Value:      "fooBrowser",
+					Operator:   searchtypes.OperatorEq,
+				},
+				Children: nil,
+			},
+		},
+	}
+
+	assertFeatureSearch(ctx, t, client,
+		featureSearchArgs{
+			pageToken: nil,
+			pageSize:  100,
+			node:      node,
+			sort:      defaultSorting(),
+			asOf:      &asOf,
+		},
+		&expectedPage,
+	)
+}
+
 func testFeatureSearchSort(ctx context.Context, t *testing.T, client *Client) {
 	testFeatureSearchSortName(ctx, t, client)
 	testFeatureSearchSortBaselineStatus(ctx, t, client)
@@ -1422,6 +1896,7 @@ func testFeatureSearchSortName(ctx context.Context, t *testing.T, client *Client
 			name:     "Name asc",
 			sortable: NewFeatureNameSort(true),
 			expectedPage: &FeatureResultPage{
+				Facets:        nil,
 				Total:         4,
 				NextPageToken: nil,
 				Features: []FeatureResult{
@@ -1436,6 +1911,7 @@ func testFeatureSearchSortName(ctx context.Context, t *testing.T, client *Client
 			name:     "Name desc",
 			sortable: NewFeatureNameSort(false),
 			expectedPage: &FeatureResultPage{
+				Facets:        nil,
 				Total:         4,
 				NextPageToken: nil,
 				Features: []FeatureResult{
@@ -1455,6 +1931,7 @@ func testFeatureSearchSortName(ctx context.Context, t *testing.T, client *Client
 					pageSize:  100,
 					node:      nil,
 					sort:      tc.sortable,
+					asOf:      nil,
 				},
 				tc.expectedPage,
 			)
@@ -1474,6 +1951,7 @@ func testFeatureSearchSortBaselineStatus(ctx context.Context, t *testing.T, clie
 			name:     "BaselineStatus asc",
 			sortable: NewBaselineStatusSort(true),
 			expectedPage: &FeatureResultPage{
+				Facets:        nil,
 				Total:         4,
 				NextPageToken: nil,
 				Features: []FeatureResult{
@@ -1492,6 +1970,7 @@ func testFeatureSearchSortBaselineStatus(ctx context.Context, t *testing.T, clie
 			name:     "BaselineStatus desc",
 			sortable: NewBaselineStatusSort(false),
 			expectedPage: &FeatureResultPage{
+				Facets:        nil,
 				Total:         4,
 				NextPageToken: nil,
 				Features: []FeatureResult{
@@ -1515,6 +1994,7 @@ func testFeatureSearchSortBaselineStatus(ctx context.Context, t *testing.T, clie
 					pageSize:  100,
 					node:      nil,
 					sort:      tc.sortable,
+					asOf:      nil,
 				},
 				tc.expectedPage,
 			)
@@ -1533,6 +2013,7 @@ func testFeatureSearchSortBrowserImpl(ctx context.Context, t *testing.T, client
 			name:     "BrowserImpl fooBrowser Stable asc",
 			sortable: NewBrowserImplSort(true, "fooBrowser", true),
 			expectedPage: &FeatureResultPage{
+				Facets:        nil,
 				Total:         4,
 				NextPageToken: nil,
 				Features: []FeatureResult{
@@ -1551,6 +2032,7 @@ func testFeatureSearchSortBrowserImpl(ctx context.Context, t *testing.T, client
 			name:     "BrowserImpl fooBrowser Stable desc",
 			sortable: NewBrowserImplSort(false, "fooBrowser", true),
 			expectedPage: &FeatureResultPage{
+				Facets:        nil,
 				Total:         4,
 				NextPageToken: nil,
 				Features: []FeatureResult{
@@ -1569,6 +2051,7 @@ func testFeatureSearchSortBrowserImpl(ctx context.Context, t *testing.T, client
 			name:     "BrowserImpl fooBrowser Experimental asc",
 			sortable: NewBrowserImplSort(true, "fooBrowser", false),
 			expectedPage: &FeatureResultPage{
+				Facets:        nil,
 				Total:         4,
 				NextPageToken: nil,
 				Features: []FeatureResult{
@@ -1587,6 +2070,7 @@ func testFeatureSearchSortBrowserImpl(ctx context.Context, t *testing.T, client
 			name:     "BrowserImpl fooBrowser Experimental desc",
 			sortable: NewBrowserImplSort(false, "fooBrowser", false),
 			expectedPage: &FeatureResultPage{
+				Facets:        nil,
 				Total:         4,
 				NextPageToken: nil,
 				Features: []FeatureResult{
@@ -1610,6 +2094,7 @@ func testFeatureSearchSortBrowserImpl(ctx context.Context, t *testing.T, client
 					pageSize:  100,
 					node:      nil,
 					sort:      tc.sortable,
+					asOf:      nil,
 				},
 				tc.expectedPage,
 			)
@@ -1629,6 +2114,8 @@ func TestFeaturesSearch(t *testing.T) {
 		testFeatureSearchFilters(ctx, t, client)
 		testFeatureSearchSort(ctx, t, client)
 		testFeatureSearchComplexQueries(ctx, t, client)
+		testFeatureSearchAsOf(ctx, t, client)
+		testFeatureSearchFacets(ctx, t, client)
 	})
 
 	// Try with LocalFeatureBaseQuery
@@ -1639,6 +2126,8 @@ func TestFeaturesSearch(t *testing.T) {
 		testFeatureSearchFilters(ctx, t, client)
 		testFeatureSearchSort(ctx, t, client)
 		testFeatureSearchComplexQueries(ctx, t, client)
+		testFeatureSearchAsOf(ctx, t, client)
+		testFeatureSearchFacets(ctx, t, client)
 	})
 }
 
@@ -1647,6 +2136,7 @@ type featureSearchArgs struct {
 	pageSize  int
 	node      *searchtypes.SearchNode
 	sort      Sortable
+	asOf      *time.Time
 }
 
 func assertFeatureSearch(
@@ -1665,6 +2155,8 @@ func assertFeatureSearch(
 		// to pass this.
 		defaultWPTMetricView(),
 		getDefaultTestBrowserList(),
+		args.asOf,
+		nil,
 	)
 	if err != nil {
 		t.Errorf("unexpected error during search of features %s", err.Error())
@@ -1681,7 +2173,8 @@ func AreFeatureResultPagesEqual(a, b *FeatureResultPage) bool {
 	return a.Total == b.Total &&
 		((a.NextPageToken == nil && b.NextPageToken == nil) ||
 			((a.NextPageToken != nil && b.NextPageToken != nil) && *a.NextPageToken == *b.NextPageToken)) &&
-		AreFeatureResultsSlicesEqual(a.Features, b.Features)
+		AreFeatureResultsSlicesEqual(a.Features, b.Features) &&
+		reflect.DeepEqual(a.Facets, b.Facets)
 }
 
 func AreFeatureResultsSlicesEqual(a, b []FeatureResult) bool {
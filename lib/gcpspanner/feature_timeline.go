@@ -0,0 +1,192 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcpspanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/api/iterator"
+)
+
+// FeatureTimelineBrowserAvailability describes the first release of a browser
+// where a feature became available.
+type FeatureTimelineBrowserAvailability struct {
+	BrowserName    string    `spanner:"BrowserName"`
+	BrowserVersion string    `spanner:"BrowserVersion"`
+	ReleaseDate    time.Time `spanner:"ReleaseDate"`
+}
+
+// FeatureTimelineWPTMilestone describes the first run for a given browser and
+// channel where the pass rate of a feature reached the threshold.
+type FeatureTimelineWPTMilestone struct {
+	BrowserName string    `spanner:"BrowserName"`
+	Channel     string    `spanner:"Channel"`
+	Threshold   *big.Rat  `spanner:"Threshold"`
+	TimeStart   time.Time `spanner:"TimeStart"`
+}
+
+// FeatureTimeline contains the events that make up the implementation history
+// of a feature. Each list is returned in chronological order.
+type FeatureTimeline struct {
+	BrowserAvailabilities []FeatureTimelineBrowserAvailability
+	Baseline              *FeatureBaselineStatus
+	WPTMilestones         []FeatureTimelineWPTMilestone
+}
+
+const (
+	featureTimelineAvailabilitiesQuery = `
+	SELECT
+		bfa.BrowserName,
+		bfa.BrowserVersion,
+		br.ReleaseDate
+	FROM BrowserFeatureAvailabilities bfa
+	JOIN BrowserReleases br
+		ON bfa.BrowserName = br.BrowserName AND bfa.BrowserVersion = br.BrowserVersion
+	WHERE bfa.WebFeatureID = @webFeatureID
+	ORDER BY br.ReleaseDate ASC, bfa.BrowserName ASC`
+
+	featureTimelineBaselineQuery = `
+	SELECT
+		WebFeatureID, Status, LowDate, HighDate
+	FROM FeatureBaselineStatus
+	WHERE WebFeatureID = @webFeatureID
+	LIMIT 1`
+
+	// featureTimelineWPTMilestonesRawQuery finds the earliest run per browser and
+	// channel where the pass rate met each threshold.
+	// The pass rate column is filled in based on the metric view.
+	featureTimelineWPTMilestonesRawQuery = `
+	SELECT
+		Threshold,
+		m.BrowserName,
+		m.Channel,
+		MIN(m.TimeStart) AS TimeStart
+	FROM WPTRunFeatureMetrics m
+	CROSS JOIN UNNEST([NUMERIC '0.5', NUMERIC '0.9', NUMERIC '1']) AS Threshold
+	WHERE m.WebFeatureID = @webFeatureID
+		AND m.%[1]s IS NOT NULL
+		AND m.%[1]s >= Threshold
+	GROUP BY Threshold, m.BrowserName, m.Channel
+	ORDER BY TimeStart ASC, m.BrowserName ASC, m.Channel ASC, Threshold ASC`
+)
+
+// GetFeatureTimeline returns the browser availabilities, baseline status and
+// WPT pass rate milestones for a given feature.
+func (c *Client) GetFeatureTimeline(
+	ctx context.Context,
+	featureKey string,
+	wptMetricView WPTMetricView,
+) (*FeatureTimeline, error) {
+	id, err := c.GetIDFromFeatureKey(ctx, NewFeatureKeyFilter(featureKey))
+	if err != nil {
+		return nil, err
+	}
+	if id == nil {
+		return nil, ErrInternalQueryFailure
+	}
+	params := map[string]interface{}{
+		"webFeatureID": *id,
+	}
+
+	txn := c.ReadOnlyTransaction()
+	defer txn.Close()
+
+	// 1. Get the first release for each browser where the feature is available.
+	stmt := spanner.NewStatement(featureTimelineAvailabilitiesQuery)
+	stmt.Params = params
+	availabilities, err := queryFeatureTimelineRows[FeatureTimelineBrowserAvailability](ctx, txn, stmt)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Get the baseline status.
+	baseline, err := c.getFeatureTimelineBaseline(ctx, txn, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Get the WPT milestones.
+	stmt = spanner.NewStatement(
+		fmt.Sprintf(featureTimelineWPTMilestonesRawQuery, metricsPassRateColumn(wptMetricView)))
+	stmt.Params = params
+	milestones, err := queryFeatureTimelineRows[FeatureTimelineWPTMilestone](ctx, txn, stmt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FeatureTimeline{
+		BrowserAvailabilities: availabilities,
+		Baseline:              baseline,
+		WPTMilestones:         milestones,
+	}, nil
+}
+
+func (c *Client) getFeatureTimelineBaseline(
+	ctx context.Context,
+	txn *spanner.ReadOnlyTransaction,
+	params map[string]interface{}) (*FeatureBaselineStatus, error) {
+	stmt := spanner.NewStatement(featureTimelineBaselineQuery)
+	stmt.Params = params
+	it := txn.Query(ctx, stmt)
+	defer it.Stop()
+
+	row, err := it.Next()
+	if err != nil {
+		// No baseline information for this feature.
+		if errors.Is(err, iterator.Done) {
+			return nil, nil
+		}
+
+		return nil, errors.Join(ErrInternalQueryFailure, err)
+	}
+	var status SpannerFeatureBaselineStatus
+	if err := row.ToStruct(&status); err != nil {
+		return nil, errors.Join(ErrInternalQueryFailure, err)
+	}
+	status.Status = (*BaselineStatus)(status.InternalStatus)
+
+	return &status.FeatureBaselineStatus, nil
+}
+
+func queryFeatureTimelineRows[T any](
+	ctx context.Context,
+	txn *spanner.ReadOnlyTransaction,
+	stmt spanner.Statement) ([]T, error) {
+	it := txn.Query(ctx, stmt)
+	defer it.Stop()
+
+	var ret []T
+	for {
+		row, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, errors.Join(ErrInternalQueryFailure, err)
+		}
+		var item T
+		if err := row.ToStruct(&item); err != nil {
+			return nil, errors.Join(ErrInternalQueryFailure, err)
+		}
+		ret = append(ret, item)
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcpspanner
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/web-platform-tests/wpt.fyi/shared"
+)
+
+func setupRequiredTablesForFeatureTimeline(ctx context.Context,
+	client *Client, t *testing.T) {
+	setupRequiredTablesForBrowserFeatureAvailability(ctx, client, t)
+	for _, availability := range getSampleBrowserAvailabilities() {
+		err := client.InsertBrowserFeatureAvailability(
+			ctx, availability.FeatureKey, availability.BrowserFeatureAvailability)
+		if err != nil {
+			t.Errorf("unexpected error during insert of availabilities. %s", err.Error())
+		}
+	}
+	for _, status := range getSampleBaselineStatuses() {
+		err := client.UpsertFeatureBaselineStatus(ctx, status.featureKey, status.status)
+		if err != nil {
+			t.Errorf("unexpected error during insert of statuses. %s", err.Error())
+		}
+	}
+	for _, run := range getSampleRuns() {
+		err := client.InsertWPTRun(ctx, run)
+		if err != nil {
+			t.Errorf("unexpected error during insert of runs. %s", err.Error())
+		}
+	}
+	for _, metric := range getSampleRunMetrics() {
+		err := client.UpsertWPTRunFeatureMetrics(ctx, metric.ExternalRunID, metric.Metrics)
+		if err != nil {
+			t.Errorf("unexpected error during insert of metrics. %s", err.Error())
+		}
+	}
+}
+
+func getExpectedFeatureTimelineMilestones(
+	browserName, channel string, timeStart time.Time) []FeatureTimelineWPTMilestone {
+	return []FeatureTimelineWPTMilestone{
+		{BrowserName: browserName, Channel: channel, Threshold: big.NewRat(1, 2), TimeStart: timeStart},
+		{BrowserName: browserName, Channel: channel, Threshold: big.NewRat(9, 10), TimeStart: timeStart},
+		{BrowserName: browserName, Channel: channel, Threshold: big.NewRat(1, 1), TimeStart: timeStart},
+	}
+}
+
+func milestoneEquality(left, right FeatureTimelineWPTMilestone) bool {
+	return left.BrowserName == right.BrowserName &&
+		left.Channel == right.Channel &&
+		left.Threshold.Cmp(right.Threshold) == 0 &&
+		left.TimeStart.Equal(right.TimeStart)
+}
+
+func TestGetFeatureTimeline(t *testing.T) {
+	client := getTestDatabase(t)
+	ctx := context.Background()
+	setupRequiredTablesForFeatureTimeline(ctx, client, t)
+
+	timeline, err := client.GetFeatureTimeline(ctx, "feature2", WPTTestView)
+	if err != nil {
+		t.Fatalf("unexpected error. %s", err.Error())
+	}
+
+	expectedAvailabilities := []FeatureTimelineBrowserAvailability{
+		{
+			BrowserName:    "fooBrowser",
+			BrowserVersion: "1.0.0",
+			ReleaseDate:    time.Date(2000, time.February, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			BrowserName:    "barBrowser",
+			BrowserVersion: "2.0.0",
+			ReleaseDate:    time.Date(2000, time.March, 2, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	if !slices.Equal(expectedAvailabilities, timeline.BrowserAvailabilities) {
+		t.Errorf("unequal availabilities.\nexpected %+v\nreceived %+v",
+			expectedAvailabilities, timeline.BrowserAvailabilities)
+	}
+
+	expectedBaseline := FeatureBaselineStatus{
+		Status:   valuePtr(BaselineStatusHigh),
+		LowDate:  valuePtr[time.Time](time.Date(2000, time.January, 15, 0, 0, 0, 0, time.UTC)),
+		HighDate: valuePtr[time.Time](time.Date(2000, time.January, 31, 0, 0, 0, 0, time.UTC)),
+	}
+	if timeline.Baseline == nil || !statusEquality(expectedBaseline, *timeline.Baseline) {
+		t.Errorf("unequal baseline.\nexpected %+v\nreceived %+v", expectedBaseline, timeline.Baseline)
+	}
+
+	// The stable fooBrowser runs never pass any tests for feature2 so they do not show up.
+	jan2 := time.Date(2000, time.January, 2, 0, 0, 0, 0, time.UTC)
+	var expectedMilestones []FeatureTimelineWPTMilestone
+	expectedMilestones = append(expectedMilestones,
+		getExpectedFeatureTimelineMilestones("barBrowser", shared.ExperimentalLabel, jan2)...)
+	expectedMilestones = append(expectedMilestones,
+		getExpectedFeatureTimelineMilestones("barBrowser", shared.StableLabel, jan2)...)
+	expectedMilestones = append(expectedMilestones,
+		getExpectedFeatureTimelineMilestones("fooBrowser", shared.ExperimentalLabel, jan2)...)
+	if !slices.EqualFunc(expectedMilestones, timeline.WPTMilestones, milestoneEquality) {
+		t.Errorf("unequal milestones.\nexpected %+v\nreceived %+v", expectedMilestones, timeline.WPTMilestones)
+	}
+
+	// Feature without any data.
+	timeline, err = client.GetFeatureTimeline(ctx, "feature4", WPTTestView)
+	if err != nil {
+		t.Errorf("unexpected error. %s", err.Error())
+	}
+	if timeline.BrowserAvailabilities != nil || timeline.Baseline != nil || timeline.WPTMilestones != nil {
+		t.Errorf("expected empty timeline. received %+v", timeline)
+	}
+
+	// Non existent feature.
+	_, err = client.GetFeatureTimeline(ctx, "nopefeature", WPTTestView)
+	if !errors.Is(err, ErrQueryReturnedNoResults) {
+		t.Errorf("expected ErrQueryReturnedNoResults. received %v", err)
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"slices"
+	"time"
 
 	"cloud.google.com/go/spanner"
 	"google.golang.org/api/iterator"
@@ -28,6 +29,7 @@ func (c *Client) GetFeature(
 	filter Filterable,
 	wptMetricView WPTMetricView,
 	browsers []string,
+	asOf *time.Time,
 ) (*FeatureResult, error) {
 	txn := c.ReadOnlyTransaction()
 	defer txn.Close()
@@ -36,6 +38,8 @@ func (c *Client) GetFeature(
 		baseQuery:     c.featureSearchQuery,
 		wptMetricView: wptMetricView,
 		browsers:      browsers,
+		asOf:          asOf,
+		pageSize:      1,
 	}
 	stmt := b.Build(filter)
 
@@ -56,7 +60,57 @@ func (c *Client) GetFeature(
 	if err := row.ToStruct(&result); err != nil {
 		return nil, errors.Join(ErrInternalQueryFailure, err)
 	}
+	actualResult := convertSpannerFeatureResult(result)
 
+	return &actualResult, nil
+}
+
+// GetFeatures returns the features of the given feature keys in one query.
+// Feature keys that do not exist are left out of the results.
+func (c *Client) GetFeatures(
+	ctx context.Context,
+	featureKeys []string,
+	wptMetricView WPTMetricView,
+	browsers []string,
+) ([]FeatureResult, error) {
+	if len(featureKeys) == 0 {
+		return nil, nil
+	}
+	txn := c.ReadOnlyTransaction()
+	defer txn.Close()
+
+	b := GetFeatureQueryBuilder{
+		baseQuery:     c.featureSearchQuery,
+		wptMetricView: wptMetricView,
+		browsers:      browsers,
+		asOf:          nil,
+		pageSize:      len(featureKeys),
+	}
+	stmt := b.Build(NewFeatureKeysFilter(featureKeys))
+
+	it := txn.Query(ctx, stmt)
+	defer it.Stop()
+
+	results := make([]FeatureResult, 0, len(featureKeys))
+	for {
+		row, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, errors.Join(ErrInternalQueryFailure, err)
+		}
+		var result SpannerFeatureResult
+		if err := row.ToStruct(&result); err != nil {
+			return nil, errors.Join(ErrInternalQueryFailure, err)
+		}
+		results = append(results, convertSpannerFeatureResult(result))
+	}
+
+	return results, nil
+}
+
+func convertSpannerFeatureResult(result SpannerFeatureResult) FeatureResult {
 	stableMetrics := convertSpannerMetrics(result.StableMetrics)
 	experimentalMetrics := convertSpannerMetrics(result.ExperimentalMetrics)
 
@@ -71,7 +125,7 @@ func (c *Client) GetFeature(
 		result.SpecLinks = nil
 	}
 
-	actualResult := FeatureResult{
+	return FeatureResult{
 		FeatureKey:             result.FeatureKey,
 		Name:                   result.Name,
 		Status:                 result.Status,
@@ -82,8 +136,6 @@ func (c *Client) GetFeature(
 		HighDate:               result.HighDate,
 		SpecLinks:              result.SpecLinks,
 	}
-
-	return &actualResult, nil
 }
 
 func (c *Client) GetIDFromFeatureKey(ctx context.Context, filter *FeatureIDFilter) (*string, error) {
@@ -121,3 +173,40 @@ func (c *Client) GetIDFromFeatureKey(ctx context.Context, filter *FeatureIDFilte
 
 	return &id, nil
 }
+
+// GetIDsFromFeatureKeys returns the IDs of the given feature keys in one query, keyed by feature key.
+// Feature keys that do not exist are left out of the map.
+func (c *Client) GetIDsFromFeatureKeys(ctx context.Context, featureKeys []string) (map[string]string, error) {
+	stmt := spanner.NewStatement(`
+	SELECT
+		FeatureKey, ID
+	FROM WebFeatures
+	WHERE FeatureKey IN UNNEST(@featureKeys)
+	`)
+	stmt.Params = map[string]interface{}{
+		"featureKeys": featureKeys,
+	}
+
+	txn := c.Single()
+	defer txn.Close()
+	it := txn.Query(ctx, stmt)
+	defer it.Stop()
+
+	ids := make(map[string]string, len(featureKeys))
+	for {
+		row, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, errors.Join(ErrInternalQueryFailure, err)
+		}
+		var featureKey, id string
+		if err := row.Columns(&featureKey, &id); err != nil {
+			return nil, errors.Join(ErrInternalQueryFailure, err)
+		}
+		ids[featureKey] = id
+	}
+
+	return ids, nil
+}
@@ -16,20 +16,38 @@ package gcpspanner
 
 import (
 	"maps"
+	"time"
 
 	"cloud.google.com/go/spanner"
 )
 
 func NewFeatureKeyFilter(featureKey string) *FeatureIDFilter {
-	return &FeatureIDFilter{featureKey: featureKey}
+	return &FeatureIDFilter{featureKey: featureKey, resolveAlias: false}
+}
+
+// NewFeatureKeyOrAliasFilter is like NewFeatureKeyFilter, but the key can also be an alias
+// of a renamed feature, which then resolves to the renamed feature.
+// Aliases take precedence because the feature that was renamed may still exist under its previous key.
+func NewFeatureKeyOrAliasFilter(featureKey string) *FeatureIDFilter {
+	return &FeatureIDFilter{featureKey: featureKey, resolveAlias: true}
 }
 
 // FeatureIDFilter will limit the search to a particular feature ID.
 type FeatureIDFilter struct {
-	featureKey string
+	featureKey   string
+	resolveAlias bool
 }
 
 func (f FeatureIDFilter) Clause() string {
+	if f.resolveAlias {
+		return `
+wf.ID = COALESCE(
+	(SELECT fa.WebFeatureID FROM FeatureAliases fa WHERE fa.Alias = @featureKey),
+	(SELECT wf2.ID FROM WebFeatures wf2 WHERE wf2.FeatureKey = @featureKey)
+)
+`
+	}
+
 	return `
 wf.FeatureKey = @featureKey
 `
@@ -41,11 +59,36 @@ func (f FeatureIDFilter) Params() map[string]interface{} {
 	}
 }
 
-// GetFeatureQueryBuilder builds a query to search for one feature.
+func NewFeatureKeysFilter(featureKeys []string) *FeatureKeysFilter {
+	return &FeatureKeysFilter{featureKeys: featureKeys}
+}
+
+// FeatureKeysFilter will limit the search to a set of feature keys.
+type FeatureKeysFilter struct {
+	featureKeys []string
+}
+
+func (f FeatureKeysFilter) Clause() string {
+	return `
+wf.FeatureKey IN UNNEST(@featureKeys)
+`
+}
+
+func (f FeatureKeysFilter) Params() map[string]interface{} {
+	return map[string]interface{}{
+		"featureKeys": f.featureKeys,
+	}
+}
+
+// GetFeatureQueryBuilder builds a query to search for one feature, or for the features of a FeatureKeysFilter.
 type GetFeatureQueryBuilder struct {
 	baseQuery     FeatureSearchBaseQuery
 	wptMetricView WPTMetricView
 	browsers      []string
+	// asOf is the optional point in time to evaluate the feature at.
+	asOf *time.Time
+	// pageSize is the maximum number of features returned.
+	pageSize int
 }
 
 func (q GetFeatureQueryBuilder) Build(
@@ -57,17 +100,21 @@ func (q GetFeatureQueryBuilder) Build(
 		Filters:                 nil,
 		PageFilters:             nil,
 		Offset:                  0,
-		PageSize:                1,
+		PageSize:                q.pageSize,
 		Browsers:                q.browsers,
 		SortClause:              "",
 		SortByStableBrowserImpl: nil,
 		SortByExpBrowserImpl:    nil,
+		AsOf:                    q.asOf != nil,
 	}
 	queryArgs.Filters = defaultFeatureSearchFilters()
 	if filter != nil {
 		queryArgs.Filters = append(queryArgs.Filters, filter.Clause())
 		maps.Copy(filterParams, filter.Params())
 	}
+	if q.asOf != nil {
+		filterParams[asOfDateParamName] = *q.asOf
+	}
 
 	sql, params := q.baseQuery.Query(queryArgs)
 	maps.Copy(filterParams, params)
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 )
 
 func TestGetFeature(t *testing.T) {
@@ -28,7 +29,7 @@ func TestGetFeature(t *testing.T) {
 
 	// Test for present feature
 	result, err := client.GetFeature(ctx, NewFeatureKeyFilter("feature2"), defaultWPTMetricView(),
-		getDefaultTestBrowserList())
+		getDefaultTestBrowserList(), nil)
 	if err != nil {
 		t.Errorf("unexpected error. %s", err.Error())
 	}
@@ -56,7 +57,7 @@ func TestGetFeature(t *testing.T) {
 
 	// Test for non existent feature
 	result, err = client.GetFeature(ctx, NewFeatureKeyFilter("nopefeature2"), defaultWPTMetricView(),
-		getDefaultTestBrowserList())
+		getDefaultTestBrowserList(), nil)
 	if !errors.Is(err, ErrQueryReturnedNoResults) {
 		t.Errorf("unexpected error. %s", err)
 	}
@@ -72,4 +73,80 @@ func TestGetFeature(t *testing.T) {
 	if id != nil {
 		t.Error("expected null id")
 	}
+
+	// Look up multiple ids at once. Feature keys that do not exist are left out.
+	ids, err := client.GetIDsFromFeatureKeys(ctx, []string{"feature1", "feature2", "nopefeature2"})
+	if err != nil {
+		t.Errorf("unexpected error. %s", err.Error())
+	}
+	if len(ids) != 2 {
+		t.Errorf("expected 2 ids. received %v", ids)
+	}
+	id, err = client.GetIDFromFeatureKey(ctx, NewFeatureKeyFilter("feature1"))
+	if err != nil {
+		t.Errorf("unexpected error. %s", err.Error())
+	} else if ids["feature1"] != *id {
+		t.Errorf("expected the same id as GetIDFromFeatureKey. received %v", ids)
+	}
+
+	// Look up multiple features at once. Feature keys that do not exist are left out.
+	results, err := client.GetFeatures(ctx, []string{"feature2", "nopefeature2"}, defaultWPTMetricView(),
+		getDefaultTestBrowserList())
+	if err != nil {
+		t.Errorf("unexpected error. %s", err.Error())
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 feature. received %d", len(results))
+	}
+	stabilizeFeatureResult(results[0])
+	if !AreFeatureResultsEqual(*expectedResult, results[0]) {
+		t.Errorf("unequal results. expected (%+v) received (%+v) ",
+			PrettyPrintFeatureResult(*expectedResult), PrettyPrintFeatureResult(results[0]))
+	}
+}
+
+func TestGetFeatureAsOf(t *testing.T) {
+	client := getTestDatabase(t)
+	ctx := context.Background()
+
+	setupRequiredTablesForFeaturesSearch(ctx, client, t)
+
+	// feature2 became baseline low on 2000-01-04 and high on 2000-01-31.
+	// barBrowser 2.0.0 (the only browser with feature2) was released on 2000-03-02.
+	asOf := time.Date(2000, time.January, 20, 0, 0, 0, 0, time.UTC)
+	result, err := client.GetFeature(ctx, NewFeatureKeyFilter("feature2"), defaultWPTMetricView(),
+		getDefaultTestBrowserList(), &asOf)
+	if err != nil {
+		t.Fatalf("unexpected error. %s", err.Error())
+	}
+
+	expectedResult := getFeatureSearchTestFeature(FeatureSearchTestFId2)
+	expectedResult.Status = valuePtr(string(BaselineStatusLow))
+	expectedResult.HighDate = nil
+	expectedResult.ImplementationStatuses = nil
+
+	stabilizeFeatureResult(*result)
+
+	if !AreFeatureResultsEqual(expectedResult, *result) {
+		t.Errorf("unequal results. expected (%+v) received (%+v) ",
+			PrettyPrintFeatureResult(expectedResult), PrettyPrintFeatureResult(*result))
+	}
+
+	// Before any of the runs, there should be no metrics.
+	asOf = time.Date(1999, time.December, 31, 0, 0, 0, 0, time.UTC)
+	result, err = client.GetFeature(ctx, NewFeatureKeyFilter("feature2"), defaultWPTMetricView(),
+		getDefaultTestBrowserList(), &asOf)
+	if err != nil {
+		t.Fatalf("unexpected error. %s", err.Error())
+	}
+
+	expectedResult.Status = valuePtr(string(BaselineStatusNone))
+	expectedResult.LowDate = nil
+	expectedResult.StableMetrics = nil
+	expectedResult.ExperimentalMetrics = nil
+
+	if !AreFeatureResultsEqual(expectedResult, *result) {
+		t.Errorf("unequal results. expected (%+v) received (%+v) ",
+			PrettyPrintFeatureResult(expectedResult), PrettyPrintFeatureResult(*result))
+	}
 }
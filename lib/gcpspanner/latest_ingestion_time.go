@@ -0,0 +1,79 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcpspanner
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/api/iterator"
+)
+
+// latestIngestionTimeQuery returns the end time of the most recent WPT run,
+// the date of the most recent browser release and the time of the most recent
+// change to the excluded feature keys.
+// The ingested tables do not record when rows were written, so these are the closest
+// approximation of when data was last ingested.
+const latestIngestionTimeQuery = `
+SELECT
+	(SELECT MAX(TimeEnd) FROM WPTRuns) AS LatestWPTRunTime,
+	(SELECT MAX(ReleaseDate) FROM BrowserReleases) AS LatestBrowserReleaseTime,
+	(SELECT MAX(CreatedAt) FROM ExcludedFeatureKeyAuditLogs) AS LatestExclusionChangeTime
+`
+
+type spannerLatestIngestionTime struct {
+	LatestWPTRunTime          *time.Time `spanner:"LatestWPTRunTime"`
+	LatestBrowserReleaseTime  *time.Time `spanner:"LatestBrowserReleaseTime"`
+	LatestExclusionChangeTime *time.Time `spanner:"LatestExclusionChangeTime"`
+}
+
+// GetLatestIngestionTime returns the most recent time that data was ingested.
+// It returns ErrQueryReturnedNoResults if nothing has been ingested yet.
+func (c *Client) GetLatestIngestionTime(ctx context.Context) (*time.Time, error) {
+	txn := c.Single()
+	defer txn.Close()
+	it := txn.Query(ctx, spanner.NewStatement(latestIngestionTimeQuery))
+	defer it.Stop()
+
+	row, err := it.Next()
+	if errors.Is(err, iterator.Done) {
+		return nil, ErrQueryReturnedNoResults
+	}
+	if err != nil {
+		return nil, errors.Join(ErrInternalQueryFailure, err)
+	}
+	var result spannerLatestIngestionTime
+	if err := row.ToStruct(&result); err != nil {
+		return nil, errors.Join(ErrInternalQueryFailure, err)
+	}
+
+	var latest *time.Time
+	for _, t := range []*time.Time{
+		result.LatestWPTRunTime,
+		result.LatestBrowserReleaseTime,
+		result.LatestExclusionChangeTime,
+	} {
+		if latest == nil || (t != nil && t.After(*latest)) {
+			latest = t
+		}
+	}
+	if latest == nil {
+		return nil, ErrQueryReturnedNoResults
+	}
+
+	return latest, nil
+}
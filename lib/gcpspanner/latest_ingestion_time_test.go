@@ -0,0 +1,75 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcpspanner
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGetLatestIngestionTime(t *testing.T) {
+	client := getTestDatabase(t)
+	ctx := context.Background()
+
+	_, err := client.GetLatestIngestionTime(ctx)
+	if !errors.Is(err, ErrQueryReturnedNoResults) {
+		t.Errorf("expected ErrQueryReturnedNoResults for empty database. received %v", err)
+	}
+
+	for _, run := range getSampleRuns() {
+		err := client.InsertWPTRun(ctx, run)
+		if err != nil {
+			t.Fatalf("unexpected error during insert of runs. %s", err.Error())
+		}
+	}
+	latest, err := client.GetLatestIngestionTime(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error. %s", err.Error())
+	}
+	expected := time.Date(2000, time.January, 2, 1, 0, 0, 0, time.UTC)
+	if !latest.Equal(expected) {
+		t.Errorf("unexpected latest time with only runs. expected %s received %s", expected, latest)
+	}
+
+	for _, release := range getSampleBrowserReleases() {
+		err := client.InsertBrowserRelease(ctx, release)
+		if err != nil {
+			t.Fatalf("unexpected error during insert of releases. %s", err.Error())
+		}
+	}
+	latest, err = client.GetLatestIngestionTime(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error. %s", err.Error())
+	}
+	expected = time.Date(2000, time.March, 2, 0, 0, 0, 0, time.UTC)
+	if !latest.Equal(expected) {
+		t.Errorf("unexpected latest time with releases. expected %s received %s", expected, latest)
+	}
+
+	// Changing the excluded feature keys changes the responses too.
+	before := time.Now()
+	if _, err := client.AddExcludedFeatureKey(ctx, "feature1", "duplicate", "admin@example.com"); err != nil {
+		t.Fatalf("unexpected error excluding feature. %s", err.Error())
+	}
+	latest, err = client.GetLatestIngestionTime(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error. %s", err.Error())
+	}
+	if latest.Before(before.Add(-time.Minute)) {
+		t.Errorf("expected the exclusion time to be the latest. received %s", latest)
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"cmp"
 	"context"
 	"log/slog"
+	"slices"
 	"time"
 
 	"github.com/GoogleChrome/webstatus.dev/lib/gcpspanner"
@@ -38,6 +39,16 @@ type BackendSpannerClient interface {
 		pageSize int,
 		pageToken *string,
 	) ([]gcpspanner.WPTRunFeatureMetricWithTime, *string, error)
+	ListFirstMetricsPageForFeatureIDsBrowserAndChannel(
+		ctx context.Context,
+		featureIDs []string,
+		browser string,
+		channel string,
+		metric gcpspanner.WPTMetricView,
+		startAt time.Time,
+		endAt time.Time,
+		pageSize int,
+	) (map[string]gcpspanner.WPTRunFeatureMetricsPage, error)
 	ListMetricsOverTimeWithAggregatedTotals(
 		ctx context.Context,
 		featureIDs []string,
@@ -48,6 +59,16 @@ type BackendSpannerClient interface {
 		pageSize int,
 		pageToken *string,
 	) ([]gcpspanner.WPTRunAggregationMetricWithTime, *string, error)
+	ListWPTInteropMetrics(
+		ctx context.Context,
+		featureIDs []string,
+		browsers []string,
+		channel string,
+		metric gcpspanner.WPTMetricView,
+		startAt, endAt time.Time,
+		pageSize int,
+		pageToken *string,
+	) ([]gcpspanner.WPTInteropMetricWithTime, *string, error)
 	FeaturesSearch(
 		ctx context.Context,
 		pageToken *string,
@@ -56,17 +77,28 @@ type BackendSpannerClient interface {
 		sortOrder gcpspanner.Sortable,
 		wptMetricView gcpspanner.WPTMetricView,
 		browsers []string,
+		asOf *time.Time,
+		facets []gcpspanner.FeatureSearchFacet,
 	) (*gcpspanner.FeatureResultPage, error)
 	GetFeature(
 		ctx context.Context,
 		filter gcpspanner.Filterable,
 		wptMetricView gcpspanner.WPTMetricView,
 		browsers []string,
+		asOf *time.Time,
 	) (*gcpspanner.FeatureResult, error)
+	GetFeatureKeysFromAliases(ctx context.Context, aliases []string) (map[string]string, error)
+	GetFeatures(
+		ctx context.Context,
+		featureKeys []string,
+		wptMetricView gcpspanner.WPTMetricView,
+		browsers []string,
+	) ([]gcpspanner.FeatureResult, error)
 	GetIDFromFeatureKey(
 		ctx context.Context,
 		filter *gcpspanner.FeatureIDFilter,
 	) (*string, error)
+	GetIDsFromFeatureKeys(ctx context.Context, featureKeys []string) (map[string]string, error)
 	ListBrowserFeatureCountMetric(
 		ctx context.Context,
 		browser string,
@@ -75,6 +107,28 @@ type BackendSpannerClient interface {
 		pageSize int,
 		pageToken *string,
 	) (*gcpspanner.BrowserFeatureCountResultPage, error)
+	GetFeatureTimeline(
+		ctx context.Context,
+		featureKey string,
+		wptMetricView gcpspanner.WPTMetricView,
+	) (*gcpspanner.FeatureTimeline, error)
+	GetFeatureCompatFeatures(ctx context.Context, featureKey string) ([]gcpspanner.FeatureCompatFeature, error)
+	ListFeatureTimeToBaseline(
+		ctx context.Context,
+		searchNode *searchtypes.SearchNode,
+	) ([]gcpspanner.FeatureTimeToBaseline, error)
+	ListBrowserCompatMatrix(
+		ctx context.Context,
+		browsers []string,
+		at time.Time,
+	) ([]gcpspanner.BrowserPairFeatureGap, error)
+	GetLatestIngestionTime(ctx context.Context) (*time.Time, error)
+	ListExcludedFeatureKeys(ctx context.Context) ([]gcpspanner.ExcludedFeatureKey, error)
+	AddExcludedFeatureKey(
+		ctx context.Context,
+		featureKey, reason, author string,
+	) (*gcpspanner.ExcludedFeatureKey, error)
+	RemoveExcludedFeatureKey(ctx context.Context, featureKey, author string) error
 }
 
 // Backend converts queries to spanner to usable entities for the backend
@@ -162,6 +216,77 @@ func (s *Backend) ListMetricsOverTimeWithAggregatedTotals(
 	return backendMetrics, nextPageToken, nil
 }
 
+func (s *Backend) ListWPTInteropMetrics(
+	ctx context.Context,
+	featureIDs []string,
+	browsers []backend.BrowserPathParam,
+	channel string,
+	metricView backend.MetricViewPathParam,
+	startAt, endAt time.Time,
+	pageSize int,
+	pageToken *string,
+) (*backend.WPTInteropMetricsPage, error) {
+	metrics, nextPageToken, err := s.client.ListWPTInteropMetrics(
+		ctx,
+		featureIDs,
+		BrowserList(browsers).ToStringList(),
+		channel,
+		getSpannerWPTMetricView(metricView),
+		startAt,
+		endAt,
+		pageSize,
+		pageToken,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert the interop metric type to backend metrics
+	backendMetrics := make([]backend.WPTRunMetric, 0, len(metrics))
+	// The metrics of each feature are grouped into their own series.
+	var featureKeys []string
+	featureMetrics := make(map[string][]backend.WPTRunMetric)
+	for _, metric := range metrics {
+		backendMetrics = append(backendMetrics, backend.WPTRunMetric{
+			RunTimestamp:    metric.TimeStart,
+			TestPassCount:   metric.TestPass,
+			TotalTestsCount: metric.TotalTests,
+		})
+		for _, featureMetric := range metric.FeatureMetrics {
+			if _, found := featureMetrics[featureMetric.FeatureKey]; !found {
+				featureKeys = append(featureKeys, featureMetric.FeatureKey)
+			}
+			featureMetrics[featureMetric.FeatureKey] = append(featureMetrics[featureMetric.FeatureKey],
+				backend.WPTRunMetric{
+					RunTimestamp:    metric.TimeStart,
+					TestPassCount:   featureMetric.TestPass,
+					TotalTestsCount: featureMetric.TotalTests,
+				})
+		}
+	}
+
+	var features *[]backend.WPTFeatureRunMetrics
+	if len(featureIDs) > 0 {
+		slices.Sort(featureKeys)
+		backendFeatures := make([]backend.WPTFeatureRunMetrics, 0, len(featureKeys))
+		for _, featureKey := range featureKeys {
+			backendFeatures = append(backendFeatures, backend.WPTFeatureRunMetrics{
+				FeatureId: featureKey,
+				Data:      featureMetrics[featureKey],
+			})
+		}
+		features = &backendFeatures
+	}
+
+	return &backend.WPTInteropMetricsPage{
+		Metadata: &backend.PageMetadata{
+			NextPageToken: nextPageToken,
+		},
+		Data:     backendMetrics,
+		Features: features,
+	}, nil
+}
+
 func (s *Backend) ListMetricsForFeatureIDBrowserAndChannel(
 	ctx context.Context,
 	featureID string,
@@ -200,6 +325,53 @@ func (s *Backend) ListMetricsForFeatureIDBrowserAndChannel(
 	return backendMetrics, nextPageToken, nil
 }
 
+// ListFirstMetricsPageForFeatureIDsBrowserAndChannel returns the first page of
+// metrics of multiple features in one query, keyed by feature ID.
+// Features that do not exist are left out of the returned map.
+func (s *Backend) ListFirstMetricsPageForFeatureIDsBrowserAndChannel(
+	ctx context.Context,
+	featureIDs []string,
+	browser string,
+	channel string,
+	metricView backend.MetricViewPathParam,
+	startAt, endAt time.Time,
+	pageSize int,
+) (map[string]*backend.WPTRunMetricsPage, error) {
+	pages, err := s.client.ListFirstMetricsPageForFeatureIDsBrowserAndChannel(
+		ctx,
+		featureIDs,
+		browser,
+		channel,
+		getSpannerWPTMetricView(metricView),
+		startAt,
+		endAt,
+		pageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	backendPages := make(map[string]*backend.WPTRunMetricsPage, len(pages))
+	for featureID, page := range pages {
+		backendMetrics := make([]backend.WPTRunMetric, 0, len(page.Metrics))
+		for _, metric := range page.Metrics {
+			backendMetrics = append(backendMetrics, backend.WPTRunMetric{
+				RunTimestamp:    metric.TimeStart,
+				TestPassCount:   metric.TestPass,
+				TotalTestsCount: metric.TotalTests,
+			})
+		}
+		backendPages[featureID] = &backend.WPTRunMetricsPage{
+			Metadata: &backend.PageMetadata{
+				NextPageToken: page.NextPageToken,
+			},
+			Data: backendMetrics,
+		}
+	}
+
+	return backendPages, nil
+}
+
 func convertBaselineStatusBackendToSpanner(status backend.BaselineInfoStatus) gcpspanner.BaselineStatus {
 	switch status {
 	case backend.Widely:
@@ -319,6 +491,7 @@ func (s *Backend) convertFeatureResult(featureResult *gcpspanner.FeatureResult)
 			featureResult.HighDate,
 		),
 		Wpt:                    nil,
+		RedirectedFrom:         nil,
 		Spec:                   nil,
 		Usage:                  nil,
 		BrowserImplementations: nil,
@@ -397,11 +570,13 @@ func (s *Backend) FeaturesSearch(
 	sortOrder *backend.GetV1FeaturesParamsSort,
 	wptMetricView backend.WPTMetricView,
 	browsers []backend.BrowserPathParam,
+	asOf *time.Time,
+	facets []string,
 ) (*backend.FeaturePage, error) {
 	spannerSortOrder := getFeatureSearchSortOrder(sortOrder)
 	page, err := s.client.FeaturesSearch(ctx, pageToken, pageSize, searchNode,
 		spannerSortOrder, getSpannerWPTMetricView(wptMetricView),
-		BrowserList(browsers).ToStringList())
+		BrowserList(browsers).ToStringList(), asOf, getSpannerFeatureSearchFacets(facets))
 	if err != nil {
 		return nil, err
 	}
@@ -417,12 +592,69 @@ func (s *Backend) FeaturesSearch(
 			NextPageToken: page.NextPageToken,
 			Total:         page.Total,
 		},
-		Data: results,
+		Data:   results,
+		Facets: convertFeatureSearchFacetsToBackend(page.Facets),
 	}
 
 	return ret, nil
 }
 
+func getSpannerFeatureSearchFacets(facets []string) []gcpspanner.FeatureSearchFacet {
+	if len(facets) == 0 {
+		return nil
+	}
+	ret := make([]gcpspanner.FeatureSearchFacet, 0, len(facets))
+	for _, facet := range facets {
+		switch gcpspanner.FeatureSearchFacet(facet) {
+		case gcpspanner.BaselineStatusFacet:
+			ret = append(ret, gcpspanner.BaselineStatusFacet)
+		case gcpspanner.AvailableOnFacet:
+			ret = append(ret, gcpspanner.AvailableOnFacet)
+		default:
+			slog.Warn("unsupported facet", "facet", facet)
+		}
+	}
+
+	return ret
+}
+
+func convertFeatureSearchFacetsToBackend(facets *gcpspanner.FeatureSearchFacets) *backend.FeatureSearchFacets {
+	if facets == nil {
+		return nil
+	}
+	ret := &backend.FeatureSearchFacets{
+		BaselineStatus: nil,
+		AvailableOn:    nil,
+	}
+	if facets.BaselineStatus != nil {
+		counts := make([]backend.FacetValueCount, 0, len(facets.BaselineStatus))
+		for _, count := range facets.BaselineStatus {
+			status := count.Value
+			baseline := convertBaselineSpannerToBackend(&status, nil, nil)
+			if baseline == nil || baseline.Status == nil {
+				continue
+			}
+			counts = append(counts, backend.FacetValueCount{
+				Value: string(*baseline.Status),
+				Count: count.Count,
+			})
+		}
+		ret.BaselineStatus = &counts
+	}
+	if facets.AvailableOn != nil {
+		counts := make([]backend.FacetValueCount, 0, len(facets.AvailableOn))
+		for _, count := range facets.AvailableOn {
+			counts = append(counts, backend.FacetValueCount{
+				Value: count.Value,
+				Count: count.Count,
+			})
+		}
+		ret.AvailableOn = &counts
+	}
+
+	return ret
+}
+
 // TODO: Pass in context to be used by slog.ErrorContext.
 func getFeatureSearchSortOrder(
 	sortOrder *backend.GetV1FeaturesParamsSort) gcpspanner.Sortable {
@@ -471,6 +703,34 @@ func getFeatureSearchSortOrder(
 		return gcpspanner.NewBrowserImplSort(true, string(backend.Safari), true)
 	case backend.StableSafariDesc:
 		return gcpspanner.NewBrowserImplSort(false, string(backend.Safari), true)
+	case backend.BaselineLowDateAsc:
+		return gcpspanner.NewBaselineLowDateSort(true)
+	case backend.BaselineLowDateDesc:
+		return gcpspanner.NewBaselineLowDateSort(false)
+	case backend.BaselineHighDateAsc:
+		return gcpspanner.NewBaselineHighDateSort(true)
+	case backend.BaselineHighDateDesc:
+		return gcpspanner.NewBaselineHighDateSort(false)
+	case backend.AvailableDateChromeAsc:
+		return gcpspanner.NewBrowserAvailableDateSort(true, string(backend.Chrome))
+	case backend.AvailableDateChromeDesc:
+		return gcpspanner.NewBrowserAvailableDateSort(false, string(backend.Chrome))
+	case backend.AvailableDateEdgeAsc:
+		return gcpspanner.NewBrowserAvailableDateSort(true, string(backend.Edge))
+	case backend.AvailableDateEdgeDesc:
+		return gcpspanner.NewBrowserAvailableDateSort(false, string(backend.Edge))
+	case backend.AvailableDateFirefoxAsc:
+		return gcpspanner.NewBrowserAvailableDateSort(true, string(backend.Firefox))
+	case backend.AvailableDateFirefoxDesc:
+		return gcpspanner.NewBrowserAvailableDateSort(false, string(backend.Firefox))
+	case backend.AvailableDateSafariAsc:
+		return gcpspanner.NewBrowserAvailableDateSort(true, string(backend.Safari))
+	case backend.AvailableDateSafariDesc:
+		return gcpspanner.NewBrowserAvailableDateSort(false, string(backend.Safari))
+	case backend.SpecAsc:
+		return gcpspanner.NewSpecSort(true)
+	case backend.SpecDesc:
+		return gcpspanner.NewSpecSort(false)
 	}
 
 	// Unknown sort order
@@ -484,22 +744,78 @@ func (s *Backend) GetFeature(
 	featureID string,
 	wptMetricView backend.WPTMetricView,
 	browsers []backend.BrowserPathParam,
+	asOf *time.Time,
 ) (*backend.Feature, error) {
-	filter := gcpspanner.NewFeatureKeyFilter(featureID)
+	filter := gcpspanner.NewFeatureKeyOrAliasFilter(featureID)
 	featureResult, err := s.client.GetFeature(ctx, filter, getSpannerWPTMetricView(wptMetricView),
+		BrowserList(browsers).ToStringList(), asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	feature := s.convertFeatureResult(featureResult)
+	// The requested key is an alias of a renamed feature.
+	if feature.FeatureId != featureID {
+		feature.RedirectedFrom = &featureID
+	}
+
+	return feature, nil
+}
+
+// GetFeatures returns multiple features, keyed by feature ID. The keys can also be aliases of renamed features.
+// Features that do not exist are left out of the returned map.
+func (s *Backend) GetFeatures(
+	ctx context.Context,
+	featureIDs []string,
+	wptMetricView backend.WPTMetricView,
+	browsers []backend.BrowserPathParam,
+) (map[string]*backend.Feature, error) {
+	aliases, err := s.client.GetFeatureKeysFromAliases(ctx, featureIDs)
+	if err != nil {
+		return nil, err
+	}
+	// Aliases take precedence, like in GetFeature.
+	featureKeys := make([]string, 0, len(featureIDs))
+	for _, featureID := range featureIDs {
+		if featureKey, found := aliases[featureID]; found {
+			featureID = featureKey
+		}
+		featureKeys = append(featureKeys, featureID)
+	}
+
+	featureResults, err := s.client.GetFeatures(ctx, featureKeys, getSpannerWPTMetricView(wptMetricView),
 		BrowserList(browsers).ToStringList())
 	if err != nil {
 		return nil, err
 	}
+	featureResultsByKey := make(map[string]*gcpspanner.FeatureResult, len(featureResults))
+	for idx := range featureResults {
+		featureResultsByKey[featureResults[idx].FeatureKey] = &featureResults[idx]
+	}
 
-	return s.convertFeatureResult(featureResult), nil
+	features := make(map[string]*backend.Feature, len(featureIDs))
+	for idx, featureID := range featureIDs {
+		featureResult, found := featureResultsByKey[featureKeys[idx]]
+		if !found {
+			continue
+		}
+		feature := s.convertFeatureResult(featureResult)
+		// The requested key is an alias of a renamed feature.
+		if feature.FeatureId != featureID {
+			feature.RedirectedFrom = &featureID
+		}
+		features[featureID] = feature
+	}
+
+	return features, nil
 }
 
+// GetIDFromFeatureKey returns the ID of the feature. The key can also be an alias of a renamed feature.
 func (s *Backend) GetIDFromFeatureKey(
 	ctx context.Context,
 	featureID string,
 ) (*string, error) {
-	filter := gcpspanner.NewFeatureKeyFilter(featureID)
+	filter := gcpspanner.NewFeatureKeyOrAliasFilter(featureID)
 	id, err := s.client.GetIDFromFeatureKey(ctx, filter)
 	if err != nil {
 		return nil, err
@@ -507,3 +823,259 @@ func (s *Backend) GetIDFromFeatureKey(
 
 	return id, nil
 }
+
+// GetIDsFromFeatureKeys looks up the IDs of multiple feature keys in one query.
+// Feature keys that do not exist are left out of the returned map.
+func (s *Backend) GetIDsFromFeatureKeys(
+	ctx context.Context,
+	featureKeys []string,
+) (map[string]string, error) {
+	return s.client.GetIDsFromFeatureKeys(ctx, featureKeys)
+}
+
+func (s *Backend) GetFeatureTimeline(
+	ctx context.Context,
+	featureID string,
+	wptMetricView backend.WPTMetricView,
+) (*backend.FeatureTimeline, error) {
+	timeline, err := s.client.GetFeatureTimeline(ctx, featureID, getSpannerWPTMetricView(wptMetricView))
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]backend.FeatureTimelineEvent, 0,
+		len(timeline.BrowserAvailabilities)+len(timeline.WPTMilestones)+2)
+	for idx := range timeline.BrowserAvailabilities {
+		availability := timeline.BrowserAvailabilities[idx]
+		events = append(events, backend.FeatureTimelineEvent{
+			Timestamp:         availability.ReleaseDate,
+			Type:              backend.BrowserAvailable,
+			Browser:           &availability.BrowserName,
+			BrowserVersion:    &availability.BrowserVersion,
+			Channel:           nil,
+			PassRateThreshold: nil,
+		})
+	}
+
+	if timeline.Baseline != nil {
+		if timeline.Baseline.LowDate != nil {
+			events = append(events, newBaselineTimelineEvent(*timeline.Baseline.LowDate, backend.BaselineLow))
+		}
+		if timeline.Baseline.HighDate != nil {
+			events = append(events, newBaselineTimelineEvent(*timeline.Baseline.HighDate, backend.BaselineHigh))
+		}
+	}
+
+	for idx := range timeline.WPTMilestones {
+		milestone := timeline.WPTMilestones[idx]
+		var threshold *float64
+		if milestone.Threshold != nil {
+			value, _ := milestone.Threshold.Float64()
+			threshold = &value
+		}
+		events = append(events, backend.FeatureTimelineEvent{
+			Timestamp:         milestone.TimeStart,
+			Type:              backend.WPTMilestone,
+			Browser:           &milestone.BrowserName,
+			BrowserVersion:    nil,
+			Channel:           &milestone.Channel,
+			PassRateThreshold: threshold,
+		})
+	}
+
+	// Each source is already in chronological order. A stable sort merges them
+	// while keeping availabilities before baseline changes before milestones on ties.
+	slices.SortStableFunc(events, func(a, b backend.FeatureTimelineEvent) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+
+	return &backend.FeatureTimeline{
+		FeatureId: featureID,
+		Events:    events,
+	}, nil
+}
+
+func newBaselineTimelineEvent(
+	timestamp time.Time, eventType backend.FeatureTimelineEventType) backend.FeatureTimelineEvent {
+	return backend.FeatureTimelineEvent{
+		Timestamp:         timestamp,
+		Type:              eventType,
+		Browser:           nil,
+		BrowserVersion:    nil,
+		Channel:           nil,
+		PassRateThreshold: nil,
+	}
+}
+
+func (s *Backend) GetFeatureCompatFeatures(
+	ctx context.Context,
+	featureID string,
+) (*backend.FeatureCompatFeatures, error) {
+	compatFeatures, err := s.client.GetFeatureCompatFeatures(ctx, featureID)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]backend.FeatureCompatFeature, 0, len(compatFeatures))
+	for _, compatFeature := range compatFeatures {
+		browserSupport := make([]backend.CompatFeatureBrowserSupport, 0, len(compatFeature.BrowserSupport))
+		for _, support := range compatFeature.BrowserSupport {
+			browserSupport = append(browserSupport, backend.CompatFeatureBrowserSupport{
+				Browser:      support.BrowserName,
+				VersionAdded: support.VersionAdded,
+			})
+		}
+		data = append(data, backend.FeatureCompatFeature{
+			CompatFeature:  compatFeature.CompatFeature,
+			BrowserSupport: browserSupport,
+		})
+	}
+
+	return &backend.FeatureCompatFeatures{
+		FeatureId:      featureID,
+		CompatFeatures: data,
+	}, nil
+}
+
+// timeToBaselinePercentiles are the percentiles returned by ListFeatureTimeToBaseline.
+// nolint: gochecknoglobals // WONTFIX. Constant list of percentiles.
+var timeToBaselinePercentiles = []int{25, 50, 75, 90}
+
+func (s *Backend) ListFeatureTimeToBaseline(
+	ctx context.Context,
+	searchNode *searchtypes.SearchNode,
+) (*backend.TimeToBaselineStats, error) {
+	results, err := s.client.ListFeatureTimeToBaseline(ctx, searchNode)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]backend.FeatureTimeToBaseline, 0, len(results))
+	var daysToLow, daysLowToHigh []int64
+	for _, result := range results {
+		data = append(data, backend.FeatureTimeToBaseline{
+			FeatureId:             result.FeatureKey,
+			Name:                  result.Name,
+			FirstAvailableDate:    convertTimeToDate(result.FirstAvailableDate),
+			BaselineLowDate:       convertTimeToDate(result.LowDate),
+			BaselineHighDate:      convertTimeToDate(result.HighDate),
+			DaysToBaselineLow:     result.DaysToLow,
+			DaysBaselineLowToHigh: result.DaysLowToHigh,
+		})
+		if result.DaysToLow != nil {
+			daysToLow = append(daysToLow, *result.DaysToLow)
+		}
+		if result.DaysLowToHigh != nil {
+			daysLowToHigh = append(daysLowToHigh, *result.DaysLowToHigh)
+		}
+	}
+
+	return &backend.TimeToBaselineStats{
+		Data:                             data,
+		DaysToBaselineLowPercentiles:     calculateDayPercentiles(daysToLow),
+		DaysBaselineLowToHighPercentiles: calculateDayPercentiles(daysLowToHigh),
+	}, nil
+}
+
+func convertTimeToDate(in *time.Time) *openapi_types.Date {
+	if in == nil {
+		return nil
+	}
+
+	return &openapi_types.Date{Time: *in}
+}
+
+// calculateDayPercentiles uses the nearest-rank method to compute the
+// timeToBaselinePercentiles of the given values.
+// If there are no values, an empty list is returned.
+func calculateDayPercentiles(values []int64) []backend.TimeToBaselinePercentile {
+	ret := make([]backend.TimeToBaselinePercentile, 0, len(timeToBaselinePercentiles))
+	if len(values) == 0 {
+		return ret
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	for _, percentile := range timeToBaselinePercentiles {
+		// Nearest rank: ceil(p / 100 * n), converted to a zero based index.
+		rank := (percentile*len(sorted) + 99) / 100
+		ret = append(ret, backend.TimeToBaselinePercentile{
+			Percentile: percentile,
+			Days:       sorted[max(rank-1, 0)],
+		})
+	}
+
+	return ret
+}
+
+func (s *Backend) GetBrowserCompatMatrix(
+	ctx context.Context,
+	browsers []backend.BrowserPathParam,
+	at time.Time,
+) (*backend.BrowserCompatMatrix, error) {
+	gaps, err := s.client.ListBrowserCompatMatrix(ctx, BrowserList(browsers).ToStringList(), at)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]backend.BrowserPairFeatureGap, 0, len(gaps))
+	for _, gap := range gaps {
+		data = append(data, backend.BrowserPairFeatureGap{
+			BrowserA: gap.BrowserA,
+			BrowserB: gap.BrowserB,
+			Count:    gap.FeatureCount,
+		})
+	}
+
+	return &backend.BrowserCompatMatrix{
+		Data: data,
+	}, nil
+}
+
+// GetLastModified returns the most recent time that data was ingested.
+func (s *Backend) GetLastModified(ctx context.Context) (time.Time, error) {
+	latest, err := s.client.GetLatestIngestionTime(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return *latest, nil
+}
+
+func convertExcludedFeatureKey(key gcpspanner.ExcludedFeatureKey) backend.ExcludedFeatureKey {
+	return backend.ExcludedFeatureKey{
+		FeatureKey: key.FeatureKey,
+		Reason:     key.Reason,
+		Author:     key.Author,
+		CreatedAt:  key.CreatedAt,
+	}
+}
+
+func (s *Backend) ListExcludedFeatureKeys(ctx context.Context) ([]backend.ExcludedFeatureKey, error) {
+	keys, err := s.client.ListExcludedFeatureKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]backend.ExcludedFeatureKey, 0, len(keys))
+	for _, key := range keys {
+		ret = append(ret, convertExcludedFeatureKey(key))
+	}
+
+	return ret, nil
+}
+
+func (s *Backend) AddExcludedFeatureKey(
+	ctx context.Context,
+	featureKey, reason, author string,
+) (*backend.ExcludedFeatureKey, error) {
+	key, err := s.client.AddExcludedFeatureKey(ctx, featureKey, reason, author)
+	if err != nil {
+		return nil, err
+	}
+	ret := convertExcludedFeatureKey(*key)
+
+	return &ret, nil
+}
+
+func (s *Backend) RemoveExcludedFeatureKey(ctx context.Context, featureKey, author string) error {
+	return s.client.RemoveExcludedFeatureKey(ctx, featureKey, author)
+}
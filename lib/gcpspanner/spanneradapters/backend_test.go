@@ -45,6 +45,8 @@ type mockFeaturesSearchConfig struct {
 	expectedNode          *searchtypes.SearchNode
 	expectedWPTMetricView gcpspanner.WPTMetricView
 	expectedBrowsers      []string
+	expectedAsOf          *time.Time
+	expectedFacets        []gcpspanner.FeatureSearchFacet
 	result                *gcpspanner.FeatureResultPage
 	returnedError         error
 }
@@ -53,29 +55,93 @@ type mockGetFeatureConfig struct {
 	expectedFilterable    gcpspanner.Filterable
 	expectedWPTMetricView gcpspanner.WPTMetricView
 	expectedBrowsers      []string
+	expectedAsOf          *time.Time
 	result                *gcpspanner.FeatureResult
 	returnedError         error
 }
 
+type mockGetFeaturesConfig struct {
+	expectedAliases       []string
+	aliases               map[string]string
+	expectedFeatureKeys   []string
+	expectedWPTMetricView gcpspanner.WPTMetricView
+	expectedBrowsers      []string
+	result                []gcpspanner.FeatureResult
+	returnedError         error
+}
+
+type mockListFirstMetricsPagesConfig struct {
+	expectedFeatureKeys []string
+	result              map[string]gcpspanner.WPTRunFeatureMetricsPage
+	returnedError       error
+}
+
 type mockGetIDByFeaturesIDConfig struct {
 	expectedFilterable gcpspanner.Filterable
 	result             *string
 	returnedError      error
 }
 
+type mockGetIDsFromFeatureKeysConfig struct {
+	expectedFeatureKeys []string
+	result              map[string]string
+	returnedError       error
+}
+
 type mockListBrowserFeatureCountMetricConfig struct {
 	result        *gcpspanner.BrowserFeatureCountResultPage
 	returnedError error
 }
 
+type mockGetFeatureTimelineConfig struct {
+	expectedFeatureKey    string
+	expectedWPTMetricView gcpspanner.WPTMetricView
+	result                *gcpspanner.FeatureTimeline
+	returnedError         error
+}
+
+type mockGetFeatureCompatFeaturesConfig struct {
+	expectedFeatureKey string
+	result             []gcpspanner.FeatureCompatFeature
+	returnedError      error
+}
+
+type mockListFeatureTimeToBaselineConfig struct {
+	expectedSearchNode *searchtypes.SearchNode
+	result             []gcpspanner.FeatureTimeToBaseline
+	returnedError      error
+}
+
+type mockListBrowserCompatMatrixConfig struct {
+	expectedBrowsers []string
+	expectedAt       time.Time
+	result           []gcpspanner.BrowserPairFeatureGap
+	returnedError    error
+}
+
+type mockGetLatestIngestionTimeConfig struct {
+	result        *time.Time
+	returnedError error
+}
+
 type mockBackendSpannerClient struct {
 	t                                    *testing.T
 	aggregationData                      []gcpspanner.WPTRunAggregationMetricWithTime
+	interopData                          []gcpspanner.WPTInteropMetricWithTime
 	featureData                          []gcpspanner.WPTRunFeatureMetricWithTime
 	mockFeaturesSearchCfg                mockFeaturesSearchConfig
 	mockGetFeatureCfg                    mockGetFeatureConfig
+	mockGetFeaturesCfg                   mockGetFeaturesConfig
+	mockListFirstMetricsPagesCfg         mockListFirstMetricsPagesConfig
 	mockGetIDByFeaturesIDCfg             mockGetIDByFeaturesIDConfig
+	mockGetIDsFromFeatureKeysCfg         mockGetIDsFromFeatureKeysConfig
 	mockListBrowserFeatureCountMetricCfg mockListBrowserFeatureCountMetricConfig
+	mockGetFeatureTimelineCfg            mockGetFeatureTimelineConfig
+	mockGetFeatureCompatFeaturesCfg      mockGetFeatureCompatFeaturesConfig
+	mockListFeatureTimeToBaselineCfg     mockListFeatureTimeToBaselineConfig
+	mockListBrowserCompatMatrixCfg       mockListBrowserCompatMatrixConfig
+	mockGetLatestIngestionTimeCfg        mockGetLatestIngestionTimeConfig
+	mockExcludedFeatureKeysCfg           mockExcludedFeatureKeysConfig
 	pageToken                            *string
 	err                                  error
 }
@@ -84,16 +150,123 @@ func (c mockBackendSpannerClient) GetFeature(
 	_ context.Context,
 	filter gcpspanner.Filterable,
 	view gcpspanner.WPTMetricView,
-	browsers []string) (*gcpspanner.FeatureResult, error) {
+	browsers []string,
+	asOf *time.Time) (*gcpspanner.FeatureResult, error) {
 	if !reflect.DeepEqual(filter, c.mockGetFeatureCfg.expectedFilterable) ||
 		view != c.mockGetFeatureCfg.expectedWPTMetricView ||
-		!slices.Equal(browsers, c.mockGetFeatureCfg.expectedBrowsers) {
+		!slices.Equal(browsers, c.mockGetFeatureCfg.expectedBrowsers) ||
+		!reflect.DeepEqual(asOf, c.mockGetFeatureCfg.expectedAsOf) {
 		c.t.Error("unexpected input to mock")
 	}
 
 	return c.mockGetFeatureCfg.result, c.mockFeaturesSearchCfg.returnedError
 }
 
+func (c mockBackendSpannerClient) GetFeatureKeysFromAliases(
+	_ context.Context, aliases []string) (map[string]string, error) {
+	if !slices.Equal(aliases, c.mockGetFeaturesCfg.expectedAliases) {
+		c.t.Error("unexpected input to mock")
+	}
+
+	return c.mockGetFeaturesCfg.aliases, nil
+}
+
+func (c mockBackendSpannerClient) GetFeatures(
+	_ context.Context,
+	featureKeys []string,
+	view gcpspanner.WPTMetricView,
+	browsers []string) ([]gcpspanner.FeatureResult, error) {
+	if !slices.Equal(featureKeys, c.mockGetFeaturesCfg.expectedFeatureKeys) ||
+		view != c.mockGetFeaturesCfg.expectedWPTMetricView ||
+		!slices.Equal(browsers, c.mockGetFeaturesCfg.expectedBrowsers) {
+		c.t.Error("unexpected input to mock")
+	}
+
+	return c.mockGetFeaturesCfg.result, c.mockGetFeaturesCfg.returnedError
+}
+
+func (c mockBackendSpannerClient) GetFeatureTimeline(
+	_ context.Context,
+	featureKey string,
+	view gcpspanner.WPTMetricView) (*gcpspanner.FeatureTimeline, error) {
+	if featureKey != c.mockGetFeatureTimelineCfg.expectedFeatureKey ||
+		view != c.mockGetFeatureTimelineCfg.expectedWPTMetricView {
+		c.t.Error("unexpected input to mock")
+	}
+
+	return c.mockGetFeatureTimelineCfg.result, c.mockGetFeatureTimelineCfg.returnedError
+}
+
+func (c mockBackendSpannerClient) GetFeatureCompatFeatures(
+	_ context.Context,
+	featureKey string) ([]gcpspanner.FeatureCompatFeature, error) {
+	if featureKey != c.mockGetFeatureCompatFeaturesCfg.expectedFeatureKey {
+		c.t.Error("unexpected input to mock")
+	}
+
+	return c.mockGetFeatureCompatFeaturesCfg.result, c.mockGetFeatureCompatFeaturesCfg.returnedError
+}
+
+func (c mockBackendSpannerClient) ListFeatureTimeToBaseline(
+	_ context.Context,
+	searchNode *searchtypes.SearchNode) ([]gcpspanner.FeatureTimeToBaseline, error) {
+	if !reflect.DeepEqual(searchNode, c.mockListFeatureTimeToBaselineCfg.expectedSearchNode) {
+		c.t.Error("unexpected input to mock")
+	}
+
+	return c.mockListFeatureTimeToBaselineCfg.result, c.mockListFeatureTimeToBaselineCfg.returnedError
+}
+
+func (c mockBackendSpannerClient) ListBrowserCompatMatrix(
+	_ context.Context,
+	browsers []string,
+	at time.Time) ([]gcpspanner.BrowserPairFeatureGap, error) {
+	if !slices.Equal(browsers, c.mockListBrowserCompatMatrixCfg.expectedBrowsers) ||
+		!at.Equal(c.mockListBrowserCompatMatrixCfg.expectedAt) {
+		c.t.Error("unexpected input to mock")
+	}
+
+	return c.mockListBrowserCompatMatrixCfg.result, c.mockListBrowserCompatMatrixCfg.returnedError
+}
+
+func (c mockBackendSpannerClient) GetLatestIngestionTime(_ context.Context) (*time.Time, error) {
+	return c.mockGetLatestIngestionTimeCfg.result, c.mockGetLatestIngestionTimeCfg.returnedError
+}
+
+type mockExcludedFeatureKeysConfig struct {
+	expectedFeatureKey string
+	expectedReason     string
+	expectedAuthor     string
+	keys               []gcpspanner.ExcludedFeatureKey
+	returnedError      error
+}
+
+func (c mockBackendSpannerClient) ListExcludedFeatureKeys(_ context.Context) ([]gcpspanner.ExcludedFeatureKey, error) {
+	return c.mockExcludedFeatureKeysCfg.keys, c.mockExcludedFeatureKeysCfg.returnedError
+}
+
+func (c mockBackendSpannerClient) AddExcludedFeatureKey(
+	_ context.Context, featureKey, reason, author string) (*gcpspanner.ExcludedFeatureKey, error) {
+	cfg := c.mockExcludedFeatureKeysCfg
+	if featureKey != cfg.expectedFeatureKey || reason != cfg.expectedReason || author != cfg.expectedAuthor {
+		c.t.Error("unexpected input to mock")
+	}
+	if cfg.returnedError != nil {
+		return nil, cfg.returnedError
+	}
+
+	return &cfg.keys[0], nil
+}
+
+func (c mockBackendSpannerClient) RemoveExcludedFeatureKey(_ context.Context, featureKey, author string) error {
+	cfg := c.mockExcludedFeatureKeysCfg
+	if featureKey != cfg.expectedFeatureKey || author != cfg.expectedAuthor {
+		c.t.Error("unexpected input to mock")
+	}
+
+	return cfg.returnedError
+}
+
 func (c mockBackendSpannerClient) GetIDFromFeatureKey(
 	_ context.Context, filter *gcpspanner.FeatureIDFilter) (*string, error) {
 	if !reflect.DeepEqual(filter, c.mockGetIDByFeaturesIDCfg.expectedFilterable) {
@@ -103,6 +276,15 @@ func (c mockBackendSpannerClient) GetIDFromFeatureKey(
 	return c.mockGetIDByFeaturesIDCfg.result, c.mockGetIDByFeaturesIDCfg.returnedError
 }
 
+func (c mockBackendSpannerClient) GetIDsFromFeatureKeys(
+	_ context.Context, featureKeys []string) (map[string]string, error) {
+	if !slices.Equal(featureKeys, c.mockGetIDsFromFeatureKeysCfg.expectedFeatureKeys) {
+		c.t.Error("unexpected input to mock")
+	}
+
+	return c.mockGetIDsFromFeatureKeysCfg.result, c.mockGetIDsFromFeatureKeysCfg.returnedError
+}
+
 func (c mockBackendSpannerClient) ListBrowserFeatureCountMetric(
 	ctx context.Context,
 	browser string,
@@ -149,6 +331,30 @@ func (c mockBackendSpannerClient) ListMetricsForFeatureIDBrowserAndChannel(
 	return c.featureData, c.pageToken, c.err
 }
 
+func (c mockBackendSpannerClient) ListFirstMetricsPageForFeatureIDsBrowserAndChannel(
+	ctx context.Context,
+	featureIDs []string,
+	browser string,
+	channel string,
+	metric gcpspanner.WPTMetricView,
+	startAt time.Time,
+	endAt time.Time,
+	pageSize int,
+) (map[string]gcpspanner.WPTRunFeatureMetricsPage, error) {
+	if ctx != context.Background() ||
+		!slices.Equal(featureIDs, c.mockListFirstMetricsPagesCfg.expectedFeatureKeys) ||
+		browser != "browser" ||
+		channel != "channel" ||
+		metric != gcpspanner.WPTSubtestView ||
+		!startAt.Equal(testStart) ||
+		!endAt.Equal(testEnd) ||
+		pageSize != 100 {
+		c.t.Error("unexpected input to mock")
+	}
+
+	return c.mockListFirstMetricsPagesCfg.result, c.mockListFirstMetricsPagesCfg.returnedError
+}
+
 func (c mockBackendSpannerClient) ListMetricsOverTimeWithAggregatedTotals(
 	ctx context.Context,
 	featureIDs []string,
@@ -174,6 +380,31 @@ func (c mockBackendSpannerClient) ListMetricsOverTimeWithAggregatedTotals(
 	return c.aggregationData, c.pageToken, c.err
 }
 
+func (c mockBackendSpannerClient) ListWPTInteropMetrics(
+	ctx context.Context,
+	featureIDs []string,
+	browsers []string,
+	channel string,
+	metric gcpspanner.WPTMetricView,
+	startAt, endAt time.Time,
+	pageSize int,
+	pageToken *string,
+) ([]gcpspanner.WPTInteropMetricWithTime, *string, error) {
+	if ctx != context.Background() ||
+		!slices.Equal[[]string](featureIDs, []string{"feature1", "feature2"}) ||
+		!slices.Equal[[]string](browsers, []string{"chrome", "firefox"}) ||
+		channel != "channel" ||
+		metric != gcpspanner.WPTTestView ||
+		!startAt.Equal(testStart) ||
+		!endAt.Equal(testEnd) ||
+		pageSize != 100 ||
+		pageToken != nonNilInputPageToken {
+		c.t.Error("unexpected input to mock")
+	}
+
+	return c.interopData, c.pageToken, c.err
+}
+
 func (c mockBackendSpannerClient) FeaturesSearch(
 	_ context.Context,
 	pageToken *string,
@@ -181,13 +412,17 @@ func (c mockBackendSpannerClient) FeaturesSearch(
 	searchNode *searchtypes.SearchNode,
 	sortOrder gcpspanner.Sortable,
 	wptMetricView gcpspanner.WPTMetricView,
-	browsers []string) (*gcpspanner.FeatureResultPage, error) {
+	browsers []string,
+	asOf *time.Time,
+	facets []gcpspanner.FeatureSearchFacet) (*gcpspanner.FeatureResultPage, error) {
 	if pageToken != c.mockFeaturesSearchCfg.expectedPageToken ||
 		pageSize != c.mockFeaturesSearchCfg.expectedPageSize ||
 		!reflect.DeepEqual(searchNode, c.mockFeaturesSearchCfg.expectedNode) ||
 		!reflect.DeepEqual(sortOrder, c.mockFeaturesSearchCfg.expectedSortable) ||
 		wptMetricView != c.mockFeaturesSearchCfg.expectedWPTMetricView ||
-		!slices.Equal(browsers, c.mockFeaturesSearchCfg.expectedBrowsers) {
+		!slices.Equal(browsers, c.mockFeaturesSearchCfg.expectedBrowsers) ||
+		!reflect.DeepEqual(asOf, c.mockFeaturesSearchCfg.expectedAsOf) ||
+		!slices.Equal(facets, c.mockFeaturesSearchCfg.expectedFacets) {
 		c.t.Error("unexpected input to mock")
 	}
 
@@ -287,6 +522,54 @@ func TestListMetricsForFeatureIDBrowserAndChannel(t *testing.T) {
 	}
 }
 
+func TestListFirstMetricsPageForFeatureIDsBrowserAndChannel(t *testing.T) {
+	//nolint: exhaustruct
+	mock := mockBackendSpannerClient{
+		t: t,
+		mockListFirstMetricsPagesCfg: mockListFirstMetricsPagesConfig{
+			expectedFeatureKeys: []string{"feature1", "feature2"},
+			result: map[string]gcpspanner.WPTRunFeatureMetricsPage{
+				"feature1": {
+					Metrics: []gcpspanner.WPTRunFeatureMetricWithTime{
+						{
+							TimeStart:  time.Date(2000, time.January, 10, 0, 0, 0, 0, time.UTC),
+							RunID:      10,
+							TotalTests: valuePtr[int64](20),
+							TestPass:   valuePtr[int64](10),
+						},
+					},
+					NextPageToken: nonNilNextPageToken,
+				},
+			},
+			returnedError: nil,
+		},
+	}
+	b := NewBackend(mock)
+	pages, err := b.ListFirstMetricsPageForFeatureIDsBrowserAndChannel(
+		context.Background(), []string{"feature1", "feature2"}, "browser", "channel", backend.SubtestCounts,
+		testStart, testEnd, 100)
+	if err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	expectedPages := map[string]*backend.WPTRunMetricsPage{
+		"feature1": {
+			Data: []backend.WPTRunMetric{
+				{
+					RunTimestamp:    time.Date(2000, time.January, 10, 0, 0, 0, 0, time.UTC),
+					TotalTestsCount: valuePtr[int64](20),
+					TestPassCount:   valuePtr[int64](10),
+				},
+			},
+			Metadata: &backend.PageMetadata{
+				NextPageToken: nonNilNextPageToken,
+			},
+		},
+	}
+	if !reflect.DeepEqual(pages, expectedPages) {
+		t.Errorf("unexpected pages %v", pages)
+	}
+}
+
 func TestListBrowserFeatureCountMetric(t *testing.T) {
 	testCases := []struct {
 		name         string
@@ -471,6 +754,166 @@ func TestListMetricsOverTimeWithAggregatedTotals(t *testing.T) {
 	}
 }
 
+func TestListWPTInteropMetrics(t *testing.T) {
+	testCases := []struct {
+		name           string
+		interopData    []gcpspanner.WPTInteropMetricWithTime
+		pageToken      *string
+		err            error
+		expectedOutput *backend.WPTInteropMetricsPage
+		expectedErr    error
+	}{
+		{
+			name: "success",
+			interopData: []gcpspanner.WPTInteropMetricWithTime{
+				{
+					TimeStart:        time.Date(2000, time.January, 10, 0, 0, 0, 0, time.UTC),
+					FullRevisionHash: "abc",
+					TotalTests:       valuePtr[int64](20),
+					TestPass:         valuePtr[int64](10),
+					FeatureMetrics:   nil,
+				},
+				{
+					TimeStart:        time.Date(2000, time.January, 9, 0, 0, 0, 0, time.UTC),
+					FullRevisionHash: "def",
+					TotalTests:       valuePtr[int64](19),
+					TestPass:         valuePtr[int64](9),
+					FeatureMetrics:   nil,
+				},
+			},
+			pageToken: nonNilNextPageToken,
+			err:       nil,
+			expectedOutput: &backend.WPTInteropMetricsPage{
+				Metadata: &backend.PageMetadata{
+					NextPageToken: nonNilNextPageToken,
+				},
+				Data: []backend.WPTRunMetric{
+					{
+						RunTimestamp:    time.Date(2000, time.January, 10, 0, 0, 0, 0, time.UTC),
+						TotalTestsCount: valuePtr[int64](20),
+						TestPassCount:   valuePtr[int64](10),
+					},
+					{
+						RunTimestamp:    time.Date(2000, time.January, 9, 0, 0, 0, 0, time.UTC),
+						TotalTestsCount: valuePtr[int64](19),
+						TestPassCount:   valuePtr[int64](9),
+					},
+				},
+				Features: &[]backend.WPTFeatureRunMetrics{},
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "success with feature metrics",
+			interopData: []gcpspanner.WPTInteropMetricWithTime{
+				{
+					TimeStart:        time.Date(2000, time.January, 10, 0, 0, 0, 0, time.UTC),
+					FullRevisionHash: "abc",
+					TotalTests:       valuePtr[int64](20),
+					TestPass:         valuePtr[int64](10),
+					FeatureMetrics: []*gcpspanner.WPTInteropFeatureMetric{
+						{FeatureKey: "feature1", TotalTests: valuePtr[int64](15), TestPass: valuePtr[int64](5)},
+						{FeatureKey: "feature2", TotalTests: valuePtr[int64](5), TestPass: valuePtr[int64](5)},
+					},
+				},
+				{
+					TimeStart:        time.Date(2000, time.January, 9, 0, 0, 0, 0, time.UTC),
+					FullRevisionHash: "def",
+					TotalTests:       valuePtr[int64](19),
+					TestPass:         valuePtr[int64](9),
+					FeatureMetrics: []*gcpspanner.WPTInteropFeatureMetric{
+						{FeatureKey: "feature2", TotalTests: valuePtr[int64](19), TestPass: valuePtr[int64](9)},
+					},
+				},
+			},
+			pageToken: nil,
+			err:       nil,
+			expectedOutput: &backend.WPTInteropMetricsPage{
+				Metadata: &backend.PageMetadata{
+					NextPageToken: nil,
+				},
+				Data: []backend.WPTRunMetric{
+					{
+						RunTimestamp:    time.Date(2000, time.January, 10, 0, 0, 0, 0, time.UTC),
+						TotalTestsCount: valuePtr[int64](20),
+						TestPassCount:   valuePtr[int64](10),
+					},
+					{
+						RunTimestamp:    time.Date(2000, time.January, 9, 0, 0, 0, 0, time.UTC),
+						TotalTestsCount: valuePtr[int64](19),
+						TestPassCount:   valuePtr[int64](9),
+					},
+				},
+				Features: &[]backend.WPTFeatureRunMetrics{
+					{
+						FeatureId: "feature1",
+						Data: []backend.WPTRunMetric{
+							{
+								RunTimestamp:    time.Date(2000, time.January, 10, 0, 0, 0, 0, time.UTC),
+								TotalTestsCount: valuePtr[int64](15),
+								TestPassCount:   valuePtr[int64](5),
+							},
+						},
+					},
+					{
+						FeatureId: "feature2",
+						Data: []backend.WPTRunMetric{
+							{
+								RunTimestamp:    time.Date(2000, time.January, 10, 0, 0, 0, 0, time.UTC),
+								TotalTestsCount: valuePtr[int64](5),
+								TestPassCount:   valuePtr[int64](5),
+							},
+							{
+								RunTimestamp:    time.Date(2000, time.January, 9, 0, 0, 0, 0, time.UTC),
+								TotalTestsCount: valuePtr[int64](19),
+								TestPassCount:   valuePtr[int64](9),
+							},
+						},
+					},
+				},
+			},
+			expectedErr: nil,
+		},
+		{
+			name:           "failure",
+			interopData:    nil,
+			pageToken:      nil,
+			err:            errTest,
+			expectedOutput: nil,
+			expectedErr:    errTest,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			//nolint: exhaustruct
+			mock := mockBackendSpannerClient{
+				t:           t,
+				interopData: tc.interopData,
+				pageToken:   tc.pageToken,
+				err:         tc.err,
+			}
+			b := NewBackend(mock)
+			page, err := b.ListWPTInteropMetrics(
+				context.Background(),
+				[]string{"feature1", "feature2"},
+				[]backend.BrowserPathParam{backend.Chrome, backend.Firefox},
+				"channel",
+				backend.TestCounts,
+				testStart,
+				testEnd,
+				100,
+				nonNilInputPageToken)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Error("unexpected error")
+			}
+
+			if !reflect.DeepEqual(page, tc.expectedOutput) {
+				t.Errorf("unexpected page.\nexpected %+v\nreceived %+v", tc.expectedOutput, page)
+			}
+		})
+	}
+}
+
 func TestConvertBaselineStatusBackendToSpanner(t *testing.T) {
 	var backendToSpannerTests = []struct {
 		name     string
@@ -583,6 +1026,8 @@ func TestFeaturesSearch(t *testing.T) {
 		inputPageSize      int
 		inputWPTMetricView backend.WPTMetricView
 		inputBrowsers      BrowserList
+		inputAsOf          *time.Time
+		inputFacets        []string
 		searchNode         *searchtypes.SearchNode
 		sortOrder          *backend.GetV1FeaturesParamsSort
 		expectedPage       *backend.FeaturePage
@@ -604,7 +1049,22 @@ func TestFeaturesSearch(t *testing.T) {
 					"browser2",
 					"browser3",
 				},
+				expectedAsOf: nil,
+				expectedFacets: []gcpspanner.FeatureSearchFacet{
+					gcpspanner.BaselineStatusFacet,
+					gcpspanner.AvailableOnFacet,
+				},
 				result: &gcpspanner.FeatureResultPage{
+					Facets: &gcpspanner.FeatureSearchFacets{
+						BaselineStatus: []gcpspanner.FacetValueCount{
+							{Value: "high", Count: 3},
+							{Value: "low", Count: 2},
+							{Value: "none", Count: 1},
+						},
+						AvailableOn: []gcpspanner.FacetValueCount{
+							{Value: "browser1", Count: 5},
+						},
+					},
 					Total:         100,
 					NextPageToken: nonNilNextPageToken,
 					Features: []gcpspanner.FeatureResult{
@@ -707,11 +1167,23 @@ func TestFeaturesSearch(t *testing.T) {
 				"browser2",
 				"browser3",
 			},
+			inputAsOf:   nil,
+			inputFacets: []string{"baseline_status", "available_on"},
 			expectedPage: &backend.FeaturePage{
 				Metadata: backend.PageMetadataWithTotal{
 					NextPageToken: nonNilNextPageToken,
 					Total:         100,
 				},
+				Facets: &backend.FeatureSearchFacets{
+					BaselineStatus: &[]backend.FacetValueCount{
+						{Value: "widely", Count: 3},
+						{Value: "newly", Count: 2},
+						{Value: "limited", Count: 1},
+					},
+					AvailableOn: &[]backend.FacetValueCount{
+						{Value: "browser1", Count: 5},
+					},
+				},
 				Data: []backend.Feature{
 					{
 						Baseline: &backend.BaselineInfo{
@@ -739,6 +1211,7 @@ func TestFeaturesSearch(t *testing.T) {
 								},
 							},
 						},
+						RedirectedFrom: nil,
 						BrowserImplementations: &map[string]backend.BrowserImplementation{
 							"browser3": {
 								Status: valuePtr(backend.Available),
@@ -794,6 +1267,7 @@ func TestFeaturesSearch(t *testing.T) {
 								},
 							},
 						},
+						RedirectedFrom: nil,
 						BrowserImplementations: &map[string]backend.BrowserImplementation{
 							"browser1": {
 								Status: valuePtr(backend.Available),
@@ -826,7 +1300,9 @@ func TestFeaturesSearch(t *testing.T) {
 				tc.searchNode,
 				tc.sortOrder,
 				tc.inputWPTMetricView,
-				tc.inputBrowsers)
+				tc.inputBrowsers,
+				tc.inputAsOf,
+				tc.inputFacets)
 			if !errors.Is(err, tc.cfg.returnedError) {
 				t.Error("unexpected error")
 			}
@@ -844,7 +1320,8 @@ func CompareFeatures(f1, f2 backend.Feature) bool {
 	// 1. Basic Equality Checks
 	if f1.FeatureId != f2.FeatureId ||
 		f1.Name != f2.Name ||
-		f1.Usage != f2.Usage {
+		f1.Usage != f2.Usage ||
+		!reflect.DeepEqual(f1.RedirectedFrom, f2.RedirectedFrom) {
 		return false
 	}
 
@@ -933,6 +1410,7 @@ func TestGetFeature(t *testing.T) {
 		inputFeatureID     string
 		inputWPTMetricView backend.WPTMetricView
 		inputBrowsers      BrowserList
+		inputAsOf          *time.Time
 		expectedFeature    *backend.Feature
 	}{
 		{
@@ -944,14 +1422,16 @@ func TestGetFeature(t *testing.T) {
 				"browser2",
 				"browser3",
 			},
+			inputAsOf: valuePtr(testEnd),
 			cfg: mockGetFeatureConfig{
-				expectedFilterable:    gcpspanner.NewFeatureKeyFilter("feature1"),
+				expectedFilterable:    gcpspanner.NewFeatureKeyOrAliasFilter("feature1"),
 				expectedWPTMetricView: gcpspanner.WPTSubtestView,
 				expectedBrowsers: []string{
 					"browser1",
 					"browser2",
 					"browser3",
 				},
+				expectedAsOf: valuePtr(testEnd),
 				result: &gcpspanner.FeatureResult{
 					Name:       "feature 1",
 					FeatureKey: "feature1",
@@ -1024,6 +1504,42 @@ func TestGetFeature(t *testing.T) {
 						},
 					},
 				},
+				RedirectedFrom:         nil,
+				BrowserImplementations: nil,
+			},
+		},
+		{
+			name:               "alias of renamed feature",
+			inputFeatureID:     "old-feature1",
+			inputWPTMetricView: backend.SubtestCounts,
+			inputBrowsers:      []backend.BrowserPathParam{"browser1"},
+			inputAsOf:          nil,
+			cfg: mockGetFeatureConfig{
+				expectedFilterable:    gcpspanner.NewFeatureKeyOrAliasFilter("old-feature1"),
+				expectedWPTMetricView: gcpspanner.WPTSubtestView,
+				expectedBrowsers:      []string{"browser1"},
+				expectedAsOf:          nil,
+				result: &gcpspanner.FeatureResult{
+					Name:                   "feature 1",
+					FeatureKey:             "feature1",
+					Status:                 nil,
+					LowDate:                nil,
+					HighDate:               nil,
+					StableMetrics:          nil,
+					ExperimentalMetrics:    nil,
+					ImplementationStatuses: nil,
+					SpecLinks:              nil,
+				},
+				returnedError: nil,
+			},
+			expectedFeature: &backend.Feature{
+				Baseline:               nil,
+				FeatureId:              "feature1",
+				Name:                   "feature 1",
+				Spec:                   nil,
+				Usage:                  nil,
+				Wpt:                    nil,
+				RedirectedFrom:         valuePtr("old-feature1"),
 				BrowserImplementations: nil,
 			},
 		},
@@ -1038,7 +1554,7 @@ func TestGetFeature(t *testing.T) {
 			bk := NewBackend(mock)
 			feature, err := bk.GetFeature(
 				context.Background(),
-				tc.inputFeatureID, tc.inputWPTMetricView, tc.inputBrowsers)
+				tc.inputFeatureID, tc.inputWPTMetricView, tc.inputBrowsers, tc.inputAsOf)
 			if !errors.Is(err, tc.cfg.returnedError) {
 				t.Error("unexpected error")
 			}
@@ -1051,15 +1567,613 @@ func TestGetFeature(t *testing.T) {
 	}
 }
 
-func TestGetFeatureSearchSortOrder(t *testing.T) {
-	sortOrderTests := []struct {
-		input *backend.GetV1FeaturesParamsSort
-		want  gcpspanner.Sortable
-	}{
-		{input: nil, want: gcpspanner.NewBaselineStatusSort(false)},
-		{
-			input: valuePtr[backend.GetV1FeaturesParamsSort](backend.NameAsc),
-			want:  gcpspanner.NewFeatureNameSort(true),
+func TestGetIDsFromFeatureKeys(t *testing.T) {
+	//nolint: exhaustruct
+	mock := mockBackendSpannerClient{
+		t: t,
+		mockGetIDsFromFeatureKeysCfg: mockGetIDsFromFeatureKeysConfig{
+			expectedFeatureKeys: []string{"grid", "subgrid"},
+			result:              map[string]string{"grid": "id1"},
+			returnedError:       nil,
+		},
+	}
+	bk := NewBackend(mock)
+	ids, err := bk.GetIDsFromFeatureKeys(context.Background(), []string{"grid", "subgrid"})
+	if err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if !reflect.DeepEqual(ids, map[string]string{"grid": "id1"}) {
+		t.Errorf("unexpected ids %v", ids)
+	}
+}
+
+func TestGetFeatures(t *testing.T) {
+	//nolint: exhaustruct
+	mock := mockBackendSpannerClient{
+		t: t,
+		mockGetFeaturesCfg: mockGetFeaturesConfig{
+			expectedAliases:       []string{"feature1", "old-feature3", "feature2"},
+			aliases:               map[string]string{"old-feature3": "feature3"},
+			expectedFeatureKeys:   []string{"feature1", "feature3", "feature2"},
+			expectedWPTMetricView: gcpspanner.WPTSubtestView,
+			expectedBrowsers:      []string{"browser1"},
+			result: []gcpspanner.FeatureResult{
+				{
+					Name:                   "feature 1",
+					FeatureKey:             "feature1",
+					Status:                 valuePtr("low"),
+					LowDate:                nil,
+					HighDate:               nil,
+					StableMetrics:          nil,
+					ExperimentalMetrics:    nil,
+					ImplementationStatuses: nil,
+					SpecLinks:              nil,
+				},
+				{
+					Name:                   "feature 3",
+					FeatureKey:             "feature3",
+					Status:                 nil,
+					LowDate:                nil,
+					HighDate:               nil,
+					StableMetrics:          nil,
+					ExperimentalMetrics:    nil,
+					ImplementationStatuses: nil,
+					SpecLinks:              nil,
+				},
+			},
+			returnedError: nil,
+		},
+	}
+	bk := NewBackend(mock)
+	features, err := bk.GetFeatures(context.Background(), []string{"feature1", "old-feature3", "feature2"},
+		backend.SubtestCounts, []backend.BrowserPathParam{"browser1"})
+	if err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if len(features) != 2 || features["feature1"] == nil || features["old-feature3"] == nil {
+		t.Fatalf("unexpected features %v", features)
+	}
+	if features["feature1"].FeatureId != "feature1" || features["feature1"].Name != "feature 1" ||
+		features["feature1"].RedirectedFrom != nil {
+		t.Errorf("unexpected feature %+v", features["feature1"])
+	}
+	// Aliases resolve to the renamed feature.
+	if features["old-feature3"].FeatureId != "feature3" || features["old-feature3"].RedirectedFrom == nil ||
+		*features["old-feature3"].RedirectedFrom != "old-feature3" {
+		t.Errorf("unexpected feature %+v", features["old-feature3"])
+	}
+}
+
+func TestGetFeatureTimeline(t *testing.T) {
+	testCases := []struct {
+		name               string
+		cfg                mockGetFeatureTimelineConfig
+		inputFeatureID     string
+		inputWPTMetricView backend.WPTMetricView
+		expectedTimeline   *backend.FeatureTimeline
+		expectedErr        error
+	}{
+		{
+			name:               "merges events in chronological order",
+			inputFeatureID:     "feature1",
+			inputWPTMetricView: backend.TestCounts,
+			cfg: mockGetFeatureTimelineConfig{
+				expectedFeatureKey:    "feature1",
+				expectedWPTMetricView: gcpspanner.WPTTestView,
+				result: &gcpspanner.FeatureTimeline{
+					BrowserAvailabilities: []gcpspanner.FeatureTimelineBrowserAvailability{
+						{
+							BrowserName:    "browser1",
+							BrowserVersion: "100",
+							ReleaseDate:    time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+						},
+						{
+							BrowserName:    "browser2",
+							BrowserVersion: "10",
+							ReleaseDate:    time.Date(2000, time.March, 1, 0, 0, 0, 0, time.UTC),
+						},
+					},
+					Baseline: &gcpspanner.FeatureBaselineStatus{
+						Status:   valuePtr(gcpspanner.BaselineStatusLow),
+						LowDate:  valuePtr(time.Date(2000, time.March, 1, 0, 0, 0, 0, time.UTC)),
+						HighDate: nil,
+					},
+					WPTMilestones: []gcpspanner.FeatureTimelineWPTMilestone{
+						{
+							BrowserName: "browser1",
+							Channel:     "stable",
+							Threshold:   big.NewRat(1, 2),
+							TimeStart:   time.Date(2000, time.February, 1, 0, 0, 0, 0, time.UTC),
+						},
+					},
+				},
+				returnedError: nil,
+			},
+			expectedTimeline: &backend.FeatureTimeline{
+				FeatureId: "feature1",
+				Events: []backend.FeatureTimelineEvent{
+					{
+						Timestamp:         time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+						Type:              backend.BrowserAvailable,
+						Browser:           valuePtr("browser1"),
+						BrowserVersion:    valuePtr("100"),
+						Channel:           nil,
+						PassRateThreshold: nil,
+					},
+					{
+						Timestamp:         time.Date(2000, time.February, 1, 0, 0, 0, 0, time.UTC),
+						Type:              backend.WPTMilestone,
+						Browser:           valuePtr("browser1"),
+						BrowserVersion:    nil,
+						Channel:           valuePtr("stable"),
+						PassRateThreshold: valuePtr[float64](0.5),
+					},
+					{
+						Timestamp:         time.Date(2000, time.March, 1, 0, 0, 0, 0, time.UTC),
+						Type:              backend.BrowserAvailable,
+						Browser:           valuePtr("browser2"),
+						BrowserVersion:    valuePtr("10"),
+						Channel:           nil,
+						PassRateThreshold: nil,
+					},
+					{
+						Timestamp:         time.Date(2000, time.March, 1, 0, 0, 0, 0, time.UTC),
+						Type:              backend.BaselineLow,
+						Browser:           nil,
+						BrowserVersion:    nil,
+						Channel:           nil,
+						PassRateThreshold: nil,
+					},
+				},
+			},
+			expectedErr: nil,
+		},
+		{
+			name:               "error",
+			inputFeatureID:     "feature1",
+			inputWPTMetricView: backend.SubtestCounts,
+			cfg: mockGetFeatureTimelineConfig{
+				expectedFeatureKey:    "feature1",
+				expectedWPTMetricView: gcpspanner.WPTSubtestView,
+				result:                nil,
+				returnedError:         errTest,
+			},
+			expectedTimeline: nil,
+			expectedErr:      errTest,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			//nolint: exhaustruct
+			mock := mockBackendSpannerClient{
+				t:                         t,
+				mockGetFeatureTimelineCfg: tc.cfg,
+			}
+			bk := NewBackend(mock)
+			timeline, err := bk.GetFeatureTimeline(context.Background(), tc.inputFeatureID, tc.inputWPTMetricView)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("unexpected error %v", err)
+			}
+			if !reflect.DeepEqual(timeline, tc.expectedTimeline) {
+				t.Errorf("unexpected timeline.\nexpected %+v\nreceived %+v", tc.expectedTimeline, timeline)
+			}
+		})
+	}
+}
+
+func TestGetFeatureCompatFeatures(t *testing.T) {
+	testCases := []struct {
+		name                   string
+		cfg                    mockGetFeatureCompatFeaturesConfig
+		expectedCompatFeatures *backend.FeatureCompatFeatures
+		expectedErr            error
+	}{
+		{
+			name: "success",
+			cfg: mockGetFeatureCompatFeaturesConfig{
+				expectedFeatureKey: "feature1",
+				result: []gcpspanner.FeatureCompatFeature{
+					{
+						CompatFeature: "api.Feature1",
+						BrowserSupport: []*gcpspanner.CompatFeatureBrowserSupport{
+							{BrowserName: "chrome", VersionAdded: valuePtr("100")},
+							{BrowserName: "safari", VersionAdded: nil},
+						},
+					},
+					{
+						CompatFeature:  "api.Feature1.sub",
+						BrowserSupport: []*gcpspanner.CompatFeatureBrowserSupport{},
+					},
+				},
+				returnedError: nil,
+			},
+			expectedCompatFeatures: &backend.FeatureCompatFeatures{
+				FeatureId: "feature1",
+				CompatFeatures: []backend.FeatureCompatFeature{
+					{
+						CompatFeature: "api.Feature1",
+						BrowserSupport: []backend.CompatFeatureBrowserSupport{
+							{Browser: "chrome", VersionAdded: valuePtr("100")},
+							{Browser: "safari", VersionAdded: nil},
+						},
+					},
+					{
+						CompatFeature:  "api.Feature1.sub",
+						BrowserSupport: []backend.CompatFeatureBrowserSupport{},
+					},
+				},
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "error",
+			cfg: mockGetFeatureCompatFeaturesConfig{
+				expectedFeatureKey: "feature1",
+				result:             nil,
+				returnedError:      errTest,
+			},
+			expectedCompatFeatures: nil,
+			expectedErr:            errTest,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			//nolint: exhaustruct
+			mock := mockBackendSpannerClient{
+				t:                               t,
+				mockGetFeatureCompatFeaturesCfg: tc.cfg,
+			}
+			bk := NewBackend(mock)
+			compatFeatures, err := bk.GetFeatureCompatFeatures(context.Background(), "feature1")
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("unexpected error %v", err)
+			}
+			if !reflect.DeepEqual(compatFeatures, tc.expectedCompatFeatures) {
+				t.Errorf("unexpected compat features.\nexpected %+v\nreceived %+v",
+					tc.expectedCompatFeatures, compatFeatures)
+			}
+		})
+	}
+}
+
+func TestListFeatureTimeToBaseline(t *testing.T) {
+	searchNode := &searchtypes.SearchNode{
+		Keyword: searchtypes.KeywordRoot,
+		Term:    nil,
+		Children: []*searchtypes.SearchNode{
+			{
+				Keyword:  searchtypes.KeywordNone,
+				Children: nil,
+				Term: &searchtypes.SearchTerm{
+					Identifier: searchtypes.IdentifierBaselineStatus,
+					Value:      "widely",
+					Operator:   searchtypes.OperatorEq,
+				},
+			},
+		},
+	}
+	testCases := []struct {
+		name          string
+		cfg           mockListFeatureTimeToBaselineConfig
+		expectedStats *backend.TimeToBaselineStats
+		expectedErr   error
+	}{
+		{
+			name: "success",
+			cfg: mockListFeatureTimeToBaselineConfig{
+				expectedSearchNode: searchNode,
+				result: []gcpspanner.FeatureTimeToBaseline{
+					{
+						FeatureKey:         "feature1",
+						Name:               "Feature 1",
+						FirstAvailableDate: valuePtr(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)),
+						LowDate:            valuePtr(time.Date(2000, time.January, 11, 0, 0, 0, 0, time.UTC)),
+						HighDate:           valuePtr(time.Date(2002, time.July, 10, 0, 0, 0, 0, time.UTC)),
+						DaysToLow:          valuePtr[int64](10),
+						DaysLowToHigh:      valuePtr[int64](910),
+					},
+					{
+						FeatureKey:         "feature2",
+						Name:               "Feature 2",
+						FirstAvailableDate: valuePtr(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)),
+						LowDate:            valuePtr(time.Date(2000, time.January, 31, 0, 0, 0, 0, time.UTC)),
+						HighDate:           nil,
+						DaysToLow:          valuePtr[int64](30),
+						DaysLowToHigh:      nil,
+					},
+					{
+						FeatureKey:         "feature3",
+						Name:               "Feature 3",
+						FirstAvailableDate: nil,
+						LowDate:            nil,
+						HighDate:           nil,
+						DaysToLow:          nil,
+						DaysLowToHigh:      nil,
+					},
+				},
+				returnedError: nil,
+			},
+			expectedStats: &backend.TimeToBaselineStats{
+				Data: []backend.FeatureTimeToBaseline{
+					{
+						FeatureId: "feature1",
+						Name:      "Feature 1",
+						FirstAvailableDate: &openapi_types.Date{
+							Time: time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)},
+						BaselineLowDate: &openapi_types.Date{
+							Time: time.Date(2000, time.January, 11, 0, 0, 0, 0, time.UTC)},
+						BaselineHighDate: &openapi_types.Date{
+							Time: time.Date(2002, time.July, 10, 0, 0, 0, 0, time.UTC)},
+						DaysToBaselineLow:     valuePtr[int64](10),
+						DaysBaselineLowToHigh: valuePtr[int64](910),
+					},
+					{
+						FeatureId: "feature2",
+						Name:      "Feature 2",
+						FirstAvailableDate: &openapi_types.Date{
+							Time: time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)},
+						BaselineLowDate: &openapi_types.Date{
+							Time: time.Date(2000, time.January, 31, 0, 0, 0, 0, time.UTC)},
+						BaselineHighDate:      nil,
+						DaysToBaselineLow:     valuePtr[int64](30),
+						DaysBaselineLowToHigh: nil,
+					},
+					{
+						FeatureId:             "feature3",
+						Name:                  "Feature 3",
+						FirstAvailableDate:    nil,
+						BaselineLowDate:       nil,
+						BaselineHighDate:      nil,
+						DaysToBaselineLow:     nil,
+						DaysBaselineLowToHigh: nil,
+					},
+				},
+				DaysToBaselineLowPercentiles: []backend.TimeToBaselinePercentile{
+					{Percentile: 25, Days: 10},
+					{Percentile: 50, Days: 10},
+					{Percentile: 75, Days: 30},
+					{Percentile: 90, Days: 30},
+				},
+				DaysBaselineLowToHighPercentiles: []backend.TimeToBaselinePercentile{
+					{Percentile: 25, Days: 910},
+					{Percentile: 50, Days: 910},
+					{Percentile: 75, Days: 910},
+					{Percentile: 90, Days: 910},
+				},
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "error",
+			cfg: mockListFeatureTimeToBaselineConfig{
+				expectedSearchNode: searchNode,
+				result:             nil,
+				returnedError:      errTest,
+			},
+			expectedStats: nil,
+			expectedErr:   errTest,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			//nolint: exhaustruct
+			mock := mockBackendSpannerClient{
+				t:                                t,
+				mockListFeatureTimeToBaselineCfg: tc.cfg,
+			}
+			bk := NewBackend(mock)
+			stats, err := bk.ListFeatureTimeToBaseline(context.Background(), searchNode)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("unexpected error %v", err)
+			}
+			if !reflect.DeepEqual(stats, tc.expectedStats) {
+				t.Errorf("unexpected stats.\nexpected %+v\nreceived %+v", tc.expectedStats, stats)
+			}
+		})
+	}
+}
+
+func TestCalculateDayPercentiles(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    []int64
+		expected []backend.TimeToBaselinePercentile
+	}{
+		{
+			name:     "no values",
+			input:    nil,
+			expected: []backend.TimeToBaselinePercentile{},
+		},
+		{
+			name:  "unsorted values",
+			input: []int64{100, 20, 50, 10, 90, 30, 80, 40, 70, 60},
+			expected: []backend.TimeToBaselinePercentile{
+				{Percentile: 25, Days: 30},
+				{Percentile: 50, Days: 50},
+				{Percentile: 75, Days: 80},
+				{Percentile: 90, Days: 90},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			percentiles := calculateDayPercentiles(tc.input)
+			if !reflect.DeepEqual(percentiles, tc.expected) {
+				t.Errorf("unexpected percentiles.\nexpected %+v\nreceived %+v", tc.expected, percentiles)
+			}
+		})
+	}
+}
+
+func TestGetBrowserCompatMatrix(t *testing.T) {
+	testCases := []struct {
+		name           string
+		cfg            mockListBrowserCompatMatrixConfig
+		expectedMatrix *backend.BrowserCompatMatrix
+		expectedErr    error
+	}{
+		{
+			name: "success",
+			cfg: mockListBrowserCompatMatrixConfig{
+				expectedBrowsers: []string{"chrome", "firefox"},
+				expectedAt:       testStart,
+				result: []gcpspanner.BrowserPairFeatureGap{
+					{BrowserA: "chrome", BrowserB: "firefox", FeatureCount: 10},
+					{BrowserA: "firefox", BrowserB: "chrome", FeatureCount: 5},
+				},
+				returnedError: nil,
+			},
+			expectedMatrix: &backend.BrowserCompatMatrix{
+				Data: []backend.BrowserPairFeatureGap{
+					{BrowserA: "chrome", BrowserB: "firefox", Count: 10},
+					{BrowserA: "firefox", BrowserB: "chrome", Count: 5},
+				},
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "error",
+			cfg: mockListBrowserCompatMatrixConfig{
+				expectedBrowsers: []string{"chrome", "firefox"},
+				expectedAt:       testStart,
+				result:           nil,
+				returnedError:    errTest,
+			},
+			expectedMatrix: nil,
+			expectedErr:    errTest,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			//nolint: exhaustruct
+			mock := mockBackendSpannerClient{
+				t:                              t,
+				mockListBrowserCompatMatrixCfg: tc.cfg,
+			}
+			bk := NewBackend(mock)
+			matrix, err := bk.GetBrowserCompatMatrix(
+				context.Background(),
+				[]backend.BrowserPathParam{backend.Chrome, backend.Firefox},
+				testStart)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("unexpected error %v", err)
+			}
+			if !reflect.DeepEqual(matrix, tc.expectedMatrix) {
+				t.Errorf("unexpected matrix.\nexpected %+v\nreceived %+v", tc.expectedMatrix, matrix)
+			}
+		})
+	}
+}
+
+func TestGetLastModified(t *testing.T) {
+	latest := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name         string
+		cfg          mockGetLatestIngestionTimeConfig
+		expectedTime time.Time
+		expectedErr  error
+	}{
+		{
+			name: "success",
+			cfg: mockGetLatestIngestionTimeConfig{
+				result:        &latest,
+				returnedError: nil,
+			},
+			expectedTime: latest,
+			expectedErr:  nil,
+		},
+		{
+			name: "no data ingested",
+			cfg: mockGetLatestIngestionTimeConfig{
+				result:        nil,
+				returnedError: gcpspanner.ErrQueryReturnedNoResults,
+			},
+			expectedTime: time.Time{},
+			expectedErr:  gcpspanner.ErrQueryReturnedNoResults,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			//nolint: exhaustruct
+			mock := mockBackendSpannerClient{
+				t:                             t,
+				mockGetLatestIngestionTimeCfg: tc.cfg,
+			}
+			bk := NewBackend(mock)
+			lastModified, err := bk.GetLastModified(context.Background())
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("unexpected error %v", err)
+			}
+			if !lastModified.Equal(tc.expectedTime) {
+				t.Errorf("unexpected time. expected %s received %s", tc.expectedTime, lastModified)
+			}
+		})
+	}
+}
+
+func TestExcludedFeatureKeys(t *testing.T) {
+	createdAt := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	keys := []gcpspanner.ExcludedFeatureKey{
+		{
+			FeatureKey: "bogus",
+			Reason:     valuePtr("not a real feature"),
+			Author:     valuePtr("admin@example.com"),
+			CreatedAt:  &createdAt,
+		},
+		{FeatureKey: "manual", Reason: nil, Author: nil, CreatedAt: nil},
+	}
+	//nolint: exhaustruct
+	mock := mockBackendSpannerClient{
+		t: t,
+		mockExcludedFeatureKeysCfg: mockExcludedFeatureKeysConfig{
+			expectedFeatureKey: "bogus",
+			expectedReason:     "not a real feature",
+			expectedAuthor:     "admin@example.com",
+			keys:               keys,
+			returnedError:      nil,
+		},
+	}
+	bk := NewBackend(mock)
+
+	listed, err := bk.ListExcludedFeatureKeys(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	expected := []backend.ExcludedFeatureKey{
+		{
+			FeatureKey: "bogus",
+			Reason:     valuePtr("not a real feature"),
+			Author:     valuePtr("admin@example.com"),
+			CreatedAt:  &createdAt,
+		},
+		{FeatureKey: "manual", Reason: nil, Author: nil, CreatedAt: nil},
+	}
+	if !reflect.DeepEqual(listed, expected) {
+		t.Errorf("unexpected keys %+v", listed)
+	}
+
+	added, err := bk.AddExcludedFeatureKey(context.Background(), "bogus", "not a real feature", "admin@example.com")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !reflect.DeepEqual(*added, expected[0]) {
+		t.Errorf("unexpected added key %+v", added)
+	}
+
+	if err := bk.RemoveExcludedFeatureKey(context.Background(), "bogus", "admin@example.com"); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestGetFeatureSearchSortOrder(t *testing.T) {
+	sortOrderTests := []struct {
+		input *backend.GetV1FeaturesParamsSort
+		want  gcpspanner.Sortable
+	}{
+		{input: nil, want: gcpspanner.NewBaselineStatusSort(false)},
+		{
+			input: valuePtr[backend.GetV1FeaturesParamsSort](backend.NameAsc),
+			want:  gcpspanner.NewFeatureNameSort(true),
 		},
 		{
 			input: valuePtr[backend.GetV1FeaturesParamsSort](backend.NameDesc),
@@ -1137,6 +2251,62 @@ func TestGetFeatureSearchSortOrder(t *testing.T) {
 			input: valuePtr(backend.StableSafariDesc),
 			want:  gcpspanner.NewBrowserImplSort(false, "safari", true),
 		},
+		{
+			input: valuePtr(backend.BaselineLowDateAsc),
+			want:  gcpspanner.NewBaselineLowDateSort(true),
+		},
+		{
+			input: valuePtr(backend.BaselineLowDateDesc),
+			want:  gcpspanner.NewBaselineLowDateSort(false),
+		},
+		{
+			input: valuePtr(backend.BaselineHighDateAsc),
+			want:  gcpspanner.NewBaselineHighDateSort(true),
+		},
+		{
+			input: valuePtr(backend.BaselineHighDateDesc),
+			want:  gcpspanner.NewBaselineHighDateSort(false),
+		},
+		{
+			input: valuePtr(backend.AvailableDateChromeAsc),
+			want:  gcpspanner.NewBrowserAvailableDateSort(true, "chrome"),
+		},
+		{
+			input: valuePtr(backend.AvailableDateChromeDesc),
+			want:  gcpspanner.NewBrowserAvailableDateSort(false, "chrome"),
+		},
+		{
+			input: valuePtr(backend.AvailableDateEdgeAsc),
+			want:  gcpspanner.NewBrowserAvailableDateSort(true, "edge"),
+		},
+		{
+			input: valuePtr(backend.AvailableDateEdgeDesc),
+			want:  gcpspanner.NewBrowserAvailableDateSort(false, "edge"),
+		},
+		{
+			input: valuePtr(backend.AvailableDateFirefoxAsc),
+			want:  gcpspanner.NewBrowserAvailableDateSort(true, "firefox"),
+		},
+		{
+			input: valuePtr(backend.AvailableDateFirefoxDesc),
+			want:  gcpspanner.NewBrowserAvailableDateSort(false, "firefox"),
+		},
+		{
+			input: valuePtr(backend.AvailableDateSafariAsc),
+			want:  gcpspanner.NewBrowserAvailableDateSort(true, "safari"),
+		},
+		{
+			input: valuePtr(backend.AvailableDateSafariDesc),
+			want:  gcpspanner.NewBrowserAvailableDateSort(false, "safari"),
+		},
+		{
+			input: valuePtr(backend.SpecAsc),
+			want:  gcpspanner.NewSpecSort(true),
+		},
+		{
+			input: valuePtr(backend.SpecDesc),
+			want:  gcpspanner.NewSpecSort(false),
+		},
 	}
 
 	for _, tt := range sortOrderTests {
@@ -1192,6 +2362,7 @@ func TestConvertFeatureResult(t *testing.T) {
 				Spec:                   nil,
 				Usage:                  nil,
 				Wpt:                    nil,
+				RedirectedFrom:         nil,
 				BrowserImplementations: nil,
 			},
 		},
@@ -31,6 +31,7 @@ func NewBCDWorkflowConsumer(client BCDWorkflowSpannerClient) *BCDConsumer {
 // only apply to inserting BCD data.
 type BCDWorkflowSpannerClient interface {
 	InsertBrowserRelease(ctx context.Context, release gcpspanner.BrowserRelease) error
+	UpsertBrowserCompatFeatureSupports(ctx context.Context, supports []gcpspanner.BrowserCompatFeatureSupport) error
 }
 
 // BCDConsumer is the adapter that takes data from the BCD workflow and prepares
@@ -53,3 +54,21 @@ func (b *BCDConsumer) InsertBrowserReleases(ctx context.Context, releases []bcdc
 
 	return nil
 }
+
+func (b *BCDConsumer) UpsertCompatFeatureSupports(
+	ctx context.Context, supports []bcdconsumertypes.CompatFeatureSupport) error {
+	spannerSupports := make([]gcpspanner.BrowserCompatFeatureSupport, 0, len(supports))
+	for _, support := range supports {
+		spannerSupports = append(spannerSupports, gcpspanner.BrowserCompatFeatureSupport{
+			CompatFeature: support.CompatFeature,
+			BrowserName:   string(support.BrowserName),
+			VersionAdded:  support.VersionAdded,
+		})
+	}
+	err := b.client.UpsertBrowserCompatFeatureSupports(ctx, spannerSupports)
+	if err != nil {
+		return errors.Join(bcdconsumertypes.ErrUnableToStoreCompatFeatureSupport, err)
+	}
+
+	return nil
+}
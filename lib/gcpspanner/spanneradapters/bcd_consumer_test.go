@@ -17,6 +17,7 @@ package spanneradapters
 import (
 	"context"
 	"errors"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -26,7 +27,9 @@ import (
 )
 
 type MockSpannerClient struct {
-	callHistory []gcpspanner.BrowserRelease
+	callHistory        []gcpspanner.BrowserRelease
+	supportCallHistory [][]gcpspanner.BrowserCompatFeatureSupport
+	supportErr         error
 }
 
 func getFailureRelease() bcdconsumertypes.BrowserRelease {
@@ -47,6 +50,13 @@ func (m *MockSpannerClient) InsertBrowserRelease(_ context.Context, release gcps
 	return nil
 }
 
+func (m *MockSpannerClient) UpsertBrowserCompatFeatureSupports(
+	_ context.Context, supports []gcpspanner.BrowserCompatFeatureSupport) error {
+	m.supportCallHistory = append(m.supportCallHistory, supports)
+
+	return m.supportErr
+}
+
 func TestInsertBrowserReleases(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -81,7 +91,9 @@ func TestInsertBrowserReleases(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			mockClient := &MockSpannerClient{
-				callHistory: []gcpspanner.BrowserRelease{},
+				callHistory:        []gcpspanner.BrowserRelease{},
+				supportCallHistory: nil,
+				supportErr:         nil,
 			}
 			consumer := NewBCDWorkflowConsumer(mockClient)
 
@@ -108,3 +120,68 @@ func compareReleases(r1 bcdconsumertypes.BrowserRelease, r2 gcpspanner.BrowserRe
 		r1.BrowserVersion == r2.BrowserVersion &&
 		r1.ReleaseDate.Equal(r2.ReleaseDate)
 }
+
+func TestUpsertCompatFeatureSupports(t *testing.T) {
+	supports := []bcdconsumertypes.CompatFeatureSupport{
+		{
+			CompatFeature: "api.AbortController",
+			BrowserName:   bcdconsumertypes.Chrome,
+			VersionAdded:  valuePtr("66"),
+		},
+		{
+			CompatFeature: "api.AbortController",
+			BrowserName:   bcdconsumertypes.Safari,
+			VersionAdded:  nil,
+		},
+	}
+	testCases := []struct {
+		name          string
+		clientErr     error
+		expectedError error
+	}{
+		{
+			name:          "Success",
+			clientErr:     nil,
+			expectedError: nil,
+		},
+		{
+			name:          "Unable to store support",
+			clientErr:     errors.New("Simulated Spanner error"),
+			expectedError: bcdconsumertypes.ErrUnableToStoreCompatFeatureSupport,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := &MockSpannerClient{
+				callHistory:        nil,
+				supportCallHistory: nil,
+				supportErr:         tc.clientErr,
+			}
+			consumer := NewBCDWorkflowConsumer(mockClient)
+
+			err := consumer.UpsertCompatFeatureSupports(context.Background(), supports)
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("Unexpected error: %v", err)
+			}
+
+			expected := [][]gcpspanner.BrowserCompatFeatureSupport{
+				{
+					{
+						CompatFeature: "api.AbortController",
+						BrowserName:   "chrome",
+						VersionAdded:  valuePtr("66"),
+					},
+					{
+						CompatFeature: "api.AbortController",
+						BrowserName:   "safari",
+						VersionAdded:  nil,
+					},
+				},
+			}
+			if !reflect.DeepEqual(expected, mockClient.supportCallHistory) {
+				t.Errorf("Call argument mismatch. Expected: %v, got: %v", expected, mockClient.supportCallHistory)
+			}
+		})
+	}
+}
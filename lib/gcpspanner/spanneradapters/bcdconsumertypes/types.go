@@ -27,6 +27,15 @@ type BrowserRelease struct {
 	ReleaseDate    time.Time
 }
 
+// CompatFeatureSupport is the version of a browser that added support for a BCD key.
+// BCD keys are the dotted paths to the compat data in the data.json. For example: api.AbortController.abort.
+type CompatFeatureSupport struct {
+	CompatFeature string
+	BrowserName   BrowserName
+	// VersionAdded is nil if the browser does not fully support the BCD key.
+	VersionAdded *string
+}
+
 // BrowserName is an enumeration of the high-level keys found in the data.json
 // for the browsers. The json schema itself does not list these names explicitly
 // so we maintain our own subset here.
@@ -42,3 +51,7 @@ const (
 // ErrUnableToStoreBrowserRelease indicates that the storage layer was unable to save
 // the browser release.
 var ErrUnableToStoreBrowserRelease = errors.New("unable to store browser release")
+
+// ErrUnableToStoreCompatFeatureSupport indicates that the storage layer was unable to save
+// the browser support of the BCD keys.
+var ErrUnableToStoreCompatFeatureSupport = errors.New("unable to store compat feature support")
@@ -32,6 +32,8 @@ type WebFeatureSpannerClient interface {
 		featureID string,
 		featureAvailability gcpspanner.BrowserFeatureAvailability) error
 	UpsertFeatureSpec(ctx context.Context, webFeatureID string, input gcpspanner.FeatureSpec) error
+	UpsertFeatureAlias(ctx context.Context, featureKey string, alias string) error
+	UpsertFeatureCompatFeatures(ctx context.Context, featureKey string, compatFeatures []string) error
 }
 
 // NewWebFeaturesConsumer constructs an adapter for the web features consumer service.
@@ -96,6 +98,25 @@ func (c *WebFeaturesConsumer) InsertWebFeatures(
 			return nil, err
 		}
 
+		// Read the previous keys of the feature.
+		err = consumeFeatureAliases(ctx, c.client, featureID, featureData)
+		if err != nil {
+			return nil, err
+		}
+
+		// Read the BCD keys of the feature.
+		// Always store them so that keys removed from the feature are removed.
+		err = c.client.UpsertFeatureCompatFeatures(ctx, featureID, featureData.CompatFeatures)
+		if err != nil {
+			slog.ErrorContext(ctx, "unable to insert FeatureCompatFeatures",
+				"compatFeatures", featureData.CompatFeatures,
+				"featureID", featureID,
+				"error", err,
+			)
+
+			return nil, err
+		}
+
 		ret[featureID] = *id
 	}
 
@@ -138,6 +159,40 @@ func consumeFeatureSpecInformation(ctx context.Context,
 
 }
 
+// consumeFeatureAliases stores the previous keys of a renamed feature so that they resolve to the feature.
+// Aliases that are removed from the web features repo are kept so that old links keep working.
+func consumeFeatureAliases(ctx context.Context,
+	client WebFeatureSpannerClient,
+	featureID string,
+	featureData web_platform_dx__web_features.FeatureData) error {
+	if featureData.Alias == nil {
+		return nil
+	}
+
+	var aliases []string
+	if featureData.Alias.String != nil {
+		aliases = []string{*featureData.Alias.String}
+	} else if len(featureData.Alias.StringArray) > 0 {
+		aliases = featureData.Alias.StringArray
+	}
+
+	for _, alias := range aliases {
+		err := client.UpsertFeatureAlias(ctx, featureID, alias)
+		if err != nil {
+			slog.ErrorContext(ctx,
+				"unable to insert FeatureAlias",
+				"alias", alias,
+				"featureID", featureID,
+				"error", err,
+			)
+
+			return err
+		}
+	}
+
+	return nil
+}
+
 func extractBrowserAvailability(
 	featureData web_platform_dx__web_features.FeatureData) []gcpspanner.BrowserFeatureAvailability {
 	var fba []gcpspanner.BrowserFeatureAvailability
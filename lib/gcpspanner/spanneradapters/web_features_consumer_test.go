@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"reflect"
+	"slices"
 	"testing"
 	"time"
 
@@ -159,6 +160,19 @@ type mockUpsertFeatureSpecConfig struct {
 	expectedCount  int
 }
 
+type mockUpsertFeatureAliasConfig struct {
+	// expectedInputs maps each feature key to its expected aliases.
+	expectedInputs map[string][]string
+	outputs        map[string]error
+	expectedCount  int
+}
+
+type mockUpsertFeatureCompatFeaturesConfig struct {
+	expectedInputs map[string][]string
+	outputs        map[string]error
+	expectedCount  int
+}
+
 type mockWebFeatureSpannerClient struct {
 	t                                               *testing.T
 	upsertWebFeatureCount                           int
@@ -169,6 +183,10 @@ type mockWebFeatureSpannerClient struct {
 	mockInsertBrowserFeatureAvailabilityCfg         mockInsertBrowserFeatureAvailabilityConfig
 	mockUpsertFeatureSpecCfg                        mockUpsertFeatureSpecConfig
 	upsertFeatureSpecCount                          int
+	mockUpsertFeatureAliasCfg                       mockUpsertFeatureAliasConfig
+	upsertFeatureAliasCount                         int
+	mockUpsertFeatureCompatFeaturesCfg              mockUpsertFeatureCompatFeaturesConfig
+	upsertFeatureCompatFeaturesCount                int
 }
 
 func (c *mockWebFeatureSpannerClient) UpsertWebFeature(
@@ -231,6 +249,33 @@ func (c *mockWebFeatureSpannerClient) UpsertFeatureSpec(
 	return c.mockUpsertFeatureSpecCfg.outputs[featureID]
 }
 
+func (c *mockWebFeatureSpannerClient) UpsertFeatureAlias(
+	_ context.Context, featureID string, alias string) error {
+	if !slices.Contains(c.mockUpsertFeatureAliasCfg.expectedInputs[featureID], alias) {
+		c.t.Errorf("unexpected alias %s for feature %s", alias, featureID)
+	}
+	c.upsertFeatureAliasCount++
+
+	return c.mockUpsertFeatureAliasCfg.outputs[featureID]
+}
+
+func (c *mockWebFeatureSpannerClient) UpsertFeatureCompatFeatures(
+	_ context.Context, featureID string, compatFeatures []string) error {
+	if len(c.mockUpsertFeatureCompatFeaturesCfg.expectedInputs) <= c.upsertFeatureCompatFeaturesCount {
+		c.t.Fatal("no more expected input for UpsertFeatureCompatFeatures")
+	}
+	expectedInput, found := c.mockUpsertFeatureCompatFeaturesCfg.expectedInputs[featureID]
+	if !found {
+		c.t.Errorf("unexpected input %v", compatFeatures)
+	}
+	if !reflect.DeepEqual(expectedInput, compatFeatures) {
+		c.t.Errorf("unexpected input expected %v received %v", expectedInput, compatFeatures)
+	}
+	c.upsertFeatureCompatFeaturesCount++
+
+	return c.mockUpsertFeatureCompatFeaturesCfg.outputs[featureID]
+}
+
 func (c *mockWebFeatureSpannerClient) InsertBrowserFeatureAvailability(
 	_ context.Context, featureID string, featureAvailability gcpspanner.BrowserFeatureAvailability) error {
 	expectedCountForFeature := c.insertBrowserFeatureAvailabilityCountPerFeature[featureID]
@@ -266,16 +311,22 @@ func newMockmockWebFeatureSpannerClient(
 	mockUpsertFeatureBaselineStatusCfg mockUpsertFeatureBaselineStatusConfig,
 	mockInsertBrowserFeatureAvailabilityCfg mockInsertBrowserFeatureAvailabilityConfig,
 	mockUpsertFeatureSpecCfg mockUpsertFeatureSpecConfig,
+	mockUpsertFeatureAliasCfg mockUpsertFeatureAliasConfig,
+	mockUpsertFeatureCompatFeaturesCfg mockUpsertFeatureCompatFeaturesConfig,
 ) *mockWebFeatureSpannerClient {
 	return &mockWebFeatureSpannerClient{
-		t:                                       t,
-		mockUpsertWebFeatureCfg:                 mockUpsertWebFeatureCfg,
-		mockUpsertFeatureBaselineStatusCfg:      mockUpsertFeatureBaselineStatusCfg,
-		mockInsertBrowserFeatureAvailabilityCfg: mockInsertBrowserFeatureAvailabilityCfg,
-		mockUpsertFeatureSpecCfg:                mockUpsertFeatureSpecCfg,
-		upsertWebFeatureCount:                   0,
-		upsertFeatureBaselineStatusCount:        0,
-		upsertFeatureSpecCount:                  0,
+		t:                                               t,
+		mockUpsertWebFeatureCfg:                         mockUpsertWebFeatureCfg,
+		mockUpsertFeatureBaselineStatusCfg:              mockUpsertFeatureBaselineStatusCfg,
+		mockInsertBrowserFeatureAvailabilityCfg:         mockInsertBrowserFeatureAvailabilityCfg,
+		mockUpsertFeatureSpecCfg:                        mockUpsertFeatureSpecCfg,
+		mockUpsertFeatureAliasCfg:                       mockUpsertFeatureAliasCfg,
+		mockUpsertFeatureCompatFeaturesCfg:              mockUpsertFeatureCompatFeaturesCfg,
+		upsertWebFeatureCount:                           0,
+		upsertFeatureBaselineStatusCount:                0,
+		upsertFeatureSpecCount:                          0,
+		upsertFeatureAliasCount:                         0,
+		upsertFeatureCompatFeaturesCount:                0,
 		insertBrowserFeatureAvailabilityCountPerFeature: map[string]int{},
 	}
 }
@@ -284,6 +335,8 @@ var ErrWebFeatureTest = errors.New("web feature test error")
 var ErrBaselineStatusTest = errors.New("baseline status test error")
 var ErrBrowserFeatureAvailabilityTest = errors.New("browser feature availability test error")
 var ErrFeatureSpecTest = errors.New("feature spec test error")
+var ErrFeatureAliasTest = errors.New("feature alias test error")
+var ErrFeatureCompatFeaturesTest = errors.New("feature compat features test error")
 
 func TestInsertWebFeatures(t *testing.T) {
 	testCases := []struct {
@@ -292,6 +345,8 @@ func TestInsertWebFeatures(t *testing.T) {
 		mockUpsertFeatureBaselineStatusCfg      mockUpsertFeatureBaselineStatusConfig
 		mockInsertBrowserFeatureAvailabilityCfg mockInsertBrowserFeatureAvailabilityConfig
 		mockUpsertFeatureSpecCfg                mockUpsertFeatureSpecConfig
+		mockUpsertFeatureAliasCfg               mockUpsertFeatureAliasConfig
+		mockUpsertFeatureCompatFeaturesCfg      mockUpsertFeatureCompatFeaturesConfig
 		input                                   map[string]web_platform_dx__web_features.FeatureData
 		expectedError                           error // Expected error from InsertWebFeatures
 	}{
@@ -397,12 +452,37 @@ func TestInsertWebFeatures(t *testing.T) {
 				},
 				expectedCount: 2,
 			},
+			mockUpsertFeatureAliasCfg: mockUpsertFeatureAliasConfig{
+				expectedInputs: map[string][]string{
+					"feature1": {"old-feature1"},
+					"feature2": {"old-feature2a", "old-feature2b"},
+				},
+				outputs: map[string]error{
+					"feature1": nil,
+					"feature2": nil,
+				},
+				expectedCount: 3,
+			},
+			mockUpsertFeatureCompatFeaturesCfg: mockUpsertFeatureCompatFeaturesConfig{
+				expectedInputs: map[string][]string{
+					"feature1": {"api.Feature1", "api.Feature1.sub"},
+					"feature2": nil,
+				},
+				outputs: map[string]error{
+					"feature1": nil,
+					"feature2": nil,
+				},
+				expectedCount: 2,
+			},
 			input: map[string]web_platform_dx__web_features.FeatureData{
 				"feature1": {
-					Name:           "Feature 1",
-					Alias:          nil,
+					Name: "Feature 1",
+					Alias: &web_platform_dx__web_features.Alias{
+						String:      valuePtr("old-feature1"),
+						StringArray: nil,
+					},
 					Caniuse:        nil,
-					CompatFeatures: nil,
+					CompatFeatures: []string{"api.Feature1", "api.Feature1.sub"},
 					Spec: &web_platform_dx__web_features.Alias{
 						StringArray: []string{"feature1-link1", "feature1-link2"},
 						String:      nil,
@@ -429,8 +509,11 @@ func TestInsertWebFeatures(t *testing.T) {
 					UsageStats:      nil,
 				},
 				"feature2": {
-					Name:           "Feature 2",
-					Alias:          nil,
+					Name: "Feature 2",
+					Alias: &web_platform_dx__web_features.Alias{
+						String:      nil,
+						StringArray: []string{"old-feature2a", "old-feature2b"},
+					},
 					Caniuse:        nil,
 					CompatFeatures: nil,
 					Spec: &web_platform_dx__web_features.Alias{
@@ -493,6 +576,16 @@ func TestInsertWebFeatures(t *testing.T) {
 				outputs:        map[string]error{},
 				expectedCount:  0,
 			},
+			mockUpsertFeatureAliasCfg: mockUpsertFeatureAliasConfig{
+				expectedInputs: map[string][]string{},
+				outputs:        map[string]error{},
+				expectedCount:  0,
+			},
+			mockUpsertFeatureCompatFeaturesCfg: mockUpsertFeatureCompatFeaturesConfig{
+				expectedInputs: map[string][]string{},
+				outputs:        map[string]error{},
+				expectedCount:  0,
+			},
 			input: map[string]web_platform_dx__web_features.FeatureData{
 				"feature1": {
 					Name:           "Feature 1",
@@ -556,6 +649,16 @@ func TestInsertWebFeatures(t *testing.T) {
 				outputs:        map[string]error{},
 				expectedCount:  0,
 			},
+			mockUpsertFeatureAliasCfg: mockUpsertFeatureAliasConfig{
+				expectedInputs: map[string][]string{},
+				outputs:        map[string]error{},
+				expectedCount:  0,
+			},
+			mockUpsertFeatureCompatFeaturesCfg: mockUpsertFeatureCompatFeaturesConfig{
+				expectedInputs: map[string][]string{},
+				outputs:        map[string]error{},
+				expectedCount:  0,
+			},
 			input: map[string]web_platform_dx__web_features.FeatureData{
 				"feature1": {
 					Name:           "Feature 1",
@@ -630,6 +733,16 @@ func TestInsertWebFeatures(t *testing.T) {
 				outputs:        map[string]error{},
 				expectedCount:  0,
 			},
+			mockUpsertFeatureAliasCfg: mockUpsertFeatureAliasConfig{
+				expectedInputs: map[string][]string{},
+				outputs:        map[string]error{},
+				expectedCount:  0,
+			},
+			mockUpsertFeatureCompatFeaturesCfg: mockUpsertFeatureCompatFeaturesConfig{
+				expectedInputs: map[string][]string{},
+				outputs:        map[string]error{},
+				expectedCount:  0,
+			},
 			input: map[string]web_platform_dx__web_features.FeatureData{
 				"feature1": {
 					Name:           "Feature 1",
@@ -733,6 +846,16 @@ func TestInsertWebFeatures(t *testing.T) {
 				},
 				expectedCount: 1,
 			},
+			mockUpsertFeatureAliasCfg: mockUpsertFeatureAliasConfig{
+				expectedInputs: map[string][]string{},
+				outputs:        map[string]error{},
+				expectedCount:  0,
+			},
+			mockUpsertFeatureCompatFeaturesCfg: mockUpsertFeatureCompatFeaturesConfig{
+				expectedInputs: map[string][]string{},
+				outputs:        map[string]error{},
+				expectedCount:  0,
+			},
 			input: map[string]web_platform_dx__web_features.FeatureData{
 				"feature1": {
 					Name:           "Feature 1",
@@ -767,6 +890,256 @@ func TestInsertWebFeatures(t *testing.T) {
 			},
 			expectedError: ErrFeatureSpecTest,
 		},
+		{
+			name: "upsert feature alias failure",
+			mockUpsertWebFeatureCfg: mockUpsertWebFeatureConfig{
+				expectedInputs: map[string]gcpspanner.WebFeature{
+					"feature1": {
+						FeatureKey: "feature1",
+						Name:       "Feature 1",
+					},
+				},
+				outputs: map[string]error{
+					"feature1": nil,
+				},
+				outputIDs: map[string]*string{
+					"feature1": valuePtr("id-1"),
+				},
+				expectedCount: 1,
+			},
+			mockUpsertFeatureBaselineStatusCfg: mockUpsertFeatureBaselineStatusConfig{
+				expectedInputs: map[string]gcpspanner.FeatureBaselineStatus{
+					"feature1": {
+						Status:   valuePtr(gcpspanner.BaselineStatusHigh),
+						HighDate: nil,
+						LowDate:  nil,
+					},
+				},
+				outputs: map[string]error{
+					"feature1": nil,
+				},
+				expectedCount: 1,
+			},
+			mockInsertBrowserFeatureAvailabilityCfg: mockInsertBrowserFeatureAvailabilityConfig{
+				expectedInputs: map[string][]gcpspanner.BrowserFeatureAvailability{
+					"feature1": {
+						{
+							BrowserName:    "chrome",
+							BrowserVersion: "100",
+						},
+						{
+							BrowserName:    "edge",
+							BrowserVersion: "101",
+						},
+						{
+							BrowserName:    "firefox",
+							BrowserVersion: "102",
+						},
+						{
+							BrowserName:    "safari",
+							BrowserVersion: "103",
+						},
+					},
+				},
+				outputs: map[string][]error{
+					"feature1": {nil, nil, nil, nil},
+				},
+				expectedCountPerFeature: map[string]int{
+					"feature1": 4,
+				},
+			},
+			mockUpsertFeatureSpecCfg: mockUpsertFeatureSpecConfig{
+				expectedInputs: map[string]gcpspanner.FeatureSpec{
+					"feature1": {
+						Links: []string{
+							"feature1-link1",
+							"feature1-link2",
+						},
+					},
+				},
+				outputs: map[string]error{
+					"feature1": nil,
+				},
+				expectedCount: 1,
+			},
+			mockUpsertFeatureAliasCfg: mockUpsertFeatureAliasConfig{
+				expectedInputs: map[string][]string{
+					"feature1": {"old-feature1"},
+				},
+				outputs: map[string]error{
+					"feature1": ErrFeatureAliasTest,
+				},
+				expectedCount: 1,
+			},
+			mockUpsertFeatureCompatFeaturesCfg: mockUpsertFeatureCompatFeaturesConfig{
+				expectedInputs: map[string][]string{},
+				outputs:        map[string]error{},
+				expectedCount:  0,
+			},
+			input: map[string]web_platform_dx__web_features.FeatureData{
+				"feature1": {
+					Name: "Feature 1",
+					Alias: &web_platform_dx__web_features.Alias{
+						String:      valuePtr("old-feature1"),
+						StringArray: nil,
+					},
+					Caniuse:        nil,
+					CompatFeatures: nil,
+					Spec: &web_platform_dx__web_features.Alias{
+						StringArray: []string{"feature1-link1", "feature1-link2"},
+						String:      nil,
+					},
+					Status: &web_platform_dx__web_features.Status{
+						BaselineHighDate: nil,
+						BaselineLowDate:  nil,
+						Support: &web_platform_dx__web_features.Support{
+							Chrome:         valuePtr("100"),
+							ChromeAndroid:  nil,
+							Edge:           valuePtr("101"),
+							Firefox:        valuePtr("102"),
+							FirefoxAndroid: nil,
+							Safari:         valuePtr("103"),
+							SafariIos:      nil,
+						},
+						Baseline: &web_platform_dx__web_features.BaselineUnion{
+							Enum: valuePtr(web_platform_dx__web_features.High),
+							Bool: nil,
+						},
+					},
+					Description:     "text",
+					DescriptionHTML: "<html>",
+					UsageStats:      nil,
+				},
+			},
+			expectedError: ErrFeatureAliasTest,
+		},
+		{
+			name: "upsert feature compat features failure",
+			mockUpsertWebFeatureCfg: mockUpsertWebFeatureConfig{
+				expectedInputs: map[string]gcpspanner.WebFeature{
+					"feature1": {
+						FeatureKey: "feature1",
+						Name:       "Feature 1",
+					},
+				},
+				outputs: map[string]error{
+					"feature1": nil,
+				},
+				outputIDs: map[string]*string{
+					"feature1": valuePtr("id-1"),
+				},
+				expectedCount: 1,
+			},
+			mockUpsertFeatureBaselineStatusCfg: mockUpsertFeatureBaselineStatusConfig{
+				expectedInputs: map[string]gcpspanner.FeatureBaselineStatus{
+					"feature1": {
+						Status:   valuePtr(gcpspanner.BaselineStatusHigh),
+						HighDate: nil,
+						LowDate:  nil,
+					},
+				},
+				outputs: map[string]error{
+					"feature1": nil,
+				},
+				expectedCount: 1,
+			},
+			mockInsertBrowserFeatureAvailabilityCfg: mockInsertBrowserFeatureAvailabilityConfig{
+				expectedInputs: map[string][]gcpspanner.BrowserFeatureAvailability{
+					"feature1": {
+						{
+							BrowserName:    "chrome",
+							BrowserVersion: "100",
+						},
+						{
+							BrowserName:    "edge",
+							BrowserVersion: "101",
+						},
+						{
+							BrowserName:    "firefox",
+							BrowserVersion: "102",
+						},
+						{
+							BrowserName:    "safari",
+							BrowserVersion: "103",
+						},
+					},
+				},
+				outputs: map[string][]error{
+					"feature1": {nil, nil, nil, nil},
+				},
+				expectedCountPerFeature: map[string]int{
+					"feature1": 4,
+				},
+			},
+			mockUpsertFeatureSpecCfg: mockUpsertFeatureSpecConfig{
+				expectedInputs: map[string]gcpspanner.FeatureSpec{
+					"feature1": {
+						Links: []string{
+							"feature1-link1",
+							"feature1-link2",
+						},
+					},
+				},
+				outputs: map[string]error{
+					"feature1": nil,
+				},
+				expectedCount: 1,
+			},
+			mockUpsertFeatureAliasCfg: mockUpsertFeatureAliasConfig{
+				expectedInputs: map[string][]string{
+					"feature1": {"old-feature1"},
+				},
+				outputs: map[string]error{
+					"feature1": nil,
+				},
+				expectedCount: 1,
+			},
+			mockUpsertFeatureCompatFeaturesCfg: mockUpsertFeatureCompatFeaturesConfig{
+				expectedInputs: map[string][]string{
+					"feature1": {"api.Feature1"},
+				},
+				outputs: map[string]error{
+					"feature1": ErrFeatureCompatFeaturesTest,
+				},
+				expectedCount: 1,
+			},
+			input: map[string]web_platform_dx__web_features.FeatureData{
+				"feature1": {
+					Name: "Feature 1",
+					Alias: &web_platform_dx__web_features.Alias{
+						String:      valuePtr("old-feature1"),
+						StringArray: nil,
+					},
+					Caniuse:        nil,
+					CompatFeatures: []string{"api.Feature1"},
+					Spec: &web_platform_dx__web_features.Alias{
+						StringArray: []string{"feature1-link1", "feature1-link2"},
+						String:      nil,
+					},
+					Status: &web_platform_dx__web_features.Status{
+						BaselineHighDate: nil,
+						BaselineLowDate:  nil,
+						Support: &web_platform_dx__web_features.Support{
+							Chrome:         valuePtr("100"),
+							ChromeAndroid:  nil,
+							Edge:           valuePtr("101"),
+							Firefox:        valuePtr("102"),
+							FirefoxAndroid: nil,
+							Safari:         valuePtr("103"),
+							SafariIos:      nil,
+						},
+						Baseline: &web_platform_dx__web_features.BaselineUnion{
+							Enum: valuePtr(web_platform_dx__web_features.High),
+							Bool: nil,
+						},
+					},
+					Description:     "text",
+					DescriptionHTML: "<html>",
+					UsageStats:      nil,
+				},
+			},
+			expectedError: ErrFeatureCompatFeaturesTest,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -777,6 +1150,8 @@ func TestInsertWebFeatures(t *testing.T) {
 				tc.mockUpsertFeatureBaselineStatusCfg,
 				tc.mockInsertBrowserFeatureAvailabilityCfg,
 				tc.mockUpsertFeatureSpecCfg,
+				tc.mockUpsertFeatureAliasCfg,
+				tc.mockUpsertFeatureCompatFeaturesCfg,
 			)
 			consumer := NewWebFeaturesConsumer(mockClient)
 
@@ -806,6 +1181,20 @@ func TestInsertWebFeatures(t *testing.T) {
 					mockClient.upsertFeatureSpecCount)
 			}
 
+			if mockClient.upsertFeatureAliasCount !=
+				mockClient.mockUpsertFeatureAliasCfg.expectedCount {
+				t.Errorf("expected %d calls to UpsertFeatureAlias, got %d",
+					mockClient.mockUpsertFeatureAliasCfg.expectedCount,
+					mockClient.upsertFeatureAliasCount)
+			}
+
+			if mockClient.upsertFeatureCompatFeaturesCount !=
+				mockClient.mockUpsertFeatureCompatFeaturesCfg.expectedCount {
+				t.Errorf("expected %d calls to UpsertFeatureCompatFeatures, got %d",
+					mockClient.mockUpsertFeatureCompatFeaturesCfg.expectedCount,
+					mockClient.upsertFeatureCompatFeaturesCount)
+			}
+
 			if !reflect.DeepEqual(mockClient.insertBrowserFeatureAvailabilityCountPerFeature,
 				tc.mockInsertBrowserFeatureAvailabilityCfg.expectedCountPerFeature) {
 				t.Errorf("Unexpected call counts for InsertBrowserFeatureAvailability. Expected: %v, Got: %v",
@@ -0,0 +1,146 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanneradapters
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/GoogleChrome/webstatus.dev/lib/gcpspanner"
+	"github.com/GoogleChrome/webstatus.dev/lib/gcpspanner/searchtypes"
+	"github.com/GoogleChrome/webstatus.dev/lib/webhooks"
+)
+
+// WebhooksSpannerClient expects a subset of the functionality from lib/gcpspanner that
+// only apply to detecting the changes of features.
+type WebhooksSpannerClient interface {
+	FeaturesSearch(
+		ctx context.Context,
+		pageToken *string,
+		pageSize int,
+		searchNode *searchtypes.SearchNode,
+		sortOrder gcpspanner.Sortable,
+		wptMetricView gcpspanner.WPTMetricView,
+		browsers []string,
+		asOf *time.Time,
+		facets []gcpspanner.FeatureSearchFacet,
+	) (*gcpspanner.FeatureResultPage, error)
+}
+
+// NewWebhooks constructs an adapter for the webhook notifier.
+func NewWebhooks(client WebhooksSpannerClient) *Webhooks {
+	return &Webhooks{client: client}
+}
+
+// Webhooks implements webhooks.FeatureStateGetter.
+type Webhooks struct {
+	client WebhooksSpannerClient
+}
+
+// webhookPageSize is the number of features read per query when scanning every feature.
+const webhookPageSize = 500
+
+// webhookBrowsers are the browsers that subscriptions are notified about.
+// nolint: gochecknoglobals // WONTFIX. Read only list.
+var webhookBrowsers = []string{"chrome", "edge", "firefox", "safari"}
+
+// webhookBaselineStatuses converts the stored baseline status to the values used by the API.
+// nolint: gochecknoglobals // WONTFIX. Read only map.
+var webhookBaselineStatuses = map[gcpspanner.BaselineStatus]string{
+	gcpspanner.BaselineStatusHigh: "widely",
+	gcpspanner.BaselineStatusLow:  "newly",
+	gcpspanner.BaselineStatusNone: "limited",
+}
+
+func (w *Webhooks) forEachFeature(
+	ctx context.Context, searchNode *searchtypes.SearchNode, fn func(gcpspanner.FeatureResult)) error {
+	var pageToken *string
+	for {
+		page, err := w.client.FeaturesSearch(ctx, pageToken, webhookPageSize, searchNode,
+			gcpspanner.NewFeatureNameSort(true), gcpspanner.WPTSubtestView, webhookBrowsers, nil, nil)
+		if err != nil {
+			return err
+		}
+		for _, feature := range page.Features {
+			fn(feature)
+		}
+		if page.NextPageToken == nil || len(page.Features) == 0 {
+			return nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+func (w *Webhooks) ListFeatureStates(ctx context.Context) ([]webhooks.FeatureState, error) {
+	var states []webhooks.FeatureState
+	err := w.forEachFeature(ctx, nil, func(feature gcpspanner.FeatureResult) {
+		states = append(states, convertFeatureResultToState(feature))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return states, nil
+}
+
+func (w *Webhooks) MatchFeatureIDs(ctx context.Context, query string) ([]string, error) {
+	searchNode, err := searchtypes.FeaturesSearchQueryParser{}.Parse(query)
+	if err != nil {
+		return nil, err
+	}
+	var featureIDs []string
+	err = w.forEachFeature(ctx, searchNode, func(feature gcpspanner.FeatureResult) {
+		featureIDs = append(featureIDs, feature.FeatureKey)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return featureIDs, nil
+}
+
+func convertFeatureResultToState(feature gcpspanner.FeatureResult) webhooks.FeatureState {
+	var baselineStatus string
+	if feature.Status != nil {
+		baselineStatus = webhookBaselineStatuses[gcpspanner.BaselineStatus(*feature.Status)]
+	}
+
+	var availableBrowsers []string
+	for _, status := range feature.ImplementationStatuses {
+		if status != nil && status.ImplementationStatus == gcpspanner.Available {
+			availableBrowsers = append(availableBrowsers, status.BrowserName)
+		}
+	}
+	slices.Sort(availableBrowsers)
+
+	var scores map[string]float64
+	for _, metric := range feature.StableMetrics {
+		if metric == nil || metric.PassRate == nil {
+			continue
+		}
+		if scores == nil {
+			scores = make(map[string]float64, len(feature.StableMetrics))
+		}
+		scores[metric.BrowserName], _ = metric.PassRate.Float64()
+	}
+
+	return webhooks.FeatureState{
+		FeatureID:         feature.FeatureKey,
+		BaselineStatus:    baselineStatus,
+		AvailableBrowsers: availableBrowsers,
+		StableWPTScores:   scores,
+	}
+}
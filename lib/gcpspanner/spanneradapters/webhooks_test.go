@@ -0,0 +1,150 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanneradapters
+
+import (
+	"context"
+	"math/big"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/GoogleChrome/webstatus.dev/lib/gcpspanner"
+	"github.com/GoogleChrome/webstatus.dev/lib/gcpspanner/searchtypes"
+	"github.com/GoogleChrome/webstatus.dev/lib/webhooks"
+)
+
+type mockWebhooksSpannerClient struct {
+	t     *testing.T
+	pages []gcpspanner.FeatureResultPage
+	// searchNodes records whether each call had a search node.
+	searchNodes []bool
+}
+
+func (c *mockWebhooksSpannerClient) FeaturesSearch(
+	_ context.Context,
+	pageToken *string,
+	pageSize int,
+	searchNode *searchtypes.SearchNode,
+	_ gcpspanner.Sortable,
+	wptMetricView gcpspanner.WPTMetricView,
+	browsers []string,
+	_ *time.Time,
+	_ []gcpspanner.FeatureSearchFacet,
+) (*gcpspanner.FeatureResultPage, error) {
+	if pageSize != webhookPageSize || wptMetricView != gcpspanner.WPTSubtestView ||
+		!slices.Equal(browsers, webhookBrowsers) {
+		c.t.Error("unexpected input to mock")
+	}
+	c.searchNodes = append(c.searchNodes, searchNode != nil)
+	idx := 0
+	if pageToken != nil {
+		idx = len(*pageToken)
+	}
+
+	return &c.pages[idx], nil
+}
+
+func TestWebhooksListFeatureStates(t *testing.T) {
+	next := "1"
+	mock := &mockWebhooksSpannerClient{
+		t: t,
+		pages: []gcpspanner.FeatureResultPage{
+			{
+				Total:         2,
+				NextPageToken: &next,
+				// nolint: exhaustruct // Only the fields used by the notifier are needed.
+				Features: []gcpspanner.FeatureResult{
+					{
+						FeatureKey: "grid",
+						Status:     valuePtr(string(gcpspanner.BaselineStatusHigh)),
+						StableMetrics: []*gcpspanner.FeatureResultMetric{
+							{BrowserName: "chrome", PassRate: big.NewRat(9, 10), FeatureRunDetails: nil},
+							{BrowserName: "safari", PassRate: nil, FeatureRunDetails: nil},
+						},
+						ImplementationStatuses: []*gcpspanner.ImplementationStatus{
+							// nolint: exhaustruct // Dates are not needed.
+							{BrowserName: "safari", ImplementationStatus: gcpspanner.Available},
+							// nolint: exhaustruct // Dates are not needed.
+							{BrowserName: "chrome", ImplementationStatus: gcpspanner.Available},
+							// nolint: exhaustruct // Dates are not needed.
+							{BrowserName: "firefox", ImplementationStatus: gcpspanner.Unavailable},
+						},
+					},
+				},
+				Facets: nil,
+			},
+			{
+				Total:         2,
+				NextPageToken: nil,
+				// nolint: exhaustruct // Only the fields used by the notifier are needed.
+				Features: []gcpspanner.FeatureResult{{FeatureKey: "has"}},
+				Facets:   nil,
+			},
+		},
+		searchNodes: nil,
+	}
+
+	states, err := NewWebhooks(mock).ListFeatureStates(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	expected := []webhooks.FeatureState{
+		{
+			FeatureID:         "grid",
+			BaselineStatus:    "widely",
+			AvailableBrowsers: []string{"chrome", "safari"},
+			StableWPTScores:   map[string]float64{"chrome": 0.9},
+		},
+		{FeatureID: "has", BaselineStatus: "", AvailableBrowsers: nil, StableWPTScores: nil},
+	}
+	if !reflect.DeepEqual(states, expected) {
+		t.Errorf("unexpected states %+v", states)
+	}
+	if !slices.Equal(mock.searchNodes, []bool{false, false}) {
+		t.Errorf("expected every feature to be listed. received %v", mock.searchNodes)
+	}
+}
+
+func TestWebhooksMatchFeatureIDs(t *testing.T) {
+	mock := &mockWebhooksSpannerClient{
+		t: t,
+		pages: []gcpspanner.FeatureResultPage{
+			{
+				Total:         1,
+				NextPageToken: nil,
+				// nolint: exhaustruct // Only the key is needed.
+				Features: []gcpspanner.FeatureResult{{FeatureKey: "has"}},
+				Facets:   nil,
+			},
+		},
+		searchNodes: nil,
+	}
+	featureIDs, err := NewWebhooks(mock).MatchFeatureIDs(context.Background(), "baseline_status:limited")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !slices.Equal(featureIDs, []string{"has"}) {
+		t.Errorf("unexpected feature ids %v", featureIDs)
+	}
+	if !slices.Equal(mock.searchNodes, []bool{true}) {
+		t.Errorf("expected the query to be used. received %v", mock.searchNodes)
+	}
+
+	if _, err := NewWebhooks(mock).MatchFeatureIDs(context.Background(), "badterm:"); err == nil {
+		t.Error("expected an error for an invalid query")
+	}
+}
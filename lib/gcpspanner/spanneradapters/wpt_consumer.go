@@ -34,6 +34,8 @@ type WPTWorkflowSpannerClient interface {
 	InsertWPTRun(ctx context.Context, run gcpspanner.WPTRun) error
 	UpsertWPTRunFeatureMetrics(ctx context.Context, externalRunID int64,
 		in map[string]gcpspanner.WPTRunFeatureMetric) error
+	UpsertWPTRunFeatureTestResults(ctx context.Context, externalRunID int64,
+		in map[string][]gcpspanner.WPTRunFeatureTestResult) error
 }
 
 // WPTConsumer is the adapter that takes data from the WPT workflow and prepares
@@ -102,6 +104,42 @@ func (w *WPTConsumer) UpsertWPTRunFeatureMetrics(
 	return nil
 }
 
+func convertWorkflowTestResultsToGCPTestResults(
+	resultsPerFeature map[string][]wptconsumertypes.WPTTestResult,
+) map[string][]gcpspanner.WPTRunFeatureTestResult {
+	ret := make(map[string][]gcpspanner.WPTRunFeatureTestResult, len(resultsPerFeature))
+	for featureID, consumerResults := range resultsPerFeature {
+		results := make([]gcpspanner.WPTRunFeatureTestResult, 0, len(consumerResults))
+		for _, consumerResult := range consumerResults {
+			results = append(results, gcpspanner.WPTRunFeatureTestResult{
+				TestName:      consumerResult.TestName,
+				Passed:        consumerResult.Passed,
+				SubtestPass:   consumerResult.SubtestPass,
+				TotalSubtests: consumerResult.TotalSubtests,
+			})
+		}
+		ret[featureID] = results
+	}
+
+	return ret
+}
+
+func (w *WPTConsumer) UpsertWPTRunFeatureTestResults(
+	ctx context.Context,
+	runID int64,
+	resultsPerFeature map[string][]wptconsumertypes.WPTTestResult) error {
+	results := convertWorkflowTestResultsToGCPTestResults(resultsPerFeature)
+
+	if len(results) > 0 {
+		err := w.client.UpsertWPTRunFeatureTestResults(ctx, runID, results)
+		if err != nil {
+			return errors.Join(wptconsumertypes.ErrUnableToStoreWPTRunFeatureTestResults, err)
+		}
+	}
+
+	return nil
+}
+
 // NewWPTRun creates a gcpspanner WPTRun from the incoming TestRun from wpt.fyi.
 func NewWPTRun(testRun shared.TestRun) gcpspanner.WPTRun {
 	return gcpspanner.WPTRun{
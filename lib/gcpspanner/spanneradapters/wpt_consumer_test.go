@@ -27,9 +27,10 @@ import (
 )
 
 type MockWPTWorkflowSpannerClient struct {
-	InsertWPTRunConfig               *InsertWPTRunConfig
-	UpsertWPTRunFeatureMetricsConfig *UpsertWPTRunFeatureMetricsConfig
-	t                                *testing.T
+	InsertWPTRunConfig                   *InsertWPTRunConfig
+	UpsertWPTRunFeatureMetricsConfig     *UpsertWPTRunFeatureMetricsConfig
+	UpsertWPTRunFeatureTestResultsConfig *UpsertWPTRunFeatureTestResultsConfig
+	t                                    *testing.T
 }
 
 type InsertWPTRunConfig struct {
@@ -44,6 +45,13 @@ type UpsertWPTRunFeatureMetricsConfig struct {
 	err error
 }
 
+type UpsertWPTRunFeatureTestResultsConfig struct {
+	inputID      int64
+	inputResults map[string][]gcpspanner.WPTRunFeatureTestResult
+
+	err error
+}
+
 // Implementations of the interface methods.
 func (m *MockWPTWorkflowSpannerClient) InsertWPTRun(_ context.Context, run gcpspanner.WPTRun) error {
 	if !reflect.DeepEqual(run, *m.InsertWPTRunConfig.input) {
@@ -63,6 +71,16 @@ func (m *MockWPTWorkflowSpannerClient) UpsertWPTRunFeatureMetrics(
 	return m.UpsertWPTRunFeatureMetricsConfig.err
 }
 
+func (m *MockWPTWorkflowSpannerClient) UpsertWPTRunFeatureTestResults(
+	_ context.Context, externalRunID int64, in map[string][]gcpspanner.WPTRunFeatureTestResult) error {
+	if externalRunID != m.UpsertWPTRunFeatureTestResultsConfig.inputID ||
+		!reflect.DeepEqual(in, m.UpsertWPTRunFeatureTestResultsConfig.inputResults) {
+		m.t.Error("unexpected input to UpsertWPTRunFeatureTestResults")
+	}
+
+	return m.UpsertWPTRunFeatureTestResultsConfig.err
+}
+
 func getSampleTestRun() shared.TestRun {
 	// nolint: exhaustruct // WONTFIX: external struct
 	return shared.TestRun{
@@ -156,9 +174,10 @@ func TestWPTConsumer_InsertWPTRun(t *testing.T) {
 	for idx, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			mockClient := &MockWPTWorkflowSpannerClient{
-				InsertWPTRunConfig:               &testCases[idx].mockConfig,
-				UpsertWPTRunFeatureMetricsConfig: nil,
-				t:                                t,
+				InsertWPTRunConfig:                   &testCases[idx].mockConfig,
+				UpsertWPTRunFeatureMetricsConfig:     nil,
+				UpsertWPTRunFeatureTestResultsConfig: nil,
+				t:                                    t,
 			}
 			consumer := NewWPTWorkflowConsumer(mockClient)
 
@@ -269,9 +288,10 @@ func TestWPTConsumer_UpsertWPTRunFeatureMetrics(t *testing.T) {
 	for idx, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			mockClient := &MockWPTWorkflowSpannerClient{
-				UpsertWPTRunFeatureMetricsConfig: &testCases[idx].mockConfig,
-				InsertWPTRunConfig:               nil,
-				t:                                t,
+				UpsertWPTRunFeatureMetricsConfig:     &testCases[idx].mockConfig,
+				InsertWPTRunConfig:                   nil,
+				UpsertWPTRunFeatureTestResultsConfig: nil,
+				t:                                    t,
 			}
 			consumer := NewWPTWorkflowConsumer(mockClient)
 
@@ -283,6 +303,111 @@ func TestWPTConsumer_UpsertWPTRunFeatureMetrics(t *testing.T) {
 	}
 }
 
+func TestWPTConsumer_UpsertWPTRunFeatureTestResults(t *testing.T) {
+	testCases := []struct {
+		name          string
+		mockConfig    *UpsertWPTRunFeatureTestResultsConfig
+		externalRunID int64
+		results       map[string][]wptconsumertypes.WPTTestResult
+		expectedError error
+	}{
+		{
+			name: "Success",
+			mockConfig: &UpsertWPTRunFeatureTestResultsConfig{
+				inputID: 123,
+				inputResults: map[string][]gcpspanner.WPTRunFeatureTestResult{
+					"feature1": {
+						{
+							TestName:      "test1.html",
+							Passed:        true,
+							SubtestPass:   valuePtr[int64](1),
+							TotalSubtests: valuePtr[int64](1),
+						},
+						{
+							TestName:      "test2.html",
+							Passed:        false,
+							SubtestPass:   nil,
+							TotalSubtests: nil,
+						},
+					},
+				},
+				err: nil,
+			},
+			externalRunID: 123,
+			results: map[string][]wptconsumertypes.WPTTestResult{
+				"feature1": {
+					{
+						TestName:      "test1.html",
+						Passed:        true,
+						SubtestPass:   valuePtr[int64](1),
+						TotalSubtests: valuePtr[int64](1),
+					},
+					{
+						TestName:      "test2.html",
+						Passed:        false,
+						SubtestPass:   nil,
+						TotalSubtests: nil,
+					},
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name: "Database error",
+			mockConfig: &UpsertWPTRunFeatureTestResultsConfig{
+				inputID: 123,
+				inputResults: map[string][]gcpspanner.WPTRunFeatureTestResult{
+					"feature1": {
+						{
+							TestName:      "test1.html",
+							Passed:        true,
+							SubtestPass:   valuePtr[int64](1),
+							TotalSubtests: valuePtr[int64](1),
+						},
+					},
+				},
+				err: errors.New("database error"),
+			},
+			externalRunID: 123,
+			results: map[string][]wptconsumertypes.WPTTestResult{
+				"feature1": {
+					{
+						TestName:      "test1.html",
+						Passed:        true,
+						SubtestPass:   valuePtr[int64](1),
+						TotalSubtests: valuePtr[int64](1),
+					},
+				},
+			},
+			expectedError: wptconsumertypes.ErrUnableToStoreWPTRunFeatureTestResults,
+		},
+		{
+			name:          "No results skips the database",
+			mockConfig:    nil,
+			externalRunID: 123,
+			results:       map[string][]wptconsumertypes.WPTTestResult{},
+			expectedError: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := &MockWPTWorkflowSpannerClient{
+				UpsertWPTRunFeatureMetricsConfig:     nil,
+				InsertWPTRunConfig:                   nil,
+				UpsertWPTRunFeatureTestResultsConfig: tc.mockConfig,
+				t:                                    t,
+			}
+			consumer := NewWPTWorkflowConsumer(mockClient)
+
+			err := consumer.UpsertWPTRunFeatureTestResults(context.Background(), tc.externalRunID, tc.results)
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("Expected error: %v, got: %v", tc.expectedError, err)
+			}
+		})
+	}
+}
+
 func TestNewWPTRun(t *testing.T) {
 	testCases := []struct {
 		name           string
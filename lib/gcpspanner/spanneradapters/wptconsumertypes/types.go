@@ -28,6 +28,16 @@ type WPTFeatureMetric struct {
 	FeatureRunDetails map[string]interface{}
 }
 
+// WPTTestResult is the result of an individual test that comes from the WPT Consumer.
+// SubtestPass and TotalSubtests are nil when the subtest counts can not be trusted
+// (e.g. the test crashed).
+type WPTTestResult struct {
+	TestName      string
+	Passed        bool
+	SubtestPass   *int64
+	TotalSubtests *int64
+}
+
 // ErrInvalidDataFromWPT indicates that the data will not be stored because it
 // contains unexpected data from WPT.
 var ErrInvalidDataFromWPT = errors.New("invalid data from WPT")
@@ -39,3 +49,7 @@ var ErrUnableToStoreWPTRun = errors.New("unable to store wpt run data")
 // ErrUnableToStoreWPTRunFeatureMetrics indicates that the storage layer was
 // unable to save the wpt run feature metrics.
 var ErrUnableToStoreWPTRunFeatureMetrics = errors.New("unable to store wpt run feature metrics")
+
+// ErrUnableToStoreWPTRunFeatureTestResults indicates that the storage layer was
+// unable to save the individual test results of a wpt run.
+var ErrUnableToStoreWPTRunFeatureTestResults = errors.New("unable to store wpt run feature test results")
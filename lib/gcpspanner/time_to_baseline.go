@@ -0,0 +1,132 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcpspanner
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	"github.com/GoogleChrome/webstatus.dev/lib/gcpspanner/searchtypes"
+	"google.golang.org/api/iterator"
+)
+
+func init() {
+	timeToBaselineQueryTemplate = NewQueryTemplate(timeToBaselineQueryRawTemplate)
+}
+
+// nolint: gochecknoglobals // WONTFIX. Compile the template once at startup. Startup fails if invalid.
+var (
+	// timeToBaselineQueryTemplate is the compiled version of timeToBaselineQueryRawTemplate.
+	timeToBaselineQueryTemplate BaseQueryTemplate
+)
+
+const (
+	// timeToBaselineQueryRawTemplate returns the baseline dates of each feature
+	// along with the earliest release date of any browser where the feature
+	// became available. The day differences are NULL if either side is missing.
+	timeToBaselineQueryRawTemplate = `
+SELECT
+	wf.FeatureKey,
+	wf.Name,
+	fa.FirstAvailableDate,
+	fbs.LowDate,
+	fbs.HighDate,
+	TIMESTAMP_DIFF(fbs.LowDate, fa.FirstAvailableDate, DAY) AS DaysToLow,
+	TIMESTAMP_DIFF(fbs.HighDate, fbs.LowDate, DAY) AS DaysLowToHigh
+{{ .BaseQueryFragment }}
+LEFT OUTER JOIN (
+	SELECT
+		bfa.WebFeatureID,
+		MIN(br.ReleaseDate) AS FirstAvailableDate
+	FROM BrowserFeatureAvailabilities bfa
+	JOIN BrowserReleases br
+		ON bfa.BrowserName = br.BrowserName AND bfa.BrowserVersion = br.BrowserVersion
+	GROUP BY bfa.WebFeatureID
+) fa ON wf.ID = fa.WebFeatureID
+WHERE 1=1
+{{ range .Filters }}
+	AND {{ . }}
+{{ end }}
+ORDER BY wf.FeatureKey ASC
+`
+)
+
+// TimeToBaselineTemplateData contains the variables for timeToBaselineQueryRawTemplate.
+type TimeToBaselineTemplateData struct {
+	BaseQueryFragment string
+	Filters           []string
+}
+
+// FeatureTimeToBaseline contains the number of days it took a feature to reach
+// each baseline stage.
+type FeatureTimeToBaseline struct {
+	FeatureKey         string     `spanner:"FeatureKey"`
+	Name               string     `spanner:"Name"`
+	FirstAvailableDate *time.Time `spanner:"FirstAvailableDate"`
+	LowDate            *time.Time `spanner:"LowDate"`
+	HighDate           *time.Time `spanner:"HighDate"`
+	// DaysToLow is the number of days between the first browser availability and the baseline low date.
+	DaysToLow *int64 `spanner:"DaysToLow"`
+	// DaysLowToHigh is the number of days between the baseline low date and the baseline high date.
+	DaysLowToHigh *int64 `spanner:"DaysLowToHigh"`
+}
+
+// ListFeatureTimeToBaseline returns the time to baseline for every feature
+// that matches the optional search node. The results are ordered by feature key.
+func (c *Client) ListFeatureTimeToBaseline(
+	ctx context.Context,
+	searchNode *searchtypes.SearchNode,
+) ([]FeatureTimeToBaseline, error) {
+	filterBuilder := NewFeatureSearchFilterBuilder()
+	filter := filterBuilder.Build(searchNode)
+
+	tmplData := TimeToBaselineTemplateData{
+		BaseQueryFragment: commonFSBaseQueryTemplate,
+		Filters:           defaultFeatureSearchFilters(),
+	}
+	params := make(map[string]interface{})
+	if filter != nil {
+		tmplData.Filters = append(tmplData.Filters, filter.Filters()...)
+		params = filter.Params()
+	}
+
+	stmt := spanner.NewStatement(timeToBaselineQueryTemplate.Execute(tmplData))
+	stmt.Params = params
+
+	txn := c.Single()
+	defer txn.Close()
+	it := txn.Query(ctx, stmt)
+	defer it.Stop()
+
+	var results []FeatureTimeToBaseline
+	for {
+		row, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, errors.Join(ErrInternalQueryFailure, err)
+		}
+		var result FeatureTimeToBaseline
+		if err := row.ToStruct(&result); err != nil {
+			return nil, errors.Join(ErrInternalQueryFailure, err)
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,131 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcpspanner
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/GoogleChrome/webstatus.dev/lib/gcpspanner/searchtypes"
+)
+
+func setupRequiredTablesForTimeToBaseline(ctx context.Context,
+	client *Client, t *testing.T) {
+	setupRequiredTablesForBrowserFeatureAvailability(ctx, client, t)
+	for _, availability := range getSampleBrowserAvailabilities() {
+		err := client.InsertBrowserFeatureAvailability(
+			ctx, availability.FeatureKey, availability.BrowserFeatureAvailability)
+		if err != nil {
+			t.Errorf("unexpected error during insert of availabilities. %s", err.Error())
+		}
+	}
+	for _, status := range getSampleBaselineStatuses() {
+		err := client.UpsertFeatureBaselineStatus(ctx, status.featureKey, status.status)
+		if err != nil {
+			t.Errorf("unexpected error during insert of statuses. %s", err.Error())
+		}
+	}
+}
+
+func TestListFeatureTimeToBaseline(t *testing.T) {
+	client := getTestDatabase(t)
+	ctx := context.Background()
+	setupRequiredTablesForTimeToBaseline(ctx, client, t)
+
+	feature2 := FeatureTimeToBaseline{
+		FeatureKey:         "feature2",
+		Name:               "Feature 2",
+		FirstAvailableDate: valuePtr(time.Date(2000, time.February, 1, 0, 0, 0, 0, time.UTC)),
+		LowDate:            valuePtr(time.Date(2000, time.January, 15, 0, 0, 0, 0, time.UTC)),
+		HighDate:           valuePtr(time.Date(2000, time.January, 31, 0, 0, 0, 0, time.UTC)),
+		// The sample data has a low date before the first availability.
+		DaysToLow:     valuePtr[int64](-17),
+		DaysLowToHigh: valuePtr[int64](16),
+	}
+
+	testCases := []struct {
+		name       string
+		searchNode *searchtypes.SearchNode
+		expected   []FeatureTimeToBaseline
+	}{
+		{
+			name:       "no filter",
+			searchNode: nil,
+			expected: []FeatureTimeToBaseline{
+				{
+					FeatureKey:         "feature1",
+					Name:               "Feature 1",
+					FirstAvailableDate: valuePtr(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)),
+					LowDate:            nil,
+					HighDate:           nil,
+					DaysToLow:          nil,
+					DaysLowToHigh:      nil,
+				},
+				feature2,
+				{
+					FeatureKey:         "feature3",
+					Name:               "Feature 3",
+					FirstAvailableDate: nil,
+					LowDate:            nil,
+					HighDate:           nil,
+					DaysToLow:          nil,
+					DaysLowToHigh:      nil,
+				},
+				{
+					FeatureKey:         "feature4",
+					Name:               "Feature 4",
+					FirstAvailableDate: nil,
+					LowDate:            nil,
+					HighDate:           nil,
+					DaysToLow:          nil,
+					DaysLowToHigh:      nil,
+				},
+			},
+		},
+		{
+			name: "baseline_status:widely",
+			searchNode: &searchtypes.SearchNode{
+				Keyword: searchtypes.KeywordRoot,
+				Term:    nil,
+				Children: []*searchtypes.SearchNode{
+					{
+						Children: nil,
+						Term: &searchtypes.SearchTerm{
+							Identifier: searchtypes.IdentifierBaselineStatus,
+							Value:      "widely",
+							Operator:   searchtypes.OperatorEq,
+						},
+						Keyword: searchtypes.KeywordNone,
+					},
+				},
+			},
+			expected: []FeatureTimeToBaseline{feature2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			results, err := client.ListFeatureTimeToBaseline(ctx, tc.searchNode)
+			if err != nil {
+				t.Fatalf("unexpected error. %s", err.Error())
+			}
+			if !reflect.DeepEqual(tc.expected, results) {
+				t.Errorf("unequal results.\nexpected %+v\nreceived %+v", tc.expected, results)
+			}
+		})
+	}
+}
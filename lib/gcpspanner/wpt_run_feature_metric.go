@@ -30,12 +30,15 @@ const WPTRunFeatureMetricTable = "WPTRunFeatureMetrics"
 
 func init() {
 	getFeatureMetricBaseTemplate = NewQueryTemplate(getFeatureMetricBaseRawTemplate)
+	getFeaturesFirstMetricsPageTemplate = NewQueryTemplate(getFeaturesFirstMetricsPageRawTemplate)
 }
 
 // nolint: gochecknoglobals // WONTFIX. Compile the template once at startup. Startup fails if invalid.
 var (
 	// getFeatureMetricBaseTemplate is the compiled version of getFeatureMetricBaseRawTemplate.
 	getFeatureMetricBaseTemplate BaseQueryTemplate
+	// getFeaturesFirstMetricsPageTemplate is the compiled version of getFeaturesFirstMetricsPageRawTemplate.
+	getFeaturesFirstMetricsPageTemplate BaseQueryTemplate
 )
 
 const (
@@ -77,6 +80,31 @@ const (
 		AND (r.TimeStart < @lastTimestamp OR
 			r.TimeStart = @lastTimestamp AND r.ExternalRunID < @lastRunID)`
 
+	// getFeaturesFirstMetricsPageRawTemplate returns the first page of metrics of each feature key in one query.
+	getFeaturesFirstMetricsPageRawTemplate = `
+	SELECT
+		fk AS FeatureKey,
+		ARRAY(
+			SELECT AS STRUCT
+				r.ExternalRunID,
+				r.TimeStart,
+				wpfm.{{ .TotalColumn }} AS TotalTests,
+				wpfm.{{ .PassColumn }} AS TestPass
+			FROM WPTRuns r
+			JOIN WPTRunFeatureMetrics wpfm ON r.ID = wpfm.ID
+			JOIN WebFeatures wf ON wf.ID = wpfm.WebFeatureID
+			WHERE wf.FeatureKey = fk
+				AND r.BrowserName = @browserName
+				AND wpfm.{{ .TotalColumn }} IS NOT NULL
+				AND wpfm.{{ .PassColumn }} IS NOT NULL
+				AND r.Channel = @channel
+				AND r.TimeStart >= @startAt AND r.TimeStart < @endAt
+			ORDER BY r.TimeStart DESC, r.ExternalRunID DESC LIMIT @pageSize
+		) AS Metrics
+	FROM UNNEST(@featureKeys) AS fk
+	LEFT OUTER JOIN ExcludedFeatureKeys efk ON fk = efk.FeatureKey
+	WHERE efk.FeatureKey IS NULL`
+
 	singleFeatureMetricSubsetRawTemplate    = `AND wf.FeatureKey = @featureKey`
 	multipleFeaturesMetricSubsetRawTemplate = `AND wf.FeatureKey IN UNNEST(@featureKeys)`
 )
@@ -398,6 +426,84 @@ func (c *Client) ListMetricsForFeatureIDBrowserAndChannel(
 	return featureMetrics, nil, nil
 }
 
+// FeaturesMetricsTemplateData contains the variables for getFeaturesFirstMetricsPageRawTemplate.
+type FeaturesMetricsTemplateData struct {
+	TotalColumn string
+	PassColumn  string
+}
+
+// WPTRunFeatureMetricsPage contains a page of metrics of a feature.
+type WPTRunFeatureMetricsPage struct {
+	Metrics       []WPTRunFeatureMetricWithTime
+	NextPageToken *string
+}
+
+type spannerWPTRunFeatureMetricsPage struct {
+	FeatureKey string                         `spanner:"FeatureKey"`
+	Metrics    []*WPTRunFeatureMetricWithTime `spanner:"Metrics"`
+}
+
+// ListFirstMetricsPageForFeatureIDsBrowserAndChannel returns the first page of
+// metrics of multiple web feature keys in one query, keyed by feature key.
+// It returns the same pages as ListMetricsForFeatureIDBrowserAndChannel without
+// a page token. Feature keys that do not exist or are excluded are left out.
+func (c *Client) ListFirstMetricsPageForFeatureIDsBrowserAndChannel(
+	ctx context.Context,
+	featureKeys []string,
+	browser string,
+	channel string,
+	metric WPTMetricView,
+	startAt time.Time,
+	endAt time.Time,
+	pageSize int,
+) (map[string]WPTRunFeatureMetricsPage, error) {
+	tmpl := getFeaturesFirstMetricsPageTemplate.Execute(FeaturesMetricsTemplateData{
+		TotalColumn: metricsTotalTestColumn(metric),
+		PassColumn:  metricsTestPassColumn(metric),
+	})
+	stmt := spanner.NewStatement(tmpl)
+	stmt.Params = map[string]interface{}{
+		"featureKeys": featureKeys,
+		"browserName": browser,
+		"channel":     channel,
+		"startAt":     startAt,
+		"endAt":       endAt,
+		"pageSize":    pageSize,
+	}
+
+	txn := c.Single()
+	defer txn.Close()
+	it := txn.Query(ctx, stmt)
+	defer it.Stop()
+
+	pages := make(map[string]WPTRunFeatureMetricsPage, len(featureKeys))
+	for {
+		row, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, errors.Join(ErrInternalQueryFailure, err)
+		}
+		var result spannerWPTRunFeatureMetricsPage
+		if err := row.ToStruct(&result); err != nil {
+			return nil, errors.Join(ErrInternalQueryFailure, err)
+		}
+		var page WPTRunFeatureMetricsPage
+		for _, metric := range result.Metrics {
+			page.Metrics = append(page.Metrics, *metric)
+		}
+		if len(page.Metrics) == pageSize {
+			lastFeatureMetric := page.Metrics[len(page.Metrics)-1]
+			newCursor := encodeWPTRunCursor(lastFeatureMetric.TimeStart, lastFeatureMetric.RunID)
+			page.NextPageToken = &newCursor
+		}
+		pages[result.FeatureKey] = page
+	}
+
+	return pages, nil
+}
+
 // WPTRunAggregationMetricWithTime contains metrics for a particular aggregation
 // at a given time. For now, it is the same metrics as
 // WPTRunFeatureMetricWithTime.
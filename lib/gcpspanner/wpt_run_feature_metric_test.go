@@ -552,6 +552,47 @@ func TestListMetricsForFeatureIDBrowserAndChannel(t *testing.T) {
 	if !reflect.DeepEqual(expectedMetricsPageThree, metrics) {
 		t.Errorf("unequal metrics. expected (%+v) received (%+v) ", expectedMetricsPageThree, metrics)
 	}
+
+	// Test 3. Get the first page of multiple features at once.
+	pages, err := client.ListFirstMetricsPageForFeatureIDsBrowserAndChannel(
+		ctx,
+		[]string{"feature1", "feature2", "nopefeature"},
+		"fooBrowser",
+		shared.StableLabel,
+		WPTTestView,
+		time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2000, time.January, 3, 0, 0, 0, 0, time.UTC),
+		1,
+	)
+	if !errors.Is(err, nil) {
+		t.Errorf("expected no error during listing of metrics. received %s", err.Error())
+	}
+	if _, found := pages["nopefeature"]; found {
+		t.Error("expected no page for an unknown feature")
+	}
+	for _, featureKey := range []string{"feature1", "feature2"} {
+		metrics, token, err := client.ListMetricsForFeatureIDBrowserAndChannel(
+			ctx,
+			featureKey,
+			"fooBrowser",
+			shared.StableLabel,
+			WPTTestView,
+			time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2000, time.January, 3, 0, 0, 0, 0, time.UTC),
+			1,
+			nil,
+		)
+		if !errors.Is(err, nil) {
+			t.Errorf("expected no error during listing of metrics. received %s", err.Error())
+		}
+		expectedPage := WPTRunFeatureMetricsPage{Metrics: metrics, NextPageToken: token}
+		if !reflect.DeepEqual(expectedPage, pages[featureKey]) {
+			t.Errorf("unequal page for %s. expected (%+v) received (%+v) ", featureKey, expectedPage, pages[featureKey])
+		}
+	}
+	if !reflect.DeepEqual(expectedMetricsPageOne, pages["feature1"].Metrics) {
+		t.Errorf("unequal metrics. expected (%+v) received (%+v) ", expectedMetricsPageOne, pages["feature1"].Metrics)
+	}
 }
 
 func testGetAllAggregatedMetrics(ctx context.Context, client *Client, t *testing.T) {
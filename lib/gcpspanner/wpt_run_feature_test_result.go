@@ -0,0 +1,339 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcpspanner
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/api/iterator"
+)
+
+const wptRunFeatureTestResultsTable = "WPTRunFeatureTestResults"
+
+// wptRunFeatureTestResultsBatchSize is the maximum number of rows written per
+// transaction. A run can have tens of thousands of tests which would exceed
+// the mutation limit of a single commit.
+const wptRunFeatureTestResultsBatchSize = 1000
+
+func init() {
+	wptInteropMetricsTemplate = NewQueryTemplate(wptInteropMetricsRawTemplate)
+}
+
+// nolint: gochecknoglobals // WONTFIX. Compile the template once at startup. Startup fails if invalid.
+var (
+	// wptInteropMetricsTemplate is the compiled version of wptInteropMetricsRawTemplate.
+	wptInteropMetricsTemplate BaseQueryTemplate
+)
+
+// SpannerWPTRunFeatureTestResult is a wrapper for the test result that is
+// actually stored in spanner.
+type SpannerWPTRunFeatureTestResult struct {
+	ID           string `spanner:"ID"`
+	WebFeatureID string `spanner:"WebFeatureID"`
+	WPTRunFeatureTestResult
+}
+
+// WPTRunFeatureTestResult represents the result of an individual test for a
+// feature in a run.
+type WPTRunFeatureTestResult struct {
+	TestName      string `spanner:"TestName"`
+	Passed        bool   `spanner:"Passed"`
+	SubtestPass   *int64 `spanner:"SubtestPass"`
+	TotalSubtests *int64 `spanner:"TotalSubtests"`
+}
+
+// UpsertWPTRunFeatureTestResults will upsert the individual test results for
+// a given WPT Run ID. The RunID must exist in a row in the WPTRuns table.
+// Results for unknown web feature keys are skipped.
+func (c *Client) UpsertWPTRunFeatureTestResults(
+	ctx context.Context,
+	externalRunID int64,
+	inputResults map[string][]WPTRunFeatureTestResult) error {
+	wptRunData, err := c.GetWPTRunDataByRunIDForMetrics(ctx, externalRunID)
+	if err != nil {
+		return err
+	}
+
+	var spannerResults []SpannerWPTRunFeatureTestResult
+	for featureKey, results := range inputResults {
+		featureID, err := c.GetIDFromFeatureKey(ctx, NewFeatureKeyFilter(featureKey))
+		if err != nil {
+			if errors.Is(err, ErrQueryReturnedNoResults) {
+				slog.WarnContext(
+					ctx,
+					"unable to find internal webfeatureID for key. will skip", "key",
+					featureKey)
+
+				continue
+			}
+
+			return errors.Join(err, ErrInternalQueryFailure)
+		}
+		if featureID == nil {
+			return ErrInternalQueryFailure
+		}
+		for _, result := range results {
+			spannerResults = append(spannerResults, SpannerWPTRunFeatureTestResult{
+				ID:                      wptRunData.ID,
+				WebFeatureID:            *featureID,
+				WPTRunFeatureTestResult: result,
+			})
+		}
+	}
+
+	for start := 0; start < len(spannerResults); start += wptRunFeatureTestResultsBatchSize {
+		end := min(start+wptRunFeatureTestResultsBatchSize, len(spannerResults))
+		mutations := make([]*spanner.Mutation, 0, end-start)
+		for _, result := range spannerResults[start:end] {
+			m, err := spanner.InsertOrUpdateStruct(wptRunFeatureTestResultsTable, result)
+			if err != nil {
+				return errors.Join(ErrInternalQueryFailure, err)
+			}
+			mutations = append(mutations, m)
+		}
+		_, err := c.ReadWriteTransaction(ctx, func(_ context.Context, txn *spanner.ReadWriteTransaction) error {
+			return txn.BufferWrite(mutations)
+		})
+		if err != nil {
+			return errors.Join(ErrInternalQueryFailure, err)
+		}
+	}
+
+	return nil
+}
+
+const (
+	// wptInteropMetricsRawTemplate calculates the number of tests that pass in
+	// all of the given browsers.
+	// Runs are aligned by the revision of WPT. If a browser has multiple runs
+	// for the same revision, the latest one is used. Only revisions where all
+	// of the browsers have a run are returned.
+	// For the subtest view, the summary files only contain the number of passing
+	// subtests. As a result, the number of subtests passing everywhere for a
+	// test is the minimum number of passing subtests across the browsers.
+	// When requested, the metrics of each feature are returned as well. The
+	// totals of a revision are the sum of the metrics of its features.
+	wptInteropMetricsRawTemplate = `
+	WITH LatestRunPerBrowser AS (
+		SELECT
+			r.FullRevisionHash,
+			r.BrowserName,
+			ARRAY_AGG(r.ID ORDER BY r.TimeStart DESC LIMIT 1)[OFFSET(0)] AS ID,
+			MAX(r.TimeStart) AS TimeStart
+		FROM WPTRuns r
+		WHERE r.Channel = @channel
+			AND r.BrowserName IN UNNEST(@browserNames)
+			AND r.TimeStart >= @startAt AND r.TimeStart < @endAt
+		GROUP BY r.FullRevisionHash, r.BrowserName
+	),
+	AlignedRevisions AS (
+		SELECT
+			FullRevisionHash,
+			MAX(TimeStart) AS TimeStart
+		FROM LatestRunPerBrowser
+		GROUP BY FullRevisionHash
+		HAVING COUNT(BrowserName) = @browserCount
+	),
+	TestResultsPerRevision AS (
+		SELECT
+			ar.FullRevisionHash,
+			ar.TimeStart,
+			tr.WebFeatureID,
+			tr.TestName,
+			COUNTIF(tr.Passed) AS PassingBrowsers,
+			COUNT(tr.SubtestPass) AS BrowsersWithSubtests,
+			MIN(tr.SubtestPass) AS MinSubtestPass,
+			MAX(tr.TotalSubtests) AS MaxTotalSubtests
+		FROM AlignedRevisions ar
+		JOIN LatestRunPerBrowser lr ON lr.FullRevisionHash = ar.FullRevisionHash
+		JOIN WPTRunFeatureTestResults tr ON tr.ID = lr.ID
+		LEFT OUTER JOIN WebFeatures wf ON wf.ID = tr.WebFeatureID
+		LEFT OUTER JOIN ExcludedFeatureKeys efk ON wf.FeatureKey = efk.FeatureKey
+		WHERE ` + removeExcludedKeyFilter + `
+{{ if .FeatureKeyFilter }}
+			{{ .FeatureKeyFilter }}
+{{ end }}
+{{ if .PageFilter }}
+			{{ .PageFilter }}
+{{ end }}
+		GROUP BY ar.FullRevisionHash, ar.TimeStart, tr.WebFeatureID, tr.TestName
+	),
+	FeatureMetricsPerRevision AS (
+		SELECT
+			FullRevisionHash,
+			TimeStart,
+			WebFeatureID,
+{{ if .IsSubtestView }}
+			SUM(MaxTotalSubtests) AS TotalTests,
+			SUM(IF(BrowsersWithSubtests = @browserCount, MinSubtestPass, 0)) AS TestPass
+{{ else }}
+			COUNT(*) AS TotalTests,
+			COUNTIF(PassingBrowsers = @browserCount) AS TestPass
+{{ end }}
+		FROM TestResultsPerRevision
+		GROUP BY FullRevisionHash, TimeStart, WebFeatureID
+	),
+	RevisionMetrics AS (
+		SELECT
+			TimeStart,
+			FullRevisionHash,
+			SUM(TotalTests) AS TotalTests,
+			SUM(TestPass) AS TestPass
+		FROM FeatureMetricsPerRevision
+		GROUP BY FullRevisionHash, TimeStart
+		ORDER BY TimeStart DESC, FullRevisionHash DESC LIMIT @pageSize
+	)
+	SELECT
+		rm.TimeStart,
+		rm.FullRevisionHash,
+		rm.TotalTests,
+		rm.TestPass
+{{ if .IncludeFeatureMetrics }}
+		, ARRAY(
+			SELECT AS STRUCT
+				wf.FeatureKey,
+				fm.TotalTests,
+				fm.TestPass
+			FROM FeatureMetricsPerRevision fm
+			JOIN WebFeatures wf ON wf.ID = fm.WebFeatureID
+			WHERE fm.FullRevisionHash = rm.FullRevisionHash
+			ORDER BY wf.FeatureKey
+		) AS FeatureMetrics
+{{ end }}
+	FROM RevisionMetrics rm
+	ORDER BY rm.TimeStart DESC, rm.FullRevisionHash DESC`
+
+	wptInteropMetricsPaginationRawTemplate = `
+			AND (ar.TimeStart < @lastTimestamp OR
+				ar.TimeStart = @lastTimestamp AND ar.FullRevisionHash < @lastRevision)`
+)
+
+// WPTInteropMetricsTemplateData contains the variables for wptInteropMetricsRawTemplate.
+type WPTInteropMetricsTemplateData struct {
+	FeatureKeyFilter      string
+	PageFilter            string
+	IsSubtestView         bool
+	IncludeFeatureMetrics bool
+}
+
+// WPTInteropMetricWithTime contains the number of tests passing in all
+// browsers for a revision of WPT at a given time.
+type WPTInteropMetricWithTime struct {
+	TimeStart        time.Time `spanner:"TimeStart"`
+	FullRevisionHash string    `spanner:"FullRevisionHash"`
+	TotalTests       *int64    `spanner:"TotalTests"`
+	TestPass         *int64    `spanner:"TestPass"`
+	// FeatureMetrics is only populated when the metrics are scoped to a list of feature keys.
+	FeatureMetrics []*WPTInteropFeatureMetric `spanner:"FeatureMetrics"`
+}
+
+// WPTInteropFeatureMetric contains the number of tests passing in all
+// browsers for a single feature in a revision of WPT.
+type WPTInteropFeatureMetric struct {
+	FeatureKey string `spanner:"FeatureKey"`
+	TotalTests *int64 `spanner:"TotalTests"`
+	TestPass   *int64 `spanner:"TestPass"`
+}
+
+// ListWPTInteropMetrics attempts to return a page of metrics describing how
+// many tests pass in all of the given browsers for a channel. Users can
+// provide a list of web feature keys. If the list is provided, the metrics will
+// be scoped to those feature keys and include the metrics of each of those
+// features. If an empty or nil list is provided, the metrics apply to all
+// features.
+// A time window must be specified to analyze the runs according to the
+// TimeStart of the run.
+// If the page size matches the pageSize, a page token is returned. Else,
+// no page token is returned.
+func (c *Client) ListWPTInteropMetrics(
+	ctx context.Context,
+	featureKeys []string,
+	browsers []string,
+	channel string,
+	metric WPTMetricView,
+	startAt, endAt time.Time,
+	pageSize int,
+	pageToken *string,
+) ([]WPTInteropMetricWithTime, *string, error) {
+	params := map[string]interface{}{
+		"browserNames": browsers,
+		"browserCount": int64(len(browsers)),
+		"channel":      channel,
+		"startAt":      startAt,
+		"endAt":        endAt,
+		"pageSize":     pageSize,
+	}
+
+	tmplData := WPTInteropMetricsTemplateData{
+		FeatureKeyFilter:      "",
+		PageFilter:            "",
+		IsSubtestView:         metric == WPTSubtestView,
+		IncludeFeatureMetrics: false,
+	}
+
+	if len(featureKeys) > 0 {
+		params["featureKeys"] = featureKeys
+		tmplData.FeatureKeyFilter = multipleFeaturesMetricSubsetRawTemplate
+		tmplData.IncludeFeatureMetrics = true
+	}
+
+	if pageToken != nil {
+		cursor, err := decodeWPTInteropCursor(*pageToken)
+		if err != nil {
+			return nil, nil, errors.Join(ErrInternalQueryFailure, err)
+		}
+		params["lastTimestamp"] = cursor.LastTimeStart
+		params["lastRevision"] = cursor.LastRevision
+		tmplData.PageFilter = wptInteropMetricsPaginationRawTemplate
+	}
+
+	tmpl := wptInteropMetricsTemplate.Execute(tmplData)
+	stmt := spanner.NewStatement(tmpl)
+	stmt.Params = params
+
+	txn := c.Single()
+	defer txn.Close()
+	it := txn.Query(ctx, stmt)
+	defer it.Stop()
+
+	var interopMetrics []WPTInteropMetricWithTime
+	for {
+		row, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, nil, errors.Join(ErrInternalQueryFailure, err)
+		}
+		var interopMetric WPTInteropMetricWithTime
+		if err := row.ToStruct(&interopMetric); err != nil {
+			return nil, nil, errors.Join(ErrInternalQueryFailure, err)
+		}
+		interopMetrics = append(interopMetrics, interopMetric)
+	}
+
+	if len(interopMetrics) == pageSize {
+		lastMetric := interopMetrics[len(interopMetrics)-1]
+		newCursor := encodeWPTInteropCursor(lastMetric.TimeStart, lastMetric.FullRevisionHash)
+
+		return interopMetrics, &newCursor, nil
+	}
+
+	return interopMetrics, nil, nil
+}
@@ -0,0 +1,226 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcpspanner
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/web-platform-tests/wpt.fyi/shared"
+)
+
+func getSampleRunTestResults() []struct {
+	ExternalRunID int64
+	Results       map[string][]WPTRunFeatureTestResult
+} {
+	// nolint: dupl // Okay to duplicate for tests
+	return []struct {
+		ExternalRunID int64
+		Results       map[string][]WPTRunFeatureTestResult
+	}{
+		// Older fooBrowser stable run for the same revision. Should be ignored.
+		{
+			ExternalRunID: 0,
+			Results: map[string][]WPTRunFeatureTestResult{
+				"feature1": {
+					{TestName: "a.html", Passed: false, SubtestPass: valuePtr[int64](0), TotalSubtests: valuePtr[int64](2)},
+				},
+			},
+		},
+		// Latest fooBrowser stable run.
+		{
+			ExternalRunID: 6,
+			Results: map[string][]WPTRunFeatureTestResult{
+				"feature1": {
+					{TestName: "a.html", Passed: true, SubtestPass: valuePtr[int64](2), TotalSubtests: valuePtr[int64](2)},
+					{TestName: "b.html", Passed: false, SubtestPass: valuePtr[int64](1), TotalSubtests: valuePtr[int64](3)},
+				},
+				"feature2": {
+					{TestName: "c.html", Passed: true, SubtestPass: valuePtr[int64](1), TotalSubtests: valuePtr[int64](1)},
+				},
+			},
+		},
+		// Latest barBrowser stable run.
+		{
+			ExternalRunID: 8,
+			Results: map[string][]WPTRunFeatureTestResult{
+				"feature1": {
+					{TestName: "a.html", Passed: true, SubtestPass: valuePtr[int64](2), TotalSubtests: valuePtr[int64](2)},
+					{TestName: "b.html", Passed: false, SubtestPass: valuePtr[int64](2), TotalSubtests: valuePtr[int64](3)},
+				},
+				"feature2": {
+					{TestName: "c.html", Passed: false, SubtestPass: valuePtr[int64](0), TotalSubtests: valuePtr[int64](1)},
+				},
+				// Unknown features are skipped.
+				"nopefeature": {
+					{TestName: "d.html", Passed: true, SubtestPass: nil, TotalSubtests: nil},
+				},
+			},
+		},
+	}
+}
+
+func setupRequiredTablesForWPTInteropMetrics(ctx context.Context,
+	client *Client, t *testing.T) {
+	for _, feature := range getSampleFeatures() {
+		_, err := client.UpsertWebFeature(ctx, feature)
+		if err != nil {
+			t.Errorf("unexpected error during insert of features. %s", err.Error())
+		}
+	}
+	for _, run := range getSampleRuns() {
+		err := client.InsertWPTRun(ctx, run)
+		if err != nil {
+			t.Errorf("unexpected error during insert of runs. %s", err.Error())
+		}
+	}
+	for _, result := range getSampleRunTestResults() {
+		err := client.UpsertWPTRunFeatureTestResults(ctx, result.ExternalRunID, result.Results)
+		if err != nil {
+			t.Errorf("unexpected error during insert of test results. %s", err.Error())
+		}
+	}
+}
+
+func TestListWPTInteropMetrics(t *testing.T) {
+	client := getTestDatabase(t)
+	ctx := context.Background()
+	setupRequiredTablesForWPTInteropMetrics(ctx, client, t)
+
+	browsers := []string{"barBrowser", "fooBrowser"}
+	startAt := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	endAt := time.Date(2000, time.January, 3, 0, 0, 0, 0, time.UTC)
+	jan2 := time.Date(2000, time.January, 2, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name                   string
+		featureKeys            []string
+		metricView             WPTMetricView
+		expectedTotal          int64
+		expectedPass           int64
+		expectedFeatureMetrics []*WPTInteropFeatureMetric
+	}{
+		{
+			name:                   "all features, test view",
+			featureKeys:            nil,
+			metricView:             WPTTestView,
+			expectedTotal:          3,
+			expectedPass:           1,
+			expectedFeatureMetrics: nil,
+		},
+		{
+			name:                   "all features, subtest view",
+			featureKeys:            nil,
+			metricView:             WPTSubtestView,
+			expectedTotal:          6,
+			expectedPass:           3,
+			expectedFeatureMetrics: nil,
+		},
+		{
+			name:          "single feature, test view",
+			featureKeys:   []string{"feature2"},
+			metricView:    WPTTestView,
+			expectedTotal: 1,
+			expectedPass:  0,
+			expectedFeatureMetrics: []*WPTInteropFeatureMetric{
+				{FeatureKey: "feature2", TotalTests: valuePtr[int64](1), TestPass: valuePtr[int64](0)},
+			},
+		},
+		{
+			name:          "multiple features, subtest view",
+			featureKeys:   []string{"feature2", "feature1"},
+			metricView:    WPTSubtestView,
+			expectedTotal: 6,
+			expectedPass:  3,
+			expectedFeatureMetrics: []*WPTInteropFeatureMetric{
+				{FeatureKey: "feature1", TotalTests: valuePtr[int64](5), TestPass: valuePtr[int64](3)},
+				{FeatureKey: "feature2", TotalTests: valuePtr[int64](1), TestPass: valuePtr[int64](0)},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			metrics, token, err := client.ListWPTInteropMetrics(
+				ctx, tc.featureKeys, browsers, shared.StableLabel, tc.metricView, startAt, endAt, 100, nil)
+			if err != nil {
+				t.Fatalf("unexpected error. %s", err.Error())
+			}
+			if token != nil {
+				t.Errorf("expected nil token. received %s", *token)
+			}
+			expected := []WPTInteropMetricWithTime{
+				{
+					TimeStart:        jan2,
+					FullRevisionHash: "abcdef0123456789",
+					TotalTests:       valuePtr[int64](tc.expectedTotal),
+					TestPass:         valuePtr[int64](tc.expectedPass),
+					FeatureMetrics:   tc.expectedFeatureMetrics,
+				},
+			}
+			if !slices.EqualFunc(expected, metrics, interopMetricEquality) {
+				t.Errorf("unequal metrics.\nexpected %+v\nreceived %+v", expected, metrics)
+			}
+		})
+	}
+
+	// Pagination.
+	metrics, token, err := client.ListWPTInteropMetrics(
+		ctx, nil, browsers, shared.StableLabel, WPTTestView, startAt, endAt, 1, nil)
+	if err != nil {
+		t.Fatalf("unexpected error. %s", err.Error())
+	}
+	if len(metrics) != 1 || token == nil {
+		t.Fatalf("expected one metric and a token. received %+v %v", metrics, token)
+	}
+	metrics, token, err = client.ListWPTInteropMetrics(
+		ctx, nil, browsers, shared.StableLabel, WPTTestView, startAt, endAt, 1, token)
+	if err != nil {
+		t.Fatalf("unexpected error. %s", err.Error())
+	}
+	if len(metrics) != 0 || token != nil {
+		t.Errorf("expected empty last page. received %+v %v", metrics, token)
+	}
+
+	// No test results for the experimental runs so no revisions are returned.
+	metrics, _, err = client.ListWPTInteropMetrics(
+		ctx, nil, browsers, shared.ExperimentalLabel, WPTTestView, startAt, endAt, 100, nil)
+	if err != nil {
+		t.Fatalf("unexpected error. %s", err.Error())
+	}
+	if len(metrics) != 0 {
+		t.Errorf("expected no metrics. received %+v", metrics)
+	}
+}
+
+func interopMetricEquality(left, right WPTInteropMetricWithTime) bool {
+	return left.TimeStart.Equal(right.TimeStart) &&
+		left.FullRevisionHash == right.FullRevisionHash &&
+		int64PtrEquality(left.TotalTests, right.TotalTests) &&
+		int64PtrEquality(left.TestPass, right.TestPass) &&
+		slices.EqualFunc(left.FeatureMetrics, right.FeatureMetrics, interopFeatureMetricEquality)
+}
+
+func interopFeatureMetricEquality(left, right *WPTInteropFeatureMetric) bool {
+	return left.FeatureKey == right.FeatureKey &&
+		int64PtrEquality(left.TotalTests, right.TotalTests) &&
+		int64PtrEquality(left.TestPass, right.TestPass)
+}
+
+func int64PtrEquality(left, right *int64) bool {
+	return (left == nil && right == nil) ||
+		(left != nil && right != nil && *left == *right)
+}
@@ -65,6 +65,13 @@ func (c *Client) Store(ctx context.Context, data io.Reader, filename string) err
 	return err
 }
 
+// Ping checks that the bucket can be reached.
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.bucketHandle.Attrs(ctx)
+
+	return err
+}
+
 func (c *Client) GetLocation() string {
 	return c.bucketName
 }
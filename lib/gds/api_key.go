@@ -0,0 +1,111 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gds
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"cloud.google.com/go/datastore"
+)
+
+const apiKeyKey = "APIKeyKey"
+
+// APIKey identifies a client of the API.
+// Only the hash of the key is stored so that a leak of the datastore does not leak usable keys.
+type APIKey struct {
+	// KeyHash is the result of HashAPIKey for the key given to the client.
+	KeyHash string `datastore:"key_hash"`
+	// Owner is a human readable name of whoever the key was issued to.
+	Owner string `datastore:"owner"`
+	// QuotaTier determines the rate limits for the client.
+	QuotaTier string `datastore:"quota_tier"`
+	// Revoked keys are rejected.
+	Revoked bool `datastore:"revoked"`
+}
+
+// HashAPIKey returns the value stored in APIKey.KeyHash for the given key.
+func HashAPIKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(hash[:])
+}
+
+// apiKeyHashFilter implements Filterable to filter by key_hash.
+// Compatible kinds:
+// - apiKeyKey.
+type apiKeyHashFilter struct {
+	keyHash string
+}
+
+func (f apiKeyHashFilter) FilterQuery(query *datastore.Query) *datastore.Query {
+	return query.FilterField("key_hash", "=", f.keyHash)
+}
+
+// apiKeyMerge implements Mergeable for APIKey.
+type apiKeyMerge struct{}
+
+func (m apiKeyMerge) Merge(existing *APIKey, new *APIKey) *APIKey {
+	return &APIKey{
+		Owner:     cmp.Or[string](new.Owner, existing.Owner, ""),
+		QuotaTier: cmp.Or[string](new.QuotaTier, existing.QuotaTier, ""),
+		// Once revoked, a key stays revoked.
+		Revoked: existing.Revoked || new.Revoked,
+		// The below fields cannot be overridden during a merge.
+		KeyHash: existing.KeyHash,
+	}
+}
+
+// UpsertAPIKey inserts/updates the given API key.
+func (c *Client) UpsertAPIKey(
+	ctx context.Context,
+	data APIKey,
+) error {
+	entityClient := entityClient[APIKey]{c}
+
+	return entityClient.upsert(ctx,
+		apiKeyKey,
+		&data,
+		apiKeyMerge{},
+		apiKeyHashFilter{
+			keyHash: data.KeyHash,
+		},
+	)
+}
+
+// RevokeAPIKey marks the API key with the given hash as revoked.
+func (c *Client) RevokeAPIKey(ctx context.Context, keyHash string) error {
+	return c.UpsertAPIKey(ctx, APIKey{
+		KeyHash:   keyHash,
+		Owner:     "",
+		QuotaTier: "",
+		Revoked:   true,
+	})
+}
+
+// GetAPIKeyByHash attempts to get the API key with the given hash.
+func (c *Client) GetAPIKeyByHash(ctx context.Context, keyHash string) (*APIKey, error) {
+	entityClient := entityClient[APIKey]{c}
+	key, err := entityClient.get(ctx, apiKeyKey, apiKeyHashFilter{
+		keyHash: keyHash,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
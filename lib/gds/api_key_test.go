@@ -0,0 +1,105 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gds
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestAPIKeyOperations(t *testing.T) {
+	ctx := context.Background()
+	client, cleanup := getTestDatabase(ctx, t)
+	defer cleanup()
+
+	keyHash := HashAPIKey("secret")
+
+	// Part 0. Try to get a key that does not exist yet.
+	key, err := client.GetAPIKeyByHash(ctx, keyHash)
+	if !errors.Is(err, ErrEntityNotFound) {
+		t.Errorf("unexpected error %v", err)
+	}
+	if key != nil {
+		t.Error("expected nil key")
+	}
+
+	// Part 1. Insert the key.
+	version1 := &APIKey{
+		KeyHash:   keyHash,
+		Owner:     "ci",
+		QuotaTier: "standard",
+		Revoked:   false,
+	}
+	err = client.UpsertAPIKey(ctx, *version1)
+	if err != nil {
+		t.Errorf("failed to upsert %s", err.Error())
+	}
+	key, err = client.GetAPIKeyByHash(ctx, keyHash)
+	if err != nil {
+		t.Errorf("failed to get api key %s", err.Error())
+	}
+	if !reflect.DeepEqual(version1, key) {
+		t.Errorf("unexpected key %v", key)
+	}
+
+	// Part 2. Change the quota tier only.
+	err = client.UpsertAPIKey(ctx, APIKey{
+		KeyHash:   keyHash,
+		Owner:     "",
+		QuotaTier: "high",
+		Revoked:   false,
+	})
+	if err != nil {
+		t.Errorf("failed to upsert again %s", err.Error())
+	}
+	expectedVersion2 := &APIKey{
+		KeyHash:   keyHash,
+		Owner:     "ci",
+		QuotaTier: "high",
+		Revoked:   false,
+	}
+	key, err = client.GetAPIKeyByHash(ctx, keyHash)
+	if err != nil {
+		t.Errorf("failed to get api key %s", err.Error())
+	}
+	if !reflect.DeepEqual(expectedVersion2, key) {
+		t.Errorf("unexpected key %v", key)
+	}
+
+	// Part 3. Revoke the key. It cannot be un-revoked by an upsert.
+	err = client.RevokeAPIKey(ctx, keyHash)
+	if err != nil {
+		t.Errorf("failed to revoke %s", err.Error())
+	}
+	err = client.UpsertAPIKey(ctx, *version1)
+	if err != nil {
+		t.Errorf("failed to upsert after revoke %s", err.Error())
+	}
+	expectedVersion3 := &APIKey{
+		KeyHash:   keyHash,
+		Owner:     "ci",
+		QuotaTier: "standard",
+		Revoked:   true,
+	}
+	key, err = client.GetAPIKeyByHash(ctx, keyHash)
+	if err != nil {
+		t.Errorf("failed to get api key %s", err.Error())
+	}
+	if !reflect.DeepEqual(expectedVersion3, key) {
+		t.Errorf("unexpected key %v", key)
+	}
+}
@@ -51,6 +51,13 @@ func NewDatastoreClient(projectID string, database *string) (*Client, error) {
 	return &Client{client}, nil
 }
 
+// Ping runs a keys only query to check that the database can be reached.
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.GetAll(ctx, datastore.NewQuery(featureMetadataKey).KeysOnly().Limit(1), nil)
+
+	return err
+}
+
 // Filterable modifies a query with a given filter.
 type Filterable interface {
 	FilterQuery(*datastore.Query) *datastore.Query
@@ -179,3 +186,24 @@ func (c entityClient[T]) get(ctx context.Context, kind string, filterables ...Fi
 
 	return data[0], nil
 }
+
+// delete removes every entity of the kind that matches the filters.
+// Returns ErrEntityNotFound if no entity matched.
+func (c entityClient[T]) delete(ctx context.Context, kind string, filterables ...Filterable) error {
+	query := datastore.NewQuery(kind).KeysOnly()
+	for _, filterable := range filterables {
+		query = filterable.FilterQuery(query)
+	}
+	keys, err := c.GetAll(ctx, query, nil)
+	if err != nil {
+		slog.Error("failed to list keys", "error", err, "kind", kind)
+
+		return err
+	}
+
+	if len(keys) < 1 {
+		return ErrEntityNotFound
+	}
+
+	return c.DeleteMulti(ctx, keys)
+}
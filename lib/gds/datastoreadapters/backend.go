@@ -20,10 +20,15 @@ import (
 
 	"github.com/GoogleChrome/webstatus.dev/lib/gds"
 	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/backend"
+	"github.com/GoogleChrome/webstatus.dev/lib/httpmiddlewares"
 )
 
 type BackendDatastoreClient interface {
 	GetWebFeatureMetadata(ctx context.Context, webFeatureID string) (*gds.FeatureMetadata, error)
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (*gds.APIKey, error)
+	CreateSubscription(ctx context.Context, data gds.Subscription) error
+	GetSubscription(ctx context.Context, id string) (*gds.Subscription, error)
+	DeleteSubscription(ctx context.Context, id string) error
 }
 
 // Backend converts queries to datastore to usable entities for the backend
@@ -72,3 +77,104 @@ func (d *Backend) GetFeatureMetadata(
 		Description: &metadata.Description,
 	}, nil
 }
+
+// ValidateAPIKey returns the identity of the client that owns the key.
+func (d *Backend) ValidateAPIKey(
+	ctx context.Context,
+	key string,
+) (*httpmiddlewares.ClientIdentity, error) {
+	keyHash := gds.HashAPIKey(key)
+	apiKey, err := d.client.GetAPIKeyByHash(ctx, keyHash)
+	if errors.Is(err, gds.ErrEntityNotFound) {
+		return nil, httpmiddlewares.ErrInvalidAPIKey
+	} else if err != nil {
+		return nil, err
+	}
+	if apiKey.Revoked {
+		return nil, httpmiddlewares.ErrInvalidAPIKey
+	}
+
+	return &httpmiddlewares.ClientIdentity{
+		ID:        keyHash,
+		Owner:     apiKey.Owner,
+		QuotaTier: apiKey.QuotaTier,
+	}, nil
+}
+
+// CreateSubscription stores the subscription for the client, including its secret.
+func (d *Backend) CreateSubscription(
+	ctx context.Context,
+	clientID string,
+	subscription backend.Subscription,
+) error {
+	var featureIDs []string
+	if subscription.FeatureIds != nil {
+		featureIDs = *subscription.FeatureIds
+	}
+	var secret string
+	if subscription.Secret != nil {
+		secret = *subscription.Secret
+	}
+
+	return d.client.CreateSubscription(ctx, gds.Subscription{
+		ID:           subscription.Id,
+		ClientID:     clientID,
+		Query:        subscription.Query,
+		FeatureIDs:   featureIDs,
+		CallbackURL:  subscription.CallbackUrl,
+		Secret:       secret,
+		WPTThreshold: subscription.WptThreshold,
+		CreatedAt:    subscription.CreatedAt,
+	})
+}
+
+// getOwnedSubscription hides subscriptions of other clients as if they did not exist.
+func (d *Backend) getOwnedSubscription(ctx context.Context, clientID, id string) (*gds.Subscription, error) {
+	subscription, err := d.client.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if subscription.ClientID != clientID {
+		return nil, gds.ErrEntityNotFound
+	}
+
+	return subscription, nil
+}
+
+// GetSubscription returns the subscription without its secret.
+func (d *Backend) GetSubscription(
+	ctx context.Context,
+	clientID string,
+	subscriptionID string,
+) (*backend.Subscription, error) {
+	subscription, err := d.getOwnedSubscription(ctx, clientID, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	var featureIDs *[]string
+	if len(subscription.FeatureIDs) > 0 {
+		featureIDs = &subscription.FeatureIDs
+	}
+
+	return &backend.Subscription{
+		Id:           subscription.ID,
+		CallbackUrl:  subscription.CallbackURL,
+		Query:        subscription.Query,
+		FeatureIds:   featureIDs,
+		WptThreshold: subscription.WPTThreshold,
+		CreatedAt:    subscription.CreatedAt,
+		Secret:       nil,
+	}, nil
+}
+
+func (d *Backend) DeleteSubscription(
+	ctx context.Context,
+	clientID string,
+	subscriptionID string,
+) error {
+	if _, err := d.getOwnedSubscription(ctx, clientID, subscriptionID); err != nil {
+		return err
+	}
+
+	return d.client.DeleteSubscription(ctx, subscriptionID)
+}
@@ -19,9 +19,11 @@ import (
 	"errors"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/GoogleChrome/webstatus.dev/lib/gds"
 	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/backend"
+	"github.com/GoogleChrome/webstatus.dev/lib/httpmiddlewares"
 )
 
 type mockGetFeatureMetadataConfig struct {
@@ -30,9 +32,54 @@ type mockGetFeatureMetadataConfig struct {
 	err               error
 }
 
+type mockGetAPIKeyByHashConfig struct {
+	expectedKeyHash string
+	result          *gds.APIKey
+	err             error
+}
+
+type mockGetSubscriptionConfig struct {
+	expectedID string
+	result     *gds.Subscription
+	err        error
+}
+
 type mockBackendDatastoreClient struct {
 	t                         *testing.T
 	mockGetFeatureMetadataCfg mockGetFeatureMetadataConfig
+	mockGetAPIKeyByHashCfg    mockGetAPIKeyByHashConfig
+	mockGetSubscriptionCfg    mockGetSubscriptionConfig
+	created                   *[]gds.Subscription
+	deleted                   *[]string
+}
+
+func (c mockBackendDatastoreClient) CreateSubscription(_ context.Context, data gds.Subscription) error {
+	*c.created = append(*c.created, data)
+
+	return nil
+}
+
+func (c mockBackendDatastoreClient) GetSubscription(_ context.Context, id string) (*gds.Subscription, error) {
+	if c.mockGetSubscriptionCfg.expectedID != id {
+		c.t.Error("unexpected input to mock")
+	}
+
+	return c.mockGetSubscriptionCfg.result, c.mockGetSubscriptionCfg.err
+}
+
+func (c mockBackendDatastoreClient) DeleteSubscription(_ context.Context, id string) error {
+	*c.deleted = append(*c.deleted, id)
+
+	return nil
+}
+
+func (c mockBackendDatastoreClient) GetAPIKeyByHash(
+	_ context.Context, keyHash string) (*gds.APIKey, error) {
+	if c.mockGetAPIKeyByHashCfg.expectedKeyHash != keyHash {
+		c.t.Error("unexpected input to mock")
+	}
+
+	return c.mockGetAPIKeyByHashCfg.result, c.mockGetAPIKeyByHashCfg.err
 }
 
 func (c mockBackendDatastoreClient) GetWebFeatureMetadata(
@@ -132,6 +179,7 @@ func TestGetFeatureMetadata(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
+			//nolint: exhaustruct
 			mock := mockBackendDatastoreClient{
 				t:                         t,
 				mockGetFeatureMetadataCfg: tc.mockGetFeatureMetadataCfg,
@@ -147,3 +195,172 @@ func TestGetFeatureMetadata(t *testing.T) {
 		})
 	}
 }
+
+var errGetAPIKeyTestError = errors.New("get api key tests error")
+
+func TestValidateAPIKey(t *testing.T) {
+	keyHash := gds.HashAPIKey("secret")
+	testCases := []struct {
+		name                   string
+		mockGetAPIKeyByHashCfg mockGetAPIKeyByHashConfig
+		expectedIdentity       *httpmiddlewares.ClientIdentity
+		expectedErr            error
+	}{
+		{
+			name: "valid key",
+			mockGetAPIKeyByHashCfg: mockGetAPIKeyByHashConfig{
+				expectedKeyHash: keyHash,
+				result: &gds.APIKey{
+					KeyHash:   keyHash,
+					Owner:     "ci",
+					QuotaTier: "high",
+					Revoked:   false,
+				},
+				err: nil,
+			},
+			expectedIdentity: &httpmiddlewares.ClientIdentity{
+				ID:        keyHash,
+				Owner:     "ci",
+				QuotaTier: "high",
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "revoked key",
+			mockGetAPIKeyByHashCfg: mockGetAPIKeyByHashConfig{
+				expectedKeyHash: keyHash,
+				result: &gds.APIKey{
+					KeyHash:   keyHash,
+					Owner:     "ci",
+					QuotaTier: "high",
+					Revoked:   true,
+				},
+				err: nil,
+			},
+			expectedIdentity: nil,
+			expectedErr:      httpmiddlewares.ErrInvalidAPIKey,
+		},
+		{
+			name: "unknown key",
+			mockGetAPIKeyByHashCfg: mockGetAPIKeyByHashConfig{
+				expectedKeyHash: keyHash,
+				result:          nil,
+				err:             gds.ErrEntityNotFound,
+			},
+			expectedIdentity: nil,
+			expectedErr:      httpmiddlewares.ErrInvalidAPIKey,
+		},
+		{
+			name: "error",
+			mockGetAPIKeyByHashCfg: mockGetAPIKeyByHashConfig{
+				expectedKeyHash: keyHash,
+				result:          nil,
+				err:             errGetAPIKeyTestError,
+			},
+			expectedIdentity: nil,
+			expectedErr:      errGetAPIKeyTestError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			//nolint: exhaustruct
+			mock := mockBackendDatastoreClient{
+				t:                      t,
+				mockGetAPIKeyByHashCfg: tc.mockGetAPIKeyByHashCfg,
+			}
+			b := NewBackend(mock)
+			identity, err := b.ValidateAPIKey(context.Background(), "secret")
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("unexpected error %v", err)
+			}
+			if !reflect.DeepEqual(identity, tc.expectedIdentity) {
+				t.Errorf("unexpected identity %+v", identity)
+			}
+		})
+	}
+}
+
+func TestSubscriptions(t *testing.T) {
+	createdAt := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	var created []gds.Subscription
+	var deleted []string
+	mock := mockBackendDatastoreClient{
+		t:                         t,
+		mockGetFeatureMetadataCfg: mockGetFeatureMetadataConfig{expectedFeatureID: "", result: nil, err: nil},
+		mockGetAPIKeyByHashCfg:    mockGetAPIKeyByHashConfig{expectedKeyHash: "", result: nil, err: nil},
+		mockGetSubscriptionCfg: mockGetSubscriptionConfig{
+			expectedID: "sub1",
+			result: &gds.Subscription{
+				ID:           "sub1",
+				ClientID:     "hash",
+				Query:        nil,
+				FeatureIDs:   []string{"grid"},
+				CallbackURL:  "https://example.com/hook",
+				Secret:       "secret",
+				WPTThreshold: nil,
+				CreatedAt:    createdAt,
+			},
+			err: nil,
+		},
+		created: &created,
+		deleted: &deleted,
+	}
+	b := NewBackend(mock)
+
+	secret := "secret"
+	err := b.CreateSubscription(context.Background(), "hash", backend.Subscription{
+		Id:           "sub1",
+		CallbackUrl:  "https://example.com/hook",
+		Query:        nil,
+		FeatureIds:   &[]string{"grid"},
+		WptThreshold: nil,
+		CreatedAt:    createdAt,
+		Secret:       &secret,
+	})
+	if err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if len(created) != 1 || !reflect.DeepEqual(created[0], *mock.mockGetSubscriptionCfg.result) {
+		t.Errorf("unexpected created subscriptions %+v", created)
+	}
+
+	// The secret is never returned after creation.
+	subscription, err := b.GetSubscription(context.Background(), "hash", "sub1")
+	if err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	expected := &backend.Subscription{
+		Id:           "sub1",
+		CallbackUrl:  "https://example.com/hook",
+		Query:        nil,
+		FeatureIds:   &[]string{"grid"},
+		WptThreshold: nil,
+		CreatedAt:    createdAt,
+		Secret:       nil,
+	}
+	if !reflect.DeepEqual(subscription, expected) {
+		t.Errorf("unexpected subscription %+v", subscription)
+	}
+
+	// Other clients cannot see or delete the subscription.
+	_, err = b.GetSubscription(context.Background(), "other-hash", "sub1")
+	if !errors.Is(err, gds.ErrEntityNotFound) {
+		t.Errorf("expected not found error, received %v", err)
+	}
+	err = b.DeleteSubscription(context.Background(), "other-hash", "sub1")
+	if !errors.Is(err, gds.ErrEntityNotFound) {
+		t.Errorf("expected not found error, received %v", err)
+	}
+	if len(deleted) != 0 {
+		t.Errorf("expected no deletes, received %v", deleted)
+	}
+
+	err = b.DeleteSubscription(context.Background(), "hash", "sub1")
+	if err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if !reflect.DeepEqual(deleted, []string{"sub1"}) {
+		t.Errorf("unexpected deletes %v", deleted)
+	}
+}
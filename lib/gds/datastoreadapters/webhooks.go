@@ -0,0 +1,106 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datastoreadapters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/GoogleChrome/webstatus.dev/lib/gds"
+	"github.com/GoogleChrome/webstatus.dev/lib/webhooks"
+)
+
+// WebhooksDatastoreClient expects a subset of the functionality from lib/gds that only apply to webhooks.
+type WebhooksDatastoreClient interface {
+	ListSubscriptions(ctx context.Context, pageToken *string) ([]*gds.Subscription, *string, error)
+	GetFeatureStateSnapshot(ctx context.Context, name string) (*gds.FeatureStateSnapshot, error)
+	SaveFeatureStateSnapshot(ctx context.Context, data gds.FeatureStateSnapshot, expectedVersion int64) error
+}
+
+// latestFeatureStateSnapshot is the name of the snapshot shared by every consumer.
+const latestFeatureStateSnapshot = "latest"
+
+// NewWebhooks constructs an adapter for the webhook notifier.
+func NewWebhooks(client WebhooksDatastoreClient) *Webhooks {
+	return &Webhooks{client: client, now: time.Now}
+}
+
+// Webhooks implements webhooks.SubscriptionStore.
+type Webhooks struct {
+	client WebhooksDatastoreClient
+	now    func() time.Time
+}
+
+func (w *Webhooks) ListAllSubscriptions(ctx context.Context) ([]webhooks.Subscription, error) {
+	var ret []webhooks.Subscription
+	var pageToken *string
+	for {
+		page, nextPageToken, err := w.client.ListSubscriptions(ctx, pageToken)
+		if err != nil {
+			return nil, err
+		}
+		for _, subscription := range page {
+			ret = append(ret, webhooks.Subscription{
+				ID:           subscription.ID,
+				Query:        subscription.Query,
+				FeatureIDs:   subscription.FeatureIDs,
+				CallbackURL:  subscription.CallbackURL,
+				Secret:       subscription.Secret,
+				WPTThreshold: subscription.WPTThreshold,
+			})
+		}
+		// The cursor does not change once every entity was read.
+		if len(page) == 0 || nextPageToken == nil || (pageToken != nil && *nextPageToken == *pageToken) {
+			return ret, nil
+		}
+		pageToken = nextPageToken
+	}
+}
+
+func (w *Webhooks) GetFeatureStates(ctx context.Context) ([]webhooks.FeatureState, int64, error) {
+	snapshot, err := w.client.GetFeatureStateSnapshot(ctx, latestFeatureStateSnapshot)
+	if errors.Is(err, gds.ErrEntityNotFound) {
+		return nil, 0, webhooks.ErrNoFeatureStates
+	} else if err != nil {
+		return nil, 0, err
+	}
+	var states []webhooks.FeatureState
+	if err := json.Unmarshal(snapshot.Data, &states); err != nil {
+		return nil, 0, err
+	}
+
+	return states, snapshot.Version, nil
+}
+
+func (w *Webhooks) SaveFeatureStates(ctx context.Context, states []webhooks.FeatureState, version int64) error {
+	data, err := json.Marshal(states)
+	if err != nil {
+		return err
+	}
+
+	err = w.client.SaveFeatureStateSnapshot(ctx, gds.FeatureStateSnapshot{
+		Name:      latestFeatureStateSnapshot,
+		Data:      data,
+		UpdatedAt: w.now().UTC(),
+		Version:   0,
+	}, version)
+	if errors.Is(err, gds.ErrFeatureStateSnapshotConflict) {
+		return errors.Join(webhooks.ErrFeatureStatesChanged, err)
+	}
+
+	return err
+}
@@ -0,0 +1,133 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datastoreadapters
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/GoogleChrome/webstatus.dev/lib/gds"
+	"github.com/GoogleChrome/webstatus.dev/lib/webhooks"
+)
+
+type mockWebhooksDatastoreClient struct {
+	pages    [][]*gds.Subscription
+	snapshot *gds.FeatureStateSnapshot
+}
+
+func (c *mockWebhooksDatastoreClient) ListSubscriptions(
+	_ context.Context, pageToken *string) ([]*gds.Subscription, *string, error) {
+	idx := 0
+	if pageToken != nil {
+		idx = len(*pageToken)
+	}
+	if idx >= len(c.pages) {
+		return nil, pageToken, nil
+	}
+	next := string(make([]byte, idx+1))
+
+	return c.pages[idx], &next, nil
+}
+
+func (c *mockWebhooksDatastoreClient) GetFeatureStateSnapshot(
+	_ context.Context, _ string) (*gds.FeatureStateSnapshot, error) {
+	if c.snapshot == nil {
+		return nil, gds.ErrEntityNotFound
+	}
+
+	return c.snapshot, nil
+}
+
+func (c *mockWebhooksDatastoreClient) SaveFeatureStateSnapshot(
+	_ context.Context, data gds.FeatureStateSnapshot, expectedVersion int64) error {
+	var version int64
+	if c.snapshot != nil {
+		version = c.snapshot.Version
+	}
+	if version != expectedVersion {
+		return gds.ErrFeatureStateSnapshotConflict
+	}
+	data.Version = expectedVersion + 1
+	c.snapshot = &data
+
+	return nil
+}
+
+func TestWebhooksListAllSubscriptions(t *testing.T) {
+	threshold := 0.5
+	client := &mockWebhooksDatastoreClient{
+		pages: [][]*gds.Subscription{
+			{
+				// nolint: exhaustruct // ClientID and CreatedAt are not used by the notifier.
+				{ID: "sub1", FeatureIDs: []string{"grid"}, CallbackURL: "https://a", Secret: "s1"},
+			},
+			{
+				// nolint: exhaustruct // ClientID and CreatedAt are not used by the notifier.
+				{ID: "sub2", CallbackURL: "https://b", Secret: "s2", WPTThreshold: &threshold},
+			},
+		},
+		snapshot: nil,
+	}
+	subscriptions, err := NewWebhooks(client).ListAllSubscriptions(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	expected := []webhooks.Subscription{
+		{ID: "sub1", Query: nil, FeatureIDs: []string{"grid"}, CallbackURL: "https://a", Secret: "s1",
+			WPTThreshold: nil},
+		{ID: "sub2", Query: nil, FeatureIDs: nil, CallbackURL: "https://b", Secret: "s2",
+			WPTThreshold: &threshold},
+	}
+	if !reflect.DeepEqual(subscriptions, expected) {
+		t.Errorf("unexpected subscriptions %+v", subscriptions)
+	}
+}
+
+func TestWebhooksFeatureStates(t *testing.T) {
+	client := &mockWebhooksDatastoreClient{pages: nil, snapshot: nil}
+	adapter := NewWebhooks(client)
+
+	_, _, err := adapter.GetFeatureStates(context.Background())
+	if !errors.Is(err, webhooks.ErrNoFeatureStates) {
+		t.Errorf("expected no feature states error, received %v", err)
+	}
+
+	states := []webhooks.FeatureState{
+		{
+			FeatureID:         "grid",
+			BaselineStatus:    "widely",
+			AvailableBrowsers: []string{"chrome", "safari"},
+			StableWPTScores:   map[string]float64{"chrome": 0.99},
+		},
+	}
+	if err := adapter.SaveFeatureStates(context.Background(), states, 0); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	saved, version, err := adapter.GetFeatureStates(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !reflect.DeepEqual(saved, states) || version != 1 {
+		t.Errorf("unexpected states %+v version %d", saved, version)
+	}
+
+	// A save based on the states that were read before the last save is rejected.
+	err = adapter.SaveFeatureStates(context.Background(), states, 0)
+	if !errors.Is(err, webhooks.ErrFeatureStatesChanged) {
+		t.Errorf("expected feature states changed error, received %v", err)
+	}
+}
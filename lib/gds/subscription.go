@@ -0,0 +1,159 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gds
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"cloud.google.com/go/datastore"
+)
+
+const subscriptionKey = "SubscriptionKey"
+
+// Subscription is a webhook that is called when the matched features change.
+// Either Query or FeatureIDs selects the features.
+type Subscription struct {
+	// ID is the public identifier of the subscription.
+	ID string `datastore:"id"`
+	// ClientID is the ID of the API key that created the subscription. See httpmiddlewares.ClientIdentity.
+	// The owner of a key is a free-text label that several keys can share, so it is not used.
+	// Rotating a key does not carry its subscriptions over to the new key.
+	ClientID string `datastore:"client_id"`
+	// Query is a feature search query.
+	Query *string `datastore:"query"`
+	// FeatureIDs are web features keys.
+	FeatureIDs  []string `datastore:"feature_ids"`
+	CallbackURL string   `datastore:"callback_url,noindex"`
+	// Secret is used to sign the payloads sent to the callback URL.
+	Secret string `datastore:"secret,noindex"`
+	// WPTThreshold is the stable WPT score that triggers a notification when crossed.
+	WPTThreshold *float64  `datastore:"wpt_threshold,noindex"`
+	CreatedAt    time.Time `datastore:"created_at"`
+}
+
+// subscriptionIDFilter implements Filterable to filter by id.
+// Compatible kinds:
+// - subscriptionKey.
+type subscriptionIDFilter struct {
+	id string
+}
+
+func (f subscriptionIDFilter) FilterQuery(query *datastore.Query) *datastore.Query {
+	return query.FilterField("id", "=", f.id)
+}
+
+// CreateSubscription inserts a new subscription.
+// The caller is responsible for generating a unique ID.
+func (c *Client) CreateSubscription(ctx context.Context, data Subscription) error {
+	_, err := c.Put(ctx, datastore.IncompleteKey(subscriptionKey, nil), &data)
+
+	return err
+}
+
+// GetSubscription attempts to get the subscription with the given ID.
+func (c *Client) GetSubscription(ctx context.Context, id string) (*Subscription, error) {
+	entityClient := entityClient[Subscription]{c}
+	subscription, err := entityClient.get(ctx, subscriptionKey, subscriptionIDFilter{id: id})
+	if err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+// DeleteSubscription removes the subscription with the given ID.
+func (c *Client) DeleteSubscription(ctx context.Context, id string) error {
+	entityClient := entityClient[Subscription]{c}
+
+	return entityClient.delete(ctx, subscriptionKey, subscriptionIDFilter{id: id})
+}
+
+// ListSubscriptions returns a page of subscriptions.
+func (c *Client) ListSubscriptions(ctx context.Context, pageToken *string) ([]*Subscription, *string, error) {
+	entityClient := entityClient[Subscription]{c}
+
+	return entityClient.list(ctx, subscriptionKey, pageToken)
+}
+
+const featureStateSnapshotKey = "FeatureStateSnapshotKey"
+
+// FeatureStateSnapshot contains the state of the features that was last used to notify subscriptions.
+type FeatureStateSnapshot struct {
+	// Name identifies the snapshot. Currently, only one snapshot exists.
+	Name string `datastore:"name"`
+	// Data is the encoded state. It is opaque to datastore.
+	Data      []byte    `datastore:"data,noindex"`
+	UpdatedAt time.Time `datastore:"updated_at"`
+	// Version is incremented by every save. A snapshot that was never saved has version 0.
+	Version int64 `datastore:"version,noindex"`
+}
+
+// ErrFeatureStateSnapshotConflict indicates that the snapshot was saved again since the expected version was read.
+var ErrFeatureStateSnapshotConflict = errors.New("feature state snapshot was saved concurrently")
+
+// featureStateSnapshotNameFilter implements Filterable to filter by name.
+// Compatible kinds:
+// - featureStateSnapshotKey.
+type featureStateSnapshotNameFilter struct {
+	name string
+}
+
+func (f featureStateSnapshotNameFilter) FilterQuery(query *datastore.Query) *datastore.Query {
+	return query.FilterField("name", "=", f.name)
+}
+
+// SaveFeatureStateSnapshot inserts/replaces the snapshot with the same name if its version is still
+// expectedVersion. The version of data is ignored and the saved snapshot gets the next version.
+// Returns ErrFeatureStateSnapshotConflict if the snapshot was saved by someone else in the meantime.
+func (c *Client) SaveFeatureStateSnapshot(
+	ctx context.Context, data FeatureStateSnapshot, expectedVersion int64) error {
+	// The snapshot is keyed by its name so that two transactions that insert the first version conflict too.
+	key := datastore.NameKey(featureStateSnapshotKey, data.Name, nil)
+	_, err := c.RunInTransaction(ctx, func(tx *datastore.Transaction) error {
+		var existing FeatureStateSnapshot
+		var version int64
+		err := tx.Get(key, &existing)
+		if err == nil {
+			version = existing.Version
+		} else if !errors.Is(err, datastore.ErrNoSuchEntity) {
+			slog.ErrorContext(ctx, "unable to get feature state snapshot", "error", err)
+
+			return err
+		}
+		if version != expectedVersion {
+			return ErrFeatureStateSnapshotConflict
+		}
+		data.Version = expectedVersion + 1
+		_, err = tx.Put(key, &data)
+
+		return err
+	})
+
+	return err
+}
+
+// GetFeatureStateSnapshot attempts to get the snapshot with the given name.
+func (c *Client) GetFeatureStateSnapshot(ctx context.Context, name string) (*FeatureStateSnapshot, error) {
+	entityClient := entityClient[FeatureStateSnapshot]{c}
+	snapshot, err := entityClient.get(ctx, featureStateSnapshotKey, featureStateSnapshotNameFilter{name: name})
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
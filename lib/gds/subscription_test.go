@@ -0,0 +1,134 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gds
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSubscriptionOperations(t *testing.T) {
+	ctx := context.Background()
+	client, cleanup := getTestDatabase(ctx, t)
+	defer cleanup()
+
+	// Part 0. Try to get a subscription that does not exist yet.
+	subscription, err := client.GetSubscription(ctx, "sub1")
+	if !errors.Is(err, ErrEntityNotFound) {
+		t.Errorf("unexpected error %v", err)
+	}
+	if subscription != nil {
+		t.Error("expected nil subscription")
+	}
+
+	// Part 1. Create two subscriptions.
+	query := "baseline_status:limited"
+	threshold := 0.9
+	sub1 := &Subscription{
+		ID:           "sub1",
+		ClientID:     "hash",
+		Query:        &query,
+		FeatureIDs:   nil,
+		CallbackURL:  "https://example.com/hook",
+		Secret:       "secret1",
+		WPTThreshold: &threshold,
+		CreatedAt:    time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+	sub2 := &Subscription{
+		ID:           "sub2",
+		ClientID:     "hash",
+		Query:        nil,
+		FeatureIDs:   []string{"grid", "subgrid"},
+		CallbackURL:  "https://example.com/hook2",
+		Secret:       "secret2",
+		WPTThreshold: nil,
+		CreatedAt:    time.Date(2024, time.January, 2, 0, 0, 0, 0, time.UTC),
+	}
+	for _, sub := range []*Subscription{sub1, sub2} {
+		if err := client.CreateSubscription(ctx, *sub); err != nil {
+			t.Fatalf("failed to create subscription %s", err.Error())
+		}
+	}
+	subscription, err = client.GetSubscription(ctx, "sub2")
+	if err != nil {
+		t.Errorf("failed to get subscription %s", err.Error())
+	}
+	if !reflect.DeepEqual(sub2, subscription) {
+		t.Errorf("unexpected subscription %v", subscription)
+	}
+	subscriptions, _, err := client.ListSubscriptions(ctx, nil)
+	if err != nil {
+		t.Errorf("failed to list subscriptions %s", err.Error())
+	}
+	if len(subscriptions) != 2 {
+		t.Errorf("expected 2 subscriptions, received %d", len(subscriptions))
+	}
+
+	// Part 2. Delete a subscription.
+	if err := client.DeleteSubscription(ctx, "sub1"); err != nil {
+		t.Errorf("failed to delete subscription %s", err.Error())
+	}
+	_, err = client.GetSubscription(ctx, "sub1")
+	if !errors.Is(err, ErrEntityNotFound) {
+		t.Errorf("expected deleted subscription to be missing. error %v", err)
+	}
+	err = client.DeleteSubscription(ctx, "sub1")
+	if !errors.Is(err, ErrEntityNotFound) {
+		t.Errorf("expected not found error when deleting twice. error %v", err)
+	}
+}
+
+func TestFeatureStateSnapshotOperations(t *testing.T) {
+	ctx := context.Background()
+	client, cleanup := getTestDatabase(ctx, t)
+	defer cleanup()
+
+	_, err := client.GetFeatureStateSnapshot(ctx, "latest")
+	if !errors.Is(err, ErrEntityNotFound) {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	for version, data := range []string{`{"a":1}`, `{"a":2}`} {
+		err := client.SaveFeatureStateSnapshot(ctx, FeatureStateSnapshot{
+			Name:      "latest",
+			Data:      []byte(data),
+			UpdatedAt: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+			Version:   0,
+		}, int64(version))
+		if err != nil {
+			t.Fatalf("failed to save snapshot %s", err.Error())
+		}
+	}
+	// A save based on an older version is rejected.
+	err = client.SaveFeatureStateSnapshot(ctx, FeatureStateSnapshot{
+		Name:      "latest",
+		Data:      []byte(`{"a":3}`),
+		UpdatedAt: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+		Version:   0,
+	}, 1)
+	if !errors.Is(err, ErrFeatureStateSnapshotConflict) {
+		t.Errorf("expected conflict error, received %v", err)
+	}
+	snapshot, err := client.GetFeatureStateSnapshot(ctx, "latest")
+	if err != nil {
+		t.Fatalf("failed to get snapshot %s", err.Error())
+	}
+	if string(snapshot.Data) != `{"a":2}` || snapshot.Version != 2 {
+		t.Errorf("expected the latest data. received %s version %d", string(snapshot.Data), snapshot.Version)
+	}
+}
@@ -9,11 +9,13 @@ require (
 	github.com/GoogleChrome/webstatus.dev/lib/gen v0.0.0-00010101000000-000000000000
 	github.com/antlr4-go/antlr/v4 v4.13.0
 	github.com/deckarep/golang-set v1.8.0
+	github.com/golang-jwt/jwt/v5 v5.2.1
 	github.com/google/go-github/v60 v60.0.0
 	github.com/oapi-codegen/runtime v1.1.1
 	github.com/testcontainers/testcontainers-go v0.30.0
 	github.com/web-platform-tests/wpt.fyi v0.0.0-20240503002835-bc4b42b7c00e
 	go.opentelemetry.io/contrib/detectors/gcp v1.26.0
+	golang.org/x/sync v0.7.0
 	google.golang.org/api v0.176.1
 	google.golang.org/grpc v1.63.2
 )
@@ -135,7 +137,6 @@ require (
 	golang.org/x/mod v0.16.0 // indirect
 	golang.org/x/net v0.24.0 // indirect
 	golang.org/x/oauth2 v0.19.0 // indirect
-	golang.org/x/sync v0.7.0 // indirect
 	golang.org/x/sys v0.19.0 // indirect
 	golang.org/x/text v0.14.0 // indirect
 	golang.org/x/time v0.5.0 // indirect
@@ -0,0 +1,134 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package healthcheck provides the /healthz and /readyz handlers shared by the services.
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultCheckTimeout is how long each dependency has to respond before it is considered unavailable.
+// It is short so that the readiness probe returns before the deployment's probe timeout.
+const DefaultCheckTimeout = 2 * time.Second
+
+const (
+	// StatusOK indicates that the dependency or service is healthy.
+	StatusOK = "ok"
+	// StatusUnavailable indicates that the dependency or service cannot serve requests.
+	StatusUnavailable = "unavailable"
+)
+
+// Dependency is an external system that a service needs in order to serve requests.
+type Dependency struct {
+	// Name is the key of the dependency in the readiness response.
+	Name string
+	// Check returns an error if the dependency cannot be reached.
+	Check func(ctx context.Context) error
+}
+
+// Response is the body of the /healthz and /readyz endpoints.
+type Response struct {
+	Status       string                      `json:"status"`
+	Dependencies map[string]DependencyStatus `json:"dependencies,omitempty"`
+}
+
+// DependencyStatus is the result of checking a single dependency.
+// Errors are logged instead of returned because the endpoints are public.
+type DependencyStatus struct {
+	Status     string `json:"status"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// NewLivenessHandler returns a handler that always reports the process as up.
+func NewLivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeResponse(w, r, http.StatusOK, Response{Status: StatusOK, Dependencies: nil})
+	})
+}
+
+// NewReadinessHandler returns a handler that checks every dependency concurrently.
+// It responds with a 503 if any dependency fails or does not respond within the timeout.
+func NewReadinessHandler(timeout time.Duration, dependencies []Dependency) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		statuses := make(map[string]DependencyStatus, len(dependencies))
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		for _, dependency := range dependencies {
+			wg.Add(1)
+			go func(dependency Dependency) {
+				defer wg.Done()
+				status := checkDependency(r.Context(), timeout, dependency)
+				mu.Lock()
+				defer mu.Unlock()
+				statuses[dependency.Name] = status
+			}(dependency)
+		}
+		wg.Wait()
+
+		response := Response{Status: StatusOK, Dependencies: statuses}
+		statusCode := http.StatusOK
+		for _, status := range statuses {
+			if status.Status != StatusOK {
+				response.Status = StatusUnavailable
+				statusCode = http.StatusServiceUnavailable
+
+				break
+			}
+		}
+		writeResponse(w, r, statusCode, response)
+	})
+}
+
+func checkDependency(ctx context.Context, timeout time.Duration, dependency Dependency) DependencyStatus {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	errCh := make(chan error, 1)
+	// Run the check separately so that a check which ignores the context still times out.
+	go func() {
+		errCh <- dependency.Check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	status := DependencyStatus{Status: StatusOK, DurationMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		slog.ErrorContext(ctx, "dependency check failed", "dependency", dependency.Name, "error", err)
+		status.Status = StatusUnavailable
+	}
+
+	return status
+}
+
+func writeResponse(w http.ResponseWriter, r *http.Request, statusCode int, response Response) {
+	w.Header().Set("Content-Type", "application/json")
+	// Probes must always see the current state.
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+	err := json.NewEncoder(w).Encode(response)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "unable to write health response", "error", err)
+	}
+}
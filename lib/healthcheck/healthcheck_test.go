@@ -0,0 +1,117 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLivenessHandler(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	NewLivenessHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Errorf("unexpected status code %d", recorder.Code)
+	}
+	if recorder.Body.String() != `{"status":"ok"}`+"\n" {
+		t.Errorf("unexpected body %s", recorder.Body.String())
+	}
+}
+
+func TestReadinessHandler(t *testing.T) {
+	healthy := Dependency{Name: "spanner", Check: func(_ context.Context) error { return nil }}
+	failing := Dependency{Name: "redis", Check: func(_ context.Context) error { return errors.New("connection refused") }}
+	// Ignores the context to make sure the handler still returns on time.
+	hanging := Dependency{Name: "datastore", Check: func(_ context.Context) error {
+		time.Sleep(time.Second)
+
+		return nil
+	}}
+
+	testCases := []struct {
+		name             string
+		dependencies     []Dependency
+		expectedCode     int
+		expectedStatus   string
+		expectedStatuses map[string]string
+	}{
+		{
+			name:             "no dependencies",
+			dependencies:     nil,
+			expectedCode:     http.StatusOK,
+			expectedStatus:   StatusOK,
+			expectedStatuses: map[string]string{},
+		},
+		{
+			name:             "all healthy",
+			dependencies:     []Dependency{healthy},
+			expectedCode:     http.StatusOK,
+			expectedStatus:   StatusOK,
+			expectedStatuses: map[string]string{"spanner": StatusOK},
+		},
+		{
+			name:             "one failing",
+			dependencies:     []Dependency{healthy, failing},
+			expectedCode:     http.StatusServiceUnavailable,
+			expectedStatus:   StatusUnavailable,
+			expectedStatuses: map[string]string{"spanner": StatusOK, "redis": StatusUnavailable},
+		},
+		{
+			name:             "one timing out",
+			dependencies:     []Dependency{healthy, hanging},
+			expectedCode:     http.StatusServiceUnavailable,
+			expectedStatus:   StatusUnavailable,
+			expectedStatuses: map[string]string{"spanner": StatusOK, "datastore": StatusUnavailable},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			NewReadinessHandler(50*time.Millisecond, tc.dependencies).ServeHTTP(
+				recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			if recorder.Code != tc.expectedCode {
+				t.Errorf("unexpected status code %d", recorder.Code)
+			}
+			if recorder.Header().Get("Cache-Control") != "no-store" {
+				t.Errorf("unexpected Cache-Control %s", recorder.Header().Get("Cache-Control"))
+			}
+			var response Response
+			err := json.NewDecoder(recorder.Body).Decode(&response)
+			if err != nil {
+				t.Fatalf("unable to decode response %s", err.Error())
+			}
+			if response.Status != tc.expectedStatus {
+				t.Errorf("unexpected status %s", response.Status)
+			}
+			if len(response.Dependencies) != len(tc.expectedStatuses) {
+				t.Errorf("unexpected dependencies %+v", response.Dependencies)
+			}
+			for name, expectedStatus := range tc.expectedStatuses {
+				if response.Dependencies[name].Status != expectedStatus {
+					t.Errorf("unexpected status for %s. expected %s received %+v",
+						name, expectedStatus, response.Dependencies[name])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpmiddlewares
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/backend"
+)
+
+// APIKeyHeader is the header that clients use to send their API key.
+const APIKeyHeader = "X-API-Key"
+
+// ErrInvalidAPIKey indicates that the API key is unknown or has been revoked.
+var ErrInvalidAPIKey = errors.New("invalid api key")
+
+// ClientIdentity identifies the client that made a request with an API key.
+type ClientIdentity struct {
+	// ID uniquely identifies the key without revealing it.
+	ID        string
+	Owner     string
+	QuotaTier string
+}
+
+type clientIdentityContextKey struct{}
+
+// ContextWithClientIdentity returns a copy of the context that carries the client identity.
+func ContextWithClientIdentity(ctx context.Context, identity ClientIdentity) context.Context {
+	return context.WithValue(ctx, clientIdentityContextKey{}, identity)
+}
+
+// ClientIdentityFromContext returns the client identity of the request, if the request had a valid API key.
+func ClientIdentityFromContext(ctx context.Context) (ClientIdentity, bool) {
+	identity, ok := ctx.Value(clientIdentityContextKey{}).(ClientIdentity)
+
+	return identity, ok
+}
+
+// APIKeyValidator looks up the client that owns an API key.
+type APIKeyValidator interface {
+	// ValidateAPIKey returns ErrInvalidAPIKey if the key is unknown or revoked.
+	ValidateAPIKey(ctx context.Context, key string) (*ClientIdentity, error)
+}
+
+// NewAPIKeyMiddleware attaches the identity of clients that send a valid API key to the request context.
+// Requests without a key are anonymous and continue as-is. Requests with an invalid key are rejected with a 401.
+// If the key cannot be checked, the request continues as anonymous.
+func NewAPIKeyMiddleware(validator APIKeyValidator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(APIKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			identity, err := validator.ValidateAPIKey(r.Context(), key)
+			if errors.Is(err, ErrInvalidAPIKey) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusUnauthorized)
+				err = json.NewEncoder(w).Encode(backend.BasicErrorModel{
+					Code:    http.StatusUnauthorized,
+					Message: "invalid api key",
+				})
+				if err != nil {
+					slog.ErrorContext(r.Context(), "unable to write invalid api key response", "error", err)
+				}
+
+				return
+			} else if err != nil {
+				slog.ErrorContext(r.Context(), "unable to validate api key", "error", err)
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(ContextWithClientIdentity(r.Context(), *identity)))
+		})
+	}
+}
+
+// clientIdentityLogHandler is an slog.Handler which adds the client identity from the context.
+type clientIdentityLogHandler struct {
+	slog.Handler
+}
+
+// NewClientIdentityLogHandler wraps the handler so that logging calls with a request context
+// include the owner of the API key that made the request.
+// nolint:ireturn // Matches the slog.Handler wrapping pattern.
+func NewClientIdentityLogHandler(handler slog.Handler) slog.Handler {
+	return &clientIdentityLogHandler{Handler: handler}
+}
+
+// Handle overrides slog.Handler's Handle method.
+func (h *clientIdentityLogHandler) Handle(ctx context.Context, record slog.Record) error {
+	if identity, ok := ClientIdentityFromContext(ctx); ok {
+		record.AddAttrs(
+			slog.String("client_id", identity.ID),
+			slog.String("client_owner", identity.Owner),
+		)
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *clientIdentityLogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &clientIdentityLogHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *clientIdentityLogHandler) WithGroup(name string) slog.Handler {
+	return &clientIdentityLogHandler{Handler: h.Handler.WithGroup(name)}
+}
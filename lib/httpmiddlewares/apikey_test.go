@@ -0,0 +1,166 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpmiddlewares
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type mockAPIKeyValidator struct {
+	t           *testing.T
+	expectedKey string
+	identity    *ClientIdentity
+	err         error
+	callCount   int
+}
+
+func (m *mockAPIKeyValidator) ValidateAPIKey(_ context.Context, key string) (*ClientIdentity, error) {
+	m.callCount++
+	if key != m.expectedKey {
+		m.t.Errorf("unexpected key %s", key)
+	}
+
+	return m.identity, m.err
+}
+
+func TestAPIKeyMiddleware(t *testing.T) {
+	identity := &ClientIdentity{ID: "id", Owner: "ci", QuotaTier: "high"}
+	testCases := []struct {
+		name              string
+		apiKey            string
+		validator         *mockAPIKeyValidator
+		expectedCallCount int
+		expectedStatus    int
+		expectedBody      string
+		expectedIdentity  *ClientIdentity
+	}{
+		{
+			name:   "no api key",
+			apiKey: "",
+			// nolint: exhaustruct // Should not be called.
+			validator:         &mockAPIKeyValidator{},
+			expectedCallCount: 0,
+			expectedStatus:    http.StatusOK,
+			expectedBody:      "ok",
+			expectedIdentity:  nil,
+		},
+		{
+			name:   "valid api key",
+			apiKey: "secret",
+			validator: &mockAPIKeyValidator{
+				t:           nil,
+				expectedKey: "secret",
+				identity:    identity,
+				err:         nil,
+				callCount:   0,
+			},
+			expectedCallCount: 1,
+			expectedStatus:    http.StatusOK,
+			expectedBody:      "ok",
+			expectedIdentity:  identity,
+		},
+		{
+			name:   "invalid api key",
+			apiKey: "secret",
+			validator: &mockAPIKeyValidator{
+				t:           nil,
+				expectedKey: "secret",
+				identity:    nil,
+				err:         ErrInvalidAPIKey,
+				callCount:   0,
+			},
+			expectedCallCount: 1,
+			expectedStatus:    http.StatusUnauthorized,
+			expectedBody:      `{"code":401,"message":"invalid api key"}` + "\n",
+			expectedIdentity:  nil,
+		},
+		{
+			name:   "validator failure continues as anonymous",
+			apiKey: "secret",
+			validator: &mockAPIKeyValidator{
+				t:           nil,
+				expectedKey: "secret",
+				identity:    nil,
+				err:         errors.New("datastore error"),
+				callCount:   0,
+			},
+			expectedCallCount: 1,
+			expectedStatus:    http.StatusOK,
+			expectedBody:      "ok",
+			expectedIdentity:  nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.validator.t = t
+			var receivedIdentity *ClientIdentity
+			nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if identity, ok := ClientIdentityFromContext(r.Context()); ok {
+					receivedIdentity = &identity
+				}
+				_, err := w.Write([]byte("ok"))
+				if err != nil {
+					t.Errorf("unknown error %s", err.Error())
+				}
+			})
+			req := httptest.NewRequest(http.MethodGet, "/v1/features", nil)
+			if tc.apiKey != "" {
+				req.Header.Set(APIKeyHeader, tc.apiKey)
+			}
+			recorder := httptest.NewRecorder()
+			NewAPIKeyMiddleware(tc.validator)(nextHandler).ServeHTTP(recorder, req)
+
+			if recorder.Code != tc.expectedStatus {
+				t.Errorf("Expected status code %d, got %d", tc.expectedStatus, recorder.Code)
+			}
+			if recorder.Body.String() != tc.expectedBody {
+				t.Errorf("Expected body %q, got %q", tc.expectedBody, recorder.Body.String())
+			}
+			if tc.validator.callCount != tc.expectedCallCount {
+				t.Errorf("Expected %d validator calls, got %d", tc.expectedCallCount, tc.validator.callCount)
+			}
+			if !reflect.DeepEqual(receivedIdentity, tc.expectedIdentity) {
+				t.Errorf("Expected identity %+v, got %+v", tc.expectedIdentity, receivedIdentity)
+			}
+		})
+	}
+}
+
+func TestClientIdentityLogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	// nolint: exhaustruct // No need to use every option of 3rd party struct.
+	logger := slog.New(NewClientIdentityLogHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{})))
+
+	logger.InfoContext(context.Background(), "anonymous")
+	if strings.Contains(buf.String(), "client_owner") {
+		t.Errorf("unexpected client attributes %s", buf.String())
+	}
+
+	buf.Reset()
+	ctx := ContextWithClientIdentity(context.Background(), ClientIdentity{ID: "id", Owner: "ci", QuotaTier: "high"})
+	logger.With("key", "value").InfoContext(ctx, "identified")
+	if !strings.Contains(buf.String(), "client_id=id client_owner=ci") {
+		t.Errorf("expected client attributes %s", buf.String())
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpmiddlewares
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/backend"
+)
+
+// ErrInvalidToken indicates that the bearer token is malformed, expired or not signed by a trusted key.
+var ErrInvalidToken = errors.New("invalid token")
+
+// User is the authenticated user that made a request.
+type User struct {
+	// ID is the subject of the token. It is stable for the lifetime of the account.
+	ID    string
+	Email string
+	Roles []string
+}
+
+// HasRole returns true if the user was granted the role.
+func (u User) HasRole(role string) bool {
+	return slices.Contains(u.Roles, role)
+}
+
+type userContextKey struct{}
+
+// ContextWithUser returns a copy of the context that carries the authenticated user.
+func ContextWithUser(ctx context.Context, user User) context.Context {
+	return context.WithValue(ctx, userContextKey{}, user)
+}
+
+// UserFromContext returns the authenticated user of the request, if the request had a valid bearer token.
+func UserFromContext(ctx context.Context) (User, bool) {
+	user, ok := ctx.Value(userContextKey{}).(User)
+
+	return user, ok
+}
+
+// TokenVerifier checks the signature and claims of a bearer token.
+type TokenVerifier interface {
+	// VerifyToken returns ErrInvalidToken if the token cannot be trusted.
+	VerifyToken(ctx context.Context, token string) (*User, error)
+}
+
+func writeAuthError(ctx context.Context, w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	if code == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+	}
+	w.WriteHeader(code)
+	err := json.NewEncoder(w).Encode(backend.BasicErrorModel{
+		Code:    code,
+		Message: message,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "unable to write auth error response", "error", err)
+	}
+}
+
+// NewAuthMiddleware attaches the user of requests that send a valid bearer token to the request context.
+// Requests without a token are anonymous and continue as-is. Requests with an invalid token are rejected with a 401.
+// Unlike API keys, a token that cannot be checked fails the request because the operation may require the user.
+func NewAuthMiddleware(verifier TokenVerifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Authorization")
+			if header == "" {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			scheme, token, found := strings.Cut(header, " ")
+			if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+				writeAuthError(r.Context(), w, http.StatusUnauthorized, "authorization header must be a bearer token")
+
+				return
+			}
+
+			user, err := verifier.VerifyToken(r.Context(), token)
+			if errors.Is(err, ErrInvalidToken) {
+				slog.WarnContext(r.Context(), "rejected bearer token", "error", err)
+				writeAuthError(r.Context(), w, http.StatusUnauthorized, "invalid token")
+
+				return
+			} else if err != nil {
+				slog.ErrorContext(r.Context(), "unable to verify token", "error", err)
+				writeAuthError(r.Context(), w, http.StatusInternalServerError, "unable to verify token")
+
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(ContextWithUser(r.Context(), *user)))
+		})
+	}
+}
+
+// NewStrictAuthMiddleware enforces the security requirements of the operations in the OpenAPI spec.
+// The generated router stores the scopes of an operation's security requirement in the context under scopesKey,
+// for example backend.BearerAuthScopes. Operations with a requirement need a user from NewAuthMiddleware and
+// the user must have every listed scope as a role. Operations without a requirement are not affected.
+func NewStrictAuthMiddleware(scopesKey string) backend.StrictMiddlewareFunc {
+	return func(f backend.StrictHandlerFunc, operationID string) backend.StrictHandlerFunc {
+		return func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+			scopes, required := ctx.Value(scopesKey).([]string)
+			if !required {
+				return f(ctx, w, r, request)
+			}
+
+			user, ok := UserFromContext(ctx)
+			if !ok {
+				writeAuthError(ctx, w, http.StatusUnauthorized, "authentication required")
+
+				// The response has been written. Returning nil skips the generated response handling.
+				return nil, nil // nolint: nilnil // WONTFIX. See above.
+			}
+			for _, scope := range scopes {
+				if !user.HasRole(scope) {
+					slog.WarnContext(ctx, "user is missing role", "operation", operationID, "role", scope)
+					writeAuthError(ctx, w, http.StatusForbidden, "forbidden")
+
+					return nil, nil // nolint: nilnil // WONTFIX. The response has been written.
+				}
+			}
+
+			return f(ctx, w, r, request)
+		}
+	}
+}
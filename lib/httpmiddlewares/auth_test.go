@@ -0,0 +1,249 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpmiddlewares
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type mockTokenVerifier struct {
+	t             *testing.T
+	expectedToken string
+	user          *User
+	err           error
+	callCount     int
+}
+
+func (m *mockTokenVerifier) VerifyToken(_ context.Context, token string) (*User, error) {
+	m.callCount++
+	if token != m.expectedToken {
+		m.t.Errorf("unexpected token %s", token)
+	}
+
+	return m.user, m.err
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	user := &User{ID: "uid", Email: "user@example.com", Roles: []string{"admin"}}
+	testCases := []struct {
+		name              string
+		authorization     string
+		verifier          *mockTokenVerifier
+		expectedCallCount int
+		expectedStatus    int
+		expectedBody      string
+		expectedUser      *User
+	}{
+		{
+			name:          "no token",
+			authorization: "",
+			// nolint: exhaustruct // Should not be called.
+			verifier:          &mockTokenVerifier{},
+			expectedCallCount: 0,
+			expectedStatus:    http.StatusOK,
+			expectedBody:      "ok",
+			expectedUser:      nil,
+		},
+		{
+			name:          "valid token",
+			authorization: "Bearer token",
+			verifier: &mockTokenVerifier{
+				t:             t,
+				expectedToken: "token",
+				user:          user,
+				err:           nil,
+				callCount:     0,
+			},
+			expectedCallCount: 1,
+			expectedStatus:    http.StatusOK,
+			expectedBody:      "ok",
+			expectedUser:      user,
+		},
+		{
+			name:          "not a bearer token",
+			authorization: "Basic dXNlcjpwYXNz",
+			// nolint: exhaustruct // Should not be called.
+			verifier:          &mockTokenVerifier{},
+			expectedCallCount: 0,
+			expectedStatus:    http.StatusUnauthorized,
+			expectedBody:      `{"code":401,"message":"authorization header must be a bearer token"}`,
+			expectedUser:      nil,
+		},
+		{
+			name:          "invalid token",
+			authorization: "Bearer token",
+			verifier: &mockTokenVerifier{
+				t:             t,
+				expectedToken: "token",
+				user:          nil,
+				err:           errors.Join(ErrInvalidToken, errors.New("token is expired")),
+				callCount:     0,
+			},
+			expectedCallCount: 1,
+			expectedStatus:    http.StatusUnauthorized,
+			expectedBody:      `{"code":401,"message":"invalid token"}`,
+			expectedUser:      nil,
+		},
+		{
+			name:          "unable to verify token",
+			authorization: "Bearer token",
+			verifier: &mockTokenVerifier{
+				t:             t,
+				expectedToken: "token",
+				user:          nil,
+				err:           errors.New("key set error"),
+				callCount:     0,
+			},
+			expectedCallCount: 1,
+			expectedStatus:    http.StatusInternalServerError,
+			expectedBody:      `{"code":500,"message":"unable to verify token"}`,
+			expectedUser:      nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var receivedUser *User
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if u, ok := UserFromContext(r.Context()); ok {
+					receivedUser = &u
+				}
+				_, _ = w.Write([]byte("ok"))
+			})
+			req := httptest.NewRequest(http.MethodGet, "/v1/features", nil)
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+			recorder := httptest.NewRecorder()
+			NewAuthMiddleware(tc.verifier)(next).ServeHTTP(recorder, req)
+
+			if tc.verifier.callCount != tc.expectedCallCount {
+				t.Errorf("expected %d calls, received %d", tc.expectedCallCount, tc.verifier.callCount)
+			}
+			if recorder.Code != tc.expectedStatus {
+				t.Errorf("expected status %d, received %d", tc.expectedStatus, recorder.Code)
+			}
+			if strings.TrimSpace(recorder.Body.String()) != tc.expectedBody {
+				t.Errorf("unexpected body %s", recorder.Body.String())
+			}
+			if !reflect.DeepEqual(receivedUser, tc.expectedUser) {
+				t.Errorf("unexpected user %+v", receivedUser)
+			}
+		})
+	}
+}
+
+// testScopesKey mirrors the context key of the generated server.
+const testScopesKey = "bearerAuth.Scopes"
+
+func TestStrictAuthMiddleware(t *testing.T) {
+	testCases := []struct {
+		name           string
+		scopes         []string
+		user           *User
+		expectedCalled bool
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "no security requirement",
+			scopes:         nil,
+			user:           nil,
+			expectedCalled: true,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "",
+		},
+		{
+			name:           "authenticated",
+			scopes:         []string{},
+			user:           &User{ID: "uid", Email: "", Roles: nil},
+			expectedCalled: true,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "",
+		},
+		{
+			name:           "anonymous",
+			scopes:         []string{},
+			user:           nil,
+			expectedCalled: false,
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"code":401,"message":"authentication required"}`,
+		},
+		{
+			name:           "has role",
+			scopes:         []string{"admin"},
+			user:           &User{ID: "uid", Email: "", Roles: []string{"admin"}},
+			expectedCalled: true,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "",
+		},
+		{
+			name:           "missing role",
+			scopes:         []string{"admin"},
+			user:           &User{ID: "uid", Email: "", Roles: []string{"editor"}},
+			expectedCalled: false,
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `{"code":403,"message":"forbidden"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			called := false
+			handler := NewStrictAuthMiddleware(testScopesKey)(
+				func(_ context.Context, _ http.ResponseWriter, _ *http.Request, request interface{}) (interface{}, error) {
+					called = true
+
+					return request, nil
+				}, "testOperation")
+
+			ctx := context.Background()
+			if tc.scopes != nil {
+				ctx = context.WithValue(ctx, testScopesKey, tc.scopes) // nolint: staticcheck // Matches generated code.
+			}
+			if tc.user != nil {
+				ctx = ContextWithUser(ctx, *tc.user)
+			}
+			req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+			recorder := httptest.NewRecorder()
+			resp, err := handler(ctx, recorder, req, "request")
+			if err != nil {
+				t.Errorf("unexpected error %v", err)
+			}
+
+			if called != tc.expectedCalled {
+				t.Errorf("expected called %t, received %t", tc.expectedCalled, called)
+			}
+			if tc.expectedCalled && resp != "request" {
+				t.Errorf("unexpected response %v", resp)
+			}
+			if !tc.expectedCalled && resp != nil {
+				t.Errorf("expected no response, received %v", resp)
+			}
+			if recorder.Code != tc.expectedStatus {
+				t.Errorf("expected status %d, received %d", tc.expectedStatus, recorder.Code)
+			}
+			if strings.TrimSpace(recorder.Body.String()) != tc.expectedBody {
+				t.Errorf("unexpected body %s", recorder.Body.String())
+			}
+		})
+	}
+}
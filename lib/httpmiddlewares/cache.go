@@ -17,18 +17,27 @@ package httpmiddlewares
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"log/slog"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/GoogleChrome/webstatus.dev/lib/cachetypes"
 )
 
 // Custom ResponseWriter wrapper.
+// Successful responses with a cacheable content type are buffered so that the validators can be computed
+// before anything is written. Every other response is passed through as-is.
 type responseRecorder struct {
 	http.ResponseWriter
-	buffer     *bytes.Buffer
-	statusCode int
+	buffer      *bytes.Buffer
+	statusCode  int
+	wroteHeader bool
+	buffering   bool
 }
 
 func (rw *responseRecorder) Header() http.Header {
@@ -36,14 +45,53 @@ func (rw *responseRecorder) Header() http.Header {
 }
 
 func (rw *responseRecorder) Write(b []byte) (int, error) {
-	rw.buffer.Write(b)
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
+	if rw.buffering {
+		return rw.buffer.Write(b)
+	}
 
 	return rw.ResponseWriter.Write(b)
 }
 
 func (rw *responseRecorder) WriteHeader(statusCode int) {
+	if rw.wroteHeader {
+		return
+	}
+	rw.wroteHeader = true
 	rw.statusCode = statusCode
-	rw.ResponseWriter.WriteHeader(statusCode)
+	rw.buffering = statusCode == http.StatusOK && isCacheableContentType(rw.Header().Get("Content-Type"))
+	if !rw.buffering {
+		rw.ResponseWriter.WriteHeader(statusCode)
+	}
+}
+
+// Flush allows streamed responses to reach the client as they are written.
+func (rw *responseRecorder) Flush() {
+	if rw.buffering {
+		return
+	}
+	if flusher, ok := rw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+const defaultContentType = "application/json"
+
+// cacheableContentTypes are the content types of the responses that are cached.
+// Other responses, such as streamed exports, are never cached.
+// nolint: gochecknoglobals // WONTFIX. Read only list.
+var cacheableContentTypes = []string{defaultContentType, "image/svg+xml"}
+
+func isCacheableContentType(contentType string) bool {
+	for _, cacheable := range cacheableContentTypes {
+		if strings.HasPrefix(contentType, cacheable) {
+			return true
+		}
+	}
+
+	return false
 }
 
 type DataCacher[K string, V []byte] interface {
@@ -53,11 +101,145 @@ type DataCacher[K string, V []byte] interface {
 	Get(context.Context, K) (V, error)
 }
 
+// LastModifiedGetter returns the time that the data behind the responses was last modified.
+type LastModifiedGetter interface {
+	GetLastModified(ctx context.Context) (time.Time, error)
+}
+
+// CacheControlPolicy is the Cache-Control header value for every path that starts with PathPrefix
+// and ends with PathSuffix. An empty PathSuffix matches any path with the prefix.
+type CacheControlPolicy struct {
+	PathPrefix string
+	PathSuffix string
+	Value      string
+}
+
+// CacheMiddlewareConfig configures the validators and headers returned by the cache middleware.
+type CacheMiddlewareConfig struct {
+	// LastModifiedGetter is used for the Last-Modified header of new entries.
+	// If it is nil or fails, the time the entry was cached is used instead.
+	LastModifiedGetter LastModifiedGetter
+	// CacheControlPolicies are checked in order and the first matching policy is used.
+	CacheControlPolicies []CacheControlPolicy
+	// DefaultCacheControl is used when no policy matches. If empty, no header is set.
+	DefaultCacheControl string
+	// BypassPathPrefixes are paths whose responses depend on the client. They are never cached.
+	BypassPathPrefixes []string
+}
+
+func (c CacheMiddlewareConfig) bypass(path string) bool {
+	for _, prefix := range c.BypassPathPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c CacheMiddlewareConfig) cacheControl(path string) string {
+	for _, policy := range c.CacheControlPolicies {
+		if strings.HasPrefix(path, policy.PathPrefix) && strings.HasSuffix(path, policy.PathSuffix) {
+			return policy.Value
+		}
+	}
+
+	return c.DefaultCacheControl
+}
+
+// cachedResponse is the entry stored in the cache.
+type cachedResponse struct {
+	Body []byte `json:"body"`
+	// Empty for entries cached before other content types were supported.
+	ContentType  string    `json:"content_type,omitempty"`
+	ETag         string    `json:"etag"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+func newCachedResponse(
+	ctx context.Context,
+	body []byte,
+	contentType string,
+	getter LastModifiedGetter,
+) cachedResponse {
+	hash := sha256.Sum256(body)
+	lastModified := time.Now()
+	if getter != nil {
+		ingestionTime, err := getter.GetLastModified(ctx)
+		if err != nil {
+			slog.Warn("unable to get last modified time", "error", err)
+		} else {
+			lastModified = ingestionTime
+		}
+	}
+
+	return cachedResponse{
+		Body:        body,
+		ContentType: contentType,
+		ETag:        `"` + hex.EncodeToString(hash[:]) + `"`,
+		// HTTP dates only have second precision.
+		LastModified: lastModified.UTC().Truncate(time.Second),
+	}
+}
+
+// isNotModified evaluates the conditional request headers against the entry.
+// If-Modified-Since is ignored when If-None-Match is present.
+func isNotModified(r *http.Request, entry cachedResponse) bool {
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		for _, etag := range strings.Split(ifNoneMatch, ",") {
+			etag = strings.TrimSpace(etag)
+			// Weak comparison is used for GET requests.
+			if etag == "*" || strings.TrimPrefix(etag, "W/") == entry.ETag {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	ifModifiedSince, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+
+	return !entry.LastModified.After(ifModifiedSince)
+}
+
+// writeCachedResponse writes the entry, or a 304 if the client already has it.
+func writeCachedResponse(w http.ResponseWriter, r *http.Request, entry cachedResponse, cacheControl string) {
+	w.Header().Set("ETag", entry.ETag)
+	w.Header().Set("Last-Modified", entry.LastModified.Format(http.TimeFormat))
+	if cacheControl != "" {
+		w.Header().Set("Cache-Control", cacheControl)
+	}
+	if isNotModified(r, entry) {
+		w.Header().Del("Content-Type")
+		w.WriteHeader(http.StatusNotModified)
+
+		return
+	}
+
+	contentType := entry.ContentType
+	if contentType == "" {
+		contentType = defaultContentType
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	_, err := w.Write(entry.Body)
+	if err != nil {
+		slog.Error("unable to write cached response", "path", r.URL.Path, "error", err)
+	}
+}
+
 // TODO: Pass in context to be used by slog.ErrorContext.
-func NewCacheMiddleware[K string, V []byte](cacher DataCacher[string, []byte]) func(http.Handler) http.Handler {
+func NewCacheMiddleware[K string, V []byte](
+	cacher DataCacher[string, []byte],
+	config CacheMiddlewareConfig,
+) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if r.Method != http.MethodGet {
+			// Responses to authenticated requests may depend on the user.
+			if r.Method != http.MethodGet || config.bypass(r.URL.Path) || r.Header.Get("Authorization") != "" {
 				next.ServeHTTP(w, r)
 
 				return
@@ -67,18 +249,20 @@ func NewCacheMiddleware[K string, V []byte](cacher DataCacher[string, []byte]) f
 			if r.URL.RawQuery != "" { // Check if there are query parameters
 				cacheKey += "?" + r.URL.Query().Encode()
 			}
+			cacheControl := config.cacheControl(r.URL.Path)
 
 			// Attempt to get the response from cache
-			cachedResponse, err := cacher.Get(r.Context(), cacheKey)
+			cachedValue, err := cacher.Get(r.Context(), cacheKey)
 			if err == nil { // Cache hit
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(http.StatusOK)
-				_, err := w.Write(cachedResponse)
-				if err != nil {
-					slog.Error("unable to write cached response", "cacheKey", cacheKey, "error", err)
-				}
+				var entry cachedResponse
+				decodeErr := json.Unmarshal(cachedValue, &entry)
+				if decodeErr == nil {
+					writeCachedResponse(w, r, entry, cacheControl)
 
-				return
+					return
+				}
+				// Entries written in a different format are treated as a miss and replaced.
+				slog.Warn("unable to decode cached response", "cacheKey", cacheKey, "error", decodeErr)
 			} else if !errors.Is(err, cachetypes.ErrCachedDataNotFound) {
 				// Unknown internal error. For now log it.
 				slog.Error("cache fetched failed for unknown reasons", "error", err)
@@ -88,17 +272,29 @@ func NewCacheMiddleware[K string, V []byte](cacher DataCacher[string, []byte]) f
 				ResponseWriter: w,
 				buffer:         bytes.NewBuffer(nil),
 				// Will be changed by the actual server.
-				statusCode: 0,
+				statusCode:  0,
+				wroteHeader: false,
+				buffering:   false,
 			}
 
 			next.ServeHTTP(recorder, r)
 
-			if recorder.statusCode == http.StatusOK {
-				err = cacher.Cache(r.Context(), cacheKey, V(recorder.buffer.Bytes()))
+			if !recorder.buffering {
+				return
+			}
+
+			entry := newCachedResponse(r.Context(), recorder.buffer.Bytes(), recorder.Header().Get("Content-Type"),
+				config.LastModifiedGetter)
+			encodedEntry, err := json.Marshal(entry)
+			if err != nil {
+				slog.Warn("unable to encode cached response", "cacheKey", cacheKey, "error", err)
+			} else {
+				err = cacher.Cache(r.Context(), cacheKey, V(encodedEntry))
 				if err != nil {
 					slog.Warn("unable to cache value", "cacheKey", cacheKey, "error", err)
 				}
 			}
+			writeCachedResponse(w, r, entry, cacheControl)
 		})
 	}
 }
@@ -16,10 +16,13 @@ package httpmiddlewares
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 type mockCacher struct {
@@ -47,6 +50,40 @@ func (mc *mockCacher) Get(_ context.Context, key string) ([]byte, error) {
 	return nil, errors.New("not found")
 }
 
+type mockLastModifiedGetter struct {
+	lastModified time.Time
+	err          error
+}
+
+func (m mockLastModifiedGetter) GetLastModified(_ context.Context) (time.Time, error) {
+	return m.lastModified, m.err
+}
+
+func encodeTestCachedResponse(t *testing.T, entry cachedResponse) []byte {
+	value, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatalf("unable to encode cached response %s", err.Error())
+	}
+
+	return value
+}
+
+// nolint: gochecknoglobals // For tests only.
+var testCachedResponse = cachedResponse{
+	Body:         []byte("cached response"),
+	ContentType:  "",
+	ETag:         `"abc"`,
+	LastModified: time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+}
+
+// nolint: gochecknoglobals // For tests only.
+var testCachedSVGResponse = cachedResponse{
+	Body:         []byte("<svg></svg>"),
+	ContentType:  "image/svg+xml",
+	ETag:         `"def"`,
+	LastModified: time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+}
+
 func TestCacheMiddleware(t *testing.T) {
 	testCases := []struct {
 		name              string
@@ -63,7 +100,7 @@ func TestCacheMiddleware(t *testing.T) {
 			name:              "GET with cache hit, correct content type, and 200 status",
 			method:            http.MethodGet,
 			url:               "/test?param=value",
-			mockCache:         map[string][]byte{"/test?param=value": []byte("cached response")},
+			mockCache:         map[string][]byte{"/test?param=value": encodeTestCachedResponse(t, testCachedResponse)},
 			expectedResponse:  "cached response",
 			expectedCacheSize: 1,
 			responseHeaders:   map[string]string{"Content-Type": "application/json"},
@@ -81,6 +118,28 @@ func TestCacheMiddleware(t *testing.T) {
 			responseStatus:    http.StatusOK,
 			mockCacheError:    nil,
 		},
+		{
+			name:              "GET with cache hit, svg content type",
+			method:            http.MethodGet,
+			url:               "/badge.svg",
+			mockCache:         map[string][]byte{"/badge.svg": encodeTestCachedResponse(t, testCachedSVGResponse)},
+			expectedResponse:  "<svg></svg>",
+			expectedCacheSize: 1,
+			responseHeaders:   map[string]string{"Content-Type": "image/svg+xml"},
+			responseStatus:    http.StatusOK,
+			mockCacheError:    nil,
+		},
+		{
+			name:              "GET with cache miss, svg content type",
+			method:            http.MethodGet,
+			url:               "/badge.svg",
+			mockCache:         map[string][]byte{},
+			expectedResponse:  "test response",
+			expectedCacheSize: 1,
+			responseHeaders:   map[string]string{"Content-Type": "image/svg+xml"},
+			responseStatus:    http.StatusOK,
+			mockCacheError:    nil,
+		},
 		{
 			name:              "GET with cache miss, incorrect content type",
 			method:            http.MethodGet,
@@ -108,7 +167,8 @@ func TestCacheMiddleware(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			mockCacher := &mockCacher{cache: tc.mockCache, err: tc.mockCacheError}
-			cacheMiddleware := NewCacheMiddleware[string, []byte](mockCacher)
+			// nolint: exhaustruct // Only need the defaults.
+			cacheMiddleware := NewCacheMiddleware[string, []byte](mockCacher, CacheMiddlewareConfig{})
 
 			nextHandler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 				for key, value := range tc.responseHeaders {
@@ -133,7 +193,284 @@ func TestCacheMiddleware(t *testing.T) {
 				t.Errorf("Expected status code %d, got %d", tc.responseStatus, res.StatusCode)
 			}
 
-			// ... (rest of the test logic, including body and cache size checks) ...
+			body, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Fatalf("unable to read body %s", err.Error())
+			}
+			if string(body) != tc.expectedResponse {
+				t.Errorf("Expected body %q, got %q", tc.expectedResponse, string(body))
+			}
+			if res.Header.Get("Content-Type") != tc.responseHeaders["Content-Type"] {
+				t.Errorf("Expected Content-Type %s, got %s",
+					tc.responseHeaders["Content-Type"], res.Header.Get("Content-Type"))
+			}
+			if len(mockCacher.cache) != tc.expectedCacheSize {
+				t.Errorf("Expected cache size %d, got %d", tc.expectedCacheSize, len(mockCacher.cache))
+			}
+		})
+	}
+}
+
+func TestCacheMiddlewareConditionalRequests(t *testing.T) {
+	lastModified := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	body := []byte("test response")
+	entry := newCachedResponse(context.Background(), body, "application/json",
+		mockLastModifiedGetter{lastModified: lastModified, err: nil})
+	etag := entry.ETag
+
+	testCases := []struct {
+		name                 string
+		url                  string
+		mockCache            map[string][]byte
+		requestHeaders       map[string]string
+		expectedStatus       int
+		expectedResponse     string
+		expectedCacheControl string
+	}{
+		{
+			name:                 "cache miss without validators",
+			url:                  "/v1/features",
+			mockCache:            map[string][]byte{},
+			requestHeaders:       nil,
+			expectedStatus:       http.StatusOK,
+			expectedResponse:     string(body),
+			expectedCacheControl: "public, max-age=60",
+		},
+		{
+			name:                 "cache miss with path prefix and suffix policy",
+			url:                  "/v1/features/grid/badge.svg",
+			mockCache:            map[string][]byte{},
+			requestHeaders:       nil,
+			expectedStatus:       http.StatusOK,
+			expectedResponse:     string(body),
+			expectedCacheControl: "public, max-age=86400",
+		},
+		{
+			name:                 "cache miss with matching etag",
+			url:                  "/v1/features",
+			mockCache:            map[string][]byte{},
+			requestHeaders:       map[string]string{"If-None-Match": etag},
+			expectedStatus:       http.StatusNotModified,
+			expectedResponse:     "",
+			expectedCacheControl: "public, max-age=60",
+		},
+		{
+			name:                 "cache hit with matching weak etag in list",
+			url:                  "/v1/stats/compat-matrix",
+			mockCache:            map[string][]byte{"/v1/stats/compat-matrix": encodeTestCachedResponse(t, entry)},
+			requestHeaders:       map[string]string{"If-None-Match": `"other", W/` + etag},
+			expectedStatus:       http.StatusNotModified,
+			expectedResponse:     "",
+			expectedCacheControl: "public, max-age=3600",
+		},
+		{
+			name:                 "cache hit with different etag",
+			url:                  "/v1/stats/compat-matrix",
+			mockCache:            map[string][]byte{"/v1/stats/compat-matrix": encodeTestCachedResponse(t, entry)},
+			requestHeaders:       map[string]string{"If-None-Match": `"other"`},
+			expectedStatus:       http.StatusOK,
+			expectedResponse:     string(body),
+			expectedCacheControl: "public, max-age=3600",
+		},
+		{
+			name:      "cache hit with etag mismatch ignores If-Modified-Since",
+			url:       "/other",
+			mockCache: map[string][]byte{"/other": encodeTestCachedResponse(t, entry)},
+			requestHeaders: map[string]string{
+				"If-None-Match":     `"other"`,
+				"If-Modified-Since": lastModified.Format(http.TimeFormat),
+			},
+			expectedStatus:       http.StatusOK,
+			expectedResponse:     string(body),
+			expectedCacheControl: "no-cache",
+		},
+		{
+			name:                 "cache hit not modified since",
+			url:                  "/other",
+			mockCache:            map[string][]byte{"/other": encodeTestCachedResponse(t, entry)},
+			requestHeaders:       map[string]string{"If-Modified-Since": lastModified.Format(http.TimeFormat)},
+			expectedStatus:       http.StatusNotModified,
+			expectedResponse:     "",
+			expectedCacheControl: "no-cache",
+		},
+		{
+			name:      "cache hit modified since",
+			url:       "/other",
+			mockCache: map[string][]byte{"/other": encodeTestCachedResponse(t, entry)},
+			requestHeaders: map[string]string{
+				"If-Modified-Since": lastModified.Add(-time.Hour).Format(http.TimeFormat),
+			},
+			expectedStatus:       http.StatusOK,
+			expectedResponse:     string(body),
+			expectedCacheControl: "no-cache",
+		},
+		{
+			name:                 "cache hit with undecodable entry is treated as a miss",
+			url:                  "/other",
+			mockCache:            map[string][]byte{"/other": []byte("raw body")},
+			requestHeaders:       map[string]string{"If-None-Match": etag},
+			expectedStatus:       http.StatusNotModified,
+			expectedResponse:     "",
+			expectedCacheControl: "no-cache",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCacher := &mockCacher{cache: tc.mockCache, err: nil}
+			cacheMiddleware := NewCacheMiddleware[string, []byte](mockCacher, CacheMiddlewareConfig{
+				LastModifiedGetter: mockLastModifiedGetter{lastModified: lastModified, err: nil},
+				CacheControlPolicies: []CacheControlPolicy{
+					{PathPrefix: "/v1/features/", PathSuffix: "/badge.svg", Value: "public, max-age=86400"},
+					{PathPrefix: "/v1/stats/", PathSuffix: "", Value: "public, max-age=3600"},
+					{PathPrefix: "/v1/features", PathSuffix: "", Value: "public, max-age=60"},
+				},
+				DefaultCacheControl: "no-cache",
+				BypassPathPrefixes:  nil,
+			})
+			nextHandler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				_, err := w.Write(body)
+				if err != nil {
+					t.Errorf("unknown error %s", err.Error())
+				}
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tc.url, nil)
+			for key, value := range tc.requestHeaders {
+				req.Header.Set(key, value)
+			}
+			recorder := httptest.NewRecorder()
+			cacheMiddleware(nextHandler).ServeHTTP(recorder, req)
+
+			res := recorder.Result()
+			defer res.Body.Close()
+
+			if res.StatusCode != tc.expectedStatus {
+				t.Errorf("Expected status code %d, got %d", tc.expectedStatus, res.StatusCode)
+			}
+			resBody, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Fatalf("unable to read body %s", err.Error())
+			}
+			if string(resBody) != tc.expectedResponse {
+				t.Errorf("Expected body %q, got %q", tc.expectedResponse, string(resBody))
+			}
+			if res.Header.Get("ETag") != etag {
+				t.Errorf("Expected ETag %s, got %s", etag, res.Header.Get("ETag"))
+			}
+			if res.Header.Get("Last-Modified") != lastModified.Format(http.TimeFormat) {
+				t.Errorf("unexpected Last-Modified %s", res.Header.Get("Last-Modified"))
+			}
+			if res.Header.Get("Cache-Control") != tc.expectedCacheControl {
+				t.Errorf("Expected Cache-Control %s, got %s", tc.expectedCacheControl, res.Header.Get("Cache-Control"))
+			}
+			if len(mockCacher.cache) != 1 {
+				t.Errorf("Expected one cached entry, got %d", len(mockCacher.cache))
+			}
 		})
 	}
 }
+
+func TestCacheMiddlewareStreamedResponse(t *testing.T) {
+	mockCacher := &mockCacher{cache: map[string][]byte{}, err: nil}
+	// nolint: exhaustruct // Only need the defaults.
+	cacheMiddleware := NewCacheMiddleware[string, []byte](mockCacher, CacheMiddlewareConfig{})
+
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+		for _, line := range []string{"{\"feature_id\":\"a\"}\n", "{\"feature_id\":\"b\"}\n"} {
+			_, err := w.Write([]byte(line))
+			if err != nil {
+				t.Errorf("unknown error %s", err.Error())
+			}
+			flusher, ok := w.(http.Flusher)
+			if !ok {
+				t.Fatal("expected the response writer to be a flusher")
+			}
+			flusher.Flush()
+		}
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/features/export?format=ndjson", nil)
+	recorder := httptest.NewRecorder()
+	cacheMiddleware(nextHandler).ServeHTTP(recorder, req)
+
+	if !recorder.Flushed {
+		t.Error("expected the streamed response to be flushed")
+	}
+	expectedBody := "{\"feature_id\":\"a\"}\n{\"feature_id\":\"b\"}\n"
+	if recorder.Body.String() != expectedBody {
+		t.Errorf("Expected body %q, got %q", expectedBody, recorder.Body.String())
+	}
+	if recorder.Header().Get("ETag") != "" {
+		t.Error("expected no ETag for a streamed response")
+	}
+	if len(mockCacher.cache) != 0 {
+		t.Errorf("Expected streamed response to not be cached. cache size %d", len(mockCacher.cache))
+	}
+}
+
+func TestCacheMiddlewareBypass(t *testing.T) {
+	mockCacher := &mockCacher{cache: map[string][]byte{}, err: nil}
+	// nolint: exhaustruct // Only need the bypassed paths.
+	cacheMiddleware := NewCacheMiddleware[string, []byte](mockCacher, CacheMiddlewareConfig{
+		BypassPathPrefixes: []string{"/v1/subscriptions"},
+	})
+	calls := 0
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"id":"sub1"}`))
+		if err != nil {
+			t.Errorf("unknown error %s", err.Error())
+		}
+	})
+
+	for range 2 {
+		req := httptest.NewRequest(http.MethodGet, "/v1/subscriptions/sub1", nil)
+		recorder := httptest.NewRecorder()
+		cacheMiddleware(nextHandler).ServeHTTP(recorder, req)
+		if recorder.Header().Get("ETag") != "" || recorder.Header().Get("Cache-Control") != "" {
+			t.Errorf("expected no cache headers. received %v", recorder.Header())
+		}
+	}
+	if calls != 2 {
+		t.Errorf("expected every request to reach the handler, got %d calls", calls)
+	}
+	if len(mockCacher.cache) != 0 {
+		t.Errorf("expected no cached entries, got %d", len(mockCacher.cache))
+	}
+}
+
+func TestCacheMiddlewareBypassAuthenticated(t *testing.T) {
+	mockCacher := &mockCacher{cache: map[string][]byte{}, err: nil}
+	// nolint: exhaustruct // Not needed for authenticated requests.
+	cacheMiddleware := NewCacheMiddleware[string, []byte](mockCacher, CacheMiddlewareConfig{})
+	calls := 0
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"data":[]}`))
+		if err != nil {
+			t.Errorf("unknown error %s", err.Error())
+		}
+	})
+
+	for range 2 {
+		req := httptest.NewRequest(http.MethodGet, "/v1/features", nil)
+		req.Header.Set("Authorization", "Bearer token")
+		recorder := httptest.NewRecorder()
+		cacheMiddleware(nextHandler).ServeHTTP(recorder, req)
+	}
+	if calls != 2 {
+		t.Errorf("expected every request to reach the handler, got %d calls", calls)
+	}
+	if len(mockCacher.cache) != 0 {
+		t.Errorf("expected no cached entries, got %d", len(mockCacher.cache))
+	}
+}
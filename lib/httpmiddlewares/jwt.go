@@ -0,0 +1,309 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpmiddlewares
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrUnknownKey indicates that the key set does not contain a key with the requested ID.
+var ErrUnknownKey = errors.New("unknown key id")
+
+// ErrNoUsableKeys indicates that a JSON Web Key Set does not contain any supported signing keys.
+var ErrNoUsableKeys = errors.New("no usable keys in key set")
+
+// KeySet provides the public keys that sign bearer tokens.
+type KeySet interface {
+	// Key returns ErrUnknownKey if there is no key with the ID.
+	Key(ctx context.Context, kid string) (crypto.PublicKey, error)
+}
+
+// jsonWebKey is a public key from a JSON Web Key Set. See RFC 7517.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	// RSA parameters.
+	N string `json:"n"`
+	E string `json:"e"`
+	// Elliptic curve parameters.
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("rsa exponent is too large")
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		// ES256 is the only supported elliptic curve algorithm.
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %s", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		// Reject points that are not on the curve.
+		point := make([]byte, 65)
+		point[0] = 4
+		if x.BitLen() > 256 || y.BitLen() > 256 {
+			return nil, errors.New("invalid curve point")
+		}
+		x.FillBytes(point[1:33])
+		y.FillBytes(point[33:])
+		if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type %s", k.Kty)
+}
+
+// ParseJWKS parses the signing keys of a JSON Web Key Set, keyed by their ID.
+// Keys that are not used for signatures or have an unsupported type are skipped.
+func ParseJWKS(data []byte) (map[string]crypto.PublicKey, error) {
+	var set jsonWebKeySet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, ErrNoUsableKeys
+	}
+
+	return keys, nil
+}
+
+// StaticKeySet is a fixed set of keys. It is used for development and tests.
+type StaticKeySet struct {
+	keys map[string]crypto.PublicKey
+}
+
+// LoadJWKSFile reads a JSON Web Key Set from a file.
+func LoadJWKSFile(path string) (*StaticKeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := ParseJWKS(data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse %s. %w", path, err)
+	}
+
+	return &StaticKeySet{keys: keys}, nil
+}
+
+func (s *StaticKeySet) Key(_ context.Context, kid string) (crypto.PublicKey, error) {
+	key, found := s.keys[kid]
+	if !found {
+		return nil, ErrUnknownKey
+	}
+
+	return key, nil
+}
+
+const (
+	// remoteKeySetMaxAge is how long fetched keys are used before they are fetched again.
+	remoteKeySetMaxAge = time.Hour
+	// remoteKeySetMinRefreshInterval limits how often tokens with unknown key IDs trigger a fetch.
+	remoteKeySetMinRefreshInterval = time.Minute
+)
+
+// RemoteKeySet fetches the keys from a JWKS endpoint, such as the one published by Firebase Authentication
+// or an OIDC provider. Keys are cached and fetched again when they expire or when a token uses a new key ID.
+type RemoteKeySet struct {
+	client    *http.Client
+	url       string
+	now       func() time.Time
+	mu        sync.Mutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+}
+
+// NewRemoteKeySet creates a key set for the JWKS endpoint.
+func NewRemoteKeySet(client *http.Client, url string) *RemoteKeySet {
+	return &RemoteKeySet{
+		client:    client,
+		url:       url,
+		now:       time.Now,
+		mu:        sync.Mutex{},
+		keys:      nil,
+		fetchedAt: time.Time{},
+	}
+}
+
+func (s *RemoteKeySet) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d fetching %s", resp.StatusCode, s.url)
+	}
+	var set json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, err
+	}
+
+	return ParseJWKS(set)
+}
+
+func (s *RemoteKeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	age := now.Sub(s.fetchedAt)
+	key, found := s.keys[kid]
+	if found && age < remoteKeySetMaxAge {
+		return key, nil
+	}
+	// Keys are rotated ahead of time. Avoid fetching on every token with a made up key ID.
+	if s.keys != nil && !found && age < remoteKeySetMinRefreshInterval {
+		return nil, ErrUnknownKey
+	}
+
+	keys, err := s.fetch(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch keys. %w", err)
+	}
+	s.keys = keys
+	s.fetchedAt = now
+
+	key, found = s.keys[kid]
+	if !found {
+		return nil, ErrUnknownKey
+	}
+
+	return key, nil
+}
+
+// userClaims are the claims of the tokens issued to users. Roles is a custom claim.
+type userClaims struct {
+	jwt.RegisteredClaims
+	Email string   `json:"email"`
+	Roles []string `json:"roles"`
+}
+
+// JWTVerifier verifies bearer tokens that are JSON Web Tokens signed by a key in the key set.
+type JWTVerifier struct {
+	keys     KeySet
+	issuer   string
+	audience string
+}
+
+// NewJWTVerifier creates a verifier that only accepts tokens from the issuer for the audience.
+// For Firebase Authentication, the issuer is https://securetoken.google.com/<project id>
+// and the audience is the project ID.
+func NewJWTVerifier(keys KeySet, issuer, audience string) *JWTVerifier {
+	return &JWTVerifier{keys: keys, issuer: issuer, audience: audience}
+}
+
+func (v *JWTVerifier) VerifyToken(ctx context.Context, token string) (*User, error) {
+	// Errors from fetching the keys are returned as-is. They do not mean the token is invalid.
+	var keyErr error
+	keyFunc := func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, err := v.keys.Key(ctx, kid)
+		if err != nil && !errors.Is(err, ErrUnknownKey) {
+			keyErr = err
+		}
+
+		return key, err
+	}
+
+	var claims userClaims
+	_, err := jwt.ParseWithClaims(token, &claims, keyFunc,
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg()}),
+		jwt.WithIssuer(v.issuer),
+		jwt.WithAudience(v.audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if keyErr != nil {
+		return nil, keyErr
+	} else if err != nil {
+		return nil, errors.Join(ErrInvalidToken, err)
+	}
+	if claims.Subject == "" {
+		return nil, errors.Join(ErrInvalidToken, errors.New("token has no subject"))
+	}
+
+	return &User{ID: claims.Subject, Email: claims.Email, Roles: claims.Roles}, nil
+}
@@ -0,0 +1,304 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpmiddlewares
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testIssuer   = "https://securetoken.google.com/local"
+	testAudience = "local"
+)
+
+type testKeys struct {
+	ec  *ecdsa.PrivateKey
+	rsa *rsa.PrivateKey
+}
+
+func newTestKeys(t *testing.T) testKeys {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unable to generate ec key %v", err)
+	}
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unable to generate rsa key %v", err)
+	}
+
+	return testKeys{ec: ecKey, rsa: rsaKey}
+}
+
+func encodeBigInt(i *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(i.Bytes())
+}
+
+func (k testKeys) jwks(t *testing.T) []byte {
+	data, err := json.Marshal(map[string]any{
+		"keys": []map[string]string{
+			{
+				"kty": "EC",
+				"kid": "ec",
+				"use": "sig",
+				"crv": "P-256",
+				"x":   encodeBigInt(k.ec.X),
+				"y":   encodeBigInt(k.ec.Y),
+			},
+			{
+				"kty": "RSA",
+				"kid": "rsa",
+				"n":   encodeBigInt(k.rsa.N),
+				"e":   encodeBigInt(big.NewInt(int64(k.rsa.E))),
+			},
+			// Encryption keys are skipped.
+			{
+				"kty": "RSA",
+				"kid": "enc",
+				"use": "enc",
+				"n":   encodeBigInt(k.rsa.N),
+				"e":   encodeBigInt(big.NewInt(int64(k.rsa.E))),
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to marshal jwks %v", err)
+	}
+
+	return data
+}
+
+func (k testKeys) writeJWKS(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, k.jwks(t), 0600); err != nil {
+		t.Fatalf("unable to write jwks %v", err)
+	}
+
+	return path
+}
+
+func signTestToken(t *testing.T, method jwt.SigningMethod, kid string, key any, claims jwt.Claims) string {
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("unable to sign token %v", err)
+	}
+
+	return signed
+}
+
+func testClaims(mutate func(*userClaims)) userClaims {
+	now := time.Now()
+	claims := userClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    testIssuer,
+			Subject:   "uid",
+			Audience:  jwt.ClaimStrings{testAudience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+			NotBefore: nil,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ID:        "",
+		},
+		Email: "user@example.com",
+		Roles: []string{"admin"},
+	}
+	if mutate != nil {
+		mutate(&claims)
+	}
+
+	return claims
+}
+
+func TestJWTVerifier(t *testing.T) {
+	keys := newTestKeys(t)
+	keySet, err := LoadJWKSFile(keys.writeJWKS(t))
+	if err != nil {
+		t.Fatalf("unable to load jwks %v", err)
+	}
+	if _, err := keySet.Key(context.Background(), "enc"); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("expected encryption keys to be skipped. received %v", err)
+	}
+	verifier := NewJWTVerifier(keySet, testIssuer, testAudience)
+
+	testCases := []struct {
+		name         string
+		token        string
+		expectedUser *User
+	}{
+		{
+			name:         "valid ES256 token",
+			token:        signTestToken(t, jwt.SigningMethodES256, "ec", keys.ec, testClaims(nil)),
+			expectedUser: &User{ID: "uid", Email: "user@example.com", Roles: []string{"admin"}},
+		},
+		{
+			name:         "valid RS256 token",
+			token:        signTestToken(t, jwt.SigningMethodRS256, "rsa", keys.rsa, testClaims(nil)),
+			expectedUser: &User{ID: "uid", Email: "user@example.com", Roles: []string{"admin"}},
+		},
+		{
+			name: "expired",
+			token: signTestToken(t, jwt.SigningMethodES256, "ec", keys.ec, testClaims(func(c *userClaims) {
+				c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+			})),
+			expectedUser: nil,
+		},
+		{
+			name: "no expiration",
+			token: signTestToken(t, jwt.SigningMethodES256, "ec", keys.ec, testClaims(func(c *userClaims) {
+				c.ExpiresAt = nil
+			})),
+			expectedUser: nil,
+		},
+		{
+			name: "wrong issuer",
+			token: signTestToken(t, jwt.SigningMethodES256, "ec", keys.ec, testClaims(func(c *userClaims) {
+				c.Issuer = "https://example.com"
+			})),
+			expectedUser: nil,
+		},
+		{
+			name: "wrong audience",
+			token: signTestToken(t, jwt.SigningMethodES256, "ec", keys.ec, testClaims(func(c *userClaims) {
+				c.Audience = jwt.ClaimStrings{"other"}
+			})),
+			expectedUser: nil,
+		},
+		{
+			name: "no subject",
+			token: signTestToken(t, jwt.SigningMethodES256, "ec", keys.ec, testClaims(func(c *userClaims) {
+				c.Subject = ""
+			})),
+			expectedUser: nil,
+		},
+		{
+			name:         "unknown key",
+			token:        signTestToken(t, jwt.SigningMethodES256, "other", keys.ec, testClaims(nil)),
+			expectedUser: nil,
+		},
+		{
+			name:         "signed by another key",
+			token:        signTestToken(t, jwt.SigningMethodES256, "ec", newTestKeys(t).ec, testClaims(nil)),
+			expectedUser: nil,
+		},
+		{
+			name:         "symmetric algorithm",
+			token:        signTestToken(t, jwt.SigningMethodHS256, "rsa", []byte("secret"), testClaims(nil)),
+			expectedUser: nil,
+		},
+		{
+			name:         "malformed",
+			token:        "not-a-token",
+			expectedUser: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			user, err := verifier.VerifyToken(context.Background(), tc.token)
+			if tc.expectedUser == nil {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Errorf("expected invalid token error, received %v", err)
+				}
+
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !reflect.DeepEqual(user, tc.expectedUser) {
+				t.Errorf("unexpected user %+v", user)
+			}
+		})
+	}
+}
+
+func TestParseJWKSNoUsableKeys(t *testing.T) {
+	_, err := ParseJWKS([]byte(`{"keys":[{"kty":"oct","kid":"hmac","k":"c2VjcmV0"}]}`))
+	if !errors.Is(err, ErrNoUsableKeys) {
+		t.Errorf("expected no usable keys error, received %v", err)
+	}
+}
+
+func TestRemoteKeySet(t *testing.T) {
+	keys := newTestKeys(t)
+	fetches := 0
+	available := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fetches++
+		if !available {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+		_, _ = w.Write(keys.jwks(t))
+	}))
+	defer server.Close()
+
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	keySet := NewRemoteKeySet(server.Client(), server.URL)
+	keySet.now = func() time.Time { return now }
+	verifier := NewJWTVerifier(keySet, testIssuer, testAudience)
+	ctx := context.Background()
+
+	// The keys are fetched once and then cached.
+	for range 2 {
+		if _, err := keySet.Key(ctx, "ec"); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+	if fetches != 1 {
+		t.Errorf("expected 1 fetch, received %d", fetches)
+	}
+
+	// Unknown key IDs only trigger a fetch once the minimum interval passed.
+	if _, err := keySet.Key(ctx, "new"); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("expected unknown key error, received %v", err)
+	}
+	if fetches != 1 {
+		t.Errorf("expected 1 fetch, received %d", fetches)
+	}
+	now = now.Add(2 * remoteKeySetMinRefreshInterval)
+	if _, err := keySet.Key(ctx, "new"); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("expected unknown key error, received %v", err)
+	}
+	if fetches != 2 {
+		t.Errorf("expected 2 fetches, received %d", fetches)
+	}
+
+	// Failing to fetch the keys is not the fault of the token.
+	available = false
+	now = now.Add(remoteKeySetMaxAge)
+	_, err := verifier.VerifyToken(ctx, signTestToken(t, jwt.SigningMethodES256, "ec", keys.ec, testClaims(nil)))
+	if err == nil || errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected a non token error, received %v", err)
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpmiddlewares
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/backend"
+)
+
+// RateLimiter stores a token bucket per key.
+type RateLimiter interface {
+	// Take removes a token from the bucket for the key. The bucket holds up to limit
+	// tokens and refills completely over the period.
+	// If no token is available, it returns how long until one will be.
+	Take(ctx context.Context, key string, limit int, period time.Duration) (bool, time.Duration, error)
+}
+
+// RateLimit is the number of requests a client can make over a period.
+// A zero value means that there is no limit.
+type RateLimit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// RateLimitPolicy is the rate limit for every path that starts with PathPrefix.
+// Each policy has its own bucket per client.
+type RateLimitPolicy struct {
+	PathPrefix string
+	Limit      RateLimit
+}
+
+// RateLimitMiddlewareConfig configures the limits enforced by the rate limit middleware.
+type RateLimitMiddlewareConfig struct {
+	// Policies are checked in order and the first matching policy is used.
+	Policies []RateLimitPolicy
+	// DefaultLimit is used when no policy matches.
+	DefaultLimit RateLimit
+	// QuotaTierMultipliers scale the limits of clients with an API key in the given quota tier.
+	QuotaTierMultipliers map[string]int
+	// TrustedProxyHops is the number of trusted proxies that append an address to X-Forwarded-For
+	// after the client's address. The global external load balancer appends its own address, so it is 1
+	// behind the load balancer.
+	TrustedProxyHops int
+}
+
+func (c RateLimitMiddlewareConfig) policy(path string) RateLimitPolicy {
+	for _, policy := range c.Policies {
+		if strings.HasPrefix(path, policy.PathPrefix) {
+			return policy
+		}
+	}
+
+	return RateLimitPolicy{PathPrefix: "", Limit: c.DefaultLimit}
+}
+
+// clientKey returns the key of the client's bucket along with the client's limit.
+// Clients with a valid API key are limited by their key instead of their IP address.
+// Unvalidated keys are ignored so that clients cannot get a new bucket by changing their key.
+func (c RateLimitMiddlewareConfig) clientKey(r *http.Request, limit RateLimit) (string, RateLimit) {
+	identity, ok := ClientIdentityFromContext(r.Context())
+	if !ok {
+		return "ip-" + clientIP(r, c.TrustedProxyHops), limit
+	}
+	if multiplier, found := c.QuotaTierMultipliers[identity.QuotaTier]; found {
+		limit.Requests *= multiplier
+	}
+
+	return "key-" + identity.ID, limit
+}
+
+// clientIP returns the IP address of the client.
+// Each trusted proxy appends the address it received the request from to X-Forwarded-For, so the entry
+// before the ones added by the trusted proxies is used. Earlier entries are supplied by the client and
+// cannot be trusted.
+func clientIP(r *http.Request, trustedProxyHops int) string {
+	if forwardedFor := r.Header.Get("X-Forwarded-For"); forwardedFor != "" {
+		entries := strings.Split(forwardedFor, ",")
+		idx := max(0, len(entries)-1-trustedProxyHops)
+		if ip := strings.TrimSpace(entries[idx]); ip != "" {
+			return ip
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// NewRateLimitMiddleware must run after NewAPIKeyMiddleware so that clients with an API key are identified.
+// It returns a 429 with a Retry-After header once a client
+// has used all of the requests allowed for the route.
+// If the limiter fails, the request is allowed so that an outage of the
+// limiter's storage does not take down the API.
+func NewRateLimitMiddleware(limiter RateLimiter, config RateLimitMiddlewareConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			policy := config.policy(r.URL.Path)
+			if policy.Limit.Requests <= 0 || policy.Limit.Period <= 0 {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			clientKey, limit := config.clientKey(r, policy.Limit)
+			key := policy.PathPrefix + "-" + clientKey
+			allowed, retryAfter, err := limiter.Take(r.Context(), key, limit.Requests, limit.Period)
+			if err != nil {
+				slog.ErrorContext(r.Context(), "unable to check rate limit", "error", err)
+				next.ServeHTTP(w, r)
+
+				return
+			}
+			if allowed {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			retryAfterSeconds := max(1, int(math.Ceil(retryAfter.Seconds())))
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusTooManyRequests)
+			err = json.NewEncoder(w).Encode(backend.BasicErrorModel{
+				Code:    http.StatusTooManyRequests,
+				Message: "rate limit exceeded",
+			})
+			if err != nil {
+				slog.ErrorContext(r.Context(), "unable to write rate limit response", "error", err)
+			}
+		})
+	}
+}
+
+// InMemoryRateLimiter stores the token buckets in memory.
+// The buckets are not shared between instances, so it is only meant for local runs and tests.
+type InMemoryRateLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	now     func() time.Time
+}
+
+type tokenBucket struct {
+	tokens     float64
+	lastRefill time.Time
+}
+
+// NewInMemoryRateLimiter creates an empty InMemoryRateLimiter.
+func NewInMemoryRateLimiter() *InMemoryRateLimiter {
+	return &InMemoryRateLimiter{
+		mu:      sync.Mutex{},
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+// Take implements RateLimiter.
+func (l *InMemoryRateLimiter) Take(
+	_ context.Context,
+	key string,
+	limit int,
+	period time.Duration,
+) (bool, time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	bucket, found := l.buckets[key]
+	if !found {
+		bucket = &tokenBucket{tokens: float64(limit), lastRefill: now}
+		l.buckets[key] = bucket
+	}
+	// Tokens refill continuously at limit tokens per period.
+	tokensPerSecond := float64(limit) / period.Seconds()
+	elapsed := max(0, now.Sub(bucket.lastRefill).Seconds())
+	bucket.tokens = min(float64(limit), bucket.tokens+elapsed*tokensPerSecond)
+	bucket.lastRefill = now
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+
+		return true, 0, nil
+	}
+
+	return false, time.Duration((1 - bucket.tokens) / tokensPerSecond * float64(time.Second)), nil
+}
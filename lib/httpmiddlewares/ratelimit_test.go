@@ -0,0 +1,330 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpmiddlewares
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type mockRateLimiter struct {
+	t                 *testing.T
+	expectedKey       string
+	expectedLimit     int
+	expectedPeriod    time.Duration
+	allowed           bool
+	retryAfter        time.Duration
+	err               error
+	callCount         int
+	expectedCallCount int
+}
+
+func (m *mockRateLimiter) Take(_ context.Context, key string, limit int, period time.Duration) (bool, time.Duration, error) {
+	m.callCount++
+	if key != m.expectedKey || limit != m.expectedLimit || period != m.expectedPeriod {
+		m.t.Errorf("unexpected input key %s limit %d period %s", key, limit, period)
+	}
+
+	return m.allowed, m.retryAfter, m.err
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	config := RateLimitMiddlewareConfig{
+		Policies: []RateLimitPolicy{
+			{PathPrefix: "/v1/features:export", Limit: RateLimit{Requests: 10, Period: time.Minute}},
+			{PathPrefix: "/unlimited", Limit: RateLimit{Requests: 0, Period: 0}},
+		},
+		DefaultLimit:         RateLimit{Requests: 100, Period: time.Minute},
+		QuotaTierMultipliers: map[string]int{"high": 10},
+		TrustedProxyHops:     1,
+	}
+	testCases := []struct {
+		name               string
+		url                string
+		remoteAddr         string
+		requestHeaders     map[string]string
+		identity           *ClientIdentity
+		limiter            *mockRateLimiter
+		expectedStatus     int
+		expectedBody       string
+		expectedRetryAfter string
+	}{
+		{
+			name:           "allowed by default policy",
+			url:            "/v1/features",
+			remoteAddr:     "192.0.2.1:1234",
+			requestHeaders: nil,
+			identity:       nil,
+			limiter: &mockRateLimiter{
+				expectedKey:       "-ip-192.0.2.1",
+				expectedLimit:     100,
+				expectedPeriod:    time.Minute,
+				allowed:           true,
+				retryAfter:        0,
+				err:               nil,
+				expectedCallCount: 1,
+			},
+			expectedStatus:     http.StatusOK,
+			expectedBody:       "ok",
+			expectedRetryAfter: "",
+		},
+		{
+			name:           "limited by route policy using forwarded address",
+			url:            "/v1/features:export?format=csv",
+			remoteAddr:     "192.0.2.1:1234",
+			requestHeaders: map[string]string{"X-Forwarded-For": "198.51.100.1, 203.0.113.1, 192.0.2.100"},
+			identity:       nil,
+			limiter: &mockRateLimiter{
+				expectedKey:       "/v1/features:export-ip-203.0.113.1",
+				expectedLimit:     10,
+				expectedPeriod:    time.Minute,
+				allowed:           false,
+				retryAfter:        1500 * time.Millisecond,
+				err:               nil,
+				expectedCallCount: 1,
+			},
+			expectedStatus:     http.StatusTooManyRequests,
+			expectedBody:       `{"code":429,"message":"rate limit exceeded"}` + "\n",
+			expectedRetryAfter: "2",
+		},
+		{
+			name:           "limited by api key instead of address",
+			url:            "/v1/features",
+			remoteAddr:     "192.0.2.1:1234",
+			requestHeaders: nil,
+			identity:       &ClientIdentity{ID: "id", Owner: "ci", QuotaTier: "standard"},
+			limiter: &mockRateLimiter{
+				expectedKey:       "-key-id",
+				expectedLimit:     100,
+				expectedPeriod:    time.Minute,
+				allowed:           false,
+				retryAfter:        0,
+				err:               nil,
+				expectedCallCount: 1,
+			},
+			expectedStatus:     http.StatusTooManyRequests,
+			expectedBody:       `{"code":429,"message":"rate limit exceeded"}` + "\n",
+			expectedRetryAfter: "1",
+		},
+		{
+			name:           "api key with higher quota tier",
+			url:            "/v1/features:export",
+			remoteAddr:     "192.0.2.1:1234",
+			requestHeaders: nil,
+			identity:       &ClientIdentity{ID: "id", Owner: "ci", QuotaTier: "high"},
+			limiter: &mockRateLimiter{
+				expectedKey:       "/v1/features:export-key-id",
+				expectedLimit:     100,
+				expectedPeriod:    time.Minute,
+				allowed:           true,
+				retryAfter:        0,
+				err:               nil,
+				expectedCallCount: 1,
+			},
+			expectedStatus:     http.StatusOK,
+			expectedBody:       "ok",
+			expectedRetryAfter: "",
+		},
+		{
+			name:           "unvalidated api key header is ignored",
+			url:            "/v1/features",
+			remoteAddr:     "192.0.2.1:1234",
+			requestHeaders: map[string]string{APIKeyHeader: "secret"},
+			identity:       nil,
+			limiter: &mockRateLimiter{
+				expectedKey:       "-ip-192.0.2.1",
+				expectedLimit:     100,
+				expectedPeriod:    time.Minute,
+				allowed:           true,
+				retryAfter:        0,
+				err:               nil,
+				expectedCallCount: 1,
+			},
+			expectedStatus:     http.StatusOK,
+			expectedBody:       "ok",
+			expectedRetryAfter: "",
+		},
+		{
+			name:           "route without limit",
+			url:            "/unlimited",
+			remoteAddr:     "192.0.2.1:1234",
+			requestHeaders: nil,
+			identity:       nil,
+			// nolint: exhaustruct // Should not be called.
+			limiter:            &mockRateLimiter{expectedCallCount: 0},
+			expectedStatus:     http.StatusOK,
+			expectedBody:       "ok",
+			expectedRetryAfter: "",
+		},
+		{
+			name:           "limiter failure allows request",
+			url:            "/v1/features",
+			remoteAddr:     "192.0.2.1:1234",
+			requestHeaders: nil,
+			identity:       nil,
+			limiter: &mockRateLimiter{
+				expectedKey:       "-ip-192.0.2.1",
+				expectedLimit:     100,
+				expectedPeriod:    time.Minute,
+				allowed:           false,
+				retryAfter:        0,
+				err:               errors.New("limiter error"),
+				expectedCallCount: 1,
+			},
+			expectedStatus:     http.StatusOK,
+			expectedBody:       "ok",
+			expectedRetryAfter: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.limiter.t = t
+			nextHandler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				_, err := w.Write([]byte("ok"))
+				if err != nil {
+					t.Errorf("unknown error %s", err.Error())
+				}
+			})
+			req := httptest.NewRequest(http.MethodGet, tc.url, nil)
+			req.RemoteAddr = tc.remoteAddr
+			for key, value := range tc.requestHeaders {
+				req.Header.Set(key, value)
+			}
+			if tc.identity != nil {
+				req = req.WithContext(ContextWithClientIdentity(req.Context(), *tc.identity))
+			}
+			recorder := httptest.NewRecorder()
+			NewRateLimitMiddleware(tc.limiter, config)(nextHandler).ServeHTTP(recorder, req)
+
+			if recorder.Code != tc.expectedStatus {
+				t.Errorf("Expected status code %d, got %d", tc.expectedStatus, recorder.Code)
+			}
+			if recorder.Body.String() != tc.expectedBody {
+				t.Errorf("Expected body %q, got %q", tc.expectedBody, recorder.Body.String())
+			}
+			if recorder.Header().Get("Retry-After") != tc.expectedRetryAfter {
+				t.Errorf("Expected Retry-After %q, got %q", tc.expectedRetryAfter, recorder.Header().Get("Retry-After"))
+			}
+			if tc.limiter.callCount != tc.limiter.expectedCallCount {
+				t.Errorf("Expected %d limiter calls, got %d", tc.limiter.expectedCallCount, tc.limiter.callCount)
+			}
+		})
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	testCases := []struct {
+		name             string
+		forwardedFor     string
+		trustedProxyHops int
+		expectedIP       string
+	}{
+		{
+			name:             "no forwarded header",
+			forwardedFor:     "",
+			trustedProxyHops: 1,
+			expectedIP:       "192.0.2.1",
+		},
+		{
+			name:             "behind load balancer",
+			forwardedFor:     "198.51.100.1, 192.0.2.100",
+			trustedProxyHops: 1,
+			expectedIP:       "198.51.100.1",
+		},
+		{
+			name:             "behind load balancer with spoofed entries",
+			forwardedFor:     "203.0.113.1, 198.51.100.1, 192.0.2.100",
+			trustedProxyHops: 1,
+			expectedIP:       "198.51.100.1",
+		},
+		{
+			name:             "without trusted proxies",
+			forwardedFor:     "203.0.113.1, 198.51.100.1",
+			trustedProxyHops: 0,
+			expectedIP:       "198.51.100.1",
+		},
+		{
+			name:             "fewer entries than trusted proxies",
+			forwardedFor:     "198.51.100.1",
+			trustedProxyHops: 2,
+			expectedIP:       "198.51.100.1",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/features", nil)
+			req.RemoteAddr = "192.0.2.1:1234"
+			if tc.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tc.forwardedFor)
+			}
+			ip := clientIP(req, tc.trustedProxyHops)
+			if ip != tc.expectedIP {
+				t.Errorf("expected %s, got %s", tc.expectedIP, ip)
+			}
+		})
+	}
+}
+
+func TestInMemoryRateLimiter(t *testing.T) {
+	limiter := NewInMemoryRateLimiter()
+	now := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		allowed, _, err := limiter.Take(ctx, "client", 2, time.Minute)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if !allowed {
+			t.Errorf("expected request %d to be allowed", i)
+		}
+	}
+
+	allowed, retryAfter, err := limiter.Take(ctx, "client", 2, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if allowed {
+		t.Error("expected request to be limited")
+	}
+	if retryAfter != 30*time.Second {
+		t.Errorf("unexpected retry after %s", retryAfter)
+	}
+
+	// Other clients have their own bucket.
+	allowed, _, err = limiter.Take(ctx, "other-client", 2, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !allowed {
+		t.Error("expected other client to be allowed")
+	}
+
+	// Half of the period refills one token.
+	now = now.Add(30 * time.Second)
+	allowed, _, err = limiter.Take(ctx, "client", 2, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !allowed {
+		t.Error("expected request to be allowed after refill")
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lifecycle handles the graceful shutdown of the servers and jobs.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// DefaultShutdownTimeout is how long in-flight requests have to finish and,
+// separately, how long the closers have to run.
+// Cloud Run sends SIGKILL 10 seconds after SIGTERM.
+const DefaultShutdownTimeout = 4 * time.Second
+
+// Closer releases a resource, such as a client or telemetry exporter, during shutdown.
+type Closer struct {
+	Name  string
+	Close func(ctx context.Context) error
+}
+
+// NewCloser creates a Closer for a Close method that does not take a context.
+func NewCloser(name string, closeFn func() error) Closer {
+	return Closer{
+		Name: name,
+		Close: func(_ context.Context) error {
+			return closeFn()
+		},
+	}
+}
+
+// SignalContext returns a context that is canceled once the process receives SIGINT or SIGTERM.
+func SignalContext(parent context.Context) (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(parent, os.Interrupt, syscall.SIGTERM)
+}
+
+// Close runs the closers in order, even if one of them fails.
+// All of the closers share the timeout.
+func Close(timeout time.Duration, closers ...Closer) error {
+	// The parent context is likely canceled already, so start from a fresh context.
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var errs []error
+	for _, closer := range closers {
+		slog.InfoContext(ctx, "closing", "name", closer.Name)
+		err := closer.Close(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "unable to close", "name", closer.Name, "error", err)
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ListenAndServe serves requests until the context is canceled.
+// It then stops accepting connections, waits for in-flight requests to finish and runs the closers in order.
+// The closers also run if the server fails to start.
+func ListenAndServe(ctx context.Context, srv *http.Server, timeout time.Duration, closers ...Closer) error {
+	listener, err := net.Listen("tcp", srv.Addr)
+	if err != nil {
+		return errors.Join(err, Close(timeout, closers...))
+	}
+
+	return serve(ctx, srv, listener, timeout, closers...)
+}
+
+func serve(ctx context.Context, srv *http.Server, listener net.Listener, timeout time.Duration, closers ...Closer) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.Serve(listener)
+	}()
+
+	var err error
+	select {
+	case err = <-serveErr:
+		// The server stopped on its own.
+	case <-ctx.Done():
+		slog.InfoContext(ctx, "shutting down server")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		err = srv.Shutdown(shutdownCtx)
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		err = nil
+	}
+
+	return errors.Join(err, Close(timeout, closers...))
+}
@@ -0,0 +1,121 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+)
+
+var errTestClose = errors.New("close error")
+
+func TestClose(t *testing.T) {
+	var closed []string
+	closers := []Closer{
+		NewCloser("telemetry", func() error {
+			closed = append(closed, "telemetry")
+
+			return nil
+		}),
+		{
+			Name: "spanner",
+			Close: func(ctx context.Context) error {
+				if _, ok := ctx.Deadline(); !ok {
+					t.Error("expected closer context to have a deadline")
+				}
+				closed = append(closed, "spanner")
+
+				return errTestClose
+			},
+		},
+		NewCloser("redis", func() error {
+			closed = append(closed, "redis")
+
+			return nil
+		}),
+	}
+
+	err := Close(time.Second, closers...)
+	if !errors.Is(err, errTestClose) {
+		t.Errorf("unexpected error %v", err)
+	}
+	// The closers after the failing one still run.
+	expected := []string{"telemetry", "spanner", "redis"}
+	if !slices.Equal(closed, expected) {
+		t.Errorf("unexpected close order %v", closed)
+	}
+}
+
+func TestServeDrainsInFlightRequests(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen %s", err.Error())
+	}
+	started := make(chan struct{})
+	// nolint:exhaustruct // No need to populate 3rd party struct
+	srv := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			close(started)
+			time.Sleep(100 * time.Millisecond)
+			_, _ = w.Write([]byte("done"))
+		}),
+		ReadHeaderTimeout: time.Second,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	closed := false
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- serve(ctx, srv, listener, time.Second, NewCloser("client", func() error {
+			closed = true
+
+			return nil
+		}))
+	}()
+
+	responseBody := make(chan string, 1)
+	go func() {
+		resp, err := http.Get("http://" + listener.Addr().String())
+		if err != nil {
+			t.Errorf("unexpected request error %s", err.Error())
+			responseBody <- ""
+
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		responseBody <- string(body)
+	}()
+
+	// Shut down while the request is in flight.
+	<-started
+	cancel()
+
+	if body := <-responseBody; body != "done" {
+		t.Errorf("expected in-flight request to finish. received %q", body)
+	}
+	if err := <-serveErr; err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if !closed {
+		t.Error("expected closer to run")
+	}
+}
@@ -17,6 +17,7 @@ package rediscache
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/GoogleChrome/webstatus.dev/lib/cachetypes"
@@ -59,6 +60,23 @@ func (c *RedisDataCache[K, V]) cacheKey(key K) string {
 	return fmt.Sprintf("%s-%v", c.keyPrefix, key)
 }
 
+// Close closes the connection pool. The rate limiters created from the cache share the pool.
+func (c *RedisDataCache[K, V]) Close() error {
+	return c.redisPool.Close()
+}
+
+// Ping checks that a connection to redis can be established and used.
+func (c *RedisDataCache[K, V]) Ping(ctx context.Context) error {
+	conn, err := c.redisPool.GetContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = redis.DoContext(conn, ctx, "PING")
+
+	return err
+}
+
 // Cache stores a value in the cache.
 func (c *RedisDataCache[K, V]) Cache(
 	ctx context.Context,
@@ -104,3 +122,45 @@ func (c *RedisDataCache[K, V]) Get(
 
 	return result, nil
 }
+
+// clearBatchSize is the number of keys scanned per call while clearing the cache.
+const clearBatchSize = 1000
+
+// Clear removes every value stored with Cache under the key prefix.
+// The buckets of the rate limiters that share the key prefix are kept.
+func (c *RedisDataCache[K, V]) Clear(ctx context.Context) error {
+	conn, err := c.redisPool.GetContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	rateLimitPrefix := NewRedisRateLimiter(c).bucketKey("")
+	cursor := 0
+	for {
+		values, err := redis.Values(redis.DoContext(
+			conn, ctx, "SCAN", cursor, "MATCH", c.keyPrefix+"-*", "COUNT", clearBatchSize))
+		if err != nil {
+			return err
+		}
+		var keys []string
+		if _, err := redis.Scan(values, &cursor, &keys); err != nil {
+			return err
+		}
+		args := redis.Args{}
+		for _, key := range keys {
+			if !strings.HasPrefix(key, rateLimitPrefix) {
+				args = args.Add(key)
+			}
+		}
+		if len(args) > 0 {
+			if _, err := redis.DoContext(conn, ctx, "DEL", args...); err != nil {
+				return err
+			}
+		}
+		// SCAN returns a cursor of zero once every key was visited.
+		if cursor == 0 {
+			return nil
+		}
+	}
+}
@@ -82,6 +82,13 @@ func TestRedisDataCache(t *testing.T) {
 	testKey1 := "test-key-1"
 	testValue1 := []byte("test-value")
 
+	t.Run("ping", func(t *testing.T) {
+		err := cache.Ping(ctx)
+		if err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+	})
+
 	t.Run("cache miss", func(t *testing.T) {
 		result, err := cache.Get(ctx, testKey1)
 		if !errors.Is(err, cachetypes.ErrCachedDataNotFound) {
@@ -120,4 +127,80 @@ func TestRedisDataCache(t *testing.T) {
 
 	})
 
+	t.Run("clear", func(t *testing.T) {
+		limiter := NewRedisRateLimiter(cache)
+		if _, _, err := limiter.Take(ctx, "client", 1, time.Minute); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		for _, key := range []string{"/v1/features", "/v1/features/grid"} {
+			if err := cache.Cache(ctx, key, testValue1); err != nil {
+				t.Fatalf("invalid error storing value %v", err)
+			}
+		}
+
+		if err := cache.Clear(ctx); err != nil {
+			t.Fatalf("unexpected error clearing cache %v", err)
+		}
+		for _, key := range []string{"/v1/features", "/v1/features/grid"} {
+			if _, err := cache.Get(ctx, key); !errors.Is(err, cachetypes.ErrCachedDataNotFound) {
+				t.Errorf("expected %s to be cleared. received %v", key, err)
+			}
+		}
+		// The rate limit bucket is kept.
+		allowed, _, err := limiter.Take(ctx, "client", 1, time.Minute)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if allowed {
+			t.Error("expected the rate limit bucket to be kept")
+		}
+	})
+}
+
+func TestRedisRateLimiter(t *testing.T) {
+	cache := getTestRedis(t)
+	limiter := NewRedisRateLimiter(cache)
+	now := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		allowed, _, err := limiter.Take(ctx, "client", 2, time.Minute)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if !allowed {
+			t.Errorf("expected request %d to be allowed", i)
+		}
+	}
+
+	allowed, retryAfter, err := limiter.Take(ctx, "client", 2, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if allowed {
+		t.Error("expected request to be limited")
+	}
+	if retryAfter != 30*time.Second {
+		t.Errorf("unexpected retry after %s", retryAfter)
+	}
+
+	// Other clients have their own bucket.
+	allowed, _, err = limiter.Take(ctx, "other-client", 2, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !allowed {
+		t.Error("expected other client to be allowed")
+	}
+
+	// Half of the period refills one token.
+	now = now.Add(30 * time.Second)
+	allowed, _, err = limiter.Take(ctx, "client", 2, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !allowed {
+		t.Error("expected request to be allowed after refill")
+	}
 }
@@ -0,0 +1,109 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rediscache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// tokenBucketScript atomically refills and takes a token from the bucket stored at KEYS[1].
+// ARGV is the capacity, the refill period in milliseconds and the current time in milliseconds.
+// It returns whether a token was taken and, if not, how many milliseconds until one is available.
+// The bucket expires once it would have refilled completely.
+const tokenBucketScript = `
+local capacity = tonumber(ARGV[1])
+local period_ms = tonumber(ARGV[2])
+local now_ms = tonumber(ARGV[3])
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(bucket[1])
+local ts = tonumber(bucket[2])
+if tokens == nil or ts == nil then
+	tokens = capacity
+	ts = now_ms
+end
+local elapsed = math.max(0, now_ms - ts)
+tokens = math.min(capacity, tokens + elapsed * capacity / period_ms)
+local allowed = 0
+local retry_ms = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry_ms = math.ceil((1 - tokens) * period_ms / capacity)
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now_ms)
+redis.call('PEXPIRE', KEYS[1], period_ms)
+return {allowed, retry_ms}
+`
+
+// RedisRateLimiter stores token buckets in redis so that every instance of a
+// service shares the same buckets.
+type RedisRateLimiter struct {
+	keyPrefix string
+	redisPool *redis.Pool
+	script    *redis.Script
+	now       func() time.Time
+}
+
+// NewRedisRateLimiter creates a rate limiter that shares the connection pool and key prefix of the cache.
+func NewRedisRateLimiter[K comparable, V any](cache *RedisDataCache[K, V]) *RedisRateLimiter {
+	return &RedisRateLimiter{
+		keyPrefix: cache.keyPrefix,
+		redisPool: cache.redisPool,
+		script:    redis.NewScript(1, tokenBucketScript),
+		now:       time.Now,
+	}
+}
+
+func (l *RedisRateLimiter) bucketKey(key string) string {
+	return fmt.Sprintf("%s-ratelimit-%s", l.keyPrefix, key)
+}
+
+// Take removes a token from the bucket for the key. The bucket holds up to limit
+// tokens and refills completely over the period.
+// If no token is available, it returns how long until one will be.
+func (l *RedisRateLimiter) Take(
+	ctx context.Context,
+	key string,
+	limit int,
+	period time.Duration,
+) (bool, time.Duration, error) {
+	conn, err := l.redisPool.GetContext(ctx)
+	if err != nil {
+		return false, 0, err
+	}
+	defer conn.Close()
+
+	values, err := redis.Int64s(l.script.DoContext(
+		ctx,
+		conn,
+		l.bucketKey(key),
+		limit,
+		period.Milliseconds(),
+		l.now().UnixMilli(),
+	))
+	if err != nil {
+		return false, 0, err
+	}
+	if len(values) != 2 {
+		return false, 0, fmt.Errorf("unexpected token bucket result %v", values)
+	}
+
+	return values[0] == 1, time.Duration(values[1]) * time.Millisecond, nil
+}
@@ -0,0 +1,152 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// The messages of the errors are shown to the creators of subscriptions.
+var (
+	// ErrCallbackURLNotAbsolute indicates that a callback URL could not be parsed or has no host.
+	ErrCallbackURLNotAbsolute = errors.New("callback_url must be an absolute url")
+	// ErrCallbackURLInsecure indicates that payloads sent to a callback URL could be read in transit.
+	ErrCallbackURLInsecure = errors.New("callback_url must use https")
+	// ErrCallbackURLUnresolvable indicates that the host of a callback URL has no addresses.
+	ErrCallbackURLUnresolvable = errors.New("unable to resolve the host of callback_url")
+	// ErrDisallowedCallbackAddress indicates that a callback URL points to an internal address.
+	ErrDisallowedCallbackAddress = errors.New("callback_url must not resolve to a loopback or private address")
+)
+
+// IPResolver resolves the addresses of a host. net.DefaultResolver implements it.
+type IPResolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// CallbackPolicy restricts the addresses that payloads are sent to so that subscriptions cannot be used
+// to reach the internal services of the project.
+type CallbackPolicy struct {
+	resolver IPResolver
+	// Only for local development, where the receivers run next to the services.
+	allowLoopback bool
+}
+
+// NewCallbackPolicy constructs a CallbackPolicy.
+// allowLoopback also allows plain http to loopback hosts. It must only be enabled locally.
+func NewCallbackPolicy(resolver IPResolver, allowLoopback bool) *CallbackPolicy {
+	return &CallbackPolicy{
+		resolver:      resolver,
+		allowLoopback: allowLoopback,
+	}
+}
+
+// ValidateCallbackURL checks the URL when a subscription is created.
+// Every address of the host is checked because any of them may be dialed.
+// The addresses are checked again when dialing since DNS answers can change after the subscription is created.
+func (p *CallbackPolicy) ValidateCallbackURL(ctx context.Context, callbackURL string) error {
+	u, err := url.Parse(callbackURL)
+	if err != nil || u.Host == "" {
+		return ErrCallbackURLNotAbsolute
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return ErrCallbackURLInsecure
+	}
+
+	var ips []net.IP
+	if ip := net.ParseIP(u.Hostname()); ip != nil {
+		ips = append(ips, ip)
+	} else {
+		addrs, err := p.resolver.LookupIPAddr(ctx, u.Hostname())
+		if err != nil || len(addrs) == 0 {
+			return ErrCallbackURLUnresolvable
+		}
+		for _, addr := range addrs {
+			ips = append(ips, addr.IP)
+		}
+	}
+
+	for _, ip := range ips {
+		if err := p.checkIP(ip); err != nil {
+			return err
+		}
+		// Payloads can only be read in transit on the machine itself.
+		if u.Scheme == "http" && !ip.IsLoopback() {
+			return ErrCallbackURLInsecure
+		}
+	}
+
+	return nil
+}
+
+func (p *CallbackPolicy) checkIP(ip net.IP) error {
+	if ip.IsLoopback() {
+		if p.allowLoopback {
+			return nil
+		}
+
+		return ErrDisallowedCallbackAddress
+	}
+	if ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return ErrDisallowedCallbackAddress
+	}
+
+	return nil
+}
+
+// control is called after the address is resolved and before the connection is made.
+func (p *CallbackPolicy) control(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("%w: %s", ErrDisallowedCallbackAddress, host)
+	}
+
+	return p.checkIP(ip)
+}
+
+// NewHTTPClient returns a client for the Deliverer that only connects to the addresses allowed by the policy.
+// Redirects are not followed because they could point anywhere.
+func (p *CallbackPolicy) NewHTTPClient(timeout time.Duration) *http.Client {
+	// nolint: exhaustruct // No need to use every option of 3rd party struct.
+	dialer := &net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+		Control:   p.control,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would make the dialed address the one of the proxy.
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	// nolint: exhaustruct // No need to use every option of 3rd party struct.
+	return &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+		CheckRedirect: func(_ *http.Request, _ []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
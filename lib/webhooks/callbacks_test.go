@@ -0,0 +1,164 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type fakeResolver map[string][]string
+
+func (f fakeResolver) LookupIPAddr(_ context.Context, host string) ([]net.IPAddr, error) {
+	ips, found := f[host]
+	if !found {
+		return nil, errors.New("no such host")
+	}
+	addrs := make([]net.IPAddr, 0, len(ips))
+	for _, ip := range ips {
+		addrs = append(addrs, net.IPAddr{IP: net.ParseIP(ip), Zone: ""})
+	}
+
+	return addrs, nil
+}
+
+func TestValidateCallbackURL(t *testing.T) {
+	resolver := fakeResolver{
+		"example.com":  {"93.184.216.34"},
+		"internal.dev": {"93.184.216.34", "10.0.0.1"},
+		"localhost":    {"127.0.0.1"},
+	}
+	testCases := []struct {
+		name          string
+		callbackURL   string
+		allowLoopback bool
+		expectedErr   error
+	}{
+		{
+			name:          "public https",
+			callbackURL:   "https://example.com/hook",
+			allowLoopback: false,
+			expectedErr:   nil,
+		},
+		{
+			name:          "public http",
+			callbackURL:   "http://example.com/hook",
+			allowLoopback: true,
+			expectedErr:   ErrCallbackURLInsecure,
+		},
+		{
+			name:          "relative url",
+			callbackURL:   "/hook",
+			allowLoopback: false,
+			expectedErr:   ErrCallbackURLNotAbsolute,
+		},
+		{
+			name:          "unknown scheme",
+			callbackURL:   "ftp://example.com/hook",
+			allowLoopback: false,
+			expectedErr:   ErrCallbackURLInsecure,
+		},
+		{
+			name:          "unresolvable host",
+			callbackURL:   "https://unknown.example/hook",
+			allowLoopback: false,
+			expectedErr:   ErrCallbackURLUnresolvable,
+		},
+		{
+			name:          "one private address",
+			callbackURL:   "https://internal.dev/hook",
+			allowLoopback: false,
+			expectedErr:   ErrDisallowedCallbackAddress,
+		},
+		{
+			name:          "link local address",
+			callbackURL:   "https://169.254.169.254/computeMetadata/v1/",
+			allowLoopback: false,
+			expectedErr:   ErrDisallowedCallbackAddress,
+		},
+		{
+			name:          "unspecified address",
+			callbackURL:   "https://[::]/hook",
+			allowLoopback: false,
+			expectedErr:   ErrDisallowedCallbackAddress,
+		},
+		{
+			name:          "loopback",
+			callbackURL:   "http://localhost:9000/hook",
+			allowLoopback: false,
+			expectedErr:   ErrDisallowedCallbackAddress,
+		},
+		{
+			name:          "loopback during development",
+			callbackURL:   "http://localhost:9000/hook",
+			allowLoopback: true,
+			expectedErr:   nil,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			policy := NewCallbackPolicy(resolver, tc.allowLoopback)
+			err := policy.ValidateCallbackURL(context.Background(), tc.callbackURL)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected error %v, received %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestCallbackPolicyHTTPClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/redirect" {
+			http.Redirect(w, req, "/hook", http.StatusFound)
+
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	// The test server listens on a loopback address.
+	client := NewCallbackPolicy(fakeResolver{}, false).NewHTTPClient(time.Second)
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, srv.URL+"/hook", nil)
+	if err != nil {
+		t.Fatalf("unable to create request %v", err)
+	}
+	resp, err := client.Do(req)
+	if err == nil {
+		resp.Body.Close()
+	}
+	if !errors.Is(err, ErrDisallowedCallbackAddress) {
+		t.Errorf("expected the dial to be rejected, received %v", err)
+	}
+
+	client = NewCallbackPolicy(fakeResolver{}, true).NewHTTPClient(time.Second)
+	req, err = http.NewRequestWithContext(context.Background(), http.MethodPost, srv.URL+"/redirect", nil)
+	if err != nil {
+		t.Fatalf("unable to create request %v", err)
+	}
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Errorf("expected the redirect not to be followed, received status %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"slices"
+)
+
+// FeatureState is the part of a feature that subscriptions are notified about.
+type FeatureState struct {
+	FeatureID      string `json:"feature_id"`
+	BaselineStatus string `json:"baseline_status,omitempty"`
+	// AvailableBrowsers is sorted.
+	AvailableBrowsers []string `json:"available_browsers,omitempty"`
+	// StableWPTScores is keyed by browser. Browsers without a score are missing.
+	StableWPTScores map[string]float64 `json:"stable_wpt_scores,omitempty"`
+}
+
+// ChangeType is an enumeration of the changes that are sent to subscriptions.
+type ChangeType string
+
+const (
+	// BaselineStatusChanged is sent when the baseline status of a feature is different.
+	BaselineStatusChanged ChangeType = "baseline_status_changed"
+	// BrowserAvailable is sent when a browser starts supporting a feature.
+	BrowserAvailable ChangeType = "browser_available"
+	// WPTThresholdCrossed is sent when the stable WPT score of a browser moves across the threshold
+	// of the subscription, in either direction.
+	WPTThresholdCrossed ChangeType = "wpt_threshold_crossed"
+)
+
+// DefaultWPTThreshold is used by subscriptions without a threshold. It notifies when a browser passes every test.
+const DefaultWPTThreshold = 1.0
+
+// Change describes one change of a feature. Only the fields relevant to the Type are set.
+type Change struct {
+	FeatureID      string     `json:"feature_id"`
+	Type           ChangeType `json:"type"`
+	Browser        string     `json:"browser,omitempty"`
+	PreviousStatus string     `json:"previous_status,omitempty"`
+	Status         string     `json:"status,omitempty"`
+	PreviousScore  *float64   `json:"previous_score,omitempty"`
+	Score          *float64   `json:"score,omitempty"`
+	Threshold      *float64   `json:"threshold,omitempty"`
+}
+
+// DetectChanges compares two states of the same feature.
+// A feature that did not exist before is compared against an empty state.
+func DetectChanges(before, after FeatureState, wptThreshold float64) []Change {
+	var changes []Change
+	if before.BaselineStatus != after.BaselineStatus && after.BaselineStatus != "" {
+		changes = append(changes, Change{
+			FeatureID:      after.FeatureID,
+			Type:           BaselineStatusChanged,
+			Browser:        "",
+			PreviousStatus: before.BaselineStatus,
+			Status:         after.BaselineStatus,
+			PreviousScore:  nil,
+			Score:          nil,
+			Threshold:      nil,
+		})
+	}
+
+	for _, browser := range after.AvailableBrowsers {
+		if slices.Contains(before.AvailableBrowsers, browser) {
+			continue
+		}
+		changes = append(changes, Change{
+			FeatureID:      after.FeatureID,
+			Type:           BrowserAvailable,
+			Browser:        browser,
+			PreviousStatus: "",
+			Status:         "",
+			PreviousScore:  nil,
+			Score:          nil,
+			Threshold:      nil,
+		})
+	}
+
+	browsers := make([]string, 0, len(after.StableWPTScores))
+	for browser := range after.StableWPTScores {
+		browsers = append(browsers, browser)
+	}
+	slices.Sort(browsers)
+	for _, browser := range browsers {
+		score := after.StableWPTScores[browser]
+		// A missing score means that there were no results yet.
+		previousScore := before.StableWPTScores[browser]
+		crossedUp := previousScore < wptThreshold && score >= wptThreshold
+		crossedDown := previousScore >= wptThreshold && score < wptThreshold
+		if !crossedUp && !crossedDown {
+			continue
+		}
+		threshold := wptThreshold
+		changes = append(changes, Change{
+			FeatureID:      after.FeatureID,
+			Type:           WPTThresholdCrossed,
+			Browser:        browser,
+			PreviousStatus: "",
+			Status:         "",
+			PreviousScore:  &previousScore,
+			Score:          &score,
+			Threshold:      &threshold,
+		})
+	}
+
+	return changes
+}
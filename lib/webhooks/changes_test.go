@@ -0,0 +1,173 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"reflect"
+	"testing"
+)
+
+func valuePtr[T any](in T) *T { return &in }
+
+func TestDetectChanges(t *testing.T) {
+	testCases := []struct {
+		name     string
+		before   FeatureState
+		after    FeatureState
+		expected []Change
+	}{
+		{
+			name: "no changes",
+			before: FeatureState{
+				FeatureID:         "grid",
+				BaselineStatus:    "widely",
+				AvailableBrowsers: []string{"chrome", "safari"},
+				StableWPTScores:   map[string]float64{"chrome": 0.95},
+			},
+			after: FeatureState{
+				FeatureID:         "grid",
+				BaselineStatus:    "widely",
+				AvailableBrowsers: []string{"chrome", "safari"},
+				StableWPTScores:   map[string]float64{"chrome": 0.97},
+			},
+			expected: nil,
+		},
+		{
+			name: "baseline status and browser availability",
+			before: FeatureState{
+				FeatureID:         "grid",
+				BaselineStatus:    "limited",
+				AvailableBrowsers: []string{"chrome"},
+				StableWPTScores:   nil,
+			},
+			after: FeatureState{
+				FeatureID:         "grid",
+				BaselineStatus:    "newly",
+				AvailableBrowsers: []string{"chrome", "safari"},
+				StableWPTScores:   nil,
+			},
+			expected: []Change{
+				{
+					FeatureID:      "grid",
+					Type:           BaselineStatusChanged,
+					Browser:        "",
+					PreviousStatus: "limited",
+					Status:         "newly",
+					PreviousScore:  nil,
+					Score:          nil,
+					Threshold:      nil,
+				},
+				{
+					FeatureID:      "grid",
+					Type:           BrowserAvailable,
+					Browser:        "safari",
+					PreviousStatus: "",
+					Status:         "",
+					PreviousScore:  nil,
+					Score:          nil,
+					Threshold:      nil,
+				},
+			},
+		},
+		{
+			name: "wpt threshold crossed in both directions",
+			before: FeatureState{
+				FeatureID:         "grid",
+				BaselineStatus:    "",
+				AvailableBrowsers: nil,
+				StableWPTScores:   map[string]float64{"chrome": 0.8, "firefox": 0.95},
+			},
+			after: FeatureState{
+				FeatureID:         "grid",
+				BaselineStatus:    "",
+				AvailableBrowsers: nil,
+				StableWPTScores:   map[string]float64{"chrome": 0.9, "firefox": 0.85, "safari": 0.92},
+			},
+			expected: []Change{
+				{
+					FeatureID:      "grid",
+					Type:           WPTThresholdCrossed,
+					Browser:        "chrome",
+					PreviousStatus: "",
+					Status:         "",
+					PreviousScore:  valuePtr(0.8),
+					Score:          valuePtr(0.9),
+					Threshold:      valuePtr(0.9),
+				},
+				{
+					FeatureID:      "grid",
+					Type:           WPTThresholdCrossed,
+					Browser:        "firefox",
+					PreviousStatus: "",
+					Status:         "",
+					PreviousScore:  valuePtr(0.95),
+					Score:          valuePtr(0.85),
+					Threshold:      valuePtr(0.9),
+				},
+				{
+					FeatureID:      "grid",
+					Type:           WPTThresholdCrossed,
+					Browser:        "safari",
+					PreviousStatus: "",
+					Status:         "",
+					PreviousScore:  valuePtr(0.0),
+					Score:          valuePtr(0.92),
+					Threshold:      valuePtr(0.9),
+				},
+			},
+		},
+		{
+			name: "new feature",
+			// nolint: exhaustruct // The feature did not exist before.
+			before: FeatureState{},
+			after: FeatureState{
+				FeatureID:         "grid",
+				BaselineStatus:    "limited",
+				AvailableBrowsers: []string{"chrome"},
+				StableWPTScores:   nil,
+			},
+			expected: []Change{
+				{
+					FeatureID:      "grid",
+					Type:           BaselineStatusChanged,
+					Browser:        "",
+					PreviousStatus: "",
+					Status:         "limited",
+					PreviousScore:  nil,
+					Score:          nil,
+					Threshold:      nil,
+				},
+				{
+					FeatureID:      "grid",
+					Type:           BrowserAvailable,
+					Browser:        "chrome",
+					PreviousStatus: "",
+					Status:         "",
+					PreviousScore:  nil,
+					Score:          nil,
+					Threshold:      nil,
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			changes := DetectChanges(tc.before, tc.after, 0.9)
+			if !reflect.DeepEqual(tc.expected, changes) {
+				t.Errorf("unexpected changes.\nexpected %+v\nreceived %+v", tc.expected, changes)
+			}
+		})
+	}
+}
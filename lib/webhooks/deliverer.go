@@ -0,0 +1,148 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Payload is the JSON body sent to the callback URL of a subscription.
+type Payload struct {
+	SubscriptionID string    `json:"subscription_id"`
+	SentAt         time.Time `json:"sent_at"`
+	Changes        []Change  `json:"changes"`
+}
+
+var (
+	// ErrDeliveryFailed indicates that the callback did not accept the payload after every attempt.
+	ErrDeliveryFailed = errors.New("webhook delivery failed")
+	// errRetryable marks attempts that may succeed if tried again.
+	errRetryable = errors.New("retryable webhook response")
+)
+
+const (
+	// DefaultMaxAttempts is the number of attempts made for a payload.
+	DefaultMaxAttempts = 5
+	// DefaultInitialBackoff is the wait before the second attempt. It doubles after every attempt.
+	DefaultInitialBackoff = 2 * time.Second
+	// Bodies of responses are only read to allow the connection to be reused.
+	maxResponseBodyBytes = 4096
+)
+
+// Deliverer sends signed payloads to callback URLs.
+type Deliverer struct {
+	client         *http.Client
+	maxAttempts    int
+	initialBackoff time.Duration
+	now            func() time.Time
+	sleep          func(ctx context.Context, d time.Duration) error
+}
+
+// NewDeliverer constructs a Deliverer. The client should have a timeout.
+func NewDeliverer(client *http.Client, maxAttempts int, initialBackoff time.Duration) *Deliverer {
+	return &Deliverer{
+		client:         client,
+		maxAttempts:    max(maxAttempts, 1),
+		initialBackoff: initialBackoff,
+		now:            time.Now,
+		sleep:          sleepContext,
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Deliver posts the payload to the callback URL.
+// Network errors, 429 and 5xx responses are retried with exponential backoff.
+// Other responses outside of 2xx fail immediately because retrying them would not help.
+func (d *Deliverer) Deliver(ctx context.Context, callbackURL, secret string, payload Payload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Join(ErrDeliveryFailed, err)
+	}
+
+	backoff := d.initialBackoff
+	var lastErr error
+	for attempt := 1; attempt <= d.maxAttempts; attempt++ {
+		if attempt > 1 {
+			if err := d.sleep(ctx, backoff); err != nil {
+				return errors.Join(ErrDeliveryFailed, lastErr, err)
+			}
+			backoff *= 2
+		}
+		lastErr = d.attempt(ctx, callbackURL, secret, payload.SubscriptionID, body)
+		if lastErr == nil {
+			return nil
+		}
+		if !errors.Is(lastErr, errRetryable) {
+			break
+		}
+		slog.WarnContext(ctx, "webhook delivery attempt failed",
+			"subscription", payload.SubscriptionID, "attempt", attempt, "error", lastErr)
+	}
+
+	return errors.Join(ErrDeliveryFailed, lastErr)
+}
+
+func (d *Deliverer) attempt(ctx context.Context, callbackURL, secret, subscriptionID string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, callbackURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	// Each attempt is signed again so that the timestamp stays fresh.
+	timestamp := strconv.FormatInt(d.now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "webstatus.dev-webhooks")
+	req.Header.Set(SubscriptionHeader, subscriptionID)
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+
+		return errors.Join(errRetryable, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBodyBytes))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("%w: status %d", errRetryable, resp.StatusCode)
+	default:
+		return fmt.Errorf("callback rejected payload with status %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testReceiver is a webhook receiver that replies with the given status codes in order.
+// The last status code is repeated.
+type testReceiver struct {
+	t        *testing.T
+	secret   string
+	statuses []int
+	mu       sync.Mutex
+	payloads []Payload
+}
+
+func (r *testReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		r.t.Errorf("unable to read body %v", err)
+	}
+	err = Verify(r.secret, req.Header.Get(SignatureHeader), req.Header.Get(TimestampHeader), body,
+		time.Now(), time.Minute)
+	if err != nil {
+		r.t.Errorf("unexpected signature error %v", err)
+	}
+	var payload Payload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		r.t.Errorf("unable to decode payload %v", err)
+	}
+	if req.Header.Get(SubscriptionHeader) != payload.SubscriptionID {
+		r.t.Errorf("unexpected subscription header %s", req.Header.Get(SubscriptionHeader))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.payloads = append(r.payloads, payload)
+	status := r.statuses[min(len(r.payloads), len(r.statuses))-1]
+	w.WriteHeader(status)
+}
+
+func (r *testReceiver) received() []Payload {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.payloads
+}
+
+func newTestDeliverer(maxAttempts int) (*Deliverer, *[]time.Duration) {
+	var sleeps []time.Duration
+	deliverer := NewDeliverer(http.DefaultClient, maxAttempts, time.Second)
+	deliverer.sleep = func(_ context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+
+		return nil
+	}
+
+	return deliverer, &sleeps
+}
+
+func TestDeliver(t *testing.T) {
+	payload := Payload{
+		SubscriptionID: "sub1",
+		SentAt:         time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+		Changes: []Change{
+			{
+				FeatureID:      "grid",
+				Type:           BrowserAvailable,
+				Browser:        "safari",
+				PreviousStatus: "",
+				Status:         "",
+				PreviousScore:  nil,
+				Score:          nil,
+				Threshold:      nil,
+			},
+		},
+	}
+	testCases := []struct {
+		name             string
+		statuses         []int
+		expectedAttempts int
+		expectedSleeps   []time.Duration
+		expectedError    error
+	}{
+		{
+			name:             "success",
+			statuses:         []int{http.StatusNoContent},
+			expectedAttempts: 1,
+			expectedSleeps:   nil,
+			expectedError:    nil,
+		},
+		{
+			name:             "retries server errors and rate limits",
+			statuses:         []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK},
+			expectedAttempts: 3,
+			expectedSleeps:   []time.Duration{time.Second, 2 * time.Second},
+			expectedError:    nil,
+		},
+		{
+			name:             "gives up after max attempts",
+			statuses:         []int{http.StatusInternalServerError},
+			expectedAttempts: 3,
+			expectedSleeps:   []time.Duration{time.Second, 2 * time.Second},
+			expectedError:    ErrDeliveryFailed,
+		},
+		{
+			name:             "client errors are not retried",
+			statuses:         []int{http.StatusGone},
+			expectedAttempts: 1,
+			expectedSleeps:   nil,
+			expectedError:    ErrDeliveryFailed,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// nolint: exhaustruct // mu and payloads start empty.
+			receiver := &testReceiver{t: t, secret: "secret", statuses: tc.statuses}
+			srv := httptest.NewServer(receiver)
+			defer srv.Close()
+
+			deliverer, sleeps := newTestDeliverer(3)
+			err := deliverer.Deliver(context.Background(), srv.URL, "secret", payload)
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("expected error %v, received %v", tc.expectedError, err)
+			}
+			received := receiver.received()
+			if len(received) != tc.expectedAttempts {
+				t.Errorf("expected %d attempts, received %d", tc.expectedAttempts, len(received))
+			}
+			if !reflect.DeepEqual(*sleeps, tc.expectedSleeps) {
+				t.Errorf("unexpected sleeps %v", *sleeps)
+			}
+			for _, p := range received {
+				if !reflect.DeepEqual(p, payload) {
+					t.Errorf("unexpected payload %+v", p)
+				}
+			}
+		})
+	}
+}
+
+func TestDeliverRetriesNetworkErrors(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	callbackURL := srv.URL
+	srv.Close()
+
+	deliverer, sleeps := newTestDeliverer(2)
+	// nolint: exhaustruct // No changes are needed.
+	err := deliverer.Deliver(context.Background(), callbackURL, "secret", Payload{SubscriptionID: "sub1"})
+	if !errors.Is(err, ErrDeliveryFailed) {
+		t.Errorf("expected delivery failure, received %v", err)
+	}
+	if len(*sleeps) != 1 {
+		t.Errorf("expected one retry, received %d", len(*sleeps))
+	}
+}
@@ -0,0 +1,247 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// DefaultMaxConcurrentDeliveries limits the number of callbacks that are notified at the same time.
+	DefaultMaxConcurrentDeliveries = 16
+	// DefaultDeliveryDeadline limits how long the notifications of an ingestion can take, retries included.
+	DefaultDeliveryDeadline = 2 * time.Minute
+	// maxSaveAttempts limits how many times a run starts over after an overlapping run saved the feature states.
+	maxSaveAttempts = 3
+)
+
+// Subscription selects the features to notify a callback URL about.
+// Either Query or FeatureIDs is set.
+type Subscription struct {
+	ID           string
+	Query        *string
+	FeatureIDs   []string
+	CallbackURL  string
+	Secret       string
+	WPTThreshold *float64
+}
+
+// ErrNoFeatureStates indicates that the feature states have never been saved.
+var ErrNoFeatureStates = errors.New("no saved feature states")
+
+// ErrFeatureStatesChanged indicates that the feature states were saved again since they were read.
+var ErrFeatureStatesChanged = errors.New("feature states changed since they were read")
+
+// FeatureStateGetter returns the current state of the features after ingestion.
+type FeatureStateGetter interface {
+	ListFeatureStates(ctx context.Context) ([]FeatureState, error)
+	// MatchFeatureIDs returns the IDs of the features that currently match the search query.
+	MatchFeatureIDs(ctx context.Context, query string) ([]string, error)
+}
+
+// SubscriptionStore contains the subscriptions and the feature states that were last notified.
+type SubscriptionStore interface {
+	ListAllSubscriptions(ctx context.Context) ([]Subscription, error)
+	// GetFeatureStates returns the saved states along with their version.
+	// It returns ErrNoFeatureStates if SaveFeatureStates was never called.
+	GetFeatureStates(ctx context.Context) ([]FeatureState, int64, error)
+	// SaveFeatureStates returns ErrFeatureStatesChanged if the saved version is no longer the given version.
+	// The version of states that were never saved is 0.
+	SaveFeatureStates(ctx context.Context, states []FeatureState, version int64) error
+}
+
+// Notifier detects the changes to the features since it last ran and notifies the matching subscriptions.
+type Notifier struct {
+	features                FeatureStateGetter
+	subscriptions           SubscriptionStore
+	deliverer               *Deliverer
+	maxConcurrentDeliveries int
+	deliveryDeadline        time.Duration
+	now                     func() time.Time
+}
+
+// NewNotifier constructs a Notifier.
+func NewNotifier(
+	features FeatureStateGetter,
+	subscriptions SubscriptionStore,
+	deliverer *Deliverer,
+	maxConcurrentDeliveries int,
+	deliveryDeadline time.Duration,
+) *Notifier {
+	return &Notifier{
+		features:                features,
+		subscriptions:           subscriptions,
+		deliverer:               deliverer,
+		maxConcurrentDeliveries: max(maxConcurrentDeliveries, 1),
+		deliveryDeadline:        deliveryDeadline,
+		now:                     time.Now,
+	}
+}
+
+// NotifyFeatureChanges should be called after every successful ingestion.
+// The first call only saves the current state because there is nothing to compare it against.
+// The consumers of every data source share the saved state and their runs may overlap. The new state is saved
+// before the subscriptions are notified and only if no other run saved it since it was read. The run that
+// loses starts over from the state that the other run saved, so every change is only notified once.
+// Failed deliveries are not retried on the next call.
+// The subscriptions are notified concurrently and the deliveries that are still running after the
+// delivery deadline are cancelled.
+func (n *Notifier) NotifyFeatureChanges(ctx context.Context) error {
+	for attempt := 1; ; attempt++ {
+		err := n.notifyFeatureChanges(ctx)
+		if !errors.Is(err, ErrFeatureStatesChanged) || attempt >= maxSaveAttempts {
+			return err
+		}
+		slog.InfoContext(ctx, "feature states were saved by another run. starting over", "attempt", attempt)
+	}
+}
+
+func (n *Notifier) notifyFeatureChanges(ctx context.Context) error {
+	after, err := n.features.ListFeatureStates(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to list feature states: %w", err)
+	}
+	before, version, err := n.subscriptions.GetFeatureStates(ctx)
+	if errors.Is(err, ErrNoFeatureStates) {
+		slog.InfoContext(ctx, "saving initial feature states", "count", len(after))
+
+		return n.subscriptions.SaveFeatureStates(ctx, after, 0)
+	} else if err != nil {
+		return fmt.Errorf("unable to get previous feature states: %w", err)
+	}
+
+	previous := make(map[string]FeatureState, len(before))
+	for _, state := range before {
+		previous[state.FeatureID] = state
+	}
+	current := make(map[string]FeatureState, len(after))
+	var changedFeatureIDs []string
+	for _, state := range after {
+		current[state.FeatureID] = state
+		if stateChanged(previous[state.FeatureID], state) {
+			changedFeatureIDs = append(changedFeatureIDs, state.FeatureID)
+		}
+	}
+
+	var subscriptions []Subscription
+	if len(changedFeatureIDs) > 0 {
+		subscriptions, err = n.subscriptions.ListAllSubscriptions(ctx)
+		if err != nil {
+			// Keep the previous state so that the next run detects the same changes.
+			return fmt.Errorf("unable to list subscriptions: %w", err)
+		}
+	}
+
+	// Saving the state claims the changes. An overlapping run that read the same state cannot save it anymore.
+	if err := n.subscriptions.SaveFeatureStates(ctx, after, version); err != nil {
+		return fmt.Errorf("unable to save feature states: %w", err)
+	}
+	if len(changedFeatureIDs) == 0 {
+		return nil
+	}
+	slog.InfoContext(ctx, "notifying subscriptions",
+		"changed_features", len(changedFeatureIDs), "subscriptions", len(subscriptions))
+
+	return errors.Join(n.notifyAll(ctx, subscriptions, changedFeatureIDs, previous, current)...)
+}
+
+// stateChanged ignores the thresholds of the subscriptions. Any score change may cross one of them.
+func stateChanged(before, after FeatureState) bool {
+	return before.BaselineStatus != after.BaselineStatus ||
+		!slices.Equal(before.AvailableBrowsers, after.AvailableBrowsers) ||
+		!maps.Equal(before.StableWPTScores, after.StableWPTScores)
+}
+
+func (n *Notifier) notifyAll(
+	ctx context.Context,
+	subscriptions []Subscription,
+	changedFeatureIDs []string,
+	previous, current map[string]FeatureState,
+) []error {
+	deliveryCtx, cancel := context.WithTimeout(ctx, n.deliveryDeadline)
+	defer cancel()
+
+	var mu sync.Mutex
+	var errs []error
+	// The errors are collected instead of returned so that one failure does not cancel the other deliveries.
+	var g errgroup.Group
+	g.SetLimit(n.maxConcurrentDeliveries)
+	for _, subscription := range subscriptions {
+		g.Go(func() error {
+			err := n.notify(deliveryCtx, subscription, changedFeatureIDs, previous, current)
+			if err != nil {
+				slog.ErrorContext(ctx, "unable to notify subscription", "subscription", subscription.ID, "error", err)
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	return errs
+}
+
+func (n *Notifier) notify(
+	ctx context.Context,
+	subscription Subscription,
+	changedFeatureIDs []string,
+	previous, current map[string]FeatureState,
+) error {
+	featureIDs := subscription.FeatureIDs
+	if subscription.Query != nil {
+		var err error
+		featureIDs, err = n.features.MatchFeatureIDs(ctx, *subscription.Query)
+		if err != nil {
+			return fmt.Errorf("unable to match features: %w", err)
+		}
+	}
+	matched := make(map[string]bool, len(featureIDs))
+	for _, featureID := range featureIDs {
+		matched[featureID] = true
+	}
+
+	threshold := DefaultWPTThreshold
+	if subscription.WPTThreshold != nil {
+		threshold = *subscription.WPTThreshold
+	}
+	var changes []Change
+	for _, featureID := range changedFeatureIDs {
+		if !matched[featureID] {
+			continue
+		}
+		changes = append(changes, DetectChanges(previous[featureID], current[featureID], threshold)...)
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	return n.deliverer.Deliver(ctx, subscription.CallbackURL, subscription.Secret, Payload{
+		SubscriptionID: subscription.ID,
+		SentAt:         n.now().UTC(),
+		Changes:        changes,
+	})
+}
@@ -0,0 +1,336 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type fakeFeatureStateGetter struct {
+	states  []FeatureState
+	matches map[string][]string
+}
+
+func (f *fakeFeatureStateGetter) ListFeatureStates(_ context.Context) ([]FeatureState, error) {
+	return f.states, nil
+}
+
+func (f *fakeFeatureStateGetter) MatchFeatureIDs(_ context.Context, query string) ([]string, error) {
+	return f.matches[query], nil
+}
+
+type fakeSubscriptionStore struct {
+	subscriptions []Subscription
+	states        []FeatureState
+	version       int64
+	listErr       error
+	// beforeSave runs before the version is checked. It lets a test save the states from an overlapping run.
+	beforeSave func()
+}
+
+func (f *fakeSubscriptionStore) ListAllSubscriptions(_ context.Context) ([]Subscription, error) {
+	return f.subscriptions, f.listErr
+}
+
+func (f *fakeSubscriptionStore) GetFeatureStates(_ context.Context) ([]FeatureState, int64, error) {
+	if f.states == nil {
+		return nil, 0, ErrNoFeatureStates
+	}
+
+	return f.states, f.version, nil
+}
+
+func (f *fakeSubscriptionStore) SaveFeatureStates(_ context.Context, states []FeatureState, version int64) error {
+	if beforeSave := f.beforeSave; beforeSave != nil {
+		// Only the first save overlaps with another run.
+		f.beforeSave = nil
+		beforeSave()
+	}
+	if version != f.version {
+		return ErrFeatureStatesChanged
+	}
+	f.states = states
+	f.version++
+
+	return nil
+}
+
+func TestNotifyFeatureChanges(t *testing.T) {
+	// nolint: exhaustruct // mu and payloads start empty.
+	receiver := &testReceiver{t: t, secret: "secret", statuses: []int{http.StatusOK}}
+	srv := httptest.NewServer(receiver)
+	defer srv.Close()
+
+	initial := []FeatureState{
+		{FeatureID: "grid", BaselineStatus: "widely", AvailableBrowsers: []string{"chrome"}, StableWPTScores: nil},
+		{FeatureID: "has", BaselineStatus: "limited", AvailableBrowsers: []string{"chrome"}, StableWPTScores: nil},
+		{FeatureID: "popover", BaselineStatus: "limited", AvailableBrowsers: nil, StableWPTScores: nil},
+	}
+	features := &fakeFeatureStateGetter{
+		states:  initial,
+		matches: map[string][]string{"baseline_status:limited": {"has", "popover"}},
+	}
+	query := "baseline_status:limited"
+	// nolint: exhaustruct // Nothing is saved yet and listErr is not needed.
+	store := &fakeSubscriptionStore{
+		subscriptions: []Subscription{
+			{
+				ID:           "by-query",
+				Query:        &query,
+				FeatureIDs:   nil,
+				CallbackURL:  srv.URL,
+				Secret:       "secret",
+				WPTThreshold: nil,
+			},
+			{
+				ID:           "by-feature",
+				Query:        nil,
+				FeatureIDs:   []string{"grid"},
+				CallbackURL:  srv.URL,
+				Secret:       "secret",
+				WPTThreshold: nil,
+			},
+		},
+	}
+	deliverer, _ := newTestDeliverer(1)
+	notifier := NewNotifier(features, store, deliverer, 2, time.Minute)
+
+	// The first run only saves the states.
+	if err := notifier.NotifyFeatureChanges(context.Background()); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(receiver.received()) != 0 {
+		t.Errorf("expected no deliveries on the first run, received %d", len(receiver.received()))
+	}
+	if !reflect.DeepEqual(store.states, initial) {
+		t.Errorf("expected the initial states to be saved. received %+v", store.states)
+	}
+
+	// Only "has" changes. It is matched by the query but not by the feature list.
+	features.states = []FeatureState{
+		initial[0],
+		{FeatureID: "has", BaselineStatus: "limited", AvailableBrowsers: []string{"chrome", "firefox"},
+			StableWPTScores: nil},
+		initial[2],
+	}
+	if err := notifier.NotifyFeatureChanges(context.Background()); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	received := receiver.received()
+	if len(received) != 1 {
+		t.Fatalf("expected one delivery, received %d", len(received))
+	}
+	expectedChanges := []Change{
+		{
+			FeatureID:      "has",
+			Type:           BrowserAvailable,
+			Browser:        "firefox",
+			PreviousStatus: "",
+			Status:         "",
+			PreviousScore:  nil,
+			Score:          nil,
+			Threshold:      nil,
+		},
+	}
+	if received[0].SubscriptionID != "by-query" || !reflect.DeepEqual(received[0].Changes, expectedChanges) {
+		t.Errorf("unexpected payload %+v", received[0])
+	}
+	if !reflect.DeepEqual(store.states, features.states) {
+		t.Errorf("expected the new states to be saved. received %+v", store.states)
+	}
+
+	// Nothing changed since the last run.
+	if err := notifier.NotifyFeatureChanges(context.Background()); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(receiver.received()) != 1 {
+		t.Errorf("expected no new deliveries, received %d", len(receiver.received()))
+	}
+}
+
+func TestNotifyFeatureChangesKeepsStatesOnListError(t *testing.T) {
+	before := []FeatureState{
+		{FeatureID: "grid", BaselineStatus: "limited", AvailableBrowsers: nil, StableWPTScores: nil},
+	}
+	features := &fakeFeatureStateGetter{
+		states: []FeatureState{
+			{FeatureID: "grid", BaselineStatus: "newly", AvailableBrowsers: nil, StableWPTScores: nil},
+		},
+		matches: nil,
+	}
+	errTest := errors.New("test error")
+	store := &fakeSubscriptionStore{subscriptions: nil, states: before, version: 1, listErr: errTest, beforeSave: nil}
+	deliverer, _ := newTestDeliverer(1)
+
+	err := NewNotifier(features, store, deliverer, 2, time.Minute).NotifyFeatureChanges(context.Background())
+	if !errors.Is(err, errTest) {
+		t.Errorf("expected list error, received %v", err)
+	}
+	if !reflect.DeepEqual(store.states, before) {
+		t.Errorf("expected the previous states to be kept. received %+v", store.states)
+	}
+}
+
+func TestNotifyFeatureChangesSlowCallback(t *testing.T) {
+	// The slow callback does not answer before the end of the test.
+	// The deliveries that are still running after the deadline are cancelled.
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		<-release
+	}))
+	defer slow.Close()
+	defer close(release)
+	// nolint: exhaustruct // mu and payloads start empty.
+	receiver := &testReceiver{t: t, secret: "secret", statuses: []int{http.StatusOK}}
+	fast := httptest.NewServer(receiver)
+	defer fast.Close()
+
+	before := []FeatureState{
+		{FeatureID: "grid", BaselineStatus: "limited", AvailableBrowsers: nil, StableWPTScores: nil},
+	}
+	features := &fakeFeatureStateGetter{
+		states: []FeatureState{
+			{FeatureID: "grid", BaselineStatus: "newly", AvailableBrowsers: nil, StableWPTScores: nil},
+		},
+		matches: nil,
+	}
+	store := &fakeSubscriptionStore{
+		subscriptions: []Subscription{
+			{
+				ID:           "slow",
+				Query:        nil,
+				FeatureIDs:   []string{"grid"},
+				CallbackURL:  slow.URL,
+				Secret:       "secret",
+				WPTThreshold: nil,
+			},
+			{
+				ID:           "fast",
+				Query:        nil,
+				FeatureIDs:   []string{"grid"},
+				CallbackURL:  fast.URL,
+				Secret:       "secret",
+				WPTThreshold: nil,
+			},
+		},
+		states:     before,
+		version:    1,
+		listErr:    nil,
+		beforeSave: nil,
+	}
+	deliverer, _ := newTestDeliverer(1)
+
+	err := NewNotifier(features, store, deliverer, 2, 500*time.Millisecond).NotifyFeatureChanges(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the slow delivery to time out, received %v", err)
+	}
+	received := receiver.received()
+	if len(received) != 1 || received[0].SubscriptionID != "fast" {
+		t.Errorf("expected the fast subscription to be notified. received %+v", received)
+	}
+	if !reflect.DeepEqual(store.states, features.states) {
+		t.Errorf("expected the new states to be saved. received %+v", store.states)
+	}
+}
+
+func TestNotifyFeatureChangesOverlappingRuns(t *testing.T) {
+	// nolint: exhaustruct // mu and payloads start empty.
+	receiver := &testReceiver{t: t, secret: "secret", statuses: []int{http.StatusOK}}
+	srv := httptest.NewServer(receiver)
+	defer srv.Close()
+
+	before := []FeatureState{
+		{FeatureID: "grid", BaselineStatus: "limited", AvailableBrowsers: nil, StableWPTScores: nil},
+	}
+	features := &fakeFeatureStateGetter{
+		states: []FeatureState{
+			{FeatureID: "grid", BaselineStatus: "newly", AvailableBrowsers: nil, StableWPTScores: nil},
+		},
+		matches: nil,
+	}
+	store := &fakeSubscriptionStore{
+		subscriptions: []Subscription{
+			{
+				ID:           "by-feature",
+				Query:        nil,
+				FeatureIDs:   []string{"grid"},
+				CallbackURL:  srv.URL,
+				Secret:       "secret",
+				WPTThreshold: nil,
+			},
+		},
+		states:     before,
+		version:    1,
+		listErr:    nil,
+		beforeSave: nil,
+	}
+	deliverer, _ := newTestDeliverer(1)
+	notifier := NewNotifier(features, store, deliverer, 2, time.Minute)
+
+	// Both runs read the same states. The second run saves them first, e.g. because the other consumer
+	// finished its ingestion at the same time.
+	var overlappingErr error
+	store.beforeSave = func() {
+		overlappingErr = notifier.NotifyFeatureChanges(context.Background())
+	}
+	if err := notifier.NotifyFeatureChanges(context.Background()); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if overlappingErr != nil {
+		t.Fatalf("unexpected error from the overlapping run %v", overlappingErr)
+	}
+
+	// The change is only notified by the run that saved the states first.
+	received := receiver.received()
+	if len(received) != 1 {
+		t.Errorf("expected one delivery, received %d", len(received))
+	}
+	// The first run started over from the saved states and found nothing else to save.
+	if !reflect.DeepEqual(store.states, features.states) || store.version != 3 {
+		t.Errorf("expected the new states to be saved. received %+v version %d", store.states, store.version)
+	}
+}
+
+func TestNotifyFeatureChangesGivesUpAfterOverlappingSaves(t *testing.T) {
+	before := []FeatureState{
+		{FeatureID: "grid", BaselineStatus: "limited", AvailableBrowsers: nil, StableWPTScores: nil},
+	}
+	features := &fakeFeatureStateGetter{states: before, matches: nil}
+	store := &fakeSubscriptionStore{subscriptions: nil, states: before, version: 1, listErr: nil, beforeSave: nil}
+	// Every save is preceded by the save of another run.
+	saves := 0
+	var overlap func()
+	overlap = func() {
+		saves++
+		store.version++
+		store.beforeSave = overlap
+	}
+	store.beforeSave = overlap
+	deliverer, _ := newTestDeliverer(1)
+
+	err := NewNotifier(features, store, deliverer, 2, time.Minute).NotifyFeatureChanges(context.Background())
+	if !errors.Is(err, ErrFeatureStatesChanged) {
+		t.Errorf("expected feature states changed error, received %v", err)
+	}
+	if saves != maxSaveAttempts {
+		t.Errorf("expected %d attempts, received %d", maxSaveAttempts, saves)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// SignatureHeader contains the HMAC-SHA256 signature of the payload in the form "sha256=<hex>".
+	SignatureHeader = "X-Webstatus-Signature"
+	// TimestampHeader contains the unix time in seconds that the payload was signed at.
+	TimestampHeader = "X-Webstatus-Timestamp"
+	// SubscriptionHeader contains the ID of the subscription that the payload was sent for.
+	SubscriptionHeader = "X-Webstatus-Subscription"
+
+	signaturePrefix = "sha256="
+)
+
+// ErrInvalidSignature indicates that the signature does not match the payload or is too old.
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Sign returns the value of SignatureHeader for the body.
+// The timestamp is part of the signed content so that receivers can reject replayed payloads.
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature and timestamp headers of a payload.
+// Payloads signed more than tolerance away from now are rejected.
+func Verify(secret, signature, timestamp string, body []byte, now time.Time, tolerance time.Duration) error {
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return ErrInvalidSignature
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.Join(ErrInvalidSignature, err)
+	}
+	if now.Sub(time.Unix(seconds, 0)).Abs() > tolerance {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
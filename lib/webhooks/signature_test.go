@@ -0,0 +1,92 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"subscription_id":"sub1"}`)
+	signature := Sign("secret", "1700000000", body)
+	testCases := []struct {
+		name          string
+		secret        string
+		signature     string
+		timestamp     string
+		body          []byte
+		expectedError error
+	}{
+		{
+			name:          "valid",
+			secret:        "secret",
+			signature:     signature,
+			timestamp:     "1700000000",
+			body:          body,
+			expectedError: nil,
+		},
+		{
+			name:          "wrong secret",
+			secret:        "other",
+			signature:     signature,
+			timestamp:     "1700000000",
+			body:          body,
+			expectedError: ErrInvalidSignature,
+		},
+		{
+			name:          "modified body",
+			secret:        "secret",
+			signature:     signature,
+			timestamp:     "1700000000",
+			body:          []byte(`{"subscription_id":"sub2"}`),
+			expectedError: ErrInvalidSignature,
+		},
+		{
+			name:          "modified timestamp",
+			secret:        "secret",
+			signature:     signature,
+			timestamp:     "1700000001",
+			body:          body,
+			expectedError: ErrInvalidSignature,
+		},
+		{
+			name:          "too old",
+			secret:        "secret",
+			signature:     Sign("secret", "1699990000", body),
+			timestamp:     "1699990000",
+			body:          body,
+			expectedError: ErrInvalidSignature,
+		},
+		{
+			name:          "missing prefix",
+			secret:        "secret",
+			signature:     signature[len(signaturePrefix):],
+			timestamp:     "1700000000",
+			body:          body,
+			expectedError: ErrInvalidSignature,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Verify(tc.secret, tc.signature, tc.timestamp, tc.body, now, 5*time.Minute)
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("expected error %v, received %v", tc.expectedError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command dev_token signs user tokens for the local backend with the development key.
+//
+// Usage:
+//
+//	dev_token [--subject dev-user] [--roles admin] [--ttl 1h]
+//	dev_token --jwks
+//
+// The backend in the local cluster trusts the key through backend/manifests/auth-jwks.yaml.
+// Run with --jwks to print the key set after replacing the key.
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+// devKeyID is the key ID in the local key set.
+const devKeyID = "dev"
+
+func loadKey(path string) (*ecdsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no pem block found")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	ecKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("key is not an ecdsa key")
+	}
+
+	return ecKey, nil
+}
+
+func encodeSegment(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// signES256 creates a JSON Web Token signed with ES256. See RFC 7518 section 3.4.
+func signES256(key *ecdsa.PrivateKey, claims map[string]any) (string, error) {
+	header, err := encodeSegment(map[string]string{"alg": "ES256", "typ": "JWT", "kid": devKeyID})
+	if err != nil {
+		return "", err
+	}
+	payload, err := encodeSegment(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := header + "." + payload
+	digest := sha256.Sum256([]byte(signingInput))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		return "", err
+	}
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func jwks(key *ecdsa.PrivateKey) ([]byte, error) {
+	x := make([]byte, 32)
+	y := make([]byte, 32)
+	key.X.FillBytes(x)
+	key.Y.FillBytes(y)
+
+	return json.MarshalIndent(map[string]any{
+		"keys": []map[string]string{
+			{
+				"kty": "EC",
+				"kid": devKeyID,
+				"use": "sig",
+				"alg": "ES256",
+				"crv": "P-256",
+				"x":   base64.RawURLEncoding.EncodeToString(x),
+				"y":   base64.RawURLEncoding.EncodeToString(y),
+			},
+		},
+	}, "", "  ")
+}
+
+func main() {
+	var (
+		keyFile   = flag.String("key_file", ".dev/auth/dev-private-key.pem", "PKCS #8 ECDSA P-256 private key")
+		subject   = flag.String("subject", "dev-user", "User ID of the token")
+		email     = flag.String("email", "dev-user@example.com", "Email of the user")
+		roles     = flag.String("roles", "", "Comma-separated roles of the user")
+		issuer    = flag.String("issuer", "webstatus-local", "Issuer of the token. Must match AUTH_ISSUER")
+		audience  = flag.String("audience", "webstatus-local", "Audience of the token. Must match AUTH_AUDIENCE")
+		ttl       = flag.Duration("ttl", time.Hour, "How long the token is valid")
+		printJWKS = flag.Bool("jwks", false, "Print the public key set instead of a token")
+	)
+	flag.Parse()
+
+	key, err := loadKey(*keyFile)
+	if err != nil {
+		slog.Error("unable to load key", "path", *keyFile, "error", err)
+		os.Exit(1)
+	}
+
+	if *printJWKS {
+		data, err := jwks(key)
+		if err != nil {
+			slog.Error("unable to encode key set", "error", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+
+		return
+	}
+
+	now := time.Now()
+	claims := map[string]any{
+		"iss":   *issuer,
+		"aud":   *audience,
+		"sub":   *subject,
+		"email": *email,
+		"iat":   now.Unix(),
+		"exp":   now.Add(*ttl).Unix(),
+		"roles": []string{},
+	}
+	if *roles != "" {
+		claims["roles"] = strings.Split(*roles, ",")
+	}
+	token, err := signES256(key, claims)
+	if err != nil {
+		slog.Error("unable to sign token", "error", err)
+		os.Exit(1)
+	}
+	fmt.Println(token)
+}
@@ -0,0 +1,418 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GoogleChrome/webstatus.dev/lib/backendclient"
+	"github.com/GoogleChrome/webstatus.dev/lib/gcpspanner/searchtypes"
+	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/backend"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// The maximum page size of the API. The iterators request pages until every item is read.
+const pageSize = 100
+
+// nolint: gochecknoglobals // WONTFIX. Read only list of the browsers of the API.
+var browsers = []string{
+	string(backend.Chrome),
+	string(backend.Edge),
+	string(backend.Firefox),
+	string(backend.Safari),
+}
+
+// commonFlags are the flags shared by every command.
+type commonFlags struct {
+	apiHost string
+	format  string
+}
+
+func newFlagSet(name string, usage string) (*flag.FlagSet, *commonFlags) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: webstatus %s\n", usage)
+		fs.PrintDefaults()
+	}
+	common := new(commonFlags)
+	fs.StringVar(&common.apiHost, "api_host", defaultAPIHost, "webstatus.dev API host")
+	fs.StringVar(&common.format, "format", formatTable, "Output format: table, json or csv")
+
+	return fs, common
+}
+
+// parseArgs parses the flags of a command and returns its positional arguments.
+// Unlike flag.Parse, flags may also come after the positional arguments. e.g. search QUERY --format json.
+func parseArgs(fs *flag.FlagSet, common *commonFlags, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+
+			return nil, errUsage
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if err := validateFormat(common.format); err != nil {
+		return nil, usageError(fs, err)
+	}
+
+	return positional, nil
+}
+
+// usageError prints err and the usage of the command.
+func usageError(fs *flag.FlagSet, err error) error {
+	fmt.Fprintln(fs.Output(), err)
+	fs.Usage()
+
+	return errUsage
+}
+
+// dateFlags are the flags of the commands that return metrics over time.
+type dateFlags struct {
+	since string
+	until string
+}
+
+func addDateFlags(fs *flag.FlagSet) *dateFlags {
+	dates := new(dateFlags)
+	fs.StringVar(&dates.since, "since", "", "Start date (YYYY-MM-DD), inclusive. Defaults to one year ago")
+	fs.StringVar(&dates.until, "until", "", "End date (YYYY-MM-DD), exclusive. Defaults to tomorrow")
+
+	return dates
+}
+
+// parse returns the start and end dates of the flags. now is used for the defaults.
+func (d dateFlags) parse(now time.Time) (openapi_types.Date, openapi_types.Date, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	startAt := today.AddDate(-1, 0, 0)
+	endAt := today.AddDate(0, 0, 1)
+	var err error
+	if d.since != "" {
+		if startAt, err = time.Parse(time.DateOnly, d.since); err != nil {
+			return openapi_types.Date{}, openapi_types.Date{}, fmt.Errorf("invalid --since date %q", d.since)
+		}
+	}
+	if d.until != "" {
+		if endAt, err = time.Parse(time.DateOnly, d.until); err != nil {
+			return openapi_types.Date{}, openapi_types.Date{}, fmt.Errorf("invalid --until date %q", d.until)
+		}
+	}
+	if !startAt.Before(endAt) {
+		return openapi_types.Date{}, openapi_types.Date{}, errors.New("--since must be before --until")
+	}
+
+	return openapi_types.Date{Time: startAt}, openapi_types.Date{Time: endAt}, nil
+}
+
+// parseBrowsers parses a comma separated list of browsers.
+func parseBrowsers(in string) ([]string, error) {
+	if in == "" {
+		return nil, nil
+	}
+	list := strings.Split(in, ",")
+	for _, browser := range list {
+		if !slices.Contains(browsers, browser) {
+			return nil, fmt.Errorf("unknown browser %q. expected one of %s", browser, strings.Join(browsers, ", "))
+		}
+	}
+
+	return list, nil
+}
+
+// validateQuery parses the query locally so invalid queries fail without a request.
+func validateQuery(query string) error {
+	if query == "" {
+		return nil
+	}
+	parser := searchtypes.FeaturesSearchQueryParser{}
+	if _, err := parser.Parse(query); err != nil {
+		return fmt.Errorf("invalid query %q: %w", query, err)
+	}
+
+	return nil
+}
+
+func formatDate(date openapi_types.Date) string { return date.Format(time.DateOnly) }
+
+func formatInt(value int64) string { return strconv.FormatInt(value, 10) }
+
+func formatScore(data backend.WPTFeatureData) string {
+	return formatOptional(data.Score, func(score float64) string {
+		return strconv.FormatFloat(score, 'f', 3, 64)
+	})
+}
+
+// listFeatures reads every feature that matches the query.
+func listFeatures(ctx context.Context, common *commonFlags, query string, sort string) ([]backend.Feature, error) {
+	client, err := backendclient.NewClient(common.apiHost)
+	if err != nil {
+		return nil, err
+	}
+	//nolint: exhaustruct // Only set the parameters given on the command line.
+	params := &backend.GetV1FeaturesParams{
+		PageSize: valuePtr(pageSize),
+	}
+	if query != "" {
+		params.Q = &query
+	}
+	if sort != "" {
+		params.Sort = valuePtr(backend.GetV1FeaturesParamsSort(sort))
+	}
+
+	return client.IterateFeatures(ctx, params).All()
+}
+
+func runSearch(ctx context.Context, args []string, out io.Writer) error {
+	fs, common := newFlagSet("search", "search QUERY [--sort SORT] [--format table|json|csv]")
+	sort := fs.String("sort", "", "Sort order. e.g. name_asc, baseline_status_desc")
+	positional, err := parseArgs(fs, common, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usageError(fs, errors.New("expected exactly one query"))
+	}
+	query := positional[0]
+	if err := validateQuery(query); err != nil {
+		return usageError(fs, err)
+	}
+
+	features, err := listFeatures(ctx, common, query, *sort)
+	if err != nil {
+		return err
+	}
+	result := table{
+		headers: []string{"feature_id", "name", "baseline_status", "baseline_low_date", "available_on"},
+		rows:    make([][]string, 0, len(features)),
+	}
+	for _, feature := range features {
+		var status, lowDate string
+		if feature.Baseline != nil {
+			status = formatOptional(feature.Baseline.Status, func(s backend.BaselineInfoStatus) string {
+				return string(s)
+			})
+			lowDate = formatOptional(feature.Baseline.LowDate, formatDate)
+		}
+		var availableOn []string
+		if feature.BrowserImplementations != nil {
+			for _, browser := range browsers {
+				impl, found := (*feature.BrowserImplementations)[browser]
+				if found && impl.Status != nil && *impl.Status == backend.Available {
+					availableOn = append(availableOn, browser)
+				}
+			}
+		}
+		result.rows = append(result.rows, []string{
+			feature.FeatureId, feature.Name, status, lowDate, strings.Join(availableOn, ","),
+		})
+	}
+
+	return writeOutput(out, common.format, result, features)
+}
+
+func runFeature(ctx context.Context, args []string, out io.Writer) error {
+	fs, common := newFlagSet("feature", "feature grid [--query QUERY] [--sort SORT] [--format table|json|csv]")
+	query := fs.String("query", "", "Only show the features that match the search query")
+	sort := fs.String("sort", "", "Sort order. e.g. name_asc, stable_chrome_desc")
+	positional, err := parseArgs(fs, common, args)
+	if err != nil {
+		return err
+	}
+	if !slices.Equal(positional, []string{"grid"}) {
+		return usageError(fs, errors.New("expected the grid subcommand"))
+	}
+	if err := validateQuery(*query); err != nil {
+		return usageError(fs, err)
+	}
+
+	features, err := listFeatures(ctx, common, *query, *sort)
+	if err != nil {
+		return err
+	}
+	result := table{
+		headers: append([]string{"feature_id", "name"}, browsers...),
+		rows:    make([][]string, 0, len(features)),
+	}
+	for _, feature := range features {
+		row := []string{feature.FeatureId, feature.Name}
+		for _, browser := range browsers {
+			var score string
+			if feature.Wpt != nil && feature.Wpt.Stable != nil {
+				score = formatScore((*feature.Wpt.Stable)[browser])
+			}
+			row = append(row, score)
+		}
+		result.rows = append(result.rows, row)
+	}
+
+	return writeOutput(out, common.format, result, features)
+}
+
+// browserWPTRunMetric is a WPT metric of a browser.
+type browserWPTRunMetric struct {
+	Browser string `json:"browser"`
+	backend.WPTRunMetric
+}
+
+func runWPT(ctx context.Context, args []string, out io.Writer) error {
+	fs, common := newFlagSet("wpt",
+		"wpt grid [--browser chrome,edge] [--channel stable] [--since DATE] [--until DATE] [--format table|json|csv]")
+	browserList := fs.String("browser", strings.Join(browsers, ","), "Comma separated list of browsers")
+	channel := fs.String("channel", string(backend.Stable), "Channel: stable or experimental")
+	metricView := fs.String("metric_view", string(backend.TestCounts), "Metric view: test_counts or subtest_counts")
+	dates := addDateFlags(fs)
+	positional, err := parseArgs(fs, common, args)
+	if err != nil {
+		return err
+	}
+	if !slices.Equal(positional, []string{"grid"}) {
+		return usageError(fs, errors.New("expected the grid subcommand"))
+	}
+	selectedBrowsers, err := parseBrowsers(*browserList)
+	if err != nil {
+		return usageError(fs, err)
+	}
+	if *channel != string(backend.Stable) && *channel != string(backend.Experimental) {
+		return usageError(fs, fmt.Errorf("unknown channel %q", *channel))
+	}
+	if *metricView != string(backend.TestCounts) && *metricView != string(backend.SubtestCounts) {
+		return usageError(fs, fmt.Errorf("unknown metric view %q", *metricView))
+	}
+	startAt, endAt, err := dates.parse(time.Now().UTC())
+	if err != nil {
+		return usageError(fs, err)
+	}
+
+	client, err := backendclient.NewClient(common.apiHost)
+	if err != nil {
+		return err
+	}
+	metrics := []browserWPTRunMetric{}
+	for _, browser := range selectedBrowsers {
+		browserMetrics, iterErr := client.IterateAggregatedWPTMetrics(ctx,
+			backend.BrowserPathParam(browser),
+			backend.ChannelPathParam(*channel),
+			backend.MetricViewPathParam(*metricView),
+			backend.ListAggregatedWPTMetricsParams{
+				StartAt:    startAt,
+				EndAt:      endAt,
+				PageToken:  nil,
+				PageSize:   valuePtr(pageSize),
+				FeatureIds: nil,
+			},
+		).All()
+		if iterErr != nil {
+			return fmt.Errorf("unable to get the WPT metrics of %s: %w", browser, iterErr)
+		}
+		for _, metric := range browserMetrics {
+			metrics = append(metrics, browserWPTRunMetric{Browser: browser, WPTRunMetric: metric})
+		}
+	}
+	result := table{
+		headers: []string{"browser", "run_timestamp", "total_tests_count", "test_pass_count"},
+		rows:    make([][]string, 0, len(metrics)),
+	}
+	for _, metric := range metrics {
+		result.rows = append(result.rows, []string{
+			metric.Browser,
+			metric.RunTimestamp.Format(time.RFC3339),
+			formatOptional(metric.TotalTestsCount, formatInt),
+			formatOptional(metric.TestPassCount, formatInt),
+		})
+	}
+
+	return writeOutput(out, common.format, result, metrics)
+}
+
+func runLag(ctx context.Context, args []string, out io.Writer) error {
+	fs, common := newFlagSet("lag",
+		"lag BROWSER [--browsers chrome,edge] [--since DATE] [--until DATE] [--format table|json|csv]")
+	compared := fs.String("browsers", "", "Comma separated list of browsers to compare with. Defaults to the others")
+	dates := addDateFlags(fs)
+	positional, err := parseArgs(fs, common, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usageError(fs, errors.New("expected exactly one browser"))
+	}
+	browser := positional[0]
+	if _, err := parseBrowsers(browser); err != nil {
+		return usageError(fs, err)
+	}
+	comparedBrowsers, err := parseBrowsers(*compared)
+	if err != nil {
+		return usageError(fs, err)
+	}
+	if comparedBrowsers == nil {
+		for _, other := range browsers {
+			if other != browser {
+				comparedBrowsers = append(comparedBrowsers, other)
+			}
+		}
+	}
+	startAt, endAt, err := dates.parse(time.Now().UTC())
+	if err != nil {
+		return usageError(fs, err)
+	}
+
+	client, err := backendclient.NewClient(common.apiHost)
+	if err != nil {
+		return err
+	}
+	metrics, err := client.IterateFeatureLagMetrics(ctx, backend.BrowserPathParam(browser),
+		backend.ListFeatureLagMetricsParams{
+			StartAt:   startAt,
+			EndAt:     endAt,
+			PageToken: nil,
+			PageSize:  valuePtr(pageSize),
+			Browsers:  comparedBrowsers,
+		},
+	).All()
+	if err != nil {
+		return err
+	}
+	result := table{
+		headers: []string{"timestamp", "lagging_features"},
+		rows:    make([][]string, 0, len(metrics)),
+	}
+	for _, metric := range metrics {
+		result.rows = append(result.rows, []string{
+			metric.Timestamp.Format(time.RFC3339),
+			formatOptional(metric.Count, formatInt),
+		})
+	}
+
+	return writeOutput(out, common.format, result, metrics)
+}
+
+func valuePtr[T any](in T) *T { return &in }
@@ -0,0 +1,203 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/backend"
+)
+
+func TestParseArgs(t *testing.T) {
+	testCases := []struct {
+		name               string
+		args               []string
+		expectedPositional []string
+		expectedFormat     string
+		expectedError      error
+	}{
+		{
+			name:               "flags after positional arguments",
+			args:               []string{"available_on:chrome", "--format", "json"},
+			expectedPositional: []string{"available_on:chrome"},
+			expectedFormat:     formatJSON,
+			expectedError:      nil,
+		},
+		{
+			name:               "flags before positional arguments",
+			args:               []string{"--format=csv", "grid"},
+			expectedPositional: []string{"grid"},
+			expectedFormat:     formatCSV,
+			expectedError:      nil,
+		},
+		{
+			name:               "default format",
+			args:               []string{"grid"},
+			expectedPositional: []string{"grid"},
+			expectedFormat:     formatTable,
+			expectedError:      nil,
+		},
+		{
+			name:               "unknown format",
+			args:               []string{"grid", "--format", "xml"},
+			expectedPositional: nil,
+			expectedFormat:     "xml",
+			expectedError:      errUsage,
+		},
+		{
+			name:               "unknown flag",
+			args:               []string{"--unknown"},
+			expectedPositional: nil,
+			expectedFormat:     formatTable,
+			expectedError:      errUsage,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fs, common := newFlagSet("test", "test")
+			fs.SetOutput(io.Discard)
+			positional, err := parseArgs(fs, common, tc.args)
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("unexpected error. expected %v received %v", tc.expectedError, err)
+			}
+			if !slices.Equal(positional, tc.expectedPositional) {
+				t.Errorf("unexpected positional arguments. expected %v received %v",
+					tc.expectedPositional, positional)
+			}
+			if common.format != tc.expectedFormat {
+				t.Errorf("unexpected format. expected %s received %s", tc.expectedFormat, common.format)
+			}
+		})
+	}
+}
+
+func TestDateFlagsParse(t *testing.T) {
+	now := time.Date(2024, time.March, 15, 13, 0, 0, 0, time.UTC)
+	startAt, endAt, err := dateFlags{since: "", until: ""}.parse(now)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if formatDate(startAt) != "2023-03-15" || formatDate(endAt) != "2024-03-16" {
+		t.Errorf("unexpected default dates %s %s", formatDate(startAt), formatDate(endAt))
+	}
+
+	startAt, endAt, err = dateFlags{since: "2024-01-01", until: "2024-02-01"}.parse(now)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if formatDate(startAt) != "2024-01-01" || formatDate(endAt) != "2024-02-01" {
+		t.Errorf("unexpected dates %s %s", formatDate(startAt), formatDate(endAt))
+	}
+
+	if _, _, err := (dateFlags{since: "01/01/2024", until: ""}).parse(now); err == nil {
+		t.Error("expected error for invalid date")
+	}
+	if _, _, err := (dateFlags{since: "2024-02-01", until: "2024-01-01"}).parse(now); err == nil {
+		t.Error("expected error for since after until")
+	}
+}
+
+func TestWriteOutput(t *testing.T) {
+	result := table{
+		headers: []string{"feature_id", "name"},
+		rows:    [][]string{{"grid", "CSS Grid"}, {"has", "has(), the"}},
+	}
+	testCases := []struct {
+		format   string
+		expected string
+	}{
+		{
+			format:   formatTable,
+			expected: "feature_id  name\ngrid        CSS Grid\nhas         has(), the\n",
+		},
+		{
+			format:   formatCSV,
+			expected: "feature_id,name\ngrid,CSS Grid\nhas,\"has(), the\"\n",
+		},
+		{
+			format:   formatJSON,
+			expected: "[\n  \"grid\",\n  \"has\"\n]\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.format, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := writeOutput(&out, tc.format, result, []string{"grid", "has"}); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if out.String() != tc.expected {
+				t.Errorf("unexpected output.\nexpected:\n%s\nreceived:\n%s", tc.expected, out.String())
+			}
+		})
+	}
+}
+
+func TestRunSearch(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Query().Get("q") != "available_on:chrome" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		status := backend.Widely
+		implementations := map[string]backend.BrowserImplementation{
+			"chrome": {Status: valuePtr(backend.Available), Date: nil},
+		}
+		//nolint: exhaustruct // Only the fields used by the command are needed.
+		page := backend.FeaturePage{
+			Data: []backend.Feature{{
+				FeatureId:              "grid",
+				Name:                   "CSS Grid",
+				Baseline:               &backend.BaselineInfo{Status: &status, LowDate: nil, HighDate: nil},
+				BrowserImplementations: &implementations,
+			}},
+			Metadata: backend.PageMetadataWithTotal{NextPageToken: nil, Total: 1},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(page); err != nil {
+			t.Errorf("unable to write response %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	var out bytes.Buffer
+	err := runSearch(context.Background(),
+		[]string{"available_on:chrome", "--format", "csv", "--api_host", srv.URL}, &out)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	expected := "feature_id,name,baseline_status,baseline_low_date,available_on\ngrid,CSS Grid,widely,,chrome\n"
+	if out.String() != expected {
+		t.Errorf("unexpected output.\nexpected:\n%s\nreceived:\n%s", expected, out.String())
+	}
+
+	// Invalid queries are rejected before sending a request.
+	err = runSearch(context.Background(), []string{"badterm:", "--api_host", srv.URL}, io.Discard)
+	if !errors.Is(err, errUsage) {
+		t.Errorf("expected usage error, received %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected one request, received %d", requests)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command webstatus queries the webstatus.dev API from the terminal.
+//
+// Usage:
+//
+//	webstatus search 'available_on:chrome -available_on:safari' --format table|json|csv
+//	webstatus feature grid [--query QUERY]
+//	webstatus wpt grid --browser chrome --channel stable --since 2024-01-01
+//	webstatus lag safari [--browsers chrome,edge,firefox]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+const defaultAPIHost = "https://api.webstatus.dev"
+
+// errUsage is returned when the arguments are invalid. The usage has already been printed.
+var errUsage = errors.New("invalid usage")
+
+// command is a subcommand of the CLI.
+type command struct {
+	name        string
+	description string
+	run         func(ctx context.Context, args []string, out io.Writer) error
+}
+
+func commands() []command {
+	return []command{
+		{
+			name:        "search",
+			description: "search QUERY: list the features that match a search query",
+			run:         runSearch,
+		},
+		{
+			name:        "feature",
+			description: "feature grid: show the stable WPT scores of each feature",
+			run:         runFeature,
+		},
+		{
+			name:        "wpt",
+			description: "wpt grid: show the WPT test counts of browsers over time",
+			run:         runWPT,
+		},
+		{
+			name:        "lag",
+			description: "lag BROWSER: show the number of features a browser lags behind other browsers",
+			run:         runLag,
+		},
+	}
+}
+
+func printUsage(out io.Writer) {
+	fmt.Fprintln(out, "Usage: webstatus COMMAND [ARGS] [FLAGS]")
+	fmt.Fprintln(out, "Commands:")
+	for _, cmd := range commands() {
+		fmt.Fprintf(out, "  %s\n", cmd.description)
+	}
+	fmt.Fprintln(out, "Run webstatus COMMAND --help for the flags of a command.")
+}
+
+func run(ctx context.Context, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		printUsage(os.Stderr)
+
+		return errUsage
+	}
+	for _, cmd := range commands() {
+		if cmd.name == args[0] {
+			return cmd.run(ctx, args[1:], out)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "unknown command %q\n", args[0])
+	printUsage(os.Stderr)
+
+	return errUsage
+}
+
+func main() {
+	err := run(context.Background(), os.Args[1:], os.Stdout)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if errors.Is(err, errUsage) {
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+)
+
+func validateFormat(format string) error {
+	switch format {
+	case formatTable, formatJSON, formatCSV:
+		return nil
+	}
+
+	return fmt.Errorf("unknown format %q. expected %s, %s or %s", format, formatTable, formatJSON, formatCSV)
+}
+
+// table is the result of a command for the table and csv formats.
+type table struct {
+	headers []string
+	rows    [][]string
+}
+
+// writeOutput writes the result of a command.
+// The json format writes the items returned by the API as is, so no information is lost for scripts.
+func writeOutput(out io.Writer, format string, t table, items any) error {
+	switch format {
+	case formatJSON:
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(items)
+	case formatCSV:
+		w := csv.NewWriter(out)
+		if err := w.Write(t.headers); err != nil {
+			return err
+		}
+		if err := w.WriteAll(t.rows); err != nil {
+			return err
+		}
+
+		return w.Error()
+	default:
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, strings.Join(t.headers, "\t"))
+		for _, row := range t.rows {
+			fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+
+		return w.Flush()
+	}
+}
+
+// formatOptional formats a value that the API may omit. Missing values are empty.
+func formatOptional[T any](value *T, format func(T) string) string {
+	if value == nil {
+		return ""
+	}
+
+	return format(*value)
+}
@@ -15,15 +15,16 @@
 package main
 
 import (
-	"cmp"
 	"context"
 	"log/slog"
 	"os"
 
+	"github.com/GoogleChrome/webstatus.dev/lib/config"
 	"github.com/GoogleChrome/webstatus.dev/lib/gcpspanner"
 	"github.com/GoogleChrome/webstatus.dev/lib/gcpspanner/spanneradapters"
 	"github.com/GoogleChrome/webstatus.dev/lib/gcpspanner/spanneradapters/bcdconsumertypes"
 	"github.com/GoogleChrome/webstatus.dev/lib/gh"
+	"github.com/GoogleChrome/webstatus.dev/lib/lifecycle"
 	"github.com/GoogleChrome/webstatus.dev/lib/workerpool"
 	"github.com/GoogleChrome/webstatus.dev/workflows/steps/services/bcd_consumer/pkg/data"
 	"github.com/GoogleChrome/webstatus.dev/workflows/steps/services/bcd_consumer/pkg/workflow"
@@ -35,17 +36,33 @@ const (
 	defaultReleaseAssetName = "data.json"
 )
 
-func main() {
-	ctx := context.Background()
+// jobConfig contains the settings of the BCD consumer job.
+type jobConfig struct {
+	config.GCP
+	Spanner          config.Spanner
+	GitHub           config.GitHub
+	RepoOwner        string `env:"REPO_OWNER" usage:"Owner of the browser-compat-data repository"`
+	RepoName         string `env:"REPO_NAME" usage:"Name of the browser-compat-data repository"`
+	ReleaseAssetName string `env:"RELEASE_ASSET_NAME" usage:"Release asset that contains the data"`
+}
 
-	// Configuration and Client Setup
+func main() {
+	cfg := jobConfig{
+		GCP:              config.GCP{ProjectID: ""},
+		Spanner:          config.Spanner{Instance: "", Database: "", EmulatorHost: ""},
+		GitHub:           config.GitHub{Token: ""},
+		RepoOwner:        defaultRepoOwner,
+		RepoName:         defaultRepoName,
+		ReleaseAssetName: defaultReleaseAssetName,
+	}
+	config.MustLoad(&cfg)
 
-	projectID := os.Getenv("PROJECT_ID")
+	// Canceling the context stops the workers from starting new work on SIGTERM.
+	ctx, stop := lifecycle.SignalContext(context.Background())
+	defer stop()
 
 	// Spanner setup
-	spannerDB := os.Getenv("SPANNER_DATABASE")
-	spannerInstance := os.Getenv("SPANNER_INSTANCE")
-	spannerClient, err := gcpspanner.NewSpannerClient(projectID, spannerInstance, spannerDB)
+	spannerClient, err := gcpspanner.NewSpannerClient(cfg.ProjectID, cfg.Spanner.Instance, cfg.Spanner.Database)
 	if err != nil {
 		slog.ErrorContext(ctx, "failed to create spanner client", "error", err.Error())
 		os.Exit(1)
@@ -54,25 +71,17 @@ func main() {
 	// Currently, only one worker needed
 	numWorkers := 1
 
-	repoName := cmp.Or(os.Getenv("REPO_NAME"), defaultRepoName)
-
-	repoOwner := cmp.Or(os.Getenv("REPO_OWNER"), defaultRepoOwner)
-
-	releaseAssetName := cmp.Or(os.Getenv("RELEASE_ASSET_NAME"), defaultReleaseAssetName)
-
 	// Worker Pool Setup
 	pool := workerpool.Pool[workflow.JobArguments]{}
 
-	// Will be empty if not set and that is okay.
-	token := os.Getenv("GITHUB_TOKEN")
 	worker := workflow.NewBCDReleasesWorker(
-		gh.NewClient(token),
+		gh.NewClient(cfg.GitHub.Token),
 		data.Parser{},
 		workflow.BCDDataFilter{},
 		spanneradapters.NewBCDWorkflowConsumer(spannerClient),
-		repoOwner,
-		repoName,
-		releaseAssetName,
+		cfg.RepoOwner,
+		cfg.RepoName,
+		cfg.ReleaseAssetName,
 	)
 
 	// Job Generation
@@ -87,15 +96,28 @@ func main() {
 			},
 		)
 		slog.InfoContext(ctx, "sending args to worker pool", "args", args)
-		jobChan <- args
+		select {
+		case jobChan <- args:
+		case <-ctx.Done():
+		}
 		// Close the job channel now that we are done.
 		close(jobChan)
 	}()
 
 	// Job Execution and Error Handling
 	errs := pool.Start(ctx, jobChan, numWorkers, worker)
+	closeErr := lifecycle.Close(lifecycle.DefaultShutdownTimeout,
+		lifecycle.NewCloser("spanner", func() error {
+			spannerClient.Close()
+
+			return nil
+		}),
+	)
 	if len(errs) > 0 {
 		slog.ErrorContext(ctx, "workflow returned errors", "error", errs)
 		os.Exit(1)
 	}
+	if closeErr != nil {
+		os.Exit(1)
+	}
 }
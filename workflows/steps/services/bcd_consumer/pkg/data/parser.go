@@ -15,9 +15,11 @@
 package data
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"io"
+	"strings"
 
 	"github.com/GoogleChrome/webstatus.dev/lib/gen/jsonschema/mdn__browser_compat_data"
 )
@@ -30,20 +32,152 @@ type Parser struct{}
 // https://github.com/mdn/browser-compat-data/blob/main/schemas/browsers.schema.json
 type BCDData struct {
 	mdn__browser_compat_data.BrowserData
+	// CompatFeatures maps the BCD keys to their support information.
+	// BCD keys are the dotted paths to the compat data. For example: api.AbortController.abort.
+	CompatFeatures map[string]CompatFeature `json:"-"`
+}
+
+// CompatFeature contains the support information of a BCD key.
+type CompatFeature struct {
+	// VersionAdded maps the BCD browser names to the version that added full support.
+	// Browsers that do not fully support the key are not present.
+	VersionAdded map[string]string
 }
 
 var ErrUnexpectedFormat = errors.New("unexpected format")
 
+const (
+	browsersKey = "browsers"
+	compatKey   = "__compat"
+	// Keys with this prefix contain metadata instead of compat data.
+	metadataKeyPrefix = "__"
+	// Browsers that are only available as a preview do not count as support.
+	previewVersion = "preview"
+)
+
 // Parse attempts to parse raw byes in the BCDData.
 // It will consume the readcloser and close it.
 func (p Parser) Parse(in io.ReadCloser) (*BCDData, error) {
 	defer in.Close()
-	var ret BCDData
+	// Besides browsers, every top-level key (api, css, html...) contains compat data.
+	var raw map[string]json.RawMessage
 	decoder := json.NewDecoder(in)
-	err := decoder.Decode(&ret)
+	err := decoder.Decode(&raw)
 	if err != nil {
 		return nil, errors.Join(ErrUnexpectedFormat, err)
 	}
 
+	ret := BCDData{
+		// nolint: exhaustruct // WONTFIX. External struct. Filled in below.
+		BrowserData:    mdn__browser_compat_data.BrowserData{},
+		CompatFeatures: make(map[string]CompatFeature),
+	}
+	for key, value := range raw {
+		if key == browsersKey {
+			err = json.Unmarshal(value, &ret.BrowserData.Browsers)
+		} else if !strings.HasPrefix(key, metadataKeyPrefix) {
+			err = parseCompatFeatures(key, value, ret.CompatFeatures)
+		}
+		if err != nil {
+			return nil, errors.Join(ErrUnexpectedFormat, err)
+		}
+	}
+
 	return &ret, nil
 }
+
+// parseCompatFeatures walks the compat data tree and collects the support information of every BCD key.
+func parseCompatFeatures(bcdKey string, in json.RawMessage, out map[string]CompatFeature) error {
+	var node map[string]json.RawMessage
+	err := json.Unmarshal(in, &node)
+	if err != nil {
+		return err
+	}
+	for key, value := range node {
+		if key == compatKey {
+			feature, err := parseCompatStatement(value)
+			if err != nil {
+				return err
+			}
+			out[bcdKey] = *feature
+
+			continue
+		}
+		if strings.HasPrefix(key, metadataKeyPrefix) {
+			continue
+		}
+		err = parseCompatFeatures(bcdKey+"."+key, value, out)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// compatStatement follows the schema found at
+// https://github.com/mdn/browser-compat-data/blob/main/schemas/compat-data.schema.json
+type compatStatement struct {
+	// Support maps the browser names to a support statement or an array of support statements.
+	Support map[string]json.RawMessage `json:"support"`
+}
+
+type supportStatement struct {
+	// VersionAdded is either a version string, a boolean or null.
+	VersionAdded any `json:"version_added"`
+	// VersionRemoved is either a version string, a boolean or null.
+	VersionRemoved        any               `json:"version_removed"`
+	PartialImplementation bool              `json:"partial_implementation"`
+	Prefix                string            `json:"prefix"`
+	AlternativeName       string            `json:"alternative_name"`
+	Flags                 []json.RawMessage `json:"flags"`
+}
+
+// fullSupportVersion returns the version that added full support.
+// Support behind flags, prefixes or alternative names and partial or removed support do not count.
+func (s supportStatement) fullSupportVersion() (string, bool) {
+	version, ok := s.VersionAdded.(string)
+	if !ok || version == previewVersion {
+		return "", false
+	}
+	// Support that has not been removed has no version_removed or has it set to false.
+	if s.VersionRemoved != nil && s.VersionRemoved != false {
+		return "", false
+	}
+	if s.PartialImplementation || s.Prefix != "" || s.AlternativeName != "" || len(s.Flags) > 0 {
+		return "", false
+	}
+
+	return version, true
+}
+
+func parseCompatStatement(in json.RawMessage) (*CompatFeature, error) {
+	var statement compatStatement
+	err := json.Unmarshal(in, &statement)
+	if err != nil {
+		return nil, err
+	}
+	feature := CompatFeature{VersionAdded: make(map[string]string)}
+	for browser, rawSupport := range statement.Support {
+		var supports []supportStatement
+		// Support is either a single statement or an array of statements ordered by relevance.
+		if bytes.HasPrefix(bytes.TrimSpace(rawSupport), []byte("[")) {
+			err = json.Unmarshal(rawSupport, &supports)
+		} else {
+			supports = make([]supportStatement, 1)
+			err = json.Unmarshal(rawSupport, &supports[0])
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, support := range supports {
+			if version, ok := support.fullSupportVersion(); ok {
+				feature.VersionAdded[browser] = version
+
+				break
+			}
+		}
+	}
+
+	return &feature, nil
+}
@@ -23,6 +23,7 @@ import (
 	"net/http"
 	"os"
 	"path"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -132,6 +133,75 @@ func TestParse(t *testing.T) {
 
 }
 
+func TestParseCompatFeatures(t *testing.T) {
+	input := `{
+		"__meta": {"version": "5.5.19"},
+		"browsers": {},
+		"api": {
+			"AbortController": {
+				"__compat": {
+					"support": {
+						"chrome": {"version_added": "66"},
+						"firefox": [
+							{"version_added": "100", "flags": [{"type": "preference", "name": "abort"}]},
+							{"version_added": "57"}
+						],
+						"safari": {"version_added": "12.1", "partial_implementation": true},
+						"edge": {"version_added": "≤79"}
+					}
+				},
+				"abort": {
+					"__compat": {
+						"support": {
+							"chrome": {"version_added": "preview"},
+							"firefox": {"version_added": false},
+							"safari": {"version_added": null},
+							"edge": {"version_added": "16", "version_removed": "79"}
+						}
+					}
+				}
+			}
+		},
+		"css": {
+			"properties": {
+				"appearance": {
+					"__compat": {
+						"support": {
+							"chrome": [{"version_added": "84", "version_removed": false}, {"version_added": "1", "prefix": "-webkit-"}],
+							"safari": {"version_added": "3", "alternative_name": "-webkit-appearance"}
+						}
+					}
+				}
+			}
+		}
+	}`
+	expected := map[string]CompatFeature{
+		"api.AbortController": {
+			VersionAdded: map[string]string{
+				"chrome":  "66",
+				"firefox": "57",
+				"edge":    "≤79",
+			},
+		},
+		"api.AbortController.abort": {
+			VersionAdded: map[string]string{},
+		},
+		"css.properties.appearance": {
+			VersionAdded: map[string]string{
+				"chrome": "84",
+			},
+		},
+	}
+
+	result, err := Parser{}.Parse(io.NopCloser(strings.NewReader(input)))
+	if err != nil {
+		t.Fatalf("unable to parse err %s", err.Error())
+	}
+	if !reflect.DeepEqual(result.CompatFeatures, expected) {
+		t.Errorf("unexpected compat features\nexpected %v\nreceived %v", expected, result.CompatFeatures)
+	}
+}
+
 func TestParseError(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -143,6 +213,11 @@ func TestParseError(t *testing.T) {
 			input:         io.NopCloser(strings.NewReader("Hello, world!")),
 			expectedError: ErrUnexpectedFormat,
 		},
+		{
+			name:          "bad compat data",
+			input:         io.NopCloser(strings.NewReader(`{"api": {"AbortController": {"__compat": []}}}`)),
+			expectedError: ErrUnexpectedFormat,
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
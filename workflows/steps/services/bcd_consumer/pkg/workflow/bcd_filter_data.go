@@ -18,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"time"
 
 	"github.com/GoogleChrome/webstatus.dev/lib/gcpspanner/spanneradapters/bcdconsumertypes"
@@ -103,3 +104,40 @@ func (f BCDDataFilter) FilterData(
 
 	return ret, nil
 }
+
+// FilterCompatFeatures returns the support of every BCD key in the filtered browsers, sorted by key.
+// Browsers that do not fully support a key are returned without a version so that
+// support that is removed from BCD is also removed from storage.
+func (f BCDDataFilter) FilterCompatFeatures(
+	in *data.BCDData, filteredBrowsers []string) ([]bcdconsumertypes.CompatFeatureSupport, error) {
+	err := f.checkBrowserFilters(filteredBrowsers)
+	if err != nil {
+		return nil, err
+	}
+
+	if in == nil {
+		return nil, nil
+	}
+	keys := make([]string, 0, len(in.CompatFeatures))
+	for key := range in.CompatFeatures {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	ret := make([]bcdconsumertypes.CompatFeatureSupport, 0, len(keys)*len(filteredBrowsers))
+	for _, key := range keys {
+		for _, browser := range filteredBrowsers {
+			var versionAdded *string
+			if version, found := in.CompatFeatures[key].VersionAdded[browser]; found {
+				versionAdded = &version
+			}
+			ret = append(ret, bcdconsumertypes.CompatFeatureSupport{
+				CompatFeature: key,
+				BrowserName:   bcdconsumertypes.BrowserName(browser),
+				VersionAdded:  versionAdded,
+			})
+		}
+	}
+
+	return ret, nil
+}
@@ -80,6 +80,7 @@ func TestFilterData(t *testing.T) {
 		{
 			name: "Valid Input",
 			inputBCD: &data.BCDData{
+				CompatFeatures: nil,
 				// nolint: exhaustruct // WONTFIX. External struct.
 				BrowserData: mdn__browser_compat_data.BrowserData{
 					Browsers: map[string]mdn__browser_compat_data.BrowserStatement{
@@ -108,6 +109,7 @@ func TestFilterData(t *testing.T) {
 		{
 			name: "Non-existent Browser",
 			inputBCD: &data.BCDData{
+				CompatFeatures: nil,
 				// nolint: exhaustruct // WONTFIX. External struct.
 				BrowserData: mdn__browser_compat_data.BrowserData{
 					Browsers: map[string]mdn__browser_compat_data.BrowserStatement{
@@ -126,6 +128,7 @@ func TestFilterData(t *testing.T) {
 		{
 			name: "Invalid Release Date Format",
 			inputBCD: &data.BCDData{
+				CompatFeatures: nil,
 				// nolint: exhaustruct // WONTFIX. External struct.
 				BrowserData: mdn__browser_compat_data.BrowserData{
 					Browsers: map[string]mdn__browser_compat_data.BrowserStatement{
@@ -145,6 +148,7 @@ func TestFilterData(t *testing.T) {
 		{
 			name: "Release with No Date",
 			inputBCD: &data.BCDData{
+				CompatFeatures: nil,
 				// nolint: exhaustruct // WONTFIX. External struct.
 				BrowserData: mdn__browser_compat_data.BrowserData{
 					Browsers: map[string]mdn__browser_compat_data.BrowserStatement{
@@ -195,3 +199,64 @@ func TestFilterData(t *testing.T) {
 		})
 	}
 }
+
+func TestFilterCompatFeatures(t *testing.T) {
+	testCases := []struct {
+		name             string
+		inputBCD         *data.BCDData
+		filteredBrowsers []string
+		expectedResult   []bcdconsumertypes.CompatFeatureSupport
+		expectedError    error
+	}{
+		{
+			name: "Valid Input",
+			inputBCD: &data.BCDData{
+				// nolint: exhaustruct // WONTFIX. External struct.
+				BrowserData: mdn__browser_compat_data.BrowserData{},
+				CompatFeatures: map[string]data.CompatFeature{
+					"api.Foo.bar": {
+						VersionAdded: map[string]string{"chrome": "101", "safari": "17"},
+					},
+					"api.Foo": {
+						VersionAdded: map[string]string{"chrome": "100", "firefox": "110"},
+					},
+				},
+			},
+			filteredBrowsers: []string{"chrome", "firefox"},
+			expectedResult: []bcdconsumertypes.CompatFeatureSupport{
+				{CompatFeature: "api.Foo", BrowserName: "chrome", VersionAdded: valuePtr("100")},
+				{CompatFeature: "api.Foo", BrowserName: "firefox", VersionAdded: valuePtr("110")},
+				{CompatFeature: "api.Foo.bar", BrowserName: "chrome", VersionAdded: valuePtr("101")},
+				{CompatFeature: "api.Foo.bar", BrowserName: "firefox", VersionAdded: nil},
+			},
+			expectedError: nil,
+		},
+		{
+			name:             "Unknown Browser Filter",
+			inputBCD:         nil,
+			filteredBrowsers: []string{"bad"},
+			expectedResult:   nil,
+			expectedError:    ErrUnknownBrowserFilter,
+		},
+		{
+			name:             "Nil BCDData Input",
+			inputBCD:         nil,
+			filteredBrowsers: []string{"firefox"},
+			expectedResult:   nil,
+			expectedError:    nil,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filter := BCDDataFilter{}
+			result, err := filter.FilterCompatFeatures(tc.inputBCD, tc.filteredBrowsers)
+			if !reflect.DeepEqual(result, tc.expectedResult) {
+				t.Errorf("Unexpected result:\nGot: %v\nWant: %v", result, tc.expectedResult)
+			}
+
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("Unexpected error:\nGot: %v\nWant: %v", err, tc.expectedError)
+			}
+		})
+	}
+}
@@ -98,14 +98,17 @@ type DataParser interface {
 	Parse(in io.ReadCloser) (*data.BCDData, error)
 }
 
-// DataFilter describes the behavior to take full BCDData and only filter for the applicable browser releases.
+// DataFilter describes the behavior to take full BCDData and only filter for the applicable browser releases
+// and the browser support of the BCD keys.
 type DataFilter interface {
 	FilterData(*data.BCDData, []string) ([]bcdconsumertypes.BrowserRelease, error)
+	FilterCompatFeatures(*data.BCDData, []string) ([]bcdconsumertypes.CompatFeatureSupport, error)
 }
 
-// DataStorer describes the behavior to store the release information.
+// DataStorer describes the behavior to store the release information and the browser support of the BCD keys.
 type DataStorer interface {
 	InsertBrowserReleases(ctx context.Context, releases []bcdconsumertypes.BrowserRelease) error
+	UpsertCompatFeatureSupports(ctx context.Context, supports []bcdconsumertypes.CompatFeatureSupport) error
 }
 
 type BCDJobProcessor struct {
@@ -150,5 +153,17 @@ func (p BCDJobProcessor) Process(
 		return err
 	}
 
+	// Step 5. Filter the browser support of the BCD keys.
+	compatFeatureSupports, err := p.dataFilter.FilterCompatFeatures(data, job.browsers)
+	if err != nil {
+		return err
+	}
+
+	// Step 6. Upsert the browser support of the BCD keys.
+	err = p.dataStorer.UpsertCompatFeatureSupports(ctx, compatFeatureSupports)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
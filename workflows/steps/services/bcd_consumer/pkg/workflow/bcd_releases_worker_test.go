@@ -218,8 +218,9 @@ type mockFilterDataConfig struct {
 }
 
 type MockDataFilter struct {
-	t                 *testing.T
-	mockFilterDataCfg *mockFilterDataConfig
+	t                           *testing.T
+	mockFilterDataCfg           *mockFilterDataConfig
+	mockFilterCompatFeaturesCfg *mockFilterCompatFeaturesConfig
 }
 
 func (m *MockDataFilter) FilterData(in *data.BCDData, filters []string) ([]bcdconsumertypes.BrowserRelease, error) {
@@ -231,14 +232,37 @@ func (m *MockDataFilter) FilterData(in *data.BCDData, filters []string) ([]bcdco
 	return m.mockFilterDataCfg.retReleases, m.mockFilterDataCfg.err
 }
 
+type mockFilterCompatFeaturesConfig struct {
+	expectedData    *data.BCDData
+	expectedFilters []string
+	retSupports     []bcdconsumertypes.CompatFeatureSupport
+	err             error
+}
+
+func (m *MockDataFilter) FilterCompatFeatures(
+	in *data.BCDData, filters []string) ([]bcdconsumertypes.CompatFeatureSupport, error) {
+	if !reflect.DeepEqual(in, m.mockFilterCompatFeaturesCfg.expectedData) ||
+		!slices.Equal(filters, m.mockFilterCompatFeaturesCfg.expectedFilters) {
+		m.t.Error("unexpected args to FilterCompatFeatures")
+	}
+
+	return m.mockFilterCompatFeaturesCfg.retSupports, m.mockFilterCompatFeaturesCfg.err
+}
+
 type mockInsertBrowserReleasesConfig struct {
 	expectedReleases []bcdconsumertypes.BrowserRelease
 	err              error
 }
 
+type mockUpsertCompatFeatureSupportsConfig struct {
+	expectedSupports []bcdconsumertypes.CompatFeatureSupport
+	err              error
+}
+
 type MockDataStorer struct {
-	mockInsertBrowserReleasesCfg *mockInsertBrowserReleasesConfig
-	t                            *testing.T
+	mockInsertBrowserReleasesCfg       *mockInsertBrowserReleasesConfig
+	mockUpsertCompatFeatureSupportsCfg *mockUpsertCompatFeatureSupportsConfig
+	t                                  *testing.T
 }
 
 func (m *MockDataStorer) InsertBrowserReleases(_ context.Context, releases []bcdconsumertypes.BrowserRelease) error {
@@ -249,14 +273,25 @@ func (m *MockDataStorer) InsertBrowserReleases(_ context.Context, releases []bcd
 	return m.mockInsertBrowserReleasesCfg.err
 }
 
+func (m *MockDataStorer) UpsertCompatFeatureSupports(
+	_ context.Context, supports []bcdconsumertypes.CompatFeatureSupport) error {
+	if !reflect.DeepEqual(m.mockUpsertCompatFeatureSupportsCfg.expectedSupports, supports) {
+		m.t.Error("unexpected args to UpsertCompatFeatureSupports")
+	}
+
+	return m.mockUpsertCompatFeatureSupportsCfg.err
+}
+
 type processWorkflowTest struct {
-	name                           string
-	job                            JobArguments
-	mockDownloadFileFromReleaseCfg *mockDownloadFileFromReleaseConfig
-	mockParseCfg                   *mockParseConfig
-	mockFilterDataCfg              *mockFilterDataConfig
-	mockInsertBrowserReleasesCfg   *mockInsertBrowserReleasesConfig
-	expectedErr                    error
+	name                               string
+	job                                JobArguments
+	mockDownloadFileFromReleaseCfg     *mockDownloadFileFromReleaseConfig
+	mockParseCfg                       *mockParseConfig
+	mockFilterDataCfg                  *mockFilterDataConfig
+	mockInsertBrowserReleasesCfg       *mockInsertBrowserReleasesConfig
+	mockFilterCompatFeaturesCfg        *mockFilterCompatFeaturesConfig
+	mockUpsertCompatFeatureSupportsCfg *mockUpsertCompatFeatureSupportsConfig
+	expectedErr                        error
 }
 
 const repoOwner = "owner"
@@ -264,16 +299,19 @@ const repoName = "repo"
 const filePattern = "data.json"
 
 var (
-	errTestGetter = errors.New("test getter error")
-	errTestParse  = errors.New("test parse error")
-	errTestFilter = errors.New("test filter error")
-	errTestInsert = errors.New("test insert error")
+	errTestGetter                      = errors.New("test getter error")
+	errTestParse                       = errors.New("test parse error")
+	errTestFilter                      = errors.New("test filter error")
+	errTestInsert                      = errors.New("test insert error")
+	errTestFilterCompatFeatures        = errors.New("test filter compat features error")
+	errTestUpsertCompatFeatureSupports = errors.New("test upsert compat feature supports error")
 )
 
 func valuePtr[T any](in T) *T { return &in }
 
 func getSampleBCDData() *data.BCDData {
 	return &data.BCDData{
+		CompatFeatures: nil,
 		// nolint: exhaustruct // WONTFIX external struct
 		BrowserData: mdn__browser_compat_data.BrowserData{
 			Browsers: map[string]mdn__browser_compat_data.BrowserStatement{
@@ -311,6 +349,21 @@ func getSampleReleases() []bcdconsumertypes.BrowserRelease {
 	}
 }
 
+func getSampleCompatFeatureSupports() []bcdconsumertypes.CompatFeatureSupport {
+	return []bcdconsumertypes.CompatFeatureSupport{
+		{
+			CompatFeature: "api.Foo",
+			BrowserName:   "fooBrowser",
+			VersionAdded:  valuePtr("0"),
+		},
+		{
+			CompatFeature: "api.Foo",
+			BrowserName:   "barBrowser",
+			VersionAdded:  nil,
+		},
+	}
+}
+
 func TestProcess(t *testing.T) {
 	testCases := []processWorkflowTest{
 		{
@@ -340,6 +393,16 @@ func TestProcess(t *testing.T) {
 				expectedReleases: getSampleReleases(),
 				err:              nil,
 			},
+			mockFilterCompatFeaturesCfg: &mockFilterCompatFeaturesConfig{
+				expectedData:    getSampleBCDData(),
+				expectedFilters: []string{"fooBrowser", "barBrowser"},
+				retSupports:     getSampleCompatFeatureSupports(),
+				err:             nil,
+			},
+			mockUpsertCompatFeatureSupportsCfg: &mockUpsertCompatFeatureSupportsConfig{
+				expectedSupports: getSampleCompatFeatureSupports(),
+				err:              nil,
+			},
 			expectedErr: nil,
 		},
 		{
@@ -354,10 +417,12 @@ func TestProcess(t *testing.T) {
 				fakeFile:    io.NopCloser(strings.NewReader("success")),
 				err:         errTestGetter,
 			},
-			mockParseCfg:                 nil,
-			mockFilterDataCfg:            nil,
-			mockInsertBrowserReleasesCfg: nil,
-			expectedErr:                  errTestGetter,
+			mockParseCfg:                       nil,
+			mockFilterDataCfg:                  nil,
+			mockInsertBrowserReleasesCfg:       nil,
+			mockFilterCompatFeaturesCfg:        nil,
+			mockUpsertCompatFeatureSupportsCfg: nil,
+			expectedErr:                        errTestGetter,
 		},
 		{
 			name: "failed to parse data",
@@ -376,9 +441,11 @@ func TestProcess(t *testing.T) {
 				ret:                  getSampleBCDData(),
 				err:                  errTestParse,
 			},
-			mockFilterDataCfg:            nil,
-			mockInsertBrowserReleasesCfg: nil,
-			expectedErr:                  errTestParse,
+			mockFilterDataCfg:                  nil,
+			mockInsertBrowserReleasesCfg:       nil,
+			mockFilterCompatFeaturesCfg:        nil,
+			mockUpsertCompatFeatureSupportsCfg: nil,
+			expectedErr:                        errTestParse,
 		},
 		{
 			name: "failed to filter data",
@@ -403,8 +470,10 @@ func TestProcess(t *testing.T) {
 				retReleases:     getSampleReleases(),
 				err:             errTestFilter,
 			},
-			mockInsertBrowserReleasesCfg: nil,
-			expectedErr:                  errTestFilter,
+			mockInsertBrowserReleasesCfg:       nil,
+			mockFilterCompatFeaturesCfg:        nil,
+			mockUpsertCompatFeatureSupportsCfg: nil,
+			expectedErr:                        errTestFilter,
 		},
 		{
 			name: "failed to store data",
@@ -433,7 +502,84 @@ func TestProcess(t *testing.T) {
 				expectedReleases: getSampleReleases(),
 				err:              errTestInsert,
 			},
-			expectedErr: errTestInsert,
+			mockFilterCompatFeaturesCfg:        nil,
+			mockUpsertCompatFeatureSupportsCfg: nil,
+			expectedErr:                        errTestInsert,
+		},
+		{
+			name: "failed to filter compat features",
+			job: JobArguments{
+				browsers: []string{"fooBrowser", "barBrowser"},
+			},
+			mockDownloadFileFromReleaseCfg: &mockDownloadFileFromReleaseConfig{
+				repoOwner:   repoOwner,
+				repoName:    repoName,
+				filePattern: filePattern,
+				fakeFile:    io.NopCloser(strings.NewReader("success")),
+				err:         nil,
+			},
+			mockParseCfg: &mockParseConfig{
+				expectedFileContents: "success",
+				ret:                  getSampleBCDData(),
+				err:                  nil,
+			},
+			mockFilterDataCfg: &mockFilterDataConfig{
+				expectedData:    getSampleBCDData(),
+				expectedFilters: []string{"fooBrowser", "barBrowser"},
+				retReleases:     getSampleReleases(),
+				err:             nil,
+			},
+			mockInsertBrowserReleasesCfg: &mockInsertBrowserReleasesConfig{
+				expectedReleases: getSampleReleases(),
+				err:              nil,
+			},
+			mockFilterCompatFeaturesCfg: &mockFilterCompatFeaturesConfig{
+				expectedData:    getSampleBCDData(),
+				expectedFilters: []string{"fooBrowser", "barBrowser"},
+				retSupports:     getSampleCompatFeatureSupports(),
+				err:             errTestFilterCompatFeatures,
+			},
+			mockUpsertCompatFeatureSupportsCfg: nil,
+			expectedErr:                        errTestFilterCompatFeatures,
+		},
+		{
+			name: "failed to store compat features",
+			job: JobArguments{
+				browsers: []string{"fooBrowser", "barBrowser"},
+			},
+			mockDownloadFileFromReleaseCfg: &mockDownloadFileFromReleaseConfig{
+				repoOwner:   repoOwner,
+				repoName:    repoName,
+				filePattern: filePattern,
+				fakeFile:    io.NopCloser(strings.NewReader("success")),
+				err:         nil,
+			},
+			mockParseCfg: &mockParseConfig{
+				expectedFileContents: "success",
+				ret:                  getSampleBCDData(),
+				err:                  nil,
+			},
+			mockFilterDataCfg: &mockFilterDataConfig{
+				expectedData:    getSampleBCDData(),
+				expectedFilters: []string{"fooBrowser", "barBrowser"},
+				retReleases:     getSampleReleases(),
+				err:             nil,
+			},
+			mockInsertBrowserReleasesCfg: &mockInsertBrowserReleasesConfig{
+				expectedReleases: getSampleReleases(),
+				err:              nil,
+			},
+			mockFilterCompatFeaturesCfg: &mockFilterCompatFeaturesConfig{
+				expectedData:    getSampleBCDData(),
+				expectedFilters: []string{"fooBrowser", "barBrowser"},
+				retSupports:     getSampleCompatFeatureSupports(),
+				err:             nil,
+			},
+			mockUpsertCompatFeatureSupportsCfg: &mockUpsertCompatFeatureSupportsConfig{
+				expectedSupports: getSampleCompatFeatureSupports(),
+				err:              errTestUpsertCompatFeatureSupports,
+			},
+			expectedErr: errTestUpsertCompatFeatureSupports,
 		},
 	}
 	for _, tc := range testCases {
@@ -449,12 +595,14 @@ func TestProcess(t *testing.T) {
 					mockParseCfg: tc.mockParseCfg,
 				},
 				&MockDataFilter{
-					t:                 t,
-					mockFilterDataCfg: tc.mockFilterDataCfg,
+					t:                           t,
+					mockFilterDataCfg:           tc.mockFilterDataCfg,
+					mockFilterCompatFeaturesCfg: tc.mockFilterCompatFeaturesCfg,
 				},
 				&MockDataStorer{
-					t:                            t,
-					mockInsertBrowserReleasesCfg: tc.mockInsertBrowserReleasesCfg,
+					t:                                  t,
+					mockInsertBrowserReleasesCfg:       tc.mockInsertBrowserReleasesCfg,
+					mockUpsertCompatFeatureSupportsCfg: tc.mockUpsertCompatFeatureSupportsCfg,
 				},
 				repoOwner,
 				repoName,
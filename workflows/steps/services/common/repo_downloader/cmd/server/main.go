@@ -22,41 +22,60 @@ import (
 	"os"
 
 	"cloud.google.com/go/storage"
+	"github.com/GoogleChrome/webstatus.dev/lib/config"
 	"github.com/GoogleChrome/webstatus.dev/lib/gcs"
+	"github.com/GoogleChrome/webstatus.dev/lib/healthcheck"
+	"github.com/GoogleChrome/webstatus.dev/lib/lifecycle"
 	"github.com/GoogleChrome/webstatus.dev/workflows/steps/services/common/repo_downloader/pkg/gh"
 	"github.com/GoogleChrome/webstatus.dev/workflows/steps/services/common/repo_downloader/pkg/httpserver"
 	"google.golang.org/api/option"
 )
 
+// serverConfig contains the settings of the repo downloader.
+type serverConfig struct {
+	GitHub          config.GitHub
+	Bucket          string `env:"BUCKET" required:"true" usage:"Bucket to store the downloaded repositories in"`
+	StorageEmulator string `env:"STORAGE_EMULATOR_HOST" usage:"Cloud Storage emulator host, for local runs"`
+}
+
 func main() {
+	cfg := serverConfig{
+		GitHub:          config.GitHub{Token: ""},
+		Bucket:          "",
+		StorageEmulator: "",
+	}
+	config.MustLoad(&cfg)
+
+	ctx, stop := lifecycle.SignalContext(context.Background())
+	defer stop()
 
-	token := os.Getenv("GITHUB_TOKEN")
-	ghClient := gh.NewClient(token)
+	ghClient := gh.NewClient(cfg.GitHub.Token)
 	ghDownloader := gh.NewDownloader(ghClient, http.DefaultClient)
-	bucket := os.Getenv("BUCKET")
-	storageEmulator := os.Getenv("STORAGE_EMULATOR_HOST")
 	var options []option.ClientOption
-	if storageEmulator != "" {
-		options = append(options, option.WithEndpoint(storageEmulator+"/storage/v1/"))
+	if cfg.StorageEmulator != "" {
+		options = append(options, option.WithEndpoint(cfg.StorageEmulator+"/storage/v1/"))
 	}
-	client, err := storage.NewClient(context.TODO(), options...)
+	client, err := storage.NewClient(ctx, options...)
 	if err != nil {
 		log.Fatalf("failed to create client: %v", err)
 	}
-	storer, err := gcs.NewClient(client, bucket)
+	storer, err := gcs.NewClient(client, cfg.Bucket)
 	if err != nil {
 		slog.Error("unable to create gcs client", "error", err.Error())
 		os.Exit(1)
 	}
 
-	srv, err := httpserver.NewHTTPServer("8080", ghDownloader, storer)
+	srv, err := httpserver.NewHTTPServer("8080", ghDownloader, storer, []healthcheck.Dependency{
+		{Name: "gcs", Check: storer.Ping},
+	})
 	if err != nil {
 		slog.Error("unable to create server", "error", err.Error())
 		os.Exit(1)
 	}
-	err = srv.ListenAndServe()
+	err = lifecycle.ListenAndServe(ctx, srv, lifecycle.DefaultShutdownTimeout,
+		lifecycle.NewCloser("gcs", client.Close))
 	if err != nil {
-		slog.Error("unable to start server", "error", err.Error())
+		slog.Error("server stopped with error", "error", err.Error())
 		os.Exit(1)
 	}
 }
@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/workflows/steps/common/repo_downloader"
+	"github.com/GoogleChrome/webstatus.dev/lib/healthcheck"
 	"github.com/GoogleChrome/webstatus.dev/workflows/steps/services/common/repo_downloader/pkg/filefilter"
 	"github.com/GoogleChrome/webstatus.dev/workflows/steps/services/common/repo_downloader/pkg/gh"
 	"github.com/GoogleChrome/webstatus.dev/workflows/steps/services/common/repo_downloader/pkg/targz"
@@ -123,7 +124,12 @@ func (s *Server) PostV1GithubComOwnerName(ctx context.Context,
 	}, nil
 }
 
-func NewHTTPServer(port string, downloader *gh.Downloader, storer Storer) (*http.Server, error) {
+func NewHTTPServer(
+	port string,
+	downloader *gh.Downloader,
+	storer Storer,
+	readinessDependencies []healthcheck.Dependency,
+) (*http.Server, error) {
 	_, err := repo_downloader.GetSwagger()
 	if err != nil {
 		return nil, fmt.Errorf("error loading swagger spec. %w", err)
@@ -149,6 +155,10 @@ func NewHTTPServer(port string, downloader *gh.Downloader, storer Storer) (*http
 	// We now register our repo downloader above as the handler for the interface
 	repo_downloader.HandlerFromMux(srvStrictHandler, r)
 
+	r.Method(http.MethodGet, "/healthz", healthcheck.NewLivenessHandler())
+	r.Method(http.MethodGet, "/readyz", healthcheck.NewReadinessHandler(
+		healthcheck.DefaultCheckTimeout, readinessDependencies))
+
 	// nolint:exhaustruct // No need to populate 3rd party struct
 	return &http.Server{
 		Handler:           r,
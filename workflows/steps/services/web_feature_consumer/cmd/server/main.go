@@ -15,56 +15,101 @@
 package main
 
 import (
+	"context"
 	"log/slog"
+	"net"
 	"os"
+	"time"
 
+	"github.com/GoogleChrome/webstatus.dev/lib/config"
 	"github.com/GoogleChrome/webstatus.dev/lib/gcpspanner"
 	"github.com/GoogleChrome/webstatus.dev/lib/gcpspanner/spanneradapters"
 	"github.com/GoogleChrome/webstatus.dev/lib/gds"
 	"github.com/GoogleChrome/webstatus.dev/lib/gds/datastoreadapters"
 	"github.com/GoogleChrome/webstatus.dev/lib/gh"
+	"github.com/GoogleChrome/webstatus.dev/lib/healthcheck"
+	"github.com/GoogleChrome/webstatus.dev/lib/lifecycle"
+	"github.com/GoogleChrome/webstatus.dev/lib/webhooks"
 	"github.com/GoogleChrome/webstatus.dev/workflows/steps/services/web_feature_consumer/pkg/httpserver"
 )
 
+// serverConfig contains the settings of the web features consumer.
+type serverConfig struct {
+	config.GCP
+	Spanner   config.Spanner
+	Datastore config.Datastore
+	GitHub    config.GitHub
+	Webhooks  config.Webhooks
+}
+
+// webhookTimeout limits how long a single delivery attempt can take.
+const webhookTimeout = 10 * time.Second
+
 func main() {
-	var datastoreDB *string
-	if value, found := os.LookupEnv("DATASTORE_DATABASE"); found {
-		datastoreDB = &value
+	cfg := serverConfig{
+		GCP:       config.GCP{ProjectID: ""},
+		Spanner:   config.Spanner{Instance: "", Database: "", EmulatorHost: ""},
+		Datastore: config.Datastore{Database: nil},
+		GitHub:    config.GitHub{Token: ""},
+		Webhooks:  config.Webhooks{AllowLoopback: false},
 	}
-	fs, err := gds.NewDatastoreClient(os.Getenv("PROJECT_ID"), datastoreDB)
+	config.MustLoad(&cfg)
+
+	ctx, stop := lifecycle.SignalContext(context.Background())
+	defer stop()
+
+	fs, err := gds.NewDatastoreClient(cfg.ProjectID, cfg.Datastore.Database)
 	if err != nil {
 		slog.Error("failed to create datastore client", "error", err.Error())
 		os.Exit(1)
 	}
 
-	projectID := os.Getenv("PROJECT_ID")
-	spannerDB := os.Getenv("SPANNER_DATABASE")
-	spannerInstance := os.Getenv("SPANNER_INSTANCE")
-	spannerClient, err := gcpspanner.NewSpannerClient(projectID, spannerInstance, spannerDB)
+	spannerClient, err := gcpspanner.NewSpannerClient(cfg.ProjectID, cfg.Spanner.Instance, cfg.Spanner.Database)
 	if err != nil {
 		slog.Error("failed to create spanner client", "error", err.Error())
 		os.Exit(1)
 	}
 
-	// Will be empty if not set and that is okay.
-	token := os.Getenv("GITHUB_TOKEN")
+	notifier := webhooks.NewNotifier(
+		spanneradapters.NewWebhooks(spannerClient),
+		datastoreadapters.NewWebhooks(fs),
+		webhooks.NewDeliverer(
+			webhooks.NewCallbackPolicy(net.DefaultResolver, cfg.Webhooks.AllowLoopback).NewHTTPClient(webhookTimeout),
+			webhooks.DefaultMaxAttempts,
+			webhooks.DefaultInitialBackoff,
+		),
+		webhooks.DefaultMaxConcurrentDeliveries,
+		webhooks.DefaultDeliveryDeadline,
+	)
 
 	srv, err := httpserver.NewHTTPServer(
 		"8080",
-		gh.NewClient(token),
+		gh.NewClient(cfg.GitHub.Token),
 		spanneradapters.NewWebFeaturesConsumer(spannerClient),
 		datastoreadapters.NewWebFeaturesConsumer(fs),
+		notifier,
 		"data.json",
 		"web-platform-dx",
 		"web-features",
+		[]healthcheck.Dependency{
+			{Name: "spanner", Check: spannerClient.Ping},
+			{Name: "datastore", Check: fs.Ping},
+		},
 	)
 	if err != nil {
 		slog.Error("unable to create server", "error", err.Error())
 		os.Exit(1)
 	}
-	err = srv.ListenAndServe()
+	err = lifecycle.ListenAndServe(ctx, srv, lifecycle.DefaultShutdownTimeout,
+		lifecycle.NewCloser("spanner", func() error {
+			spannerClient.Close()
+
+			return nil
+		}),
+		lifecycle.NewCloser("datastore", fs.Close),
+	)
 	if err != nil {
-		slog.Error("unable to start server", "error", err.Error())
+		slog.Error("server stopped with error", "error", err.Error())
 		os.Exit(1)
 	}
 }
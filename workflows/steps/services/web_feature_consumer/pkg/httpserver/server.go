@@ -25,6 +25,7 @@ import (
 
 	"github.com/GoogleChrome/webstatus.dev/lib/gen/jsonschema/web_platform_dx__web_features"
 	"github.com/GoogleChrome/webstatus.dev/lib/gen/openapi/workflows/steps/web_feature_consumer"
+	"github.com/GoogleChrome/webstatus.dev/lib/healthcheck"
 	"github.com/GoogleChrome/webstatus.dev/workflows/steps/services/web_feature_consumer/pkg/data"
 	"github.com/go-chi/chi/v5"
 )
@@ -59,10 +60,16 @@ type WebFeatureMetadataStorer interface {
 		data map[string]web_platform_dx__web_features.FeatureData) error
 }
 
+// FeatureChangeNotifier notifies the subscriptions about the changes made by the ingestion.
+type FeatureChangeNotifier interface {
+	NotifyFeatureChanges(ctx context.Context) error
+}
+
 type Server struct {
 	assetGetter           AssetGetter
 	storer                WebFeatureStorer
 	metadataStorer        WebFeatureMetadataStorer
+	notifier              FeatureChangeNotifier
 	webFeaturesDataParser AssetParser
 	defaultAssetName      string
 	defaultRepoOwner      string
@@ -120,6 +127,13 @@ func (s *Server) PostV1WebFeatures(
 		}, nil
 	}
 
+	// The data was stored successfully. Failed notifications should not cause the ingestion to be retried.
+	// The notifier delivers concurrently and gives up after its deadline so that slow callbacks cannot hold the request.
+	err = s.notifier.NotifyFeatureChanges(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "unable to notify subscriptions", "error", err)
+	}
+
 	return web_feature_consumer.PostV1WebFeatures200Response{}, nil
 }
 
@@ -128,9 +142,11 @@ func NewHTTPServer(
 	assetGetter AssetGetter,
 	storer WebFeatureStorer,
 	metadataStorer WebFeatureMetadataStorer,
+	notifier FeatureChangeNotifier,
 	defaultAssetName string,
 	defaultRepoOwner string,
 	defaultRepoName string,
+	readinessDependencies []healthcheck.Dependency,
 ) (*http.Server, error) {
 	_, err := web_feature_consumer.GetSwagger()
 	if err != nil {
@@ -142,6 +158,7 @@ func NewHTTPServer(
 		assetGetter:           assetGetter,
 		storer:                storer,
 		metadataStorer:        metadataStorer,
+		notifier:              notifier,
 		webFeaturesDataParser: data.Parser{},
 		defaultAssetName:      defaultAssetName,
 		defaultRepoOwner:      defaultRepoOwner,
@@ -162,6 +179,10 @@ func NewHTTPServer(
 	// We now register our web feature router above as the handler for the interface
 	web_feature_consumer.HandlerFromMux(srvStrictHandler, r)
 
+	r.Method(http.MethodGet, "/healthz", healthcheck.NewLivenessHandler())
+	r.Method(http.MethodGet, "/readyz", healthcheck.NewReadinessHandler(
+		healthcheck.DefaultCheckTimeout, readinessDependencies))
+
 	// nolint:exhaustruct // No need to populate 3rd party struct
 	return &http.Server{
 		Handler:           r,
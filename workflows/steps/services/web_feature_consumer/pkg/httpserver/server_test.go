@@ -118,6 +118,17 @@ func (m *mockWebFeatureMetadataStorer) InsertWebFeaturesMetadata(
 	return m.mockInsertWebFeaturesMetadataCfg.returnError
 }
 
+type mockFeatureChangeNotifier struct {
+	callCount int
+	err       error
+}
+
+func (m *mockFeatureChangeNotifier) NotifyFeatureChanges(_ context.Context) error {
+	m.callCount++
+
+	return m.err
+}
+
 const (
 	testRepoOwner = "owner"
 	testRepoName  = "name"
@@ -131,6 +142,8 @@ func TestPostV1WebFeatures(t *testing.T) {
 		mockParseCfg                     mockParseConfig
 		mockInsertWebFeaturesCfg         mockInsertWebFeaturesConfig
 		mockInsertWebFeaturesMetadataCfg mockInsertWebFeaturesMetadataConfig
+		notifyErr                        error
+		expectedNotifications            int
 		expectedResponse                 web_feature_consumer.PostV1WebFeaturesResponseObject
 	}{
 		{
@@ -197,7 +210,77 @@ func TestPostV1WebFeatures(t *testing.T) {
 				},
 				returnError: nil,
 			},
-			expectedResponse: web_feature_consumer.PostV1WebFeatures200Response{},
+			notifyErr:             nil,
+			expectedNotifications: 1,
+			expectedResponse:      web_feature_consumer.PostV1WebFeatures200Response{},
+		},
+		{
+			name: "notification failure does not fail the ingestion",
+			mockDownloadFileFromReleaseCfg: mockDownloadFileFromReleaseConfig{
+				expectedOwner:    testRepoOwner,
+				expectedRepo:     testRepoName,
+				expectedFileName: testFileName,
+				returnReadCloser: io.NopCloser(strings.NewReader("hi features")),
+				returnError:      nil,
+			},
+			mockParseCfg: mockParseConfig{
+				expectedFileContents: "hi features",
+				returnData: map[string]web_platform_dx__web_features.FeatureData{
+					"feature1": {
+						Name:            "Feature 1",
+						Alias:           nil,
+						Caniuse:         nil,
+						CompatFeatures:  nil,
+						Spec:            nil,
+						Status:          nil,
+						UsageStats:      nil,
+						Description:     "text",
+						DescriptionHTML: "<html>",
+					},
+				},
+				returnError: nil,
+			},
+			mockInsertWebFeaturesCfg: mockInsertWebFeaturesConfig{
+				expectedData: map[string]web_platform_dx__web_features.FeatureData{
+					"feature1": {
+						Name:            "Feature 1",
+						Alias:           nil,
+						Caniuse:         nil,
+						CompatFeatures:  nil,
+						Spec:            nil,
+						Status:          nil,
+						UsageStats:      nil,
+						Description:     "text",
+						DescriptionHTML: "<html>",
+					},
+				},
+				returnedMapping: map[string]string{
+					"feature1": "id-1",
+				},
+				returnError: nil,
+			},
+			mockInsertWebFeaturesMetadataCfg: mockInsertWebFeaturesMetadataConfig{
+				expectedData: map[string]web_platform_dx__web_features.FeatureData{
+					"feature1": {
+						Name:            "Feature 1",
+						Alias:           nil,
+						Caniuse:         nil,
+						CompatFeatures:  nil,
+						Spec:            nil,
+						Status:          nil,
+						UsageStats:      nil,
+						Description:     "text",
+						DescriptionHTML: "<html>",
+					},
+				},
+				expectedMapping: map[string]string{
+					"feature1": "id-1",
+				},
+				returnError: nil,
+			},
+			notifyErr:             errors.New("uh-oh"),
+			expectedNotifications: 1,
+			expectedResponse:      web_feature_consumer.PostV1WebFeatures200Response{},
 		},
 		{
 			name: "fail to get asset data",
@@ -223,6 +306,8 @@ func TestPostV1WebFeatures(t *testing.T) {
 				expectedMapping: nil,
 				returnError:     nil,
 			},
+			notifyErr:             nil,
+			expectedNotifications: 0,
 			expectedResponse: web_feature_consumer.PostV1WebFeatures500JSONResponse{
 				Code:    500,
 				Message: "unable to get asset",
@@ -264,6 +349,8 @@ func TestPostV1WebFeatures(t *testing.T) {
 				expectedMapping: nil,
 				returnError:     nil,
 			},
+			notifyErr:             nil,
+			expectedNotifications: 0,
 			expectedResponse: web_feature_consumer.PostV1WebFeatures500JSONResponse{
 				Code:    500,
 				Message: "unable to parse data",
@@ -319,6 +406,8 @@ func TestPostV1WebFeatures(t *testing.T) {
 				expectedMapping: nil,
 				returnError:     nil,
 			},
+			notifyErr:             nil,
+			expectedNotifications: 0,
 			expectedResponse: web_feature_consumer.PostV1WebFeatures500JSONResponse{
 				Code:    500,
 				Message: "unable to store data",
@@ -388,6 +477,8 @@ func TestPostV1WebFeatures(t *testing.T) {
 				},
 				returnError: errors.New("uh-oh"),
 			},
+			notifyErr:             nil,
+			expectedNotifications: 0,
 			expectedResponse: web_feature_consumer.PostV1WebFeatures500JSONResponse{
 				Code:    500,
 				Message: "unable to store metadata",
@@ -413,10 +504,13 @@ func TestPostV1WebFeatures(t *testing.T) {
 				mockInsertWebFeaturesMetadataCfg: tc.mockInsertWebFeaturesMetadataCfg,
 			}
 
+			mockNotifier := &mockFeatureChangeNotifier{callCount: 0, err: tc.notifyErr}
+
 			server := &Server{
 				assetGetter:           mockGetter,
 				storer:                mockStorer,
 				metadataStorer:        mockMetadataStorer,
+				notifier:              mockNotifier,
 				webFeaturesDataParser: mockParser,
 				defaultAssetName:      testFileName,
 				defaultRepoOwner:      testRepoOwner,
@@ -432,6 +526,9 @@ func TestPostV1WebFeatures(t *testing.T) {
 			if !reflect.DeepEqual(tc.expectedResponse, response) {
 				t.Error("unexpected response")
 			}
+			if mockNotifier.callCount != tc.expectedNotifications {
+				t.Errorf("expected %d notifications, received %d", tc.expectedNotifications, mockNotifier.callCount)
+			}
 		})
 	}
 }
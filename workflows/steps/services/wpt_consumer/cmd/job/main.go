@@ -15,17 +15,21 @@
 package main
 
 import (
-	"cmp"
 	"context"
+	"fmt"
 	"log/slog"
+	"net"
 	"os"
-	"strconv"
 	"time"
 
+	"github.com/GoogleChrome/webstatus.dev/lib/config"
 	"github.com/GoogleChrome/webstatus.dev/lib/gcpspanner"
 	"github.com/GoogleChrome/webstatus.dev/lib/gcpspanner/spanneradapters"
 	"github.com/GoogleChrome/webstatus.dev/lib/gds"
+	"github.com/GoogleChrome/webstatus.dev/lib/gds/datastoreadapters"
+	"github.com/GoogleChrome/webstatus.dev/lib/lifecycle"
 	"github.com/GoogleChrome/webstatus.dev/lib/localcache"
+	"github.com/GoogleChrome/webstatus.dev/lib/webhooks"
 	"github.com/GoogleChrome/webstatus.dev/lib/workerpool"
 	"github.com/GoogleChrome/webstatus.dev/lib/wptfyi"
 	"github.com/GoogleChrome/webstatus.dev/workflows/steps/services/wpt_consumer/pkg/workflow"
@@ -33,42 +37,55 @@ import (
 	"github.com/web-platform-tests/wpt.fyi/shared"
 )
 
-func main() {
-	ctx := context.Background()
+// jobConfig contains the settings of the WPT consumer job.
+type jobConfig struct {
+	config.GCP
+	Spanner            config.Spanner
+	Datastore          config.Datastore
+	Webhooks           config.Webhooks
+	WPTFyiHostname     string        `env:"WPT_FYI_HOSTNAME" usage:"wpt.fyi host to read runs from"`
+	WPTFyiPageLimit    int           `env:"WPT_FYI_PAGE_LIMIT" usage:"Number of runs to request per page"`
+	DataWindowDuration time.Duration `env:"DATA_WINDOW_DURATION" required:"true" usage:"How far back to ingest runs"`
+}
+
+func (c *jobConfig) Validate() []error {
+	if c.WPTFyiPageLimit <= 0 || c.WPTFyiPageLimit > shared.MaxCountMaxValue {
+		return []error{fmt.Errorf("WPT_FYI_PAGE_LIMIT must be between 1 and %d", shared.MaxCountMaxValue)}
+	}
 
-	// Configuration and Client Setup
+	return nil
+}
 
-	// wpt.fyi setup
-	wptFyiHostname := cmp.Or[string](os.Getenv("WPT_FYI_HOSTNAME"), "wpt.fyi")
+// webhookTimeout limits how long a single delivery attempt can take.
+const webhookTimeout = 10 * time.Second
 
-	wptPageLimitStr := os.Getenv("WPT_FYI_PAGE_LIMIT")
-	wptPageLimit := shared.MaxCountMaxValue
-	if wptPageLimitStr != "" {
-		var parseErr error
-		wptPageLimit, parseErr = strconv.Atoi(wptPageLimitStr)
-		if parseErr != nil {
-			slog.Error("unable to parse custom page limit", "input", wptPageLimitStr)
-			os.Exit(1)
-		}
+func main() {
+	cfg := jobConfig{
+		GCP:                config.GCP{ProjectID: ""},
+		Spanner:            config.Spanner{Instance: "", Database: "", EmulatorHost: ""},
+		Datastore:          config.Datastore{Database: nil},
+		Webhooks:           config.Webhooks{AllowLoopback: false},
+		WPTFyiHostname:     "wpt.fyi",
+		WPTFyiPageLimit:    shared.MaxCountMaxValue,
+		DataWindowDuration: 0,
 	}
+	config.MustLoad(&cfg)
+
+	// Canceling the context stops the workers from starting new work on SIGTERM.
+	ctx, stop := lifecycle.SignalContext(context.Background())
+	defer stop()
+
+	// Client Setup
 
 	// Datastore setup
-	var datastoreDB *string
-	if value, found := os.LookupEnv("DATASTORE_DATABASE"); found {
-		datastoreDB = &value
-	}
-	projectID := os.Getenv("PROJECT_ID")
-	dsClient, err := gds.NewDatastoreClient(projectID, datastoreDB)
+	dsClient, err := gds.NewDatastoreClient(cfg.ProjectID, cfg.Datastore.Database)
 	if err != nil {
 		slog.Error("failed to create datastore client", "error", err.Error())
 		os.Exit(1)
 	}
-	_ = dsClient
 
 	// Spanner setup
-	spannerDB := os.Getenv("SPANNER_DATABASE")
-	spannerInstance := os.Getenv("SPANNER_INSTANCE")
-	spannerClient, err := gcpspanner.NewSpannerClient(projectID, spannerInstance, spannerDB)
+	spannerClient, err := gcpspanner.NewSpannerClient(cfg.ProjectID, cfg.Spanner.Instance, cfg.Spanner.Database)
 	if err != nil {
 		slog.Error("failed to create spanner client", "error", err.Error())
 		os.Exit(1)
@@ -78,18 +95,11 @@ func main() {
 	// Currently, keep it at 8. 4 browsers, 2 channels each.
 	numWorkers := 8
 
-	dataWindowDuration := os.Getenv("DATA_WINDOW_DURATION")
-	duration, err := time.ParseDuration(dataWindowDuration)
-	if err != nil {
-		slog.Error("unable to parse DATA_WINDOW_DURATION duration", "input value", dataWindowDuration)
-		os.Exit(1)
-	}
-
 	// Worker Pool Setup
 	pool := workerpool.Pool[workflow.JobArguments]{}
 
 	worker := workflow.NewWptRunsWorker(
-		wptfyi.NewHTTPClient(wptFyiHostname),
+		wptfyi.NewHTTPClient(cfg.WPTFyiHostname),
 		workflow.NewWPTRunsProcessor(
 			workflow.NewWPTRunProcessor(
 				workflow.NewHTTPResultsGetter(),
@@ -105,7 +115,7 @@ func main() {
 	// Job Generation
 	jobChan := make(chan workflow.JobArguments)
 	go func() {
-		startAt := time.Now().UTC().Add(-duration)
+		startAt := time.Now().UTC().Add(-cfg.DataWindowDuration)
 		browsers := shared.GetDefaultBrowserNames()
 		channels := []string{shared.StableLabel, shared.ExperimentalLabel}
 		for _, browser := range browsers {
@@ -114,10 +124,16 @@ func main() {
 					startAt,
 					browser,
 					channel,
-					wptPageLimit,
+					cfg.WPTFyiPageLimit,
 				)
 				slog.Info("sending args to worker pool", "args", args)
-				jobChan <- args
+				select {
+				case jobChan <- args:
+				case <-ctx.Done():
+					close(jobChan)
+
+					return
+				}
 			}
 		}
 		// Close the job channel now that we are done.
@@ -126,8 +142,36 @@ func main() {
 
 	// Job Execution and Error Handling
 	errs := pool.Start(ctx, jobChan, numWorkers, worker)
+	if len(errs) == 0 {
+		notifier := webhooks.NewNotifier(
+			spanneradapters.NewWebhooks(spannerClient),
+			datastoreadapters.NewWebhooks(dsClient),
+			webhooks.NewDeliverer(
+				webhooks.NewCallbackPolicy(net.DefaultResolver, cfg.Webhooks.AllowLoopback).NewHTTPClient(webhookTimeout),
+				webhooks.DefaultMaxAttempts,
+				webhooks.DefaultInitialBackoff,
+			),
+			webhooks.DefaultMaxConcurrentDeliveries,
+			webhooks.DefaultDeliveryDeadline,
+		)
+		// The scores were stored. A failed notification does not fail the job.
+		if err := notifier.NotifyFeatureChanges(ctx); err != nil {
+			slog.Error("unable to notify subscriptions", "error", err)
+		}
+	}
+	closeErr := lifecycle.Close(lifecycle.DefaultShutdownTimeout,
+		lifecycle.NewCloser("spanner", func() error {
+			spannerClient.Close()
+
+			return nil
+		}),
+		lifecycle.NewCloser("datastore", dsClient.Close),
+	)
 	if len(errs) > 0 {
 		slog.Error("workflow returned errors", "error", errs)
 		os.Exit(1)
 	}
+	if closeErr != nil {
+		os.Exit(1)
+	}
 }
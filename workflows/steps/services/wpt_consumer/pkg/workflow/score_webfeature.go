@@ -15,6 +15,7 @@
 package workflow
 
 import (
+	"cmp"
 	"context"
 	"slices"
 	"strings"
@@ -165,16 +166,7 @@ func (s ResultsSummaryFileV2) scoreTest(
 		return
 	}
 	// Calculate the value early so we can re-use for multiple web features.
-	// Logic for zero subtests
-	var countsAsPassing bool
-	if numberofSubtests == 0 {
-		// Determine the appropriate logic based on the status, as done in JavaScript
-		if WPTStatusAbbreviation(testStatus) == WPTStatusOK || WPTStatusAbbreviation(testStatus) == WPTStatusPass {
-			countsAsPassing = true // Treat as passing if status is OK or Pass
-		}
-	} else {
-		countsAsPassing = numberOfSubtestPassing == numberofSubtests
-	}
+	countsAsPassing := testCountsAsPassing(numberOfSubtestPassing, numberofSubtests, testStatus)
 	for webFeature := range webFeatures {
 		webFeatureScore := getScoreForFeature(webFeature, webFeatureScoreMap)
 		*webFeatureScore.TotalTests++
@@ -185,3 +177,68 @@ func (s ResultsSummaryFileV2) scoreTest(
 		webFeatureScoreMap[webFeature] = webFeatureScore
 	}
 }
+
+// testCountsAsPassing determines if a test as a whole is passing.
+func testCountsAsPassing(numberOfSubtestPassing int, numberofSubtests int, testStatus string) bool {
+	// Logic for zero subtests
+	if numberofSubtests == 0 {
+		// Determine the appropriate logic based on the status, as done in JavaScript
+		// Treat as passing if status is OK or Pass
+		return WPTStatusAbbreviation(testStatus) == WPTStatusOK || WPTStatusAbbreviation(testStatus) == WPTStatusPass
+	}
+
+	return numberOfSubtestPassing == numberofSubtests
+}
+
+// TestResults returns the individual test results from a V2 results summary
+// file, grouped by web feature. It follows the same rules as Score so that the
+// results can be compared across browsers.
+func (s ResultsSummaryFileV2) TestResults(
+	_ context.Context,
+	testToWebFeatures *shared.WebFeaturesData) map[string][]wptconsumertypes.WPTTestResult {
+	resultsMap := make(map[string][]wptconsumertypes.WPTTestResult)
+	for test, testSummary := range s {
+		if len(testSummary.Counts) < 2 {
+			// Need at least the number of subtests passes and the number of subtests
+			continue
+		}
+		if isTestTentative(test) {
+			continue
+		}
+		webFeatures, found := (*testToWebFeatures)[test]
+		if !found {
+			continue
+		}
+		numberOfSubtestPassing, numberofSubtests := testSummary.Counts[0], testSummary.Counts[1]
+		result := wptconsumertypes.WPTTestResult{
+			TestName:      test,
+			Passed:        testCountsAsPassing(numberOfSubtestPassing, numberofSubtests, testSummary.Status),
+			SubtestPass:   nil,
+			TotalSubtests: nil,
+		}
+		// Same as scoreSubtests, the subtest counts are not reliable for crashes.
+		if WPTStatusAbbreviation(testSummary.Status) != WPTStatusCrash {
+			if numberofSubtests == 0 {
+				numberofSubtests = 1
+				numberOfSubtestPassing = 0
+				if result.Passed {
+					numberOfSubtestPassing = 1
+				}
+			}
+			subtestPass, totalSubtests := int64(numberOfSubtestPassing), int64(numberofSubtests)
+			result.SubtestPass = &subtestPass
+			result.TotalSubtests = &totalSubtests
+		}
+		for webFeature := range webFeatures {
+			resultsMap[webFeature] = append(resultsMap[webFeature], result)
+		}
+	}
+	// Keep the results in a stable order.
+	for webFeature := range resultsMap {
+		slices.SortFunc(resultsMap[webFeature], func(a, b wptconsumertypes.WPTTestResult) int {
+			return cmp.Compare(a.TestName, b.TestName)
+		})
+	}
+
+	return resultsMap
+}
@@ -254,3 +254,98 @@ func TestScore(t *testing.T) {
 		})
 	}
 }
+
+func TestTestResults(t *testing.T) {
+	webFeaturesData := shared.WebFeaturesData{
+		"test1.html": {
+			"feature1": nil,
+			"feature2": nil,
+		},
+		"test2-not-passing.html": {
+			"feature2": nil,
+		},
+		"test3-no-subtests.html": {
+			"feature1": nil,
+		},
+		"test4-crash.html": {
+			"feature2": nil,
+		},
+		"test5.tentative.html": {
+			"feature1": nil,
+		},
+		"malformed-counts-test.html": {
+			"feature1": nil,
+		},
+	}
+	summary := ResultsSummaryFileV2{
+		"test1.html": query.SummaryResult{
+			Status: string(WPTStatusPass),
+			Counts: []int{5, 5},
+		},
+		"test2-not-passing.html": query.SummaryResult{
+			Status: string(WPTStatusFail),
+			Counts: []int{1, 11},
+		},
+		"test3-no-subtests.html": query.SummaryResult{
+			Status: string(WPTStatusOK),
+			Counts: []int{0, 0},
+		},
+		"test4-crash.html": query.SummaryResult{
+			Status: string(WPTStatusCrash),
+			Counts: []int{100, 100},
+		},
+		"test5.tentative.html": query.SummaryResult{
+			Status: string(WPTStatusPass),
+			Counts: []int{1, 1},
+		},
+		"malformed-counts-test.html": query.SummaryResult{
+			Status: string(WPTStatusPass),
+			Counts: []int{1000},
+		},
+		"no-webfeatures-mapping-test.html": query.SummaryResult{
+			Status: string(WPTStatusPass),
+			Counts: []int{1000, 1000},
+		},
+	}
+	expectedOutput := map[string][]wptconsumertypes.WPTTestResult{
+		"feature1": {
+			{
+				TestName:      "test1.html",
+				Passed:        true,
+				SubtestPass:   valuePtr[int64](5),
+				TotalSubtests: valuePtr[int64](5),
+			},
+			{
+				TestName:      "test3-no-subtests.html",
+				Passed:        true,
+				SubtestPass:   valuePtr[int64](1),
+				TotalSubtests: valuePtr[int64](1),
+			},
+		},
+		"feature2": {
+			{
+				TestName:      "test1.html",
+				Passed:        true,
+				SubtestPass:   valuePtr[int64](5),
+				TotalSubtests: valuePtr[int64](5),
+			},
+			{
+				TestName:      "test2-not-passing.html",
+				Passed:        false,
+				SubtestPass:   valuePtr[int64](1),
+				TotalSubtests: valuePtr[int64](11),
+			},
+			{
+				TestName:      "test4-crash.html",
+				Passed:        true,
+				SubtestPass:   nil,
+				TotalSubtests: nil,
+			},
+		},
+	}
+
+	output := summary.TestResults(context.Background(), &webFeaturesData)
+	if !reflect.DeepEqual(expectedOutput, output) {
+		t.Errorf("unexpected test results\nexpected %v\nreceived %v", expectedOutput, output)
+	}
+}
@@ -24,6 +24,7 @@ import (
 // ResultsSummaryFile contains the results of a given file format.
 type ResultsSummaryFile interface {
 	Score(context.Context, *shared.WebFeaturesData) map[string]wptconsumertypes.WPTFeatureMetric
+	TestResults(context.Context, *shared.WebFeaturesData) map[string][]wptconsumertypes.WPTTestResult
 }
 
 // WPTRunProcessor contains all the steps for the workflow to consume wpt data
@@ -67,6 +68,10 @@ type WebFeatureWPTScoreStorer interface {
 		context.Context,
 		int64,
 		map[string]wptconsumertypes.WPTFeatureMetric) error
+	UpsertWPTRunFeatureTestResults(
+		context.Context,
+		int64,
+		map[string][]wptconsumertypes.WPTTestResult) error
 }
 
 func (w WPTRunProcessor) ProcessRun(
@@ -100,5 +105,13 @@ func (w WPTRunProcessor) ProcessRun(
 		return err
 	}
 
+	// Upsert the individual test results for the given run.
+	// These are used to compare the results across browsers.
+	err = w.scoreStorer.UpsertWPTRunFeatureTestResults(
+		ctx, run.ID, resultsSummaryFile.TestResults(ctx, &webFeaturesData))
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
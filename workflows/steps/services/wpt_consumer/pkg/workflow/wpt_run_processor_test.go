@@ -61,7 +61,8 @@ func (m *MockWebFeaturesDataGetter) GetWebFeaturesData(
 }
 
 type MockResultsFile struct {
-	metricsPerFeature map[string]wptconsumertypes.WPTFeatureMetric
+	metricsPerFeature     map[string]wptconsumertypes.WPTFeatureMetric
+	testResultsPerFeature map[string][]wptconsumertypes.WPTTestResult
 }
 
 func (m MockResultsFile) Score(
@@ -70,6 +71,12 @@ func (m MockResultsFile) Score(
 	return m.metricsPerFeature
 }
 
+func (m MockResultsFile) TestResults(
+	_ context.Context,
+	_ *shared.WebFeaturesData) map[string][]wptconsumertypes.WPTTestResult {
+	return m.testResultsPerFeature
+}
+
 type insertRunConfig struct {
 	run shared.TestRun
 	err error
@@ -81,15 +88,23 @@ type upsertMetricConfig struct {
 	err               error
 }
 
+type upsertTestResultsConfig struct {
+	runID                 int64
+	testResultsPerFeature map[string][]wptconsumertypes.WPTTestResult
+	err                   error
+}
+
 type MockWebFeatureWPTScoreStorer struct {
-	insertRunCfg    *insertRunConfig
-	upsertMetricCfg *upsertMetricConfig
-	t               *testing.T
+	insertRunCfg         *insertRunConfig
+	upsertMetricCfg      *upsertMetricConfig
+	upsertTestResultsCfg *upsertTestResultsConfig
+	t                    *testing.T
 }
 
 var (
-	errInsertWPTRun    = errors.New("insert wpt run test error")
-	errUpsertWPTMetric = errors.New("upsert wpt metric test error")
+	errInsertWPTRun         = errors.New("insert wpt run test error")
+	errUpsertWPTMetric      = errors.New("upsert wpt metric test error")
+	errUpsertWPTTestResults = errors.New("upsert wpt test results test error")
 )
 
 func (m *MockWebFeatureWPTScoreStorer) InsertWPTRun(
@@ -114,6 +129,18 @@ func (m *MockWebFeatureWPTScoreStorer) UpsertWPTRunFeatureMetrics(
 	return m.upsertMetricCfg.err
 }
 
+func (m *MockWebFeatureWPTScoreStorer) UpsertWPTRunFeatureTestResults(
+	_ context.Context,
+	runID int64,
+	testResultsPerFeature map[string][]wptconsumertypes.WPTTestResult) error {
+	if !reflect.DeepEqual(testResultsPerFeature, m.upsertTestResultsCfg.testResultsPerFeature) ||
+		runID != m.upsertTestResultsCfg.runID {
+		m.t.Error("unexpected input to UpsertWPTRunFeatureTestResults")
+	}
+
+	return m.upsertTestResultsCfg.err
+}
+
 type processRunTest struct {
 	name                      string
 	inputRun                  shared.TestRun
@@ -121,6 +148,7 @@ type processRunTest struct {
 	mockWebFeaturesDataGetter *MockWebFeaturesDataGetter
 	insertRunConfig           *insertRunConfig
 	upsertMetricConfig        *upsertMetricConfig
+	upsertTestResultsConfig   *upsertTestResultsConfig
 	expectedErr               error
 }
 
@@ -177,6 +205,20 @@ func TestProcessRun(t *testing.T) {
 				},
 				err: nil,
 			},
+			upsertTestResultsConfig: &upsertTestResultsConfig{
+				runID: 123,
+				testResultsPerFeature: map[string][]wptconsumertypes.WPTTestResult{
+					"feature1": {
+						{
+							TestName:      "test1.html",
+							Passed:        true,
+							SubtestPass:   valuePtr[int64](10),
+							TotalSubtests: valuePtr[int64](10),
+						},
+					},
+				},
+				err: nil,
+			},
 			mockResultsDownloader: &MockResultsDownloader{
 				resultsSummary: MockResultsFile{
 					metricsPerFeature: map[string]wptconsumertypes.WPTFeatureMetric{
@@ -188,6 +230,16 @@ func TestProcessRun(t *testing.T) {
 							FeatureRunDetails: nil,
 						},
 					},
+					testResultsPerFeature: map[string][]wptconsumertypes.WPTTestResult{
+						"feature1": {
+							{
+								TestName:      "test1.html",
+								Passed:        true,
+								SubtestPass:   valuePtr[int64](10),
+								TotalSubtests: valuePtr[int64](10),
+							},
+						},
+					},
 				},
 				shouldFail: false,
 			},
@@ -219,8 +271,9 @@ func TestProcessRun(t *testing.T) {
 					},
 				},
 			},
-			insertRunConfig:    nil,
-			upsertMetricConfig: nil,
+			insertRunConfig:         nil,
+			upsertMetricConfig:      nil,
+			upsertTestResultsConfig: nil,
 			mockResultsDownloader: &MockResultsDownloader{
 				resultsSummary: nil,
 				shouldFail:     true,
@@ -249,8 +302,9 @@ func TestProcessRun(t *testing.T) {
 					},
 				},
 			},
-			insertRunConfig:    nil,
-			upsertMetricConfig: nil,
+			insertRunConfig:         nil,
+			upsertMetricConfig:      nil,
+			upsertTestResultsConfig: nil,
 			mockResultsDownloader: &MockResultsDownloader{
 				resultsSummary: MockResultsFile{
 					metricsPerFeature: map[string]wptconsumertypes.WPTFeatureMetric{
@@ -262,6 +316,7 @@ func TestProcessRun(t *testing.T) {
 							FeatureRunDetails: nil,
 						},
 					},
+					testResultsPerFeature: nil,
 				},
 				shouldFail: false,
 			},
@@ -308,7 +363,8 @@ func TestProcessRun(t *testing.T) {
 				},
 				err: errInsertWPTRun,
 			},
-			upsertMetricConfig: nil,
+			upsertMetricConfig:      nil,
+			upsertTestResultsConfig: nil,
 			mockResultsDownloader: &MockResultsDownloader{
 				resultsSummary: MockResultsFile{
 					metricsPerFeature: map[string]wptconsumertypes.WPTFeatureMetric{
@@ -320,6 +376,7 @@ func TestProcessRun(t *testing.T) {
 							FeatureRunDetails: nil,
 						},
 					},
+					testResultsPerFeature: nil,
 				},
 				shouldFail: false,
 			},
@@ -380,6 +437,7 @@ func TestProcessRun(t *testing.T) {
 				},
 				err: errUpsertWPTMetric,
 			},
+			upsertTestResultsConfig: nil,
 			mockResultsDownloader: &MockResultsDownloader{
 				resultsSummary: MockResultsFile{
 					metricsPerFeature: map[string]wptconsumertypes.WPTFeatureMetric{
@@ -391,6 +449,7 @@ func TestProcessRun(t *testing.T) {
 							FeatureRunDetails: nil,
 						},
 					},
+					testResultsPerFeature: nil,
 				},
 				shouldFail: false,
 			},
@@ -400,6 +459,67 @@ func TestProcessRun(t *testing.T) {
 			},
 			expectedErr: errUpsertWPTMetric,
 		},
+		// nolint: dupl // Ok to have similar test cases
+		{
+			name: "Fail to upsert test results",
+			// nolint: exhaustruct // WONTFIX: external struct
+			inputRun: shared.TestRun{
+				ID:        123,
+				TimeStart: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+				TimeEnd:   time.Date(2024, time.January, 2, 0, 0, 0, 0, time.UTC),
+				Labels:    []string{shared.StableLabel},
+				ProductAtRevision: shared.ProductAtRevision{
+					FullRevisionHash: "sha",
+					Product: shared.Product{
+						OSName:         "os",
+						OSVersion:      "osversion",
+						BrowserName:    "browser",
+						BrowserVersion: "browserverion",
+					},
+				},
+			},
+			insertRunConfig: &insertRunConfig{
+				// nolint: exhaustruct // WONTFIX: external struct
+				run: shared.TestRun{
+					ID:        123,
+					TimeStart: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+					TimeEnd:   time.Date(2024, time.January, 2, 0, 0, 0, 0, time.UTC),
+					Labels:    []string{shared.StableLabel},
+					ProductAtRevision: shared.ProductAtRevision{
+						FullRevisionHash: "sha",
+						Product: shared.Product{
+							OSName:         "os",
+							OSVersion:      "osversion",
+							BrowserName:    "browser",
+							BrowserVersion: "browserverion",
+						},
+					},
+				},
+				err: nil,
+			},
+			upsertMetricConfig: &upsertMetricConfig{
+				runID:             123,
+				metricsPerFeature: map[string]wptconsumertypes.WPTFeatureMetric{},
+				err:               nil,
+			},
+			upsertTestResultsConfig: &upsertTestResultsConfig{
+				runID:                 123,
+				testResultsPerFeature: map[string][]wptconsumertypes.WPTTestResult{},
+				err:                   errUpsertWPTTestResults,
+			},
+			mockResultsDownloader: &MockResultsDownloader{
+				resultsSummary: MockResultsFile{
+					metricsPerFeature:     map[string]wptconsumertypes.WPTFeatureMetric{},
+					testResultsPerFeature: map[string][]wptconsumertypes.WPTTestResult{},
+				},
+				shouldFail: false,
+			},
+			mockWebFeaturesDataGetter: &MockWebFeaturesDataGetter{
+				webFeaturesData: shared.WebFeaturesData{},
+				shouldFail:      false,
+			},
+			expectedErr: errUpsertWPTTestResults,
+		},
 	}
 	for _, tt := range testCases {
 		t.Run(tt.name, func(t *testing.T) {
@@ -407,9 +527,10 @@ func TestProcessRun(t *testing.T) {
 				tt.mockResultsDownloader,
 				tt.mockWebFeaturesDataGetter,
 				&MockWebFeatureWPTScoreStorer{
-					insertRunCfg:    tt.insertRunConfig,
-					upsertMetricCfg: tt.upsertMetricConfig,
-					t:               t,
+					insertRunCfg:         tt.insertRunConfig,
+					upsertMetricCfg:      tt.upsertMetricConfig,
+					upsertTestResultsCfg: tt.upsertTestResultsConfig,
+					t:                    t,
 				},
 			)
 